@@ -0,0 +1,234 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	"github.com/cardinalhq/flutter/pkg/cluster"
+	"github.com/cardinalhq/flutter/pkg/featuregate"
+	"github.com/cardinalhq/flutter/pkg/health"
+	"github.com/cardinalhq/flutter/pkg/script"
+)
+
+var (
+	workers         int
+	listenAddr      string
+	coordinatorAddr string
+	workerName      string
+)
+
+func init() {
+	CoordinateCmd.Flags().
+		IntVar(&workers, "workers", 0, "Number of workers to split the run across")
+	CoordinateCmd.Flags().
+		StringVar(&listenAddr, "listen", ":7070", "Address to serve the coordinator on")
+	CoordinateCmd.Flags().
+		StringArrayVarP(&configPaths, "config", "c", nil, "Configuration file(s) to load (repeatable)")
+	CoordinateCmd.Flags().
+		StringArrayVarP(&timelineFiles, "timeline", "t", nil, "Timeline file(s) to parse, each optionally offset as file.json@+30m (repeatable)")
+	CoordinateCmd.Flags().
+		StringArrayVar(&overrideFiles, "overrides", nil, "Resource attribute override file(s) to apply to every timeline (repeatable)")
+	CoordinateCmd.Flags().
+		BoolVar(&dryrun, "dryrun", false, "Do not actually run the simulation")
+	CoordinateCmd.Flags().
+		BoolVar(&backfill, "backfill", false, "Run as fast as possible, sending to every destination, with progress and ETA logged")
+	CoordinateCmd.Flags().
+		Float64Var(&maxExportRate, "max-export-rate", 0, "Maximum datapoints and spans each worker sends per second in --backfill and --dryrun mode (default: unlimited)")
+	CoordinateCmd.Flags().
+		DurationVar(&from, "from", 0, "Start time for the simulation (default: now)")
+	CoordinateCmd.Flags().
+		StringVar(&runID, "run-id", "", `Add this flutter.run_id to every resource, or "auto" for a random UUID`)
+	_ = CoordinateCmd.MarkFlagRequired("workers")
+
+	WorkerCmd.Flags().
+		StringVar(&coordinatorAddr, "coordinator", "", "Address of the coordinator to join")
+	WorkerCmd.Flags().
+		StringVar(&workerName, "name", "", "Name to join as (default: hostname-pid)")
+	WorkerCmd.Flags().
+		BoolVar(&emitJson, "json", false, "Dump the timeline in JSON format")
+	WorkerCmd.Flags().
+		BoolVar(&emitDebug, "debug", false, "Dump the OpenTelemetry payloads in JSON format")
+	WorkerCmd.Flags().
+		StringVar(&parquetDir, "parquet", "", "Write datapoints and spans to Parquet files under this directory")
+	WorkerCmd.Flags().
+		StringVar(&manifestPath, "manifest", "", "Write a JSON manifest of every series and trace resource emitted to this file")
+	WorkerCmd.Flags().
+		StringVar(&healthAddr, "health-addr", "", "Serve a health check endpoint on this address (e.g. "+health.DefaultAddr+")")
+	WorkerCmd.Flags().
+		BoolVar(&zpages, "zpages", false, "Serve a live debug page at "+script.DebugPath+" on the --health-addr server")
+	WorkerCmd.Flags().
+		StringArrayVar(&featureGates, "feature-gates", nil, "Comma-separated feature gate IDs to enable, or disable with a - prefix (repeatable)")
+	_ = WorkerCmd.MarkFlagRequired("coordinator")
+}
+
+var CoordinateCmd = &cobra.Command{
+	Use:   "coordinate",
+	Short: "Coordinate a run split across workers",
+	Long: `Serve a run to --workers workers, each started with "flutter worker".  Every
+worker runs the same configs and timelines, with the same seed, start time, and
+run ID, and emits its share of the metrics, traces, and RUM apps.  The workers
+move from tick to tick together, and one failing stops them all.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCoordinate()
+	},
+}
+
+var WorkerCmd = &cobra.Command{
+	Use:          "worker",
+	Short:        "Run a shard of a coordinated run",
+	Long:         `Join the coordinator at --coordinator and run the shard it assigns.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWorker()
+	},
+}
+
+func runCoordinate() error {
+	in, err := readInputs(configPaths, timelineFiles, overrideFiles)
+	if err != nil {
+		return err
+	}
+	// Build the script here too, so a bad config fails before any
+	// worker joins.
+	cfg, rscript, err := buildScript(in)
+	if err != nil {
+		return err
+	}
+	if err := rscript.Prepare(cfg); err != nil {
+		return fmt.Errorf("error creating running config: %w", err)
+	}
+
+	a := cluster.Assignment{
+		Inputs:         in,
+		Seed:           cfg.Seed,
+		WallclockStart: cfg.WallclockStart,
+		RunID:          cfg.RunID,
+		From:           from,
+		Dryrun:         cfg.Dryrun || dryrun,
+		Backfill:       cfg.Backfill || backfill,
+		MaxExportRate:  cfg.MaxExportRate,
+	}
+	if a.Seed == 0 {
+		a.Seed = uint64(time.Now().UnixNano())
+	}
+	if runID != "" {
+		a.RunID = runID
+	}
+	if a.RunID == "auto" {
+		a.RunID = uuid.NewString()
+	}
+	if maxExportRate != 0 {
+		a.MaxExportRate = maxExportRate
+	}
+	coord, err := cluster.NewCoordinator(workers, a)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("error listening on %q: %w", listenAddr, err)
+	}
+	srv := grpc.NewServer()
+	coord.Register(srv)
+	go func() {
+		if err := srv.Serve(ln); err != nil {
+			slog.Error("Coordinator failed", "error", err)
+		}
+	}()
+	slog.Info("Coordinator listening", "addr", ln.Addr().String(), "workers", workers, "seed", a.Seed, "runID", a.RunID)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case <-coord.Done():
+	case <-ctx.Done():
+		coord.Abort(errors.New("coordinator stopped"))
+	}
+	srv.GracefulStop()
+	if err := coord.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	slog.Info("Coordinated run finished")
+	return nil
+}
+
+func runWorker() error {
+	if err := featuregate.GlobalRegistry().Apply(featureGates); err != nil {
+		return fmt.Errorf("invalid --feature-gates: %w", err)
+	}
+	name := workerName
+	if name == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("error getting hostname: %w", err)
+		}
+		name = fmt.Sprintf("%s-%d", strings.Split(host, ".")[0], os.Getpid())
+	}
+	client, err := cluster.Dial(coordinatorAddr, name)
+	if err != nil {
+		return fmt.Errorf("error connecting to coordinator: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	slog.Info("Joining coordinator", "addr", coordinatorAddr, "worker", name)
+	a, err := client.Join(ctx)
+	stop()
+	if err != nil {
+		return fmt.Errorf("error joining coordinator: %w", err)
+	}
+	slog.Info("Joined coordinator", "shard", a.Shard, "shards", a.Shards)
+
+	err = runShard(client, a)
+	if ferr := client.Finish(err); ferr != nil {
+		slog.Warn("Error reporting to coordinator", "error", ferr)
+	}
+	return err
+}
+
+// runShard runs the worker's shard of the assigned run.
+func runShard(client *cluster.Client, a *cluster.Assignment) error {
+	cfg, rscript, err := buildScript(a.Inputs)
+	if err != nil {
+		return err
+	}
+	cfg.Seed = a.Seed
+	cfg.WallclockStart = a.WallclockStart
+	cfg.RunID = a.RunID
+	cfg.Dryrun = a.Dryrun
+	cfg.Backfill = a.Backfill
+	cfg.MaxExportRate = a.MaxExportRate
+	if err := rscript.SetShard(a.Shard, a.Shards); err != nil {
+		return err
+	}
+	rscript.OnTick(client.Tick)
+	return runScript(cfg, rscript, a.From)
+}
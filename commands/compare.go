@@ -0,0 +1,92 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cardinalhq/flutter/pkg/compare"
+)
+
+var (
+	compareTolerance float64
+	compareMaxDiffs  int
+)
+
+func init() {
+	CompareCmd.Flags().
+		Float64Var(&compareTolerance, "tolerance", 0, "Maximum absolute difference for values to be considered equal")
+	CompareCmd.Flags().
+		IntVar(&compareMaxDiffs, "max-diffs", 50, "Maximum differences to print per signal, 0 for all")
+}
+
+var CompareCmd = &cobra.Command{
+	Use:   "compare runA.jsonl runB.jsonl",
+	Short: "Compare two captured runs",
+	Long: `Compare the output of two runs captured with "simulate --json", ignoring ordering
+and span IDs.  Exits non-zero if they differ.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCompare(args[0], args[1])
+	},
+}
+
+func runCompare(pathA, pathB string) error {
+	a, err := loadRun(pathA)
+	if err != nil {
+		return err
+	}
+	b, err := loadRun(pathB)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("A: %s (%d series, %d spans)\n", pathA, len(a.Series), countSpans(a))
+	fmt.Printf("B: %s (%d series, %d spans)\n", pathB, len(b.Series), countSpans(b))
+	diffs := compare.Diff(a, b, compareTolerance, compareMaxDiffs)
+	if len(diffs) == 0 {
+		fmt.Println("runs are equivalent")
+		return nil
+	}
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	return errors.New("runs differ")
+}
+
+func loadRun(path string) (*compare.Run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %q: %w", path, err)
+	}
+	defer f.Close()
+	run, err := compare.Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", path, err)
+	}
+	return run, nil
+}
+
+func countSpans(r *compare.Run) int {
+	n := 0
+	for _, c := range r.Spans {
+		n += c
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cardinalhq/flutter/pkg/fit"
+	"github.com/cardinalhq/flutter/pkg/timeline"
+)
+
+var (
+	fitName    string
+	fitType    string
+	fitPeriod  time.Duration
+	fitSegment time.Duration
+	fitLabels  map[string]string
+)
+
+func init() {
+	FitCmd.Flags().
+		StringVar(&fitName, "name", "recorded", "Metric name for the written timeline")
+	FitCmd.Flags().
+		StringVar(&fitType, "type", "gauge", "Metric type for the written timeline")
+	FitCmd.Flags().
+		DurationVar(&fitPeriod, "period", 24*time.Hour, "Seasonality period to fit")
+	FitCmd.Flags().
+		DurationVar(&fitSegment, "segment", time.Hour, "Length of each ramp segment in the written timeline")
+	FitCmd.Flags().
+		StringToStringVar(&fitLabels, "labels", nil, "Select the Prometheus series having these labels (key=value,...)")
+}
+
+var FitCmd = &cobra.Command{
+	Use:   "fit recorded.csv|recorded.json",
+	Short: "Fit a timeline to a recorded series",
+	Long: `Estimate the baseline, seasonality, and noise of a recorded series, either
+timestamp,value CSV or a saved Prometheus query_range JSON response, and write
+a timeline that reproduces it to stdout.  The fitted parameters go to stderr.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFit(args[0])
+	},
+}
+
+func runFit(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %q: %w", path, err)
+	}
+	var series *fit.Series
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		series, err = fit.ReadPrometheus(b, fitLabels)
+	} else {
+		series, err = fit.ReadCSV(bytes.NewReader(b))
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %q: %w", path, err)
+	}
+
+	params, err := fit.Fit(series, fitPeriod)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "samples: %d over %s\nbaseline: %.6g\namplitude: %.6g (period %s, peak at %s)\nstdDev: %.6g\n",
+		len(series.Values), params.Span, params.Baseline, params.Amplitude, params.Period, params.Peak.Round(time.Second), params.StdDev)
+
+	metric, err := params.Metric(fitName, fitType, fitSegment)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(timeline.Timeline{Metrics: []timeline.Metric{metric}})
+}
@@ -14,17 +14,39 @@
 
 package commands
 
-import "github.com/spf13/cobra"
+import (
+	"slices"
+
+	"github.com/spf13/cobra"
+)
 
 var root = &cobra.Command{
 	Use:     "flutter",
 	Short:   "Flutter is a load testing tool for OpenTelemetry",
 	Long:    `Flutter is a load testing tool for OpenTelemetry. It allows you to simulate metric telemetry`,
 	Version: version,
+	// Config files given without a subcommand run a simulation, as
+	// "flutter simulate -c" would, so older invocations keep working.
+	// The simulate flags work here too.
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(configPaths) == 0 && len(timelineFiles) == 0 {
+			return cmd.Help()
+		}
+		return runSimulate(append(slices.Clone(configPaths), args...), timelineFiles)
+	},
+}
+
+func init() {
+	addSimulateFlags(root)
 }
 
 func Execute() error {
 	root.AddCommand(SimulateCmd)
+	root.AddCommand(CompareCmd)
+	root.AddCommand(FitCmd)
+	root.AddCommand(CoordinateCmd)
+	root.AddCommand(WorkerCmd)
 
 	return root.Execute()
 }
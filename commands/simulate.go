@@ -16,17 +16,24 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/cardinalhq/flutter/pkg/cluster"
 	"github.com/cardinalhq/flutter/pkg/config"
 	"github.com/cardinalhq/flutter/pkg/emitter"
+	"github.com/cardinalhq/flutter/pkg/featuregate"
+	"github.com/cardinalhq/flutter/pkg/health"
 	"github.com/cardinalhq/flutter/pkg/script"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
 	"github.com/cardinalhq/flutter/pkg/timeline"
 )
 
@@ -34,42 +41,102 @@ var (
 	// these will hold all --config and --timeline values
 	configPaths   []string
 	timelineFiles []string
+	overrideFiles []string
 	dryrun        bool
+	backfill      bool
+	maxExportRate float64
 	from          time.Duration
 	emitJson      bool
 	emitDebug     bool
 	dumpActions   bool
+	dumpFormat    string
+	dumpSpecs     bool
+	runID         string
+	parquetDir    string
+	manifestPath  string
+	healthAddr    string
+	featureGates  []string
+	zpages        bool
 )
 
 func init() {
+	addSimulateFlags(SimulateCmd)
+}
+
+// addSimulateFlags adds the simulate flags to cmd, so the root command
+// takes them too.
+func addSimulateFlags(cmd *cobra.Command) {
 	// --config / -c can be specified multiple times
-	SimulateCmd.Flags().
+	cmd.Flags().
 		StringArrayVarP(&configPaths, "config", "c", nil, "Configuration file(s) to load (repeatable)")
 
 	// --timeline / -t can be specified multiple times
-	SimulateCmd.Flags().
-		StringArrayVarP(&timelineFiles, "timeline", "t", nil, "Timeline file(s) to parse (repeatable)")
+	cmd.Flags().
+		StringArrayVarP(&timelineFiles, "timeline", "t", nil, "Timeline file(s) to parse, each optionally offset as file.json@+30m (repeatable)")
+
+	// --overrides can be specified multiple times
+	cmd.Flags().
+		StringArrayVar(&overrideFiles, "overrides", nil, "Resource attribute override file(s) to apply to every timeline (repeatable)")
 
 	// --dryrun will not actually run the simulation
-	SimulateCmd.Flags().
+	cmd.Flags().
 		BoolVar(&dryrun, "dryrun", false, "Do not actually run the simulation")
 
+	// --backfill runs as fast as it can while still sending to destinations
+	cmd.Flags().
+		BoolVar(&backfill, "backfill", false, "Run as fast as possible, sending to every destination, with progress and ETA logged")
+
+	// --max-export-rate paces backfills and dry runs
+	cmd.Flags().
+		Float64Var(&maxExportRate, "max-export-rate", 0, "Maximum datapoints and spans sent per second in --backfill and --dryrun mode (default: unlimited)")
+
 	// --from will set the start time for the simulation
-	SimulateCmd.Flags().
+	cmd.Flags().
 		DurationVar(&from, "from", 0, "Start time for the simulation (default: now)")
 
 		// --json will show the output timeline in JSON format
-	SimulateCmd.Flags().
+	cmd.Flags().
 		BoolVar(&emitJson, "json", false, "Dump the timeline in JSON format")
 
 	// --debug will show the output timeline in JSON format
-	SimulateCmd.Flags().
+	cmd.Flags().
 		BoolVar(&emitDebug, "debug", false, "Dump the OpenTelemetry payloads in JSON format")
 
 	// --dump-actions will show the actions in JSON format
-	SimulateCmd.Flags().
-		BoolVar(&dumpActions, "dump-actions", false, "Dump the actions in JSON format and exit")
-	// --dump-metrics will show the metrics in JSON format
+	cmd.Flags().
+		BoolVar(&dumpActions, "dump-actions", false, "Dump the actions and exit")
+
+	// --dump-specs will show the decoded specs of each action's component
+	cmd.Flags().
+		BoolVar(&dumpSpecs, "dump-specs", false, "Dump each action with its component's decoded, defaulted spec and exit")
+
+	// --format selects how --dump-actions writes the actions
+	cmd.Flags().
+		StringVar(&dumpFormat, "format", "json", "Format for --dump-actions and --dump-specs: "+strings.Join(script.DumpFormats, ", "))
+
+	// --parquet will write datapoints and spans to Parquet files
+	cmd.Flags().
+		StringVar(&parquetDir, "parquet", "", "Write datapoints and spans to Parquet files under this directory")
+
+	// --manifest will write a cleanup manifest of everything emitted
+	cmd.Flags().
+		StringVar(&manifestPath, "manifest", "", "Write a JSON manifest of every series and trace resource emitted to this file")
+
+	// --health-addr serves a collector-style health check endpoint
+	cmd.Flags().
+		StringVar(&healthAddr, "health-addr", "", "Serve a health check endpoint on this address (e.g. "+health.DefaultAddr+")")
+
+	// --zpages serves a live debug page on the health server
+	cmd.Flags().
+		BoolVar(&zpages, "zpages", false, "Serve a live debug page at "+script.DebugPath+" on the --health-addr server")
+
+	// --run-id stamps every resource with flutter.run_id
+	cmd.Flags().
+		StringVar(&runID, "run-id", "", `Add this flutter.run_id to every resource, or "auto" for a random UUID`)
+
+	// --feature-gates follows the collector convention: gate IDs, comma separated, prefixed with - to disable
+	cmd.Flags().
+		StringArrayVar(&featureGates, "feature-gates", nil, "Comma-separated feature gate IDs to enable, or disable with a - prefix (repeatable)")
 }
 
 var SimulateCmd = &cobra.Command{
@@ -82,37 +149,126 @@ var SimulateCmd = &cobra.Command{
 }
 
 func runSimulate(configs, timelines []string) error {
-	// load and merge all config files in order
-	cfg, err := config.LoadConfigs(configs)
+	if err := featuregate.GlobalRegistry().Apply(featureGates); err != nil {
+		return fmt.Errorf("invalid --feature-gates: %w", err)
+	}
+
+	in, err := readInputs(configs, timelines, overrideFiles)
+	if err != nil {
+		return err
+	}
+	cfg, rscript, err := buildScript(in)
 	if err != nil {
-		return fmt.Errorf("error loading config files: %w", err)
+		return err
 	}
 
-	rscript := script.NewScript()
-	for _, tl := range timelines {
-		slog.Info("Loading timeline file", "file", tl)
-		b, err := os.ReadFile(tl)
+	if dumpActions {
+		if err := rscript.DumpFormat(os.Stdout, dumpFormat); err != nil {
+			return fmt.Errorf("error dumping actions: %w", err)
+		}
+		return nil
+	}
+
+	if dumpSpecs {
+		if err := rscript.DumpResolved(os.Stdout, cfg, dumpFormat); err != nil {
+			return fmt.Errorf("error dumping specs: %w", err)
+		}
+		return nil
+	}
+
+	cfg.Dryrun = cfg.Dryrun || dryrun
+	cfg.Backfill = cfg.Backfill || backfill
+	if maxExportRate != 0 {
+		cfg.MaxExportRate = maxExportRate
+	}
+	if runID != "" {
+		cfg.RunID = runID
+	}
+	return runScript(cfg, rscript, from)
+}
+
+// readInputs reads the config, timeline, and override files a run is
+// built from.
+func readInputs(configs, timelines, overrides []string) (cluster.Inputs, error) {
+	var in cluster.Inputs
+	for _, path := range configs {
+		slog.Info("Loading config", "file", path)
+		b, err := os.ReadFile(path)
 		if err != nil {
-			return fmt.Errorf("error reading timeline file %q: %w", tl, err)
+			return in, fmt.Errorf("error loading config files: %w", err)
 		}
-		ptl, err := timeline.ParseTimeline(b)
+		in.Configs = append(in.Configs, cluster.File{Name: path, Data: b})
+	}
+	for _, path := range overrides {
+		b, err := os.ReadFile(path)
 		if err != nil {
-			return fmt.Errorf("error parsing timeline file %q: %w", tl, err)
+			return in, fmt.Errorf("error reading override file %q: %w", path, err)
 		}
-		if err := ptl.MergeIntoScript(rscript); err != nil {
-			return fmt.Errorf("error merging timeline into config: %w", err)
+		in.Overrides = append(in.Overrides, cluster.File{Name: path, Data: b})
+	}
+	for _, arg := range timelines {
+		tl, offset, err := timeline.SplitOffset(arg)
+		if err != nil {
+			return in, err
 		}
+		slog.Info("Loading timeline file", "file", tl, "offset", offset)
+		b, err := os.ReadFile(tl)
+		if err != nil {
+			return in, fmt.Errorf("error reading timeline file %q: %w", tl, err)
+		}
+		in.Timelines = append(in.Timelines, cluster.File{Name: tl, Data: b, Offset: offset})
 	}
+	return in, nil
+}
 
-	if dumpActions {
-		if err := rscript.Dump(os.Stdout); err != nil {
-			return fmt.Errorf("error dumping actions: %w", err)
-		}
-		return nil
+// buildScript merges the configs in order and builds the script from
+// their script sections and the timelines, with the overrides applied.
+func buildScript(in cluster.Inputs) (*config.Config, *script.Script, error) {
+	data := make([][]byte, 0, len(in.Configs))
+	for _, f := range in.Configs {
+		data = append(data, f.Data)
+	}
+	cfg, err := config.ParseConfigs(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading config files: %w", err)
 	}
 
-	cfg.Dryrun = cfg.Dryrun || dryrun
+	rscript := script.NewScript()
+	for _, entry := range cfg.Script {
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   entry.Name,
+			At:   entry.At,
+			To:   entry.To,
+			Type: entry.Type,
+			Spec: entry.Spec,
+		})
+	}
+	overrides := &timeline.Overrides{}
+	for _, f := range in.Overrides {
+		o, err := timeline.ParseOverrides(f.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing override file %q: %w", f.Name, err)
+		}
+		overrides.Merge(o)
+	}
+	for _, f := range in.Timelines {
+		ptl, err := timeline.ParseTimeline(f.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing timeline file %q: %w", f.Name, err)
+		}
+		ptl.ApplyOverrides(overrides)
+		ptl.Offset(f.Offset)
+		if err := ptl.MergeIntoScript(rscript); err != nil {
+			return nil, nil, fmt.Errorf("error merging timeline into config: %w", err)
+		}
+	}
+	return cfg, rscript, nil
+}
 
+// runScript adds the emitters to the script, runs it from from, and
+// reports on a dry run.
+func runScript(cfg *config.Config, rscript *script.Script, from time.Duration) error {
+	var err error
 	if !cfg.Dryrun {
 		rscript.AddEmitter(emitter.NewTickerEmitter(os.Stdout))
 	}
@@ -125,17 +281,107 @@ func runSimulate(configs, timelines []string) error {
 		rscript.AddEmitter(emitter.NewDebugEmitter(os.Stdout))
 	}
 
-	if cfg.OTLPDestination.Endpoint != "" && !cfg.Dryrun {
-		slog.Info("Using OTLP destination", "endpoint", cfg.OTLPDestination.Endpoint)
-		client := &http.Client{
-			Timeout: cfg.OTLPDestination.Timeout,
+	for _, name := range cfg.Emitters {
+		switch name {
+		case "null":
+			rscript.AddEmitter(emitter.NewNullEmitter())
+		case "counting":
+			rscript.AddEmitter(emitter.NewCountingEmitter(os.Stdout))
+		default:
+			return fmt.Errorf("unknown emitter %q", name)
+		}
+	}
+
+	// Destinations go through a tee so each can have its own error policy.
+	var tee *emitter.TeeEmitter
+	var sizes []*emitter.SizeEmitter
+	if cfg.Dryrun {
+		tee, err = emitter.NewTeeEmitter(cfg.ErrorPolicies)
+		if err != nil {
+			return fmt.Errorf("invalid errorPolicies: %w", err)
+		}
+		if err := tee.SetQueues(cfg.SendQueues); err != nil {
+			return fmt.Errorf("invalid sendQueues: %w", err)
+		}
+		if err := tee.SetTransforms(cfg.Transforms); err != nil {
+			return fmt.Errorf("invalid transforms: %w", err)
 		}
-		otlp, err := emitter.NewOTLPEmitter(client, cfg.OTLPDestination.Endpoint, cfg.OTLPDestination.Headers)
+		defer tee.Close()
+		// Measure what each destination would have been sent, or what
+		// an OTLP destination would when none is configured.
+		names := emitter.DestinationNames(cfg)
+		if len(names) == 0 {
+			names = []string{"otlp"}
+		}
+		for _, name := range names {
+			se := emitter.NewSizeEmitter(name)
+			tee.Add(name, se)
+			sizes = append(sizes, se)
+		}
+	} else {
+		dests, err := emitter.NewDestinations(cfg)
+		if err != nil {
+			return err
+		}
+		defer dests.Close()
+		tee = dests.TeeEmitter
+	}
+
+	if parquetDir != "" {
+		pe, err := emitter.NewParquetEmitter(parquetDir)
 		if err != nil {
-			return fmt.Errorf("error creating OTLP emitter: %w", err)
+			return fmt.Errorf("error creating Parquet emitter: %w", err)
+		}
+		tee.Add("parquet", pe)
+	}
+
+	if manifestPath != "" {
+		tee.Add("manifest", emitter.NewManifestEmitter(manifestPath))
+	}
+
+	for _, name := range tee.UnusedPolicies() {
+		slog.Warn("Error policy does not match any configured destination", "destination", name)
+	}
+	if tee.Len() > 0 {
+		rscript.AddEmitter(tee)
+	}
+
+	if zpages && healthAddr == "" {
+		return errors.New("--zpages requires --health-addr")
+	}
+	if healthAddr != "" {
+		hs := health.NewServer(healthAddr)
+		if zpages {
+			hs.Handle(script.DebugPath, rscript.DebugHandler())
+		}
+		if err := hs.Start(); err != nil {
+			return fmt.Errorf("error starting health server: %w", err)
 		}
-		rscript.AddEmitter(otlp)
+		defer func() { _ = hs.Shutdown(context.Background()) }()
+		rscript.OnStart(func() { hs.SetReady(true) })
+		rscript.OnStop(func() { hs.SetReady(false) })
 	}
 
-	return script.Simulate(context.Background(), cfg, rscript, from)
+	// SIGINT and SIGTERM stop the run after the current tick, flushing
+	// the emitters, as a collector would on shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := script.Simulate(ctx, cfg, rscript, from); err != nil {
+		return err
+	}
+	if cfg.Dryrun {
+		// stderr, so the summary does not mix with --json output.
+		if err := rscript.WriteGeneratorStats(os.Stderr); err != nil {
+			return fmt.Errorf("error writing generator stats: %w", err)
+		}
+		var measured []emitter.PayloadSizes
+		for _, se := range sizes {
+			measured = append(measured, se.Sizes())
+		}
+		if err := emitter.WritePayloadSizes(os.Stderr, measured, rscript.Duration()-max(from, cfg.Warmup)); err != nil {
+			return fmt.Errorf("error writing payload sizes: %w", err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,137 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/cluster"
+	"github.com/cardinalhq/flutter/pkg/script"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+const scriptConfig = `
+seed: 1
+dryrun: true
+wallclockStart: 2025-01-01T00:00:00Z
+script:
+  - type: metricGenerator
+    name: cpu_base
+    spec:
+      type: constant
+      value: 5
+  - type: metric
+    name: cpu
+    at: 30s
+    to: 1m30s
+    spec:
+      type: gauge
+      frequency: 10s
+      generators: [cpu_base]
+`
+
+const scriptTimeline = `{
+  "metrics": [{
+    "name": "requests",
+    "type": "gauge",
+    "variants": [{
+      "timeline": [{"type": "segment", "end_ts": "2m", "start": 100, "target": 100}]
+    }]
+  }]
+}`
+
+// datapointEmitter counts the datapoints of each metric and notes the
+// ticks they were emitted on.
+type datapointEmitter struct {
+	counts map[string]int
+	ticks  map[string][]time.Duration
+}
+
+func (e *datapointEmitter) EmitMetrics(_ context.Context, rs *state.RunState, md pmetric.Metrics) error {
+	for _, rm := range md.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				e.counts[m.Name()]++
+				e.ticks[m.Name()] = append(e.ticks[m.Name()], rs.Tick)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *datapointEmitter) EmitTraces(context.Context, *state.RunState, ptrace.Traces) error {
+	return nil
+}
+
+func TestBuildScript(t *testing.T) {
+	cfg, rscript, err := buildScript(cluster.Inputs{
+		Configs:   []cluster.File{{Name: "config.yaml", Data: []byte(scriptConfig)}},
+		Timelines: []cluster.File{{Name: "timeline.json", Data: []byte(scriptTimeline), Offset: time.Minute}},
+	})
+	require.NoError(t, err)
+
+	// Script entries keep their durations, and their names become the
+	// action IDs.
+	var cpu, base, timeline int
+	for _, a := range rscript.Actions() {
+		switch a.ID {
+		case "cpu":
+			cpu++
+			assert.Equal(t, "metric", a.Type)
+			assert.Equal(t, 30*time.Second, a.At)
+			assert.Equal(t, 90*time.Second, a.To)
+		case "cpu_base":
+			base++
+			assert.Equal(t, "metricGenerator", a.Type)
+		default:
+			timeline++
+		}
+	}
+	assert.Equal(t, 1, cpu)
+	assert.Equal(t, 1, base)
+	assert.Positive(t, timeline)
+
+	// The config's script runs together with the timeline.
+	e := &datapointEmitter{counts: map[string]int{}, ticks: map[string][]time.Duration{}}
+	rscript.AddEmitter(e)
+	require.NoError(t, script.Simulate(context.Background(), cfg, rscript, 0))
+	assert.Equal(t, []time.Duration{30 * time.Second, 40 * time.Second, 50 * time.Second, 60 * time.Second,
+		70 * time.Second, 80 * time.Second, 90 * time.Second}, e.ticks["cpu"])
+	assert.Positive(t, e.counts["requests"])
+	assert.GreaterOrEqual(t, e.ticks["requests"][0], time.Minute)
+
+	_, _, err = buildScript(cluster.Inputs{Configs: []cluster.File{{Name: "bad.yaml", Data: []byte("script: {")}}})
+	assert.Error(t, err)
+}
+
+func TestRootTakesSimulateFlags(t *testing.T) {
+	t.Cleanup(func() {
+		dryrun = false
+		timelineFiles = nil
+		from = 0
+	})
+	require.NoError(t, root.ParseFlags([]string{"config.yaml", "--dryrun", "-t", "scenario.json", "--from", "1m"}))
+	assert.True(t, dryrun)
+	assert.Equal(t, []string{"scenario.json"}, timelineFiles)
+	assert.Equal(t, time.Minute, from)
+	assert.Equal(t, []string{"config.yaml"}, root.Flags().Args())
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cluster splits a run across workers.  A coordinator hands
+// each worker the same inputs and fixed seed, start time, and run ID,
+// and a shard of the producers to emit, then holds the workers to the
+// same tick so the run moves in step.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// File is an input file, named as it was given.
+type File struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+	// Offset shifts a timeline, as file.json@+30m does.
+	Offset time.Duration `json:"offset,omitempty"`
+}
+
+// Inputs are the files a run is built from.
+type Inputs struct {
+	Configs   []File `json:"configs"`
+	Timelines []File `json:"timelines,omitempty"`
+	Overrides []File `json:"overrides,omitempty"`
+}
+
+// Assignment is what a worker runs: the coordinator's inputs and the
+// settings every shard must share, and its own shard.
+type Assignment struct {
+	Inputs
+	Shard          int           `json:"shard"`
+	Shards         int           `json:"shards"`
+	Seed           uint64        `json:"seed"`
+	WallclockStart time.Time     `json:"wallclockStart"`
+	RunID          string        `json:"runID,omitempty"`
+	From           time.Duration `json:"from,omitempty"`
+	Dryrun         bool          `json:"dryrun,omitempty"`
+	Backfill       bool          `json:"backfill,omitempty"`
+	MaxExportRate  float64       `json:"maxExportRate,omitempty"`
+}
+
+type JoinRequest struct {
+	Worker string `json:"worker"`
+}
+
+type TickRequest struct {
+	Worker string        `json:"worker"`
+	Tick   time.Duration `json:"tick"`
+}
+
+type TickResponse struct{}
+
+type FinishRequest struct {
+	Worker string `json:"worker"`
+	// Error is why the worker failed, or empty if it did not.
+	Error string `json:"error,omitempty"`
+}
+
+type FinishResponse struct{}
+
+// Coordinator assigns shards to a fixed number of workers and keeps
+// them at the same tick.  A worker that fails, goes away while
+// waiting, or reaches a different tick stops the whole run.
+type Coordinator struct {
+	workers    int
+	assignment Assignment
+
+	mu       sync.Mutex
+	shards   map[string]int
+	active   map[string]bool
+	ready    chan struct{}
+	round    *round
+	finished int
+	err      error
+	aborted  chan struct{}
+	done     chan struct{}
+	closed   bool
+}
+
+// round is the tick the active workers are gathering at.
+type round struct {
+	tick    time.Duration
+	arrived map[string]bool
+	release chan struct{}
+}
+
+// NewCoordinator returns a coordinator for workers workers, each
+// given a copy of assignment.  A zero WallclockStart is set when the
+// last worker joins.
+func NewCoordinator(workers int, assignment Assignment) (*Coordinator, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("workers %d must be at least 1", workers)
+	}
+	return &Coordinator{
+		workers:    workers,
+		assignment: assignment,
+		shards:     map[string]int{},
+		active:     map[string]bool{},
+		ready:      make(chan struct{}),
+		aborted:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}, nil
+}
+
+// Join assigns the worker the next shard, and returns its assignment
+// once every worker has joined.
+func (c *Coordinator) Join(ctx context.Context, req *JoinRequest) (*Assignment, error) {
+	c.mu.Lock()
+	if c.err != nil {
+		defer c.mu.Unlock()
+		return nil, c.err
+	}
+	if _, ok := c.shards[req.Worker]; ok {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("worker %q has already joined", req.Worker)
+	}
+	if len(c.shards) == c.workers {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("all %d shards are assigned", c.workers)
+	}
+	shard := len(c.shards)
+	c.shards[req.Worker] = shard
+	c.active[req.Worker] = true
+	if len(c.shards) == c.workers {
+		if c.assignment.WallclockStart.IsZero() {
+			c.assignment.WallclockStart = time.Now()
+		}
+		close(c.ready)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-c.ready:
+	case <-c.aborted:
+		return nil, c.Err()
+	case <-ctx.Done():
+		c.Abort(fmt.Errorf("worker %q left before the run started", req.Worker))
+		return nil, ctx.Err()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a := c.assignment
+	a.Shard, a.Shards = shard, c.workers
+	return &a, nil
+}
+
+// Tick returns once every active worker has reached the tick.
+func (c *Coordinator) Tick(ctx context.Context, req *TickRequest) (*TickResponse, error) {
+	c.mu.Lock()
+	if c.err != nil {
+		defer c.mu.Unlock()
+		return nil, c.err
+	}
+	if !c.active[req.Worker] {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("worker %q is not running", req.Worker)
+	}
+	r := c.round
+	if r == nil {
+		r = &round{tick: req.Tick, arrived: map[string]bool{}, release: make(chan struct{})}
+		c.round = r
+	}
+	if r.tick != req.Tick {
+		c.abortLocked(fmt.Errorf("worker %q reached tick %s while others are at %s", req.Worker, req.Tick, r.tick))
+		defer c.mu.Unlock()
+		return nil, c.err
+	}
+	r.arrived[req.Worker] = true
+	c.releaseLocked()
+	c.mu.Unlock()
+
+	select {
+	case <-r.release:
+		return &TickResponse{}, nil
+	case <-c.aborted:
+		return nil, c.Err()
+	case <-ctx.Done():
+		c.Abort(fmt.Errorf("worker %q went away at tick %s", req.Worker, req.Tick))
+		return nil, ctx.Err()
+	}
+}
+
+// Finish takes the worker out of the run, stopping the others if it
+// failed.
+func (c *Coordinator) Finish(_ context.Context, req *FinishRequest) (*FinishResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.active[req.Worker] {
+		delete(c.active, req.Worker)
+		c.finished++
+	}
+	if req.Error != "" {
+		c.abortLocked(fmt.Errorf("worker %q failed: %s", req.Worker, req.Error))
+		return &FinishResponse{}, nil
+	}
+	c.releaseLocked()
+	if c.finished == c.workers {
+		c.closeLocked()
+	}
+	return &FinishResponse{}, nil
+}
+
+// Abort stops the run, failing every waiting and later call with err.
+func (c *Coordinator) Abort(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.abortLocked(err)
+}
+
+// Done is closed once every worker has finished, or the run is
+// aborted.
+func (c *Coordinator) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns why the run was aborted, or nil.
+func (c *Coordinator) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *Coordinator) abortLocked(err error) {
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.aborted)
+	c.closeLocked()
+}
+
+func (c *Coordinator) closeLocked() {
+	if !c.closed {
+		c.closed = true
+		close(c.done)
+	}
+}
+
+// releaseLocked lets the round go once every active worker is in it.
+func (c *Coordinator) releaseLocked() {
+	r := c.round
+	if r == nil {
+		return
+	}
+	for worker := range c.active {
+		if !r.arrived[worker] {
+			return
+		}
+	}
+	close(r.release)
+	c.round = nil
+}
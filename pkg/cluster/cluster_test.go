@@ -0,0 +1,181 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// serve starts a coordinator for workers workers and returns clients
+// for them, named worker-0 and so on.
+func serve(t *testing.T, workers int, a Assignment) (*Coordinator, []*Client) {
+	t.Helper()
+	c, err := NewCoordinator(workers, a)
+	require.NoError(t, err)
+	ln := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	c.Register(srv)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return ln.DialContext(ctx)
+	})
+	var clients []*Client
+	for i := range workers + 1 {
+		client, err := Dial("passthrough:///bufnet", fmt.Sprintf("worker-%d", i), dialer)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close() })
+		clients = append(clients, client)
+	}
+	return c, clients
+}
+
+// joinAll joins the clients together and returns their assignments.
+func joinAll(t *testing.T, clients []*Client) []*Assignment {
+	t.Helper()
+	assignments := make([]*Assignment, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Go(func() {
+			a, err := client.Join(context.Background())
+			assert.NoError(t, err)
+			assignments[i] = a
+		})
+	}
+	wg.Wait()
+	return assignments
+}
+
+// tickAll has every client reach the tick and returns their errors.
+func tickAll(clients []*Client, tick time.Duration) []error {
+	errs := make([]error, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Go(func() { errs[i] = client.Tick(context.Background(), tick) })
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestCoordinator(t *testing.T) {
+	inputs := Inputs{
+		Configs:   []File{{Name: "config.yaml", Data: []byte("seed: 1\n")}},
+		Timelines: []File{{Name: "timeline.json", Data: []byte("{}"), Offset: 30 * time.Minute}},
+	}
+	c, clients := serve(t, 2, Assignment{Inputs: inputs, Seed: 7, RunID: "run", Dryrun: true})
+
+	_, err := NewCoordinator(0, Assignment{})
+	assert.Error(t, err)
+
+	assignments := joinAll(t, clients[:2])
+	shards := map[int]bool{}
+	for _, a := range assignments {
+		require.NotNil(t, a)
+		shards[a.Shard] = true
+		assert.Equal(t, 2, a.Shards)
+		assert.Equal(t, inputs, a.Inputs)
+		assert.Equal(t, uint64(7), a.Seed)
+		assert.Equal(t, "run", a.RunID)
+		assert.True(t, a.Dryrun)
+		assert.False(t, a.WallclockStart.IsZero())
+		assert.True(t, a.WallclockStart.Equal(assignments[0].WallclockStart))
+	}
+	assert.Equal(t, map[int]bool{0: true, 1: true}, shards)
+
+	_, err = clients[2].Join(context.Background())
+	assert.ErrorContains(t, err, "all 2 shards are assigned")
+
+	// The first worker waits at the tick until the second reaches it.
+	released := make(chan error)
+	go func() { released <- clients[0].Tick(context.Background(), 0) }()
+	select {
+	case <-released:
+		t.Fatal("tick released before every worker reached it")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.NoError(t, clients[1].Tick(context.Background(), 0))
+	require.NoError(t, <-released)
+
+	for _, err := range tickAll(clients[:2], time.Second) {
+		assert.NoError(t, err)
+	}
+
+	// A finished worker no longer holds the others back.
+	require.NoError(t, clients[0].Finish(nil))
+	require.NoError(t, clients[1].Tick(context.Background(), 2*time.Second))
+	require.NoError(t, clients[1].Finish(nil))
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("coordinator not done once every worker finished")
+	}
+	assert.NoError(t, c.Err())
+}
+
+func TestCoordinatorTickMismatch(t *testing.T) {
+	c, clients := serve(t, 2, Assignment{})
+	joinAll(t, clients[:2])
+
+	errs := make(chan error)
+	go func() { errs <- clients[0].Tick(context.Background(), 0) }()
+	time.Sleep(20 * time.Millisecond)
+	err := clients[1].Tick(context.Background(), time.Second)
+	assert.ErrorContains(t, err, `worker "worker-1" reached tick 1s while others are at 0s`)
+	assert.ErrorContains(t, <-errs, "reached tick 1s")
+	<-c.Done()
+	assert.Error(t, c.Err())
+}
+
+func TestCoordinatorWorkerFails(t *testing.T) {
+	c, clients := serve(t, 2, Assignment{})
+	joinAll(t, clients[:2])
+
+	errs := make(chan error)
+	go func() { errs <- clients[0].Tick(context.Background(), 0) }()
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, clients[1].Finish(errors.New("destination unreachable")))
+	assert.ErrorContains(t, <-errs, `worker "worker-1" failed: destination unreachable`)
+	<-c.Done()
+
+	_, err := clients[2].Join(context.Background())
+	assert.ErrorContains(t, err, "destination unreachable")
+}
+
+func TestCoordinatorAbort(t *testing.T) {
+	c, clients := serve(t, 2, Assignment{})
+
+	// A worker waiting to start is let go too.
+	errs := make(chan error)
+	go func() {
+		_, err := clients[0].Join(context.Background())
+		errs <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	c.Abort(errors.New("coordinator stopped"))
+	assert.ErrorContains(t, <-errs, "coordinator stopped")
+	<-c.Done()
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+)
+
+// The service is small enough to describe by hand, with JSON messages,
+// rather than generate from a .proto file.
+const (
+	serviceName = "flutter.cluster.v1.Coordinator"
+	codecName   = "json"
+)
+
+// finishTimeout bounds reporting to the coordinator once a run ends,
+// which may be because it was cancelled.
+const finishTimeout = 5 * time.Second
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+type coordinatorServer interface {
+	Join(context.Context, *JoinRequest) (*Assignment, error)
+	Tick(context.Context, *TickRequest) (*TickResponse, error)
+	Finish(context.Context, *FinishRequest) (*FinishResponse, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*coordinatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("Join", coordinatorServer.Join),
+		unary("Tick", coordinatorServer.Tick),
+		unary("Finish", coordinatorServer.Finish),
+	},
+}
+
+func unary[Req, Resp any](name string, call func(coordinatorServer, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := new(Req)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return call(srv.(coordinatorServer), ctx, req.(*Req))
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + name}
+			return interceptor(ctx, in, info, handler)
+		},
+	}
+}
+
+// Register serves the coordinator on s.
+func (c *Coordinator) Register(s grpc.ServiceRegistrar) {
+	s.RegisterService(&serviceDesc, c)
+}
+
+// Client is a worker's connection to the coordinator.
+type Client struct {
+	conn   *grpc.ClientConn
+	worker string
+}
+
+// Dial connects to the coordinator at addr as the named worker.
+func Dial(addr, worker string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)),
+	}, opts...)
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, worker: worker}, nil
+}
+
+// Join waits for the coordinator, and then for every other worker, and
+// returns this worker's assignment.
+func (c *Client) Join(ctx context.Context) (*Assignment, error) {
+	a := &Assignment{}
+	if err := c.invoke(ctx, "Join", &JoinRequest{Worker: c.worker}, a, grpc.WaitForReady(true)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Tick waits for every other worker to reach the tick.
+func (c *Client) Tick(ctx context.Context, tick time.Duration) error {
+	return c.invoke(ctx, "Tick", &TickRequest{Worker: c.worker, Tick: tick}, &TickResponse{})
+}
+
+// Finish reports the run's outcome, runErr, to the coordinator.
+func (c *Client) Finish(runErr error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), finishTimeout)
+	defer cancel()
+	req := &FinishRequest{Worker: c.worker}
+	if runErr != nil {
+		req.Error = runErr.Error()
+	}
+	return c.invoke(ctx, "Finish", req, &FinishResponse{})
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// invoke calls the method, returning the coordinator's error message
+// without the gRPC status wrapping.
+func (c *Client) invoke(ctx context.Context, method string, in, out any, opts ...grpc.CallOption) error {
+	if err := c.conn.Invoke(ctx, "/"+serviceName+"/"+method, in, out, opts...); err != nil {
+		if s, ok := status.FromError(err); ok {
+			return errors.New(s.Message())
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compare semantically diffs two runs captured with the
+// simulate command's --json output.
+package compare
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/compression"
+	"github.com/cardinalhq/flutter/pkg/emitter"
+)
+
+// Run is the telemetry of one captured run, indexed so that ordering
+// within and across payloads does not matter.
+type Run struct {
+	// Series maps a series key to its values by timestamp.
+	Series map[string]map[time.Time]float64
+	// Spans counts spans by a signature of everything except their
+	// IDs, which are not reproducible between runs.
+	Spans map[string]int
+}
+
+type jsonLine struct {
+	MetricsProtobuf string `json:"metricsProtobuf"`
+	TracesProtobuf  string `json:"tracesProtobuf"`
+}
+
+// Load reads the JSON lines written by the --json flag.  Progress
+// output that ends in a carriage return, which the ticker writes when
+// not in dry-run mode, is ignored, as are blank lines.
+func Load(r io.Reader) (*Run, error) {
+	run := &Run{
+		Series: map[string]map[time.Time]float64{},
+		Spans:  map[string]int{},
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 256*1024*1024)
+	scanner.Split(scanNewlines)
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		line := scanner.Bytes()
+		// Allow CRLF line endings without mistaking them for progress output.
+		if bytes.HasSuffix(line, []byte("}\r")) {
+			line = line[:len(line)-1]
+		}
+		if i := bytes.LastIndexByte(line, '\r'); i >= 0 {
+			line = line[i+1:]
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var jl jsonLine
+		if err := json.Unmarshal(line, &jl); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineno, err)
+		}
+		if jl.MetricsProtobuf != "" {
+			b, err := decodePayload(jl.MetricsProtobuf)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineno, err)
+			}
+			md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(b)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: failed to unmarshal metrics: %w", lineno, err)
+			}
+			run.addMetrics(md)
+		}
+		if jl.TracesProtobuf != "" {
+			b, err := decodePayload(jl.TracesProtobuf)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineno, err)
+			}
+			td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(b)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: failed to unmarshal traces: %w", lineno, err)
+			}
+			run.addTraces(td)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// scanNewlines splits on newlines only.  bufio.ScanLines would also
+// drop a trailing carriage return, hiding a final progress line.
+func scanNewlines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func decodePayload(s string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64: %w", err)
+	}
+	b, err = compression.GUnzipBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to gunzip: %w", err)
+	}
+	return b, nil
+}
+
+func (r *Run) addMetrics(md pmetric.Metrics) {
+	for _, row := range emitter.FlattenMetrics(md) {
+		key := row.Type + " " + row.Name + attrKey(row.ResourceAttributes, row.Attributes)
+		points, ok := r.Series[key]
+		if !ok {
+			points = map[time.Time]float64{}
+			r.Series[key] = points
+		}
+		points[row.Timestamp] = row.Value
+	}
+}
+
+func (r *Run) addTraces(td ptrace.Traces) {
+	rows := emitter.FlattenTraces(td)
+	names := make(map[string]string, len(rows))
+	for _, row := range rows {
+		names[row.SpanID] = row.Name
+	}
+	for _, row := range rows {
+		sig := fmt.Sprintf("%s/%s parent=%q kind=%s status=%s start=%s duration=%s%s",
+			row.ServiceName, row.Name, names[row.ParentSpanID], row.Kind, row.StatusCode,
+			row.Timestamp.Format(time.RFC3339Nano), row.Duration, attrKey(row.ResourceAttributes, row.Attributes))
+		r.Spans[sig]++
+	}
+}
+
+// attrKey renders resource and datapoint attributes, each sorted by
+// key, as {resource|datapoint}.
+func attrKey(resource, attrs map[string]string) string {
+	render := func(m map[string]string) string {
+		parts := make([]string, 0, len(m))
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			parts = append(parts, fmt.Sprintf("%s=%q", k, m[k]))
+		}
+		return strings.Join(parts, ",")
+	}
+	return "{" + render(resource) + "|" + render(attrs) + "}"
+}
+
+// Diff lists the differences between a and b.  Values are equal when
+// they differ by no more than tolerance.  At most maxDiffs detail lines
+// are reported per signal, with a count of the rest; zero means no limit.
+func Diff(a, b *Run, tolerance float64, maxDiffs int) []string {
+	var out []string
+	out = append(out, limit(diffSeries(a, b, tolerance), maxDiffs)...)
+	out = append(out, limit(diffSpans(a, b), maxDiffs)...)
+	return out
+}
+
+func limit(lines []string, maxDiffs int) []string {
+	if maxDiffs <= 0 || len(lines) <= maxDiffs {
+		return lines
+	}
+	return append(lines[:maxDiffs:maxDiffs], fmt.Sprintf("... and %d more", len(lines)-maxDiffs))
+}
+
+func diffSeries(a, b *Run, tolerance float64) []string {
+	var out []string
+	keys := map[string]bool{}
+	for k := range a.Series {
+		keys[k] = true
+	}
+	for k := range b.Series {
+		keys[k] = true
+	}
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		pa, inA := a.Series[key]
+		pb, inB := b.Series[key]
+		switch {
+		case !inA:
+			out = append(out, "series only in B: "+key)
+			continue
+		case !inB:
+			out = append(out, "series only in A: "+key)
+			continue
+		}
+		times := map[time.Time]bool{}
+		for t := range pa {
+			times[t] = true
+		}
+		for t := range pb {
+			times[t] = true
+		}
+		for _, t := range slices.SortedFunc(maps.Keys(times), time.Time.Compare) {
+			va, okA := pa[t]
+			vb, okB := pb[t]
+			ts := t.Format(time.RFC3339Nano)
+			switch {
+			case !okA:
+				out = append(out, fmt.Sprintf("datapoint only in B: %s at %s = %g", key, ts, vb))
+			case !okB:
+				out = append(out, fmt.Sprintf("datapoint only in A: %s at %s = %g", key, ts, va))
+			case math.Abs(va-vb) > tolerance:
+				out = append(out, fmt.Sprintf("value differs: %s at %s: %g != %g", key, ts, va, vb))
+			}
+		}
+	}
+	return out
+}
+
+func diffSpans(a, b *Run) []string {
+	var out []string
+	sigs := map[string]bool{}
+	for s := range a.Spans {
+		sigs[s] = true
+	}
+	for s := range b.Spans {
+		sigs[s] = true
+	}
+	for _, sig := range slices.Sorted(maps.Keys(sigs)) {
+		if ca, cb := a.Spans[sig], b.Spans[sig]; ca != cb {
+			out = append(out, fmt.Sprintf("span count differs: %s: %d != %d", sig, ca, cb))
+		}
+	}
+	return out
+}
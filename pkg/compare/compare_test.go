@@ -0,0 +1,114 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/emitter"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func gauge(name string, ts int64, value float64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "test")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(ts, 0)))
+	dp.SetDoubleValue(value)
+	return md
+}
+
+func spans(traceID byte) ptrace.Traces {
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	root := ss.Spans().AppendEmpty()
+	root.SetName("root")
+	root.SetTraceID(pcommon.TraceID{traceID})
+	root.SetSpanID(pcommon.SpanID{traceID, 1})
+	child := ss.Spans().AppendEmpty()
+	child.SetName("child")
+	child.SetTraceID(pcommon.TraceID{traceID})
+	child.SetSpanID(pcommon.SpanID{traceID, 2})
+	child.SetParentSpanID(root.SpanID())
+	return td
+}
+
+func capture(t *testing.T, metrics []pmetric.Metrics, traces []ptrace.Traces) *Run {
+	var buf bytes.Buffer
+	buf.WriteString("Tick 0 0.00% 2025-01-01 00:00:00\r")
+	e := emitter.NewJSONEmitter(&buf)
+	rs := &state.RunState{}
+	for _, md := range metrics {
+		require.NoError(t, e.EmitMetrics(context.Background(), rs, md))
+	}
+	for _, td := range traces {
+		require.NoError(t, e.EmitTraces(context.Background(), rs, td))
+	}
+	run, err := Load(&buf)
+	require.NoError(t, err)
+	return run
+}
+
+func TestDiff(t *testing.T) {
+	a := capture(t,
+		[]pmetric.Metrics{gauge("cpu", 10, 1), gauge("cpu", 20, 2), gauge("mem", 10, 5)},
+		[]ptrace.Traces{spans(1)})
+
+	t.Run("ordering and span IDs are ignored", func(t *testing.T) {
+		b := capture(t,
+			[]pmetric.Metrics{gauge("mem", 10, 5), gauge("cpu", 20, 2), gauge("cpu", 10, 1)},
+			[]ptrace.Traces{spans(9)})
+		assert.Empty(t, Diff(a, b, 0, 0))
+	})
+
+	t.Run("differences are reported", func(t *testing.T) {
+		b := capture(t,
+			[]pmetric.Metrics{gauge("cpu", 10, 1.5), gauge("disk", 10, 1)},
+			[]ptrace.Traces{spans(1), spans(2)})
+		diffs := strings.Join(Diff(a, b, 0, 0), "\n")
+		assert.Contains(t, diffs, `value differs: Gauge cpu{service.name="test"|} at 1970-01-01T00:00:10Z: 1 != 1.5`)
+		assert.Contains(t, diffs, `datapoint only in A: Gauge cpu{service.name="test"|} at 1970-01-01T00:00:20Z = 2`)
+		assert.Contains(t, diffs, `series only in A: Gauge mem`)
+		assert.Contains(t, diffs, `series only in B: Gauge disk`)
+		assert.Contains(t, diffs, `span count differs: /child parent="root"`)
+	})
+
+	t.Run("tolerance and limit", func(t *testing.T) {
+		b := capture(t,
+			[]pmetric.Metrics{gauge("cpu", 10, 1.05), gauge("cpu", 20, 2), gauge("mem", 10, 5)},
+			[]ptrace.Traces{spans(1)})
+		assert.Empty(t, Diff(a, b, 0.1, 0))
+		assert.Equal(t, []string{
+			`value differs: Gauge cpu{service.name="test"|} at 1970-01-01T00:00:10Z: 1 != 1.05`,
+		}, Diff(a, b, 0, 1))
+
+		c := capture(t, nil, nil)
+		diffs := Diff(a, c, 0, 1)
+		assert.Equal(t, "... and 1 more", diffs[1])
+	})
+}
@@ -17,6 +17,7 @@ package compression
 import (
 	"bytes"
 	"compress/gzip"
+	"io"
 )
 
 func GZipBytes(data []byte) ([]byte, error) {
@@ -30,3 +31,12 @@ func GZipBytes(data []byte) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+func GUnzipBytes(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -24,11 +24,78 @@ import (
 )
 
 type Config struct {
-	Seed            uint64          `mapstructure:"seed" yaml:"seed" json:"seed"`
-	WallclockStart  time.Time       `mapstructure:"wallclockStart" yaml:"wallclockStart" json:"wallclockStart"`
-	Duration        time.Duration   `mapstructure:"duration" yaml:"duration" json:"duration"`
-	Dryrun          bool            `mapstructure:"dryrun" yaml:"dryrun" json:"dryrun"`
-	OTLPDestination OTLPDestination `mapstructure:"otlpDestination" yaml:"otlpDestination" json:"otlpDestination"`
+	Seed           uint64        `mapstructure:"seed" yaml:"seed" json:"seed"`
+	WallclockStart time.Time     `mapstructure:"wallclockStart" yaml:"wallclockStart" json:"wallclockStart"`
+	Duration       time.Duration `mapstructure:"duration" yaml:"duration" json:"duration"`
+	Dryrun         bool          `mapstructure:"dryrun" yaml:"dryrun" json:"dryrun"`
+	// Backfill runs as fast as it can, like Dryrun, but still sends to
+	// every destination, so days of history can be loaded at once.
+	Backfill bool `mapstructure:"backfill" yaml:"backfill" json:"backfill"`
+	// MaxExportRate caps the datapoints and spans sent per second of
+	// real time in backfill and dry-run mode.  Zero is unlimited.
+	MaxExportRate float64 `mapstructure:"maxExportRate" yaml:"maxExportRate" json:"maxExportRate"`
+	// Warmup is how long, from the start of the script, generators run
+	// without emitting, so random walks and ramps reach a steady state
+	// first.  Warm-up ticks are not slept through, and the first tick
+	// after warm-up is stamped WallclockStart.
+	Warmup time.Duration `mapstructure:"warmup" yaml:"warmup" json:"warmup"`
+	// ResolutionTiers coarsen metric frequencies for data far from the
+	// end of the run, so a multi-week backfill keeps only the recent
+	// window at full resolution.
+	ResolutionTiers []ResolutionTier `mapstructure:"resolutionTiers" yaml:"resolutionTiers" json:"resolutionTiers"`
+	// TimestampAlignment is "tick" (the default) to stamp datapoints
+	// with the tick's wallclock time, or "scrape" to align them to
+	// the producer's frequency boundaries.
+	TimestampAlignment string          `mapstructure:"timestampAlignment" yaml:"timestampAlignment" json:"timestampAlignment"`
+	OTLPDestination    OTLPDestination `mapstructure:"otlpDestination" yaml:"otlpDestination" json:"otlpDestination"`
+	ObjectStorage      ObjectStorage   `mapstructure:"objectStorage" yaml:"objectStorage" json:"objectStorage"`
+	ClickHouse         ClickHouse      `mapstructure:"clickhouse" yaml:"clickhouse" json:"clickhouse"`
+	SplunkHEC          SplunkHEC       `mapstructure:"splunkHEC" yaml:"splunkHEC" json:"splunkHEC"`
+	Elasticsearch      Elasticsearch   `mapstructure:"elasticsearch" yaml:"elasticsearch" json:"elasticsearch"`
+	Influx             Influx          `mapstructure:"influx" yaml:"influx" json:"influx"`
+	Carbon             Carbon          `mapstructure:"carbon" yaml:"carbon" json:"carbon"`
+	Syslog             Syslog          `mapstructure:"syslog" yaml:"syslog" json:"syslog"`
+	FaultInjection     FaultInjection  `mapstructure:"faultInjection" yaml:"faultInjection" json:"faultInjection"`
+	SchemaConflicts    SchemaConflicts `mapstructure:"schemaConflicts" yaml:"schemaConflicts" json:"schemaConflicts"`
+	Budget             Budget          `mapstructure:"budget" yaml:"budget" json:"budget"`
+	EntityEvents       EntityEvents    `mapstructure:"entityEvents" yaml:"entityEvents" json:"entityEvents"`
+	// FailureDomains place resources in zones and regions, so an
+	// outage action can take down everything in one at once.
+	FailureDomains []FailureDomain `mapstructure:"failureDomains" yaml:"failureDomains" json:"failureDomains"`
+	// Emitters enables built-in local emitters by name: "null"
+	// discards everything and "counting" prints per-signal volume
+	// when the run ends.  Both also work in dry-run mode.
+	Emitters []string `mapstructure:"emitters" yaml:"emitters" json:"emitters"`
+	// ErrorPolicies sets how failures of each destination are handled,
+	// keyed by destination name such as "otlp" or "clickhouse".
+	ErrorPolicies map[string]ErrorPolicy `mapstructure:"errorPolicies" yaml:"errorPolicies" json:"errorPolicies"`
+	// SendQueues moves sends to a destination off the tick, keyed
+	// like ErrorPolicies.
+	SendQueues map[string]SendQueue `mapstructure:"sendQueues" yaml:"sendQueues" json:"sendQueues"`
+	// Transforms reshapes what each destination receives, keyed like
+	// ErrorPolicies, so one run can feed backends that disagree.
+	Transforms map[string]Transform `mapstructure:"transforms" yaml:"transforms" json:"transforms"`
+	Capture    Capture              `mapstructure:"capture" yaml:"capture" json:"capture"`
+	// HTTPTransport tunes the connections of every HTTP destination.
+	HTTPTransport HTTPTransport `mapstructure:"httpTransport" yaml:"httpTransport" json:"httpTransport"`
+	// RunID, when set, is added to every emitted resource as the
+	// flutter.run_id attribute, so overlapping runs into one backend
+	// can be told apart.  "auto" generates a UUID for each run.
+	RunID string `mapstructure:"runID" yaml:"runID" json:"runID"`
+	// Script holds actions written directly in the config file, the
+	// format that predates timelines.  Entries from every config file
+	// are run, in the order loaded.
+	Script []ScriptEntry `mapstructure:"script" yaml:"script" json:"script"`
+}
+
+// ScriptEntry is one action in a config file's script.  Name is the
+// action's ID.
+type ScriptEntry struct {
+	At   time.Duration  `mapstructure:"at" yaml:"at" json:"at"`
+	To   time.Duration  `mapstructure:"to" yaml:"to" json:"to"`
+	Type string         `mapstructure:"type" yaml:"type" json:"type"`
+	Name string         `mapstructure:"name" yaml:"name" json:"name"`
+	Spec map[string]any `mapstructure:"spec" yaml:"spec" json:"spec"`
 }
 
 type OTLPDestination struct {
@@ -37,6 +104,230 @@ type OTLPDestination struct {
 	Timeout  time.Duration     `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
 }
 
+// ObjectStorage defines an S3 or GCS bucket that batches of OTLP
+// protobuf payloads are uploaded to.  Credentials are read from the
+// environment.
+type ObjectStorage struct {
+	// Provider is "s3" or "gcs".
+	Provider string `mapstructure:"provider" yaml:"provider" json:"provider"`
+	Bucket   string `mapstructure:"bucket" yaml:"bucket" json:"bucket"`
+	Prefix   string `mapstructure:"prefix" yaml:"prefix" json:"prefix"`
+	Region   string `mapstructure:"region" yaml:"region" json:"region"`
+	// Endpoint overrides the provider's default endpoint, such as
+	// for MinIO or another S3-compatible store.
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+	// MaxBytes is the uncompressed size that triggers an upload.
+	MaxBytes int `mapstructure:"maxBytes" yaml:"maxBytes" json:"maxBytes"`
+	// Interval is the simulated time after which a batch is uploaded.
+	Interval time.Duration `mapstructure:"interval" yaml:"interval" json:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// ClickHouse defines a ClickHouse HTTP endpoint that flattened
+// datapoints and spans are inserted into.
+type ClickHouse struct {
+	Endpoint     string        `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+	Username     string        `mapstructure:"username" yaml:"username" json:"username"`
+	Password     string        `mapstructure:"password" yaml:"password" json:"password"`
+	MetricsTable string        `mapstructure:"metricsTable" yaml:"metricsTable" json:"metricsTable"`
+	TracesTable  string        `mapstructure:"tracesTable" yaml:"tracesTable" json:"tracesTable"`
+	Timeout      time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// SplunkHEC defines a Splunk HTTP Event Collector that datapoints are
+// sent to as metric events and spans as JSON events.
+type SplunkHEC struct {
+	Endpoint   string        `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+	Token      string        `mapstructure:"token" yaml:"token" json:"token"`
+	Index      string        `mapstructure:"index" yaml:"index" json:"index"`
+	Source     string        `mapstructure:"source" yaml:"source" json:"source"`
+	SourceType string        `mapstructure:"sourcetype" yaml:"sourcetype" json:"sourcetype"`
+	Timeout    time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// Elasticsearch defines a cluster that flattened datapoints and spans
+// are indexed into with the bulk API.
+type Elasticsearch struct {
+	Endpoint     string        `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+	APIKey       string        `mapstructure:"apiKey" yaml:"apiKey" json:"apiKey"`
+	Username     string        `mapstructure:"username" yaml:"username" json:"username"`
+	Password     string        `mapstructure:"password" yaml:"password" json:"password"`
+	MetricsIndex string        `mapstructure:"metricsIndex" yaml:"metricsIndex" json:"metricsIndex"`
+	TracesIndex  string        `mapstructure:"tracesIndex" yaml:"tracesIndex" json:"tracesIndex"`
+	Timeout      time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// Influx defines an InfluxDB or Telegraf listener that datapoints are
+// written to in line protocol.
+type Influx struct {
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+	// Version is 1 (the default) or 2, selecting the write API.
+	Version  int    `mapstructure:"version" yaml:"version" json:"version"`
+	Database string `mapstructure:"database" yaml:"database" json:"database"`
+	Username string `mapstructure:"username" yaml:"username" json:"username"`
+	Password string `mapstructure:"password" yaml:"password" json:"password"`
+	Org      string `mapstructure:"org" yaml:"org" json:"org"`
+	Bucket   string `mapstructure:"bucket" yaml:"bucket" json:"bucket"`
+	Token    string `mapstructure:"token" yaml:"token" json:"token"`
+	// TagStrategy is "all" (the default), "datapoint", or "resource",
+	// selecting which attributes become tags.
+	TagStrategy string        `mapstructure:"tagStrategy" yaml:"tagStrategy" json:"tagStrategy"`
+	Timeout     time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// Carbon defines a Graphite Carbon plaintext listener that datapoints
+// are written to over TCP.
+type Carbon struct {
+	// Address is the host:port of the listener, usually port 2003.
+	Address string `mapstructure:"address" yaml:"address" json:"address"`
+	// Template builds each metric path, with {name} for the metric
+	// name and {key} for an attribute value.  Defaults to "{name}".
+	Template string        `mapstructure:"template" yaml:"template" json:"template"`
+	Timeout  time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// Syslog defines an RFC 5424 syslog receiver that log records are
+// sent to.
+type Syslog struct {
+	// Address is the host:port of the receiver.
+	Address string `mapstructure:"address" yaml:"address" json:"address"`
+	// Network is "udp" (the default), "tcp", or "tls".
+	Network string `mapstructure:"network" yaml:"network" json:"network"`
+	// InsecureSkipVerify accepts any certificate from a tls receiver.
+	InsecureSkipVerify bool          `mapstructure:"insecureSkipVerify" yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
+	Timeout            time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// SendQueue makes a destination send from its own goroutine, through a
+// bounded queue, so a slow destination does not stall generation.
+type SendQueue struct {
+	// Size is how many payloads may wait to be sent.  Defaults to 64.
+	Size int `mapstructure:"size" yaml:"size" json:"size"`
+	// OnFull is "block" (the default) to wait for room in the queue,
+	// or "drop" to discard the payload.
+	OnFull string `mapstructure:"onFull" yaml:"onFull" json:"onFull"`
+}
+
+// Transform changes a copy of each payload before one destination
+// sends it.
+type Transform struct {
+	// Temporality is "delta" or "cumulative" to convert sums to it.
+	// Empty sends sums as generated.
+	Temporality string `mapstructure:"temporality" yaml:"temporality" json:"temporality"`
+	// FlattenAttributes replaces map-valued attributes with one
+	// attribute per leaf, named with dotted keys, and slices with their
+	// JSON encoding.
+	FlattenAttributes bool `mapstructure:"flattenAttributes" yaml:"flattenAttributes" json:"flattenAttributes"`
+}
+
+// ErrorPolicy controls how a destination's failures affect the run.
+type ErrorPolicy struct {
+	// OnError is "fail" (the default) to end the run, or "continue"
+	// to log the failure and keep sending to other destinations.
+	OnError string `mapstructure:"onError" yaml:"onError" json:"onError"`
+	// Retries is how many more times a failed send is attempted.
+	Retries    int           `mapstructure:"retries" yaml:"retries" json:"retries"`
+	RetryDelay time.Duration `mapstructure:"retryDelay" yaml:"retryDelay" json:"retryDelay"`
+	// MaxFailures disables a "continue" destination after this many
+	// consecutive failures.  Zero never disables it.
+	MaxFailures int `mapstructure:"maxFailures" yaml:"maxFailures" json:"maxFailures"`
+}
+
+// Capture records a sampled fraction of the HTTP requests and
+// responses exchanged with destinations to a JSON lines file.
+type Capture struct {
+	File string `mapstructure:"file" yaml:"file" json:"file"`
+	// SampleRate is the fraction (0-1) of requests recorded.
+	SampleRate float64 `mapstructure:"sampleRate" yaml:"sampleRate" json:"sampleRate"`
+	// MaxBodyBytes truncates recorded bodies.  Defaults to 4096.
+	MaxBodyBytes int `mapstructure:"maxBodyBytes" yaml:"maxBodyBytes" json:"maxBodyBytes"`
+}
+
+// HTTPTransport tunes how HTTP destinations hold connections, for
+// high-rate runs behind load balancers that close idle or busy ones.
+// Zero values keep Go's defaults.
+type HTTPTransport struct {
+	// ForceHTTP2 uses only HTTP/2: negotiated over TLS for https
+	// endpoints, and with prior knowledge (h2c) for http ones.
+	ForceHTTP2          bool `mapstructure:"forceHTTP2" yaml:"forceHTTP2" json:"forceHTTP2"`
+	MaxIdleConnsPerHost int  `mapstructure:"maxIdleConnsPerHost" yaml:"maxIdleConnsPerHost" json:"maxIdleConnsPerHost"`
+	// IdleConnTimeout closes connections idle for this long.
+	IdleConnTimeout time.Duration `mapstructure:"idleConnTimeout" yaml:"idleConnTimeout" json:"idleConnTimeout"`
+	// KeepAlive is the TCP keep-alive period; negative disables it.
+	KeepAlive time.Duration `mapstructure:"keepAlive" yaml:"keepAlive" json:"keepAlive"`
+	// PingInterval sends an HTTP/2 ping on a connection that has been
+	// quiet this long, and PingTimeout closes it if no reply comes.
+	PingInterval time.Duration `mapstructure:"pingInterval" yaml:"pingInterval" json:"pingInterval"`
+	PingTimeout  time.Duration `mapstructure:"pingTimeout" yaml:"pingTimeout" json:"pingTimeout"`
+}
+
+// FaultInjection configures the deliberate corruption of a fraction of
+// the payloads sent to the OTLP destination.  It is disabled unless
+// Probability is greater than zero.
+type FaultInjection struct {
+	// Probability is the chance (0-1) that any one payload is corrupted.
+	Probability float64 `mapstructure:"probability" yaml:"probability" json:"probability"`
+	// Modes limits which corruptions are applied.  Valid values are
+	// "badUTF8", "zeroTimestamp", and "oversized".  Empty means all.
+	Modes []string `mapstructure:"modes" yaml:"modes" json:"modes"`
+	// OversizeFactor is how many times the payload is repeated
+	// for the "oversized" mode.
+	OversizeFactor int `mapstructure:"oversizeFactor" yaml:"oversizeFactor" json:"oversizeFactor"`
+}
+
+// SchemaConflicts makes every metric sent to the OTLP destination
+// also appear, from a different resource, with a conflicting type
+// and unit.  It must be explicitly enabled.
+type SchemaConflicts struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	// Unit is set on the conflicting copies.  Defaults to "flutter.conflict".
+	Unit string `mapstructure:"unit" yaml:"unit" json:"unit"`
+}
+
+// ResolutionTier applies to ticks at least OlderThan before the end of
+// the run.  Metrics in it emit at most once every Frequency.  Where
+// tiers overlap, the oldest applies.
+type ResolutionTier struct {
+	OlderThan time.Duration `mapstructure:"olderThan" yaml:"olderThan" json:"olderThan"`
+	Frequency time.Duration `mapstructure:"frequency" yaml:"frequency" json:"frequency"`
+}
+
+// Budget caps flutter's own footprint.  When a limit is exceeded,
+// flutter emits less, with a warning, instead of running out of memory
+// or starving the machine.
+type Budget struct {
+	// MaxMemoryMB is the memory the Go runtime may hold, in MiB.
+	MaxMemoryMB int `mapstructure:"maxMemoryMB" yaml:"maxMemoryMB" json:"maxMemoryMB"`
+	// MaxCPU is the CPU flutter may use, in cores.  It is not checked
+	// in dry-run or backfill mode, which run as fast as they can.
+	MaxCPU float64 `mapstructure:"maxCPU" yaml:"maxCPU" json:"maxCPU"`
+}
+
+// EntityEvents emits a log event when the series of a host, node, or
+// pod first appear and when they stop, for backends that build entity
+// inventories.
+type EntityEvents struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	// DeleteAfter is how long an entity goes unseen before it is
+	// reported deleted.  It defaults to DefaultEntityDeleteAfter.
+	DeleteAfter time.Duration `mapstructure:"deleteAfter" yaml:"deleteAfter" json:"deleteAfter"`
+}
+
+// FailureDomain is a zone, a region, or a zone within a region.
+// Resources matching it are given its cloud.availability_zone and
+// cloud.region unless they set their own.
+type FailureDomain struct {
+	Zone   string `mapstructure:"zone" yaml:"zone" json:"zone"`
+	Region string `mapstructure:"region" yaml:"region" json:"region"`
+	// Resources lists, for each resource attribute, the values that
+	// place a resource in this domain.  A resource matching any of
+	// them is in it.
+	Resources map[string][]string `mapstructure:"resources" yaml:"resources" json:"resources"`
+}
+
+// DefaultEntityDeleteAfter matches the staleness window of Prometheus.
+const DefaultEntityDeleteAfter = 5 * time.Minute
+
 func DefaultConfig() *Config {
 	return &Config{
 		OTLPDestination: OTLPDestination{
@@ -46,10 +337,24 @@ func DefaultConfig() *Config {
 }
 
 func LoadConfigs(fnames []string) (*Config, error) {
-	merged := DefaultConfig()
+	data := make([][]byte, 0, len(fnames))
 	for _, fname := range fnames {
 		slog.Info("Loading config", "file", fname)
-		config, err := loadConfig(fname)
+		b, err := os.ReadFile(fname)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+	}
+	return ParseConfigs(data)
+}
+
+// ParseConfigs merges YAML config documents in order, as LoadConfigs
+// merges files.
+func ParseConfigs(data [][]byte) (*Config, error) {
+	merged := DefaultConfig()
+	for _, b := range data {
+		config, err := parseConfig(b)
 		if err != nil {
 			return nil, err
 		}
@@ -59,12 +364,63 @@ func LoadConfigs(fnames []string) (*Config, error) {
 		if config.Dryrun {
 			merged.Dryrun = true
 		}
+		if config.Backfill {
+			merged.Backfill = true
+		}
+		if config.MaxExportRate != 0 {
+			merged.MaxExportRate = config.MaxExportRate
+		}
+		if config.TimestampAlignment != "" {
+			merged.TimestampAlignment = config.TimestampAlignment
+		}
+		if config.ErrorPolicies != nil {
+			if merged.ErrorPolicies == nil {
+				merged.ErrorPolicies = make(map[string]ErrorPolicy)
+			}
+			maps.Copy(merged.ErrorPolicies, config.ErrorPolicies)
+		}
+		if config.SendQueues != nil {
+			if merged.SendQueues == nil {
+				merged.SendQueues = make(map[string]SendQueue)
+			}
+			maps.Copy(merged.SendQueues, config.SendQueues)
+		}
+		if config.Transforms != nil {
+			if merged.Transforms == nil {
+				merged.Transforms = make(map[string]Transform)
+			}
+			maps.Copy(merged.Transforms, config.Transforms)
+		}
+		if config.HTTPTransport != (HTTPTransport{}) {
+			merged.HTTPTransport = config.HTTPTransport
+		}
+		if config.Capture.File != "" {
+			merged.Capture = config.Capture
+		}
+		if config.RunID != "" {
+			merged.RunID = config.RunID
+		}
+		if len(config.Emitters) > 0 {
+			merged.Emitters = config.Emitters
+		}
 		if config.Seed != 0 {
 			merged.Seed = config.Seed
 		}
 		if config.Duration != 0 {
 			merged.Duration = config.Duration
 		}
+		if len(config.FailureDomains) > 0 {
+			merged.FailureDomains = config.FailureDomains
+		}
+		if len(config.ResolutionTiers) > 0 {
+			merged.ResolutionTiers = config.ResolutionTiers
+		}
+		if config.EntityEvents.Enabled {
+			merged.EntityEvents = config.EntityEvents
+		}
+		if config.Warmup != 0 {
+			merged.Warmup = config.Warmup
+		}
 		if config.OTLPDestination.Timeout != 0 {
 			merged.OTLPDestination.Timeout = config.OTLPDestination.Timeout
 		}
@@ -77,13 +433,56 @@ func LoadConfigs(fnames []string) (*Config, error) {
 			}
 			maps.Copy(merged.OTLPDestination.Headers, config.OTLPDestination.Headers)
 		}
+		if config.ObjectStorage.Bucket != "" {
+			merged.ObjectStorage = config.ObjectStorage
+		}
+		if config.ClickHouse.Endpoint != "" {
+			merged.ClickHouse = config.ClickHouse
+		}
+		if config.SplunkHEC.Endpoint != "" {
+			merged.SplunkHEC = config.SplunkHEC
+		}
+		if config.Elasticsearch.Endpoint != "" {
+			merged.Elasticsearch = config.Elasticsearch
+		}
+		if config.Influx.Endpoint != "" {
+			merged.Influx = config.Influx
+		}
+		if config.Carbon.Address != "" {
+			merged.Carbon = config.Carbon
+		}
+		if config.Syslog.Address != "" {
+			merged.Syslog = config.Syslog
+		}
+		if config.FaultInjection.Probability != 0 {
+			merged.FaultInjection.Probability = config.FaultInjection.Probability
+		}
+		if len(config.FaultInjection.Modes) > 0 {
+			merged.FaultInjection.Modes = config.FaultInjection.Modes
+		}
+		if config.FaultInjection.OversizeFactor != 0 {
+			merged.FaultInjection.OversizeFactor = config.FaultInjection.OversizeFactor
+		}
+		if config.SchemaConflicts.Enabled {
+			merged.SchemaConflicts.Enabled = true
+		}
+		if config.SchemaConflicts.Unit != "" {
+			merged.SchemaConflicts.Unit = config.SchemaConflicts.Unit
+		}
+		if config.Budget.MaxMemoryMB != 0 {
+			merged.Budget.MaxMemoryMB = config.Budget.MaxMemoryMB
+		}
+		if config.Budget.MaxCPU != 0 {
+			merged.Budget.MaxCPU = config.Budget.MaxCPU
+		}
+		merged.Script = append(merged.Script, config.Script...)
 	}
 	return merged, nil
 }
 
-func loadConfig(fname string) (*Config, error) {
+func parseConfig(b []byte) (*Config, error) {
 	var config Config
-	if err := LoadYAML(fname, &config); err != nil {
+	if err := yaml.Unmarshal(b, &config); err != nil {
 		return nil, err
 	}
 	if config.OTLPDestination.Timeout == 0 {
@@ -14,13 +14,42 @@
 
 package config
 
-import "github.com/mitchellh/mapstructure"
+import (
+	"reflect"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
 
 func NewMapstructureDecoder(target any) (*mapstructure.Decoder, error) {
 	decoderConfig := &mapstructure.DecoderConfig{
 		Result:      target,
 		ErrorUnused: true,
-		DecodeHook:  mapstructure.ComposeDecodeHookFunc(mapstructure.StringToTimeDurationHookFunc()),
+		DecodeHook:  mapstructure.ComposeDecodeHookFunc(mapstructure.StringToTimeDurationHookFunc(), durationDecodeHook),
 	}
 	return mapstructure.NewDecoder(decoderConfig)
 }
+
+// durationDecodeHook decodes strings such as "10s", or a number of
+// nanoseconds, into a Duration.
+func durationDecodeHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeFor[Duration]() {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		return Duration{d}, nil
+	case int:
+		return Duration{time.Duration(v)}, nil
+	case int64:
+		return Duration{time.Duration(v)}, nil
+	case float64:
+		return Duration{time.Duration(v)}, nil
+	default:
+		return data, nil
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+)
+
+// Emitter types an addEmitter action can attach.
+const (
+	AttachOTLP     = "otlp"
+	AttachJSON     = "json"
+	AttachParquet  = "parquet"
+	AttachCounting = "counting"
+)
+
+// DefaultAttachTimeout is the request timeout of an attached OTLP
+// emitter, matching the configured OTLP destination's default.
+const DefaultAttachTimeout = 5 * time.Second
+
+// EmitterSpec is the spec of an addEmitter action.  The error policy
+// and transform fields are those of a destination's errorPolicies and
+// transforms entries.
+type EmitterSpec struct {
+	// Type is "otlp", "json", "parquet" or "counting".
+	Type string `mapstructure:"type"`
+	// Endpoint, Headers and Timeout configure an "otlp" emitter.
+	Endpoint string            `mapstructure:"endpoint"`
+	Headers  map[string]string `mapstructure:"headers"`
+	Timeout  time.Duration     `mapstructure:"timeout"`
+	// Path is the file a "json" emitter writes, or the directory a
+	// "parquet" one writes under.
+	Path string `mapstructure:"path"`
+
+	config.ErrorPolicy `mapstructure:",squash"`
+	config.Transform   `mapstructure:",squash"`
+}
+
+// DecodeEmitterSpec decodes and checks an addEmitter spec, without
+// creating anything.
+func DecodeEmitterSpec(spec map[string]any) (EmitterSpec, error) {
+	var es EmitterSpec
+	decoder, err := config.NewMapstructureDecoder(&es)
+	if err != nil {
+		return es, err
+	}
+	if err := decoder.Decode(spec); err != nil {
+		return es, err
+	}
+	switch es.Type {
+	case AttachOTLP:
+		if es.Endpoint == "" {
+			return es, errors.New("an otlp emitter needs an endpoint")
+		}
+	case AttachJSON, AttachParquet:
+		if es.Path == "" {
+			return es, fmt.Errorf("a %s emitter needs a path", es.Type)
+		}
+	case AttachCounting:
+	default:
+		return es, fmt.Errorf("unknown emitter type %q", es.Type)
+	}
+	if es.Timeout < 0 {
+		return es, errors.New("timeout must not be negative")
+	}
+	if es.ErrorPolicy, err = validateErrorPolicy(es.Type, es.ErrorPolicy); err != nil {
+		return es, err
+	}
+	if err := validateTransform(es.Type, es.Transform); err != nil {
+		return es, err
+	}
+	return es, nil
+}
+
+// Attached is an emitter created while a script runs.  It sits behind
+// a tee of its own, so its error policy and transform apply as they
+// would to a configured destination.
+type Attached struct {
+	*TeeEmitter
+	closer io.Closer
+}
+
+// NewAttached creates the emitter spec describes, as destination name.
+// Close stops it and releases its file, if any, once it has been
+// flushed.
+func NewAttached(name string, spec EmitterSpec, transport config.HTTPTransport) (*Attached, error) {
+	tee, err := NewTeeEmitter(map[string]config.ErrorPolicy{name: spec.ErrorPolicy})
+	if err != nil {
+		return nil, err
+	}
+	if spec.Transform != (config.Transform{}) {
+		if err := tee.SetTransforms(map[string]config.Transform{name: spec.Transform}); err != nil {
+			return nil, err
+		}
+	}
+	a := &Attached{TeeEmitter: tee}
+
+	var e Emitter
+	switch spec.Type {
+	case AttachOTLP:
+		timeout := spec.Timeout
+		if timeout == 0 {
+			timeout = DefaultAttachTimeout
+		}
+		client, endpoint, err := NewHTTPClient(spec.Endpoint, timeout, transport)
+		if err != nil {
+			return nil, fmt.Errorf("error creating OTLP client: %w", err)
+		}
+		if e, err = NewOTLPEmitter(client, endpoint, spec.Headers); err != nil {
+			return nil, fmt.Errorf("error creating OTLP emitter: %w", err)
+		}
+	case AttachJSON:
+		f, err := os.Create(spec.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error creating JSON file: %w", err)
+		}
+		a.closer = f
+		e = NewJSONEmitter(f)
+	case AttachParquet:
+		if e, err = NewParquetEmitter(spec.Path); err != nil {
+			return nil, fmt.Errorf("error creating Parquet emitter: %w", err)
+		}
+	case AttachCounting:
+		e = NewCountingEmitter(os.Stdout)
+	default:
+		return nil, fmt.Errorf("unknown emitter type %q", spec.Type)
+	}
+	tee.Add(name, e)
+	return a, nil
+}
+
+func (a *Attached) Close() error {
+	a.TeeEmitter.Close()
+	if a.closer == nil {
+		return nil
+	}
+	err := a.closer.Close()
+	a.closer = nil
+	return err
+}
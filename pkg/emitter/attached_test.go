@@ -0,0 +1,82 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestDecodeEmitterSpec(t *testing.T) {
+	spec, err := DecodeEmitterSpec(map[string]any{
+		"type":        "otlp",
+		"endpoint":    "http://localhost:4318",
+		"timeout":     "2s",
+		"onError":     "continue",
+		"temporality": "cumulative",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, OnErrorContinue, spec.OnError)
+	assert.Equal(t, TemporalityCumulative, spec.Temporality)
+
+	spec, err = DecodeEmitterSpec(map[string]any{"type": "counting"})
+	require.NoError(t, err)
+	assert.Equal(t, OnErrorFail, spec.OnError)
+
+	for name, tc := range map[string]struct {
+		spec map[string]any
+		want string
+	}{
+		"unknown type":     {map[string]any{"type": "kafka"}, `unknown emitter type "kafka"`},
+		"otlp no endpoint": {map[string]any{"type": "otlp"}, "needs an endpoint"},
+		"json no path":     {map[string]any{"type": "json"}, "needs a path"},
+		"bad policy":       {map[string]any{"type": "counting", "onError": "ignore"}, "unknown onError policy"},
+		"bad temporality":  {map[string]any{"type": "counting", "temporality": "monthly"}, "unknown temporality"},
+		"unknown field":    {map[string]any{"type": "counting", "bogus": 1}, "bogus"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodeEmitterSpec(tc.spec)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func TestNewAttached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.jsonl")
+	spec, err := DecodeEmitterSpec(map[string]any{"type": "json", "path": path})
+	require.NoError(t, err)
+	a, err := NewAttached("archive", spec, config.HTTPTransport{})
+	require.NoError(t, err)
+
+	require.NoError(t, a.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics()))
+	require.NoError(t, a.Flush(context.Background(), &state.RunState{}))
+	require.NoError(t, a.Close())
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), "\n"))
+
+	spec.Path = filepath.Join(t.TempDir(), "missing", "out.jsonl")
+	_, err = NewAttached("archive", spec, config.HTTPTransport{})
+	assert.ErrorContains(t, err, "error creating JSON file")
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultCaptureMaxBodyBytes is how much of each body is recorded.
+const DefaultCaptureMaxBodyBytes = 4096
+
+// Capture records a sampled fraction of HTTP exchanges as JSON lines,
+// for debugging why a backend rejects payloads.  Bodies are truncated
+// to maxBody bytes and recorded as text when they are valid UTF-8,
+// otherwise as base64.  Credential headers and query parameters are
+// redacted.  One Capture may wrap the transports of several clients.
+type Capture struct {
+	rate    float64
+	maxBody int
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+	out io.Writer
+}
+
+type captureTransport struct {
+	capture *Capture
+	next    http.RoundTripper
+}
+
+var _ http.RoundTripper = (*captureTransport)(nil)
+
+type captureRecord struct {
+	Time            time.Time           `json:"time"`
+	Method          string              `json:"method"`
+	URL             string              `json:"url"`
+	RequestHeaders  map[string][]string `json:"requestHeaders"`
+	RequestBody     captureBody         `json:"requestBody"`
+	Status          int                 `json:"status,omitempty"`
+	ResponseHeaders map[string][]string `json:"responseHeaders,omitempty"`
+	ResponseBody    *captureBody        `json:"responseBody,omitempty"`
+	DurationMs      float64             `json:"durationMs"`
+	Error           string              `json:"error,omitempty"`
+}
+
+type captureBody struct {
+	Size      int    `json:"size"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Base64    string `json:"base64,omitempty"`
+}
+
+// NewCapture writes records to out.  rate is the fraction (0-1) of
+// requests recorded; a maxBody of zero uses DefaultCaptureMaxBodyBytes.
+func NewCapture(out io.Writer, rate float64, maxBody int, rnd *rand.Rand) *Capture {
+	if maxBody <= 0 {
+		maxBody = DefaultCaptureMaxBodyBytes
+	}
+	return &Capture{
+		out:     out,
+		rate:    rate,
+		maxBody: maxBody,
+		rnd:     rnd,
+	}
+}
+
+// Wrap returns a RoundTripper that records through c and then sends
+// with next, which defaults to http.DefaultTransport.
+func (c *Capture) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &captureTransport{capture: c, next: next}
+}
+
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := t.capture
+	c.mu.Lock()
+	sampled := c.rnd.Float64() < c.rate
+	c.mu.Unlock()
+	if !sampled {
+		return t.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	rec := captureRecord{
+		Time:           time.Now().UTC(),
+		Method:         req.Method,
+		URL:            redactURL(req.URL),
+		RequestHeaders: redactHeaders(req.Header),
+		RequestBody:    c.body(reqBody),
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	rec.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		rec.Error = err.Error()
+		c.write(rec)
+		return resp, err
+	}
+
+	respBody, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	rec.Status = resp.StatusCode
+	rec.ResponseHeaders = redactHeaders(resp.Header)
+	body := c.body(respBody)
+	rec.ResponseBody = &body
+	if readErr != nil {
+		rec.Error = readErr.Error()
+	}
+	c.write(rec)
+	return resp, nil
+}
+
+func (c *Capture) body(b []byte) captureBody {
+	cb := captureBody{Size: len(b)}
+	if len(b) > c.maxBody {
+		b = b[:c.maxBody]
+		cb.Truncated = true
+	}
+	if utf8.Valid(b) {
+		cb.Text = string(b)
+	} else {
+		cb.Base64 = base64.StdEncoding.EncodeToString(b)
+	}
+	return cb
+}
+
+func (c *Capture) write(rec captureRecord) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = c.out.Write(append(line, '\n'))
+}
+
+func redactHeaders(h http.Header) map[string][]string {
+	ret := make(map[string][]string, len(h))
+	for k, v := range h {
+		if isSecretName(k) {
+			ret[k] = []string{"REDACTED"}
+		} else {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// redactURL hides userinfo and credential query parameters, such as
+// the Influx v1 password.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for k := range query {
+		if k == "p" || isSecretName(k) {
+			query.Set(k, "REDACTED")
+			changed = true
+		}
+	}
+	if !changed {
+		return u.Redacted()
+	}
+	cp := *u
+	cp.RawQuery = query.Encode()
+	return cp.Redacted()
+}
+
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range []string{"auth", "key", "token", "secret", "password", "cookie"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
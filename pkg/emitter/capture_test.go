@@ -0,0 +1,85 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestCapture(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "0123456789", string(b), "server still sees the full body")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid metric name"))
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	client := &http.Client{Transport: NewCapture(&out, 1, 4, state.MakeRNG(1)).Wrap(nil)}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/write?db=sim&p=hunter2", strings.NewReader("0123456789"))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Token secret")
+	req.Header.Set("X-Api-Key", "secret")
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "invalid metric name", string(body), "caller still sees the full response")
+
+	var rec captureRecord
+	require.NoError(t, json.Unmarshal(out.Bytes(), &rec))
+	assert.Equal(t, http.MethodPost, rec.Method)
+	assert.NotContains(t, rec.URL, "hunter2")
+	assert.Equal(t, []string{"REDACTED"}, rec.RequestHeaders["Authorization"])
+	assert.Equal(t, []string{"REDACTED"}, rec.RequestHeaders["X-Api-Key"])
+	assert.Equal(t, []string{"text/plain"}, rec.RequestHeaders["Content-Type"])
+	assert.Equal(t, captureBody{Size: 10, Truncated: true, Text: "0123"}, rec.RequestBody)
+	assert.Equal(t, http.StatusBadRequest, rec.Status)
+	require.NotNil(t, rec.ResponseBody)
+	assert.Equal(t, "inva", rec.ResponseBody.Text)
+}
+
+func TestCapture_Sampling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	client := &http.Client{Transport: NewCapture(&out, 0, 0, state.MakeRNG(1)).Wrap(nil)}
+	resp, err := client.Post(srv.URL, "application/x-protobuf", bytes.NewReader([]byte{0xff, 0xfe}))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Empty(t, out.String())
+}
+
+func TestCapture_BinaryBody(t *testing.T) {
+	c := NewCapture(io.Discard, 1, 0, state.MakeRNG(1))
+	assert.Equal(t, captureBody{Size: 2, Base64: "//4="}, c.body([]byte{0xff, 0xfe}))
+}
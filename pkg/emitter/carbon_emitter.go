@@ -0,0 +1,146 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// DefaultCarbonTemplate names each Graphite path after the metric.
+const DefaultCarbonTemplate = "{name}"
+
+// CarbonEmitter writes datapoints to a Carbon plaintext listener as
+// "<path> <value> <timestamp>" lines over TCP.  The path comes from a
+// template where {name} is the metric name and {key} is the value of
+// the datapoint or resource attribute key, for example
+// "{service.name}.{name}".  Traces are not sent.
+//
+// The connection is opened on first use and reopened on the next
+// tick after a write error.
+type CarbonEmitter struct {
+	address  string
+	template string
+	timeout  time.Duration
+	conn     net.Conn
+}
+
+var (
+	_ Emitter = (*CarbonEmitter)(nil)
+	_ Flusher = (*CarbonEmitter)(nil)
+)
+
+func NewCarbonEmitter(address, template string, timeout time.Duration) *CarbonEmitter {
+	if template == "" {
+		template = DefaultCarbonTemplate
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &CarbonEmitter{
+		address:  address,
+		template: template,
+		timeout:  timeout,
+	}
+}
+
+func (e *CarbonEmitter) EmitMetrics(ctx context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, row := range FlattenMetrics(md) {
+		buf.WriteString(e.path(row))
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatFloat(row.Value, 'g', -1, 64))
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatInt(row.Timestamp.Unix(), 10))
+		buf.WriteByte('\n')
+	}
+
+	if e.conn == nil {
+		d := net.Dialer{Timeout: e.timeout}
+		conn, err := d.DialContext(ctx, "tcp", e.address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to carbon at %s: %w", e.address, err)
+		}
+		e.conn = conn
+	}
+	_ = e.conn.SetWriteDeadline(time.Now().Add(e.timeout))
+	if _, err := e.conn.Write(buf.Bytes()); err != nil {
+		_ = e.conn.Close()
+		e.conn = nil
+		return fmt.Errorf("failed to write to carbon at %s: %w", e.address, err)
+	}
+	return nil
+}
+
+func (e *CarbonEmitter) EmitTraces(_ context.Context, _ *state.RunState, _ ptrace.Traces) error {
+	return nil
+}
+
+func (e *CarbonEmitter) Flush(_ context.Context, _ *state.RunState) error {
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	return err
+}
+
+var carbonPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// path expands the template for row.  Substituted values have dots
+// and other characters Graphite treats specially replaced with
+// underscores so each placeholder stays one path node; the metric
+// name keeps its dots.  Missing attributes expand to "unknown".
+func (e *CarbonEmitter) path(row MetricRow) string {
+	return carbonPlaceholder.ReplaceAllStringFunc(e.template, func(m string) string {
+		key := m[1 : len(m)-1]
+		if key == "name" {
+			return sanitizeCarbon(row.Name, true)
+		}
+		if v, ok := row.Attributes[key]; ok && v != "" {
+			return sanitizeCarbon(v, false)
+		}
+		if v, ok := row.ResourceAttributes[key]; ok && v != "" {
+			return sanitizeCarbon(v, false)
+		}
+		return "unknown"
+	})
+}
+
+func sanitizeCarbon(s string, keepDots bool) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r == '.' && keepDots:
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestCarbonEmitter_path(t *testing.T) {
+	row := MetricRow{
+		Name:               "http.server.requests",
+		ResourceAttributes: map[string]string{"service.name": "api.v1", "host.name": "h1"},
+		Attributes:         map[string]string{"route": "/users/{id}", "host.name": "h2"},
+	}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"", "http.server.requests"},
+		{"{service.name}.{name}", "api_v1.http.server.requests"},
+		{"sim.{host.name}.{route}.{name}", "sim.h2._users__id_.http.server.requests"},
+		{"{missing}.{name}", "unknown.http.server.requests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			e := NewCarbonEmitter("", tt.template, 0)
+			assert.Equal(t, tt.want, e.path(row))
+		})
+	}
+}
+
+func TestCarbonEmitter_EmitMetrics(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	e := NewCarbonEmitter(ln.Addr().String(), "{service.name}.{name}", 0)
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics()))
+	assert.Equal(t, "test.test.metric 1 1000\n", <-lines)
+	require.NoError(t, e.Flush(context.Background(), &state.RunState{}))
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+const clickhouseTimeFormat = "2006-01-02 15:04:05.000000000"
+
+// ClickHouseEmitter inserts flattened datapoints and spans into
+// ClickHouse tables using the HTTP interface and JSONEachRow format.
+// Each tick's payload is one INSERT per signal.
+type ClickHouseEmitter struct {
+	client       *http.Client
+	endpoint     string
+	username     string
+	password     string
+	metricsTable string
+	tracesTable  string
+}
+
+var _ Emitter = (*ClickHouseEmitter)(nil)
+
+type clickhouseMetricRow struct {
+	Timestamp          string            `json:"timestamp"`
+	MetricName         string            `json:"metric_name"`
+	MetricType         string            `json:"metric_type"`
+	Unit               string            `json:"unit"`
+	Value              float64           `json:"value"`
+	ResourceAttributes map[string]string `json:"resource_attributes"`
+	Attributes         map[string]string `json:"attributes"`
+}
+
+type clickhouseSpanRow struct {
+	Timestamp          string            `json:"timestamp"`
+	TraceID            string            `json:"trace_id"`
+	SpanID             string            `json:"span_id"`
+	ParentSpanID       string            `json:"parent_span_id"`
+	SpanName           string            `json:"span_name"`
+	SpanKind           string            `json:"span_kind"`
+	ServiceName        string            `json:"service_name"`
+	DurationNs         int64             `json:"duration_ns"`
+	StatusCode         string            `json:"status_code"`
+	StatusMessage      string            `json:"status_message"`
+	ResourceAttributes map[string]string `json:"resource_attributes"`
+	Attributes         map[string]string `json:"attributes"`
+}
+
+func NewClickHouseEmitter(client *http.Client, endpoint, username, password, metricsTable, tracesTable string) (*ClickHouseEmitter, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if metricsTable == "" && tracesTable == "" {
+		return nil, errors.New("clickhouse: at least one of metricsTable or tracesTable is required")
+	}
+	return &ClickHouseEmitter{
+		client:       client,
+		endpoint:     strings.TrimRight(endpoint, "/"),
+		username:     username,
+		password:     password,
+		metricsTable: metricsTable,
+		tracesTable:  tracesTable,
+	}, nil
+}
+
+func (e *ClickHouseEmitter) EmitMetrics(ctx context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	if e.metricsTable == "" || md.DataPointCount() == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range FlattenMetrics(md) {
+		if err := enc.Encode(clickhouseMetricRow{
+			Timestamp:          row.Timestamp.Format(clickhouseTimeFormat),
+			MetricName:         row.Name,
+			MetricType:         row.Type,
+			Unit:               row.Unit,
+			Value:              row.Value,
+			ResourceAttributes: row.ResourceAttributes,
+			Attributes:         row.Attributes,
+		}); err != nil {
+			return fmt.Errorf("failed to encode metric row: %w", err)
+		}
+	}
+	return e.insert(ctx, e.metricsTable, buf.Bytes())
+}
+
+func (e *ClickHouseEmitter) EmitTraces(ctx context.Context, _ *state.RunState, td ptrace.Traces) error {
+	if e.tracesTable == "" || td.SpanCount() == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range FlattenTraces(td) {
+		if err := enc.Encode(clickhouseSpanRow{
+			Timestamp:          row.Timestamp.Format(clickhouseTimeFormat),
+			TraceID:            row.TraceID,
+			SpanID:             row.SpanID,
+			ParentSpanID:       row.ParentSpanID,
+			SpanName:           row.Name,
+			SpanKind:           row.Kind,
+			ServiceName:        row.ServiceName,
+			DurationNs:         row.Duration.Nanoseconds(),
+			StatusCode:         row.StatusCode,
+			StatusMessage:      row.StatusMessage,
+			ResourceAttributes: row.ResourceAttributes,
+			Attributes:         row.Attributes,
+		}); err != nil {
+			return fmt.Errorf("failed to encode span row: %w", err)
+		}
+	}
+	return e.insert(ctx, e.tracesTable, buf.Bytes())
+}
+
+func (e *ClickHouseEmitter) insert(ctx context.Context, table string, body []byte) error {
+	query := url.Values{}
+	query.Set("query", "INSERT INTO "+table+" FORMAT JSONEachRow")
+	headers := map[string]string{}
+	if e.username != "" {
+		headers["X-ClickHouse-User"] = e.username
+		headers["X-ClickHouse-Key"] = e.password
+	}
+	if _, err := postBody(ctx, e.client, e.endpoint+"/?"+query.Encode(), "application/x-ndjson", headers, body); err != nil {
+		return fmt.Errorf("clickhouse insert into %s failed: %w", table, err)
+	}
+	return nil
+}
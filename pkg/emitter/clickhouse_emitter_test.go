@@ -0,0 +1,108 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// clickhouseRequest is what a ClickHouse test server was sent.
+type clickhouseRequest struct {
+	query  string
+	header http.Header
+	body   string
+}
+
+func clickhouseServer(t *testing.T, status int) (*httptest.Server, *[]clickhouseRequest) {
+	t.Helper()
+	var got []clickhouseRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = append(got, clickhouseRequest{query: r.URL.Query().Get("query"), header: r.Header.Clone(), body: string(b)})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func TestNewClickHouseEmitter(t *testing.T) {
+	_, err := NewClickHouseEmitter(nil, "http://localhost:8123", "", "", "", "")
+	assert.Error(t, err)
+
+	e, err := NewClickHouseEmitter(nil, "http://localhost:8123/", "", "", "metrics", "")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8123", e.endpoint)
+	assert.Equal(t, http.DefaultClient, e.client)
+}
+
+func TestClickHouseEmitter_EmitMetrics(t *testing.T) {
+	srv, got := clickhouseServer(t, http.StatusOK)
+	e, err := NewClickHouseEmitter(srv.Client(), srv.URL, "flutter", "secret", "otel.metrics", "")
+	require.NoError(t, err)
+
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeMixedMetrics()))
+	require.Len(t, *got, 1)
+	req := (*got)[0]
+	assert.Equal(t, "INSERT INTO otel.metrics FORMAT JSONEachRow", req.query)
+	assert.Equal(t, "flutter", req.header.Get("X-ClickHouse-User"))
+	assert.Equal(t, "secret", req.header.Get("X-ClickHouse-Key"))
+	assert.Equal(t, "application/x-ndjson", req.header.Get("Content-Type"))
+	assert.Equal(t, strings.Join([]string{
+		`{"timestamp":"1970-01-01 00:16:40.000000000","metric_name":"cpu","metric_type":"Gauge","unit":"%","value":42.5,"resource_attributes":{"service.name":"api"},"attributes":{"host":"a"}}`,
+		`{"timestamp":"1970-01-01 00:16:40.000000005","metric_name":"requests","metric_type":"Sum","unit":"1","value":7,"resource_attributes":{"service.name":"api"},"attributes":{"code":"200"}}`,
+	}, "\n")+"\n", req.body)
+
+	// Traces have no table, so nothing is sent.
+	require.NoError(t, e.EmitTraces(context.Background(), &state.RunState{}, makeSpanTraces()))
+	assert.Len(t, *got, 1)
+}
+
+func TestClickHouseEmitter_EmitTraces(t *testing.T) {
+	srv, got := clickhouseServer(t, http.StatusOK)
+	e, err := NewClickHouseEmitter(srv.Client(), srv.URL, "", "", "", "otel.spans")
+	require.NoError(t, err)
+
+	require.NoError(t, e.EmitTraces(context.Background(), &state.RunState{}, makeSpanTraces()))
+	require.Len(t, *got, 1)
+	req := (*got)[0]
+	assert.Equal(t, "INSERT INTO otel.spans FORMAT JSONEachRow", req.query)
+	assert.Empty(t, req.header.Get("X-ClickHouse-User"))
+	assert.Empty(t, req.header.Get("X-ClickHouse-Key"))
+	assert.Equal(t, `{"timestamp":"1970-01-01 00:16:40.000000000","trace_id":"0102030405060708090a0b0c0d0e0f10",`+
+		`"span_id":"0102030405060708","parent_span_id":"0807060504030201","span_name":"GET /","span_kind":"Server",`+
+		`"service_name":"api","duration_ns":25000000,"status_code":"Error","status_message":"boom",`+
+		`"resource_attributes":{"service.name":"api"},"attributes":{"http.route":"/"}}`+"\n", req.body)
+
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeMixedMetrics()))
+	assert.Len(t, *got, 1)
+}
+
+func TestClickHouseEmitter_Error(t *testing.T) {
+	srv, _ := clickhouseServer(t, http.StatusBadRequest)
+	e, err := NewClickHouseEmitter(srv.Client(), srv.URL, "", "", "metrics", "")
+	require.NoError(t, err)
+	err = e.EmitMetrics(context.Background(), &state.RunState{}, makeMixedMetrics())
+	assert.ErrorContains(t, err, "clickhouse insert into metrics failed")
+}
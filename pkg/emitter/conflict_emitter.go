@@ -0,0 +1,114 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// DefaultConflictUnit is the unit used on conflicting metric copies
+// when none is configured.
+const DefaultConflictUnit = "flutter.conflict"
+
+// ConflictEmitter wraps another emitter and, for every gauge or sum
+// in a metrics payload, adds a copy with the same name but the other
+// type and a different unit.  The copies are placed under a separate
+// resource, marked with a "flutter.conflict" attribute, so a backend
+// sees the same metric name arrive with two incompatible schemas.
+// Traces and logs are passed through unchanged.
+type ConflictEmitter struct {
+	next Emitter
+	unit string
+}
+
+var (
+	_ Emitter    = (*ConflictEmitter)(nil)
+	_ LogEmitter = (*ConflictEmitter)(nil)
+)
+
+func NewConflictEmitter(next Emitter, sc config.SchemaConflicts) *ConflictEmitter {
+	unit := sc.Unit
+	if unit == "" {
+		unit = DefaultConflictUnit
+	}
+	return &ConflictEmitter{
+		next: next,
+		unit: unit,
+	}
+}
+
+func (e *ConflictEmitter) EmitMetrics(ctx context.Context, rs *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() == 0 {
+		return e.next.EmitMetrics(ctx, rs, md)
+	}
+
+	out := pmetric.NewMetrics()
+	md.CopyTo(out)
+	for _, rm := range md.ResourceMetrics().All() {
+		crm := out.ResourceMetrics().AppendEmpty()
+		rm.Resource().CopyTo(crm.Resource())
+		crm.Resource().Attributes().PutBool("flutter.conflict", true)
+		for _, sm := range rm.ScopeMetrics().All() {
+			csm := crm.ScopeMetrics().AppendEmpty()
+			sm.Scope().CopyTo(csm.Scope())
+			for _, m := range sm.Metrics().All() {
+				e.addConflict(csm.Metrics(), m)
+			}
+		}
+	}
+	return e.next.EmitMetrics(ctx, rs, out)
+}
+
+// addConflict appends a copy of m to dest with the gauge/sum type
+// swapped and the conflict unit set.  Other metric types are skipped.
+func (e *ConflictEmitter) addConflict(dest pmetric.MetricSlice, m pmetric.Metric) {
+	var dps pmetric.NumberDataPointSlice
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps = m.Gauge().DataPoints()
+	case pmetric.MetricTypeSum:
+		dps = m.Sum().DataPoints()
+	default:
+		return
+	}
+
+	cm := dest.AppendEmpty()
+	cm.SetName(m.Name())
+	cm.SetDescription(m.Description())
+	cm.SetUnit(e.unit)
+	if m.Type() == pmetric.MetricTypeGauge {
+		sum := cm.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.SetIsMonotonic(true)
+		dps.CopyTo(sum.DataPoints())
+	} else {
+		dps.CopyTo(cm.SetEmptyGauge().DataPoints())
+	}
+}
+
+func (e *ConflictEmitter) EmitTraces(ctx context.Context, rs *state.RunState, td ptrace.Traces) error {
+	return e.next.EmitTraces(ctx, rs, td)
+}
+
+func (e *ConflictEmitter) EmitLogs(ctx context.Context, rs *state.RunState, ld plog.Logs) error {
+	return EmitLogs(ctx, e.next, rs, ld)
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// captureLogEmitter also keeps the logs it is sent.
+type captureLogEmitter struct {
+	captureEmitter
+	logs []plog.Logs
+}
+
+func (c *captureLogEmitter) EmitLogs(_ context.Context, _ *state.RunState, ld plog.Logs) error {
+	c.logs = append(c.logs, ld)
+	return nil
+}
+
+func TestConflictEmitter_EmitMetrics(t *testing.T) {
+	capture := &captureLogEmitter{}
+	e := NewConflictEmitter(capture, config.SchemaConflicts{})
+	md := makeMixedMetrics()
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, md))
+	require.Len(t, capture.metrics, 1)
+	out := capture.metrics[0]
+
+	// The original payload is sent unchanged, followed by the copies.
+	require.Equal(t, 2, out.ResourceMetrics().Len())
+	original := pmetric.NewMetrics()
+	out.ResourceMetrics().At(0).CopyTo(original.ResourceMetrics().AppendEmpty())
+	assert.Equal(t, md, original)
+
+	crm := out.ResourceMetrics().At(1)
+	conflict, ok := crm.Resource().Attributes().Get("flutter.conflict")
+	require.True(t, ok)
+	assert.True(t, conflict.Bool())
+	service, _ := crm.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "api", service.Str())
+
+	// The histogram has no conflicting type, so only two copies.
+	ms := crm.ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, ms.Len())
+
+	cpu := ms.At(0)
+	assert.Equal(t, "cpu", cpu.Name())
+	assert.Equal(t, DefaultConflictUnit, cpu.Unit())
+	require.Equal(t, pmetric.MetricTypeSum, cpu.Type())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, cpu.Sum().AggregationTemporality())
+	assert.True(t, cpu.Sum().IsMonotonic())
+	assert.Equal(t, 42.5, cpu.Sum().DataPoints().At(0).DoubleValue())
+	host, _ := cpu.Sum().DataPoints().At(0).Attributes().Get("host")
+	assert.Equal(t, "a", host.Str())
+
+	requests := ms.At(1)
+	assert.Equal(t, "requests", requests.Name())
+	assert.Equal(t, DefaultConflictUnit, requests.Unit())
+	require.Equal(t, pmetric.MetricTypeGauge, requests.Type())
+	assert.Equal(t, int64(7), requests.Gauge().DataPoints().At(0).IntValue())
+}
+
+func TestConflictEmitter_Unit(t *testing.T) {
+	capture := &captureEmitter{}
+	e := NewConflictEmitter(capture, config.SchemaConflicts{Unit: "By"})
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics()))
+	m := capture.metrics[0].ResourceMetrics().At(1).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "By", m.Unit())
+}
+
+func TestConflictEmitter_PassThrough(t *testing.T) {
+	capture := &captureLogEmitter{}
+	e := NewConflictEmitter(capture, config.SchemaConflicts{})
+	ctx, rs := context.Background(), &state.RunState{}
+
+	empty := pmetric.NewMetrics()
+	require.NoError(t, e.EmitMetrics(ctx, rs, empty))
+	assert.Equal(t, empty, capture.metrics[0])
+
+	td := makeSpanTraces()
+	require.NoError(t, e.EmitTraces(ctx, rs, td))
+	require.Len(t, capture.traces, 1)
+	assert.Equal(t, makeSpanTraces(), capture.traces[0])
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+	require.NoError(t, e.EmitLogs(ctx, rs, ld))
+	require.Len(t, capture.logs, 1)
+	assert.Equal(t, ld, capture.logs[0])
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// SignalCounts is the volume seen for one signal.  Items is
+// datapoints for metrics and spans for traces; Bytes is the
+// uncompressed OTLP protobuf size.
+type SignalCounts struct {
+	Payloads int64
+	Items    int64
+	Bytes    int64
+}
+
+// ServiceCounts is the span volume seen for one service.name.  Bytes
+// is the protobuf size of the service's resource spans, without the
+// enclosing request.
+type ServiceCounts struct {
+	Spans int64
+	Bytes int64
+}
+
+// CountingEmitter tallies the volume of each signal and writes a
+// summary when the run ends.  Spans are also tallied per service.name,
+// for sizing the pipeline that will receive them.  Empty payloads are
+// not counted.
+type CountingEmitter struct {
+	out      io.Writer
+	metrics  SignalCounts
+	traces   SignalCounts
+	services map[string]ServiceCounts
+}
+
+var (
+	_ Emitter = (*CountingEmitter)(nil)
+	_ Flusher = (*CountingEmitter)(nil)
+)
+
+func NewCountingEmitter(out io.Writer) *CountingEmitter {
+	return &CountingEmitter{
+		out:      out,
+		services: map[string]ServiceCounts{},
+	}
+}
+
+func (e *CountingEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	e.metrics.Payloads++
+	e.metrics.Items += int64(md.DataPointCount())
+	e.metrics.Bytes += int64((&pmetric.ProtoMarshaler{}).MetricsSize(md))
+	return nil
+}
+
+func (e *CountingEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	if td.SpanCount() == 0 {
+		return nil
+	}
+	e.traces.Payloads++
+	e.traces.Items += int64(td.SpanCount())
+	marshaler := &ptrace.ProtoMarshaler{}
+	e.traces.Bytes += int64(marshaler.TracesSize(td))
+	for _, rs := range td.ResourceSpans().All() {
+		service := "unknown"
+		if v, ok := rs.Resource().Attributes().Get("service.name"); ok {
+			service = v.AsString()
+		}
+		c := e.services[service]
+		for _, ss := range rs.ScopeSpans().All() {
+			c.Spans += int64(ss.Spans().Len())
+		}
+		c.Bytes += int64(marshaler.ResourceSpansSize(rs))
+		e.services[service] = c
+	}
+	return nil
+}
+
+// Metrics returns the metric volume seen so far.
+func (e *CountingEmitter) Metrics() SignalCounts {
+	return e.metrics
+}
+
+// Traces returns the trace volume seen so far.
+func (e *CountingEmitter) Traces() SignalCounts {
+	return e.traces
+}
+
+// Services returns the span volume seen so far for each service.name.
+// Spans without one are counted under "unknown".
+func (e *CountingEmitter) Services() map[string]ServiceCounts {
+	return maps.Clone(e.services)
+}
+
+func (e *CountingEmitter) Flush(_ context.Context, rs *state.RunState) error {
+	if rs.RunID != "" {
+		fmt.Fprintf(e.out, "run: %s\n", rs.RunID)
+	}
+	fmt.Fprintf(e.out, "metrics: %d payloads, %d datapoints, %d bytes\n", e.metrics.Payloads, e.metrics.Items, e.metrics.Bytes)
+	fmt.Fprintf(e.out, "traces: %d payloads, %d spans, %d bytes\n", e.traces.Payloads, e.traces.Items, e.traces.Bytes)
+	for _, service := range slices.Sorted(maps.Keys(e.services)) {
+		c := e.services[service]
+		fmt.Fprintf(e.out, "  %s: %d spans, %d bytes\n", service, c.Spans, c.Bytes)
+	}
+	return nil
+}
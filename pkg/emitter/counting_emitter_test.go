@@ -0,0 +1,79 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestCountingEmitter(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{}
+	var out bytes.Buffer
+	e := NewCountingEmitter(&out)
+
+	md := makeTestMetrics()
+	require.NoError(t, e.EmitMetrics(ctx, rs, md))
+	require.NoError(t, e.EmitMetrics(ctx, rs, md))
+	require.NoError(t, e.EmitMetrics(ctx, rs, pmetric.NewMetrics()))
+	require.NoError(t, e.EmitTraces(ctx, rs, ptrace.NewTraces()))
+
+	size := int64((&pmetric.ProtoMarshaler{}).MetricsSize(md))
+	assert.Equal(t, SignalCounts{Payloads: 2, Items: 2, Bytes: 2 * size}, e.Metrics())
+	assert.Equal(t, SignalCounts{}, e.Traces())
+
+	require.NoError(t, e.Flush(ctx, rs))
+	assert.Contains(t, out.String(), "metrics: 2 payloads, 2 datapoints")
+	assert.Contains(t, out.String(), "traces: 0 payloads, 0 spans, 0 bytes")
+}
+
+func TestCountingEmitter_Services(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{}
+	var out bytes.Buffer
+	e := NewCountingEmitter(&out)
+
+	td := ptrace.NewTraces()
+	for _, service := range []string{"checkout", "cart", "checkout", ""} {
+		rspans := td.ResourceSpans().AppendEmpty()
+		if service != "" {
+			rspans.Resource().Attributes().PutStr("service.name", service)
+		}
+		spans := rspans.ScopeSpans().AppendEmpty().Spans()
+		spans.AppendEmpty().SetName("a")
+		spans.AppendEmpty().SetName("b")
+	}
+	require.NoError(t, e.EmitTraces(ctx, rs, td))
+
+	services := e.Services()
+	assert.Equal(t, int64(4), services["checkout"].Spans)
+	assert.Equal(t, int64(2), services["cart"].Spans)
+	assert.Equal(t, int64(2), services["unknown"].Spans)
+	marshaler := &ptrace.ProtoMarshaler{}
+	assert.Equal(t, int64(marshaler.ResourceSpansSize(td.ResourceSpans().At(1))), services["cart"].Bytes)
+
+	require.NoError(t, e.Flush(ctx, rs))
+	assert.Contains(t, out.String(), "  cart: 2 spans,")
+	assert.Contains(t, out.String(), "  checkout: 4 spans,")
+}
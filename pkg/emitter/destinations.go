@@ -0,0 +1,269 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/objectstore"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Destinations is the set of network destinations from a Config,
+// fanned out through a TeeEmitter so each uses its error policy.
+type Destinations struct {
+	*TeeEmitter
+	closer io.Closer
+	conns  map[string]*ConnStats
+}
+
+// NewDestinations builds every destination configured in cfg.  It
+// does not look at cfg.Dryrun; callers that honor dry-run mode should
+// not call it.  Close stops queued sends and releases the capture
+// file, if any, once the destinations are no longer used.
+func NewDestinations(cfg *config.Config) (_ *Destinations, err error) {
+	tee, err := NewTeeEmitter(cfg.ErrorPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid errorPolicies: %w", err)
+	}
+	if err := tee.SetQueues(cfg.SendQueues); err != nil {
+		return nil, fmt.Errorf("invalid sendQueues: %w", err)
+	}
+	if err := tee.SetTransforms(cfg.Transforms); err != nil {
+		return nil, fmt.Errorf("invalid transforms: %w", err)
+	}
+	d := &Destinations{TeeEmitter: tee, conns: map[string]*ConnStats{}}
+	defer func() {
+		if err != nil {
+			_ = d.Close()
+		}
+	}()
+
+	var capture *Capture
+	if cfg.Capture.File != "" {
+		f, err := os.Create(cfg.Capture.File)
+		if err != nil {
+			return nil, fmt.Errorf("error creating capture file: %w", err)
+		}
+		d.closer = f
+		slog.Info("Capturing destination requests", "file", cfg.Capture.File, "sampleRate", cfg.Capture.SampleRate)
+		capture = NewCapture(f, cfg.Capture.SampleRate, cfg.Capture.MaxBodyBytes, state.MakeRNG(cfg.Seed))
+	}
+	newClient := func(name, endpoint string, timeout time.Duration) (*http.Client, string, error) {
+		client, endpoint, err := NewHTTPClient(endpoint, timeout, cfg.HTTPTransport)
+		if err != nil {
+			return nil, "", err
+		}
+		d.conns[name] = TrackConns(client)
+		if capture != nil {
+			client.Transport = capture.Wrap(client.Transport)
+		}
+		return client, endpoint, nil
+	}
+
+	if cfg.OTLPDestination.Endpoint != "" {
+		slog.Info("Using OTLP destination", "endpoint", cfg.OTLPDestination.Endpoint)
+		client, endpoint, err := newClient("otlp", cfg.OTLPDestination.Endpoint, cfg.OTLPDestination.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("error creating OTLP client: %w", err)
+		}
+		otlp, err := NewOTLPEmitter(client, endpoint, cfg.OTLPDestination.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("error creating OTLP emitter: %w", err)
+		}
+		var dest Emitter = otlp
+		if cfg.SchemaConflicts.Enabled {
+			slog.Warn("Schema conflict mode enabled, metrics will be duplicated with conflicting types")
+			dest = NewConflictEmitter(dest, cfg.SchemaConflicts)
+		}
+		if cfg.FaultInjection.Probability > 0 {
+			slog.Warn("Fault injection enabled, some payloads will be malformed", "probability", cfg.FaultInjection.Probability)
+			dest, err = NewFaultEmitter(dest, cfg.FaultInjection, cfg.Seed)
+			if err != nil {
+				return nil, fmt.Errorf("error creating fault injection emitter: %w", err)
+			}
+		}
+		d.Add("otlp", dest)
+	}
+
+	if cfg.ObjectStorage.Bucket != "" {
+		obs := cfg.ObjectStorage
+		slog.Info("Using object storage destination", "provider", obs.Provider, "bucket", obs.Bucket, "prefix", obs.Prefix)
+		timeout := obs.Timeout
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		client, _, err := NewHTTPClient("", timeout, cfg.HTTPTransport)
+		if err != nil {
+			return nil, fmt.Errorf("error creating object storage client: %w", err)
+		}
+		d.conns["objectStorage"] = TrackConns(client)
+		if capture != nil {
+			client.Transport = capture.Wrap(client.Transport)
+		}
+		uploader, err := objectstore.NewUploader(client, obs.Provider, obs.Bucket, obs.Region, obs.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("error creating object storage uploader: %w", err)
+		}
+		d.Add("objectStorage", NewObjectStoreEmitter(uploader, obs.Prefix, obs.MaxBytes, obs.Interval))
+	}
+
+	if cfg.ClickHouse.Endpoint != "" {
+		ch := cfg.ClickHouse
+		slog.Info("Using ClickHouse destination", "endpoint", ch.Endpoint)
+		timeout := ch.Timeout
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		client, endpoint, err := newClient("clickhouse", ch.Endpoint, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("error creating ClickHouse client: %w", err)
+		}
+		che, err := NewClickHouseEmitter(client, endpoint, ch.Username, ch.Password, ch.MetricsTable, ch.TracesTable)
+		if err != nil {
+			return nil, fmt.Errorf("error creating ClickHouse emitter: %w", err)
+		}
+		d.Add("clickhouse", che)
+	}
+
+	if cfg.SplunkHEC.Endpoint != "" {
+		hec := cfg.SplunkHEC
+		slog.Info("Using Splunk HEC destination", "endpoint", hec.Endpoint)
+		timeout := hec.Timeout
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		client, endpoint, err := newClient("splunkHEC", hec.Endpoint, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Splunk HEC client: %w", err)
+		}
+		se, err := NewSplunkHECEmitter(client, endpoint, hec.Token, hec.Index, hec.Source, hec.SourceType)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Splunk HEC emitter: %w", err)
+		}
+		d.Add("splunkHEC", se)
+	}
+
+	if cfg.Elasticsearch.Endpoint != "" {
+		es := cfg.Elasticsearch
+		slog.Info("Using Elasticsearch destination", "endpoint", es.Endpoint)
+		timeout := es.Timeout
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		client, endpoint, err := newClient("elasticsearch", es.Endpoint, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Elasticsearch client: %w", err)
+		}
+		ee, err := NewElasticsearchEmitter(client, endpoint, es.APIKey, es.Username, es.Password, es.MetricsIndex, es.TracesIndex)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Elasticsearch emitter: %w", err)
+		}
+		d.Add("elasticsearch", ee)
+	}
+
+	if cfg.Influx.Endpoint != "" {
+		ifx := cfg.Influx
+		slog.Info("Using Influx destination", "endpoint", ifx.Endpoint)
+		timeout := ifx.Timeout
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		client, endpoint, err := newClient("influx", ifx.Endpoint, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Influx client: %w", err)
+		}
+		ie, err := NewInfluxEmitter(client, InfluxOptions{
+			Endpoint:    endpoint,
+			Version:     ifx.Version,
+			Database:    ifx.Database,
+			Username:    ifx.Username,
+			Password:    ifx.Password,
+			Org:         ifx.Org,
+			Bucket:      ifx.Bucket,
+			Token:       ifx.Token,
+			TagStrategy: ifx.TagStrategy,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating Influx emitter: %w", err)
+		}
+		d.Add("influx", ie)
+	}
+
+	if cfg.Carbon.Address != "" {
+		slog.Info("Using Carbon destination", "address", cfg.Carbon.Address, "template", cfg.Carbon.Template)
+		d.Add("carbon", NewCarbonEmitter(cfg.Carbon.Address, cfg.Carbon.Template, cfg.Carbon.Timeout))
+	}
+
+	if cfg.Syslog.Address != "" {
+		slog.Info("Using syslog destination", "address", cfg.Syslog.Address, "network", cfg.Syslog.Network)
+		se, err := NewSyslogEmitter(cfg.Syslog)
+		if err != nil {
+			return nil, fmt.Errorf("error creating syslog emitter: %w", err)
+		}
+		d.Add("syslog", se)
+	}
+
+	return d, nil
+}
+
+// DestinationNames returns the names NewDestinations would add for
+// cfg, in the order it adds them.
+func DestinationNames(cfg *config.Config) []string {
+	var names []string
+	for _, dest := range []struct {
+		name       string
+		configured bool
+	}{
+		{"otlp", cfg.OTLPDestination.Endpoint != ""},
+		{"objectStorage", cfg.ObjectStorage.Bucket != ""},
+		{"clickhouse", cfg.ClickHouse.Endpoint != ""},
+		{"splunkHEC", cfg.SplunkHEC.Endpoint != ""},
+		{"elasticsearch", cfg.Elasticsearch.Endpoint != ""},
+		{"influx", cfg.Influx.Endpoint != ""},
+		{"carbon", cfg.Carbon.Address != ""},
+		{"syslog", cfg.Syslog.Address != ""},
+	} {
+		if dest.configured {
+			names = append(names, dest.name)
+		}
+	}
+	return names
+}
+
+func (d *Destinations) Close() error {
+	d.TeeEmitter.Close()
+	for _, name := range slices.Sorted(maps.Keys(d.conns)) {
+		opened, reused := d.conns[name].Counts()
+		if opened+reused > 0 {
+			slog.Info("Destination connections", "destination", name, "opened", opened, "reused", reused)
+		}
+	}
+	d.conns = nil
+	if d.closer == nil {
+		return nil
+	}
+	err := d.closer.Close()
+	d.closer = nil
+	return err
+}
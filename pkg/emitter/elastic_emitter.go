@@ -0,0 +1,204 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// ElasticsearchEmitter indexes flattened datapoints and spans as
+// documents using the Elasticsearch bulk API.  Documents are written
+// with the "create" action so the indexes may also be data streams.
+type ElasticsearchEmitter struct {
+	client       *http.Client
+	url          string
+	auth         string
+	metricsIndex string
+	tracesIndex  string
+}
+
+var _ Emitter = (*ElasticsearchEmitter)(nil)
+
+type elasticMetricDoc struct {
+	Timestamp string            `json:"@timestamp"`
+	Metric    elasticMetric     `json:"metric"`
+	Resource  map[string]string `json:"resource"`
+	Attrs     map[string]string `json:"attributes"`
+}
+
+type elasticMetric struct {
+	Name  string  `json:"name"`
+	Type  string  `json:"type"`
+	Unit  string  `json:"unit,omitempty"`
+	Value float64 `json:"value"`
+}
+
+type elasticSpanDoc struct {
+	Timestamp     string            `json:"@timestamp"`
+	TraceID       string            `json:"trace_id"`
+	SpanID        string            `json:"span_id"`
+	ParentSpanID  string            `json:"parent_span_id,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind"`
+	ServiceName   string            `json:"service_name"`
+	DurationNs    int64             `json:"duration_ns"`
+	StatusCode    string            `json:"status_code"`
+	StatusMessage string            `json:"status_message,omitempty"`
+	Resource      map[string]string `json:"resource"`
+	Attrs         map[string]string `json:"attributes"`
+}
+
+type elasticBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// NewElasticsearchEmitter creates an emitter for the cluster at
+// endpoint.  apiKey takes precedence over username and password;
+// with neither, requests are unauthenticated.
+func NewElasticsearchEmitter(client *http.Client, endpoint, apiKey, username, password, metricsIndex, tracesIndex string) (*ElasticsearchEmitter, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if metricsIndex == "" && tracesIndex == "" {
+		return nil, errors.New("elasticsearch: at least one of metricsIndex or tracesIndex is required")
+	}
+	var auth string
+	switch {
+	case apiKey != "":
+		auth = "ApiKey " + apiKey
+	case username != "":
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+	return &ElasticsearchEmitter{
+		client:       client,
+		url:          strings.TrimRight(endpoint, "/") + "/_bulk",
+		auth:         auth,
+		metricsIndex: metricsIndex,
+		tracesIndex:  tracesIndex,
+	}, nil
+}
+
+func (e *ElasticsearchEmitter) EmitMetrics(ctx context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	if e.metricsIndex == "" || md.DataPointCount() == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range FlattenMetrics(md) {
+		if err := encodeBulk(enc, e.metricsIndex, elasticMetricDoc{
+			Timestamp: row.Timestamp.Format(time.RFC3339Nano),
+			Metric: elasticMetric{
+				Name:  row.Name,
+				Type:  row.Type,
+				Unit:  row.Unit,
+				Value: row.Value,
+			},
+			Resource: row.ResourceAttributes,
+			Attrs:    row.Attributes,
+		}); err != nil {
+			return fmt.Errorf("failed to encode metric document: %w", err)
+		}
+	}
+	return e.bulk(ctx, buf.Bytes())
+}
+
+func (e *ElasticsearchEmitter) EmitTraces(ctx context.Context, _ *state.RunState, td ptrace.Traces) error {
+	if e.tracesIndex == "" || td.SpanCount() == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range FlattenTraces(td) {
+		if err := encodeBulk(enc, e.tracesIndex, elasticSpanDoc{
+			Timestamp:     row.Timestamp.Format(time.RFC3339Nano),
+			TraceID:       row.TraceID,
+			SpanID:        row.SpanID,
+			ParentSpanID:  row.ParentSpanID,
+			Name:          row.Name,
+			Kind:          row.Kind,
+			ServiceName:   row.ServiceName,
+			DurationNs:    row.Duration.Nanoseconds(),
+			StatusCode:    row.StatusCode,
+			StatusMessage: row.StatusMessage,
+			Resource:      row.ResourceAttributes,
+			Attrs:         row.Attributes,
+		}); err != nil {
+			return fmt.Errorf("failed to encode span document: %w", err)
+		}
+	}
+	return e.bulk(ctx, buf.Bytes())
+}
+
+func encodeBulk(enc *json.Encoder, index string, doc any) error {
+	action := map[string]map[string]string{"create": {"_index": index}}
+	if err := enc.Encode(action); err != nil {
+		return err
+	}
+	return enc.Encode(doc)
+}
+
+// bulk sends body and checks the per-item results, since the bulk API
+// returns 200 even when every document was rejected.
+func (e *ElasticsearchEmitter) bulk(ctx context.Context, body []byte) error {
+	headers := map[string]string{}
+	if e.auth != "" {
+		headers["Authorization"] = e.auth
+	}
+	respBody, err := postBody(ctx, e.client, e.url, "application/x-ndjson", headers, body)
+	if err != nil {
+		return fmt.Errorf("elasticsearch bulk request failed: %w", err)
+	}
+	var resp elasticBulkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("failed to decode elasticsearch bulk response: %w", err)
+	}
+	if !resp.Errors {
+		return nil
+	}
+	failed := 0
+	var first string
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Error == nil {
+				continue
+			}
+			if failed == 0 {
+				first = result.Error.Type + ": " + result.Error.Reason
+			}
+			failed++
+		}
+	}
+	return fmt.Errorf("elasticsearch rejected %d of %d documents, first error: %s", failed, len(resp.Items), first)
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestElasticsearchEmitter_EmitMetrics(t *testing.T) {
+	var gotBody, gotAuth string
+	response := `{"errors":false,"items":[{"create":{"status":201}}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_bulk", r.URL.Path)
+		gotAuth = r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	e, err := NewElasticsearchEmitter(srv.Client(), srv.URL, "secret", "", "", "flutter-metrics", "")
+	require.NoError(t, err)
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics()))
+
+	assert.Equal(t, "ApiKey secret", gotAuth)
+	lines := strings.Split(strings.TrimSpace(gotBody), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"create":{"_index":"flutter-metrics"}}`, lines[0])
+	assert.JSONEq(t, `{
+		"@timestamp": "1970-01-01T00:16:40Z",
+		"metric": {"name": "test.metric", "type": "Gauge", "value": 1},
+		"resource": {"service.name": "test"},
+		"attributes": {}
+	}`, lines[1])
+
+	response = `{"errors":true,"items":[{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad field"}}}]}`
+	err = e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected 1 of 1 documents")
+	assert.Contains(t, err.Error(), "mapper_parsing_exception: bad field")
+}
+
+func TestNewElasticsearchEmitter(t *testing.T) {
+	_, err := NewElasticsearchEmitter(nil, "http://localhost:9200", "", "", "", "", "")
+	assert.Error(t, err)
+
+	e, err := NewElasticsearchEmitter(nil, "http://localhost:9200/", "", "elastic", "changeme", "", "spans")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9200/_bulk", e.url)
+	assert.Equal(t, "Basic ZWxhc3RpYzpjaGFuZ2VtZQ==", e.auth)
+}
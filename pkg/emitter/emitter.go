@@ -17,6 +17,7 @@ package emitter
 import (
 	"context"
 
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
@@ -27,3 +28,23 @@ type Emitter interface {
 	EmitMetrics(ctx context.Context, state *state.RunState, m pmetric.Metrics) error
 	EmitTraces(ctx context.Context, state *state.RunState, t ptrace.Traces) error
 }
+
+// LogEmitter is implemented by emitters that can also send logs, such
+// as entity lifecycle events.  Emitters without it skip logs.
+type LogEmitter interface {
+	EmitLogs(ctx context.Context, state *state.RunState, l plog.Logs) error
+}
+
+// EmitLogs passes ld to e if e sends logs.
+func EmitLogs(ctx context.Context, e Emitter, rs *state.RunState, ld plog.Logs) error {
+	if le, ok := e.(LogEmitter); ok {
+		return le.EmitLogs(ctx, rs, ld)
+	}
+	return nil
+}
+
+// Flusher is implemented by emitters that buffer data and need to
+// send whatever remains when the run ends.
+type Flusher interface {
+	Flush(ctx context.Context, state *state.RunState) error
+}
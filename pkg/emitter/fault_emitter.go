@@ -0,0 +1,210 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+const (
+	FaultBadUTF8       = "badUTF8"
+	FaultZeroTimestamp = "zeroTimestamp"
+	FaultOversized     = "oversized"
+
+	// DefaultOversizeFactor is used when the "oversized" mode is
+	// enabled without an explicit factor.
+	DefaultOversizeFactor = 100
+)
+
+var validFaultModes = []string{FaultBadUTF8, FaultZeroTimestamp, FaultOversized}
+
+// badUTF8 is not a valid UTF-8 sequence, which protobuf string
+// fields are required to contain.
+const badUTF8 = "\xff\xfe\xfd"
+
+// FaultEmitter wraps another emitter and, with the configured
+// probability, corrupts a copy of the payload before passing it on.
+// The original payload is never modified so other emitters see
+// the data as it was generated.
+type FaultEmitter struct {
+	next           Emitter
+	probability    float64
+	modes          []string
+	oversizeFactor int
+	rnd            *rand.Rand
+}
+
+var (
+	_ Emitter    = (*FaultEmitter)(nil)
+	_ LogEmitter = (*FaultEmitter)(nil)
+)
+
+func NewFaultEmitter(next Emitter, fi config.FaultInjection, seed uint64) (*FaultEmitter, error) {
+	if fi.Probability < 0 || fi.Probability > 1 {
+		return nil, fmt.Errorf("invalid fault injection probability: %v", fi.Probability)
+	}
+	modes := fi.Modes
+	if len(modes) == 0 {
+		modes = validFaultModes
+	}
+	for _, mode := range modes {
+		if !slices.Contains(validFaultModes, mode) {
+			return nil, fmt.Errorf("invalid fault injection mode: %q", mode)
+		}
+	}
+	factor := fi.OversizeFactor
+	if factor <= 0 {
+		factor = DefaultOversizeFactor
+	}
+	return &FaultEmitter{
+		next:           next,
+		probability:    fi.Probability,
+		modes:          modes,
+		oversizeFactor: factor,
+		rnd:            state.MakeRNG(seed),
+	}, nil
+}
+
+// pickFault returns the fault mode to apply, or "" if this payload
+// should be sent unchanged.
+func (e *FaultEmitter) pickFault() string {
+	if e.rnd.Float64() >= e.probability {
+		return ""
+	}
+	return e.modes[e.rnd.IntN(len(e.modes))]
+}
+
+func (e *FaultEmitter) EmitMetrics(ctx context.Context, rs *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() == 0 {
+		return e.next.EmitMetrics(ctx, rs, md)
+	}
+	fault := e.pickFault()
+	if fault == "" {
+		return e.next.EmitMetrics(ctx, rs, md)
+	}
+
+	bad := pmetric.NewMetrics()
+	md.CopyTo(bad)
+	switch fault {
+	case FaultBadUTF8:
+		for _, rm := range bad.ResourceMetrics().All() {
+			rm.Resource().Attributes().PutStr("flutter.fault", badUTF8)
+		}
+	case FaultZeroTimestamp:
+		zeroMetricTimestamps(bad)
+	case FaultOversized:
+		orig := bad.ResourceMetrics()
+		n := orig.Len()
+		for range e.oversizeFactor - 1 {
+			for i := range n {
+				orig.At(i).CopyTo(orig.AppendEmpty())
+			}
+		}
+	}
+	slog.Debug("Injecting metric fault", "fault", fault, "tick", rs.Tick)
+	return e.next.EmitMetrics(ctx, rs, bad)
+}
+
+func (e *FaultEmitter) EmitTraces(ctx context.Context, rs *state.RunState, td ptrace.Traces) error {
+	if td.SpanCount() == 0 {
+		return e.next.EmitTraces(ctx, rs, td)
+	}
+	fault := e.pickFault()
+	if fault == "" {
+		return e.next.EmitTraces(ctx, rs, td)
+	}
+
+	bad := ptrace.NewTraces()
+	td.CopyTo(bad)
+	switch fault {
+	case FaultBadUTF8:
+		for _, rspan := range bad.ResourceSpans().All() {
+			rspan.Resource().Attributes().PutStr("flutter.fault", badUTF8)
+		}
+	case FaultZeroTimestamp:
+		for _, rspan := range bad.ResourceSpans().All() {
+			for _, ss := range rspan.ScopeSpans().All() {
+				for _, span := range ss.Spans().All() {
+					span.SetStartTimestamp(0)
+					span.SetEndTimestamp(0)
+				}
+			}
+		}
+	case FaultOversized:
+		orig := bad.ResourceSpans()
+		n := orig.Len()
+		for range e.oversizeFactor - 1 {
+			for i := range n {
+				orig.At(i).CopyTo(orig.AppendEmpty())
+			}
+		}
+	}
+	slog.Debug("Injecting trace fault", "fault", fault, "tick", rs.Tick)
+	return e.next.EmitTraces(ctx, rs, bad)
+}
+
+// EmitLogs passes logs through unchanged; faults are only injected
+// into metrics and traces.
+func (e *FaultEmitter) EmitLogs(ctx context.Context, rs *state.RunState, ld plog.Logs) error {
+	return EmitLogs(ctx, e.next, rs, ld)
+}
+
+func zeroMetricTimestamps(md pmetric.Metrics) {
+	for _, rm := range md.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					for _, dp := range m.Gauge().DataPoints().All() {
+						dp.SetStartTimestamp(0)
+						dp.SetTimestamp(0)
+					}
+				case pmetric.MetricTypeSum:
+					for _, dp := range m.Sum().DataPoints().All() {
+						dp.SetStartTimestamp(0)
+						dp.SetTimestamp(0)
+					}
+				case pmetric.MetricTypeHistogram:
+					for _, dp := range m.Histogram().DataPoints().All() {
+						dp.SetStartTimestamp(0)
+						dp.SetTimestamp(0)
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					for _, dp := range m.ExponentialHistogram().DataPoints().All() {
+						dp.SetStartTimestamp(0)
+						dp.SetTimestamp(0)
+					}
+				case pmetric.MetricTypeSummary:
+					for _, dp := range m.Summary().DataPoints().All() {
+						dp.SetStartTimestamp(0)
+						dp.SetTimestamp(0)
+					}
+				case pmetric.MetricTypeEmpty:
+				}
+			}
+		}
+	}
+}
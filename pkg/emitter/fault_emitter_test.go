@@ -0,0 +1,115 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type captureEmitter struct {
+	metrics []pmetric.Metrics
+	traces  []ptrace.Traces
+}
+
+func (c *captureEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	c.metrics = append(c.metrics, md)
+	return nil
+}
+
+func (c *captureEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	c.traces = append(c.traces, td)
+	return nil
+}
+
+func makeTestMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "test")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("test.metric")
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1000, 0)))
+	dp.SetDoubleValue(1)
+	return md
+}
+
+func TestNewFaultEmitter(t *testing.T) {
+	_, err := NewFaultEmitter(&captureEmitter{}, config.FaultInjection{Probability: 1.5}, 1)
+	assert.Error(t, err)
+
+	_, err = NewFaultEmitter(&captureEmitter{}, config.FaultInjection{Probability: 0.5, Modes: []string{"bogus"}}, 1)
+	assert.Error(t, err)
+
+	e, err := NewFaultEmitter(&captureEmitter{}, config.FaultInjection{Probability: 0.5}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, validFaultModes, e.modes)
+	assert.Equal(t, DefaultOversizeFactor, e.oversizeFactor)
+}
+
+func TestFaultEmitter_EmitMetrics(t *testing.T) {
+	rs := &state.RunState{}
+
+	t.Run("zero probability passes through", func(t *testing.T) {
+		capture := &captureEmitter{}
+		e, err := NewFaultEmitter(capture, config.FaultInjection{Probability: 0}, 1)
+		require.NoError(t, err)
+		md := makeTestMetrics()
+		require.NoError(t, e.EmitMetrics(context.Background(), rs, md))
+		require.Len(t, capture.metrics, 1)
+		assert.Equal(t, md, capture.metrics[0])
+	})
+
+	t.Run("badUTF8", func(t *testing.T) {
+		capture := &captureEmitter{}
+		e, err := NewFaultEmitter(capture, config.FaultInjection{Probability: 1, Modes: []string{FaultBadUTF8}}, 1)
+		require.NoError(t, err)
+		md := makeTestMetrics()
+		require.NoError(t, e.EmitMetrics(context.Background(), rs, md))
+		require.Len(t, capture.metrics, 1)
+		v, ok := capture.metrics[0].ResourceMetrics().At(0).Resource().Attributes().Get("flutter.fault")
+		require.True(t, ok)
+		assert.Equal(t, badUTF8, v.Str())
+		_, ok = md.ResourceMetrics().At(0).Resource().Attributes().Get("flutter.fault")
+		assert.False(t, ok, "original payload must not be modified")
+	})
+
+	t.Run("zeroTimestamp", func(t *testing.T) {
+		capture := &captureEmitter{}
+		e, err := NewFaultEmitter(capture, config.FaultInjection{Probability: 1, Modes: []string{FaultZeroTimestamp}}, 1)
+		require.NoError(t, err)
+		require.NoError(t, e.EmitMetrics(context.Background(), rs, makeTestMetrics()))
+		dp := capture.metrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+		assert.Equal(t, pcommon.Timestamp(0), dp.Timestamp())
+	})
+
+	t.Run("oversized", func(t *testing.T) {
+		capture := &captureEmitter{}
+		e, err := NewFaultEmitter(capture, config.FaultInjection{Probability: 1, Modes: []string{FaultOversized}, OversizeFactor: 5}, 1)
+		require.NoError(t, err)
+		require.NoError(t, e.EmitMetrics(context.Background(), rs, makeTestMetrics()))
+		assert.Equal(t, 5, capture.metrics[0].DataPointCount())
+	})
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// MetricRow is one gauge or sum datapoint, flattened for emitters
+// that write to tabular or line-oriented destinations.
+type MetricRow struct {
+	Timestamp          time.Time
+	Name               string
+	Type               string
+	Unit               string
+	Value              float64
+	ResourceAttributes map[string]string
+	Attributes         map[string]string
+}
+
+// SpanRow is one span, flattened for tabular destinations.
+type SpanRow struct {
+	Timestamp          time.Time
+	TraceID            string
+	SpanID             string
+	ParentSpanID       string
+	Name               string
+	Kind               string
+	ServiceName        string
+	Duration           time.Duration
+	StatusCode         string
+	StatusMessage      string
+	ResourceAttributes map[string]string
+	Attributes         map[string]string
+}
+
+// FlattenMetrics returns a row for every gauge and sum datapoint.
+// Other metric types are skipped.
+func FlattenMetrics(md pmetric.Metrics) []MetricRow {
+	rows := make([]MetricRow, 0, md.DataPointCount())
+	for _, rm := range md.ResourceMetrics().All() {
+		rattr := attrStrings(rm.Resource().Attributes())
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				var dps pmetric.NumberDataPointSlice
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					dps = m.Gauge().DataPoints()
+				case pmetric.MetricTypeSum:
+					dps = m.Sum().DataPoints()
+				default:
+					continue
+				}
+				for _, dp := range dps.All() {
+					rows = append(rows, MetricRow{
+						Timestamp:          dp.Timestamp().AsTime().UTC(),
+						Name:               m.Name(),
+						Type:               m.Type().String(),
+						Unit:               m.Unit(),
+						Value:              numberValue(dp),
+						ResourceAttributes: rattr,
+						Attributes:         attrStrings(dp.Attributes()),
+					})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// FlattenTraces returns a row for every span.
+func FlattenTraces(td ptrace.Traces) []SpanRow {
+	rows := make([]SpanRow, 0, td.SpanCount())
+	for _, rspan := range td.ResourceSpans().All() {
+		rattr := attrStrings(rspan.Resource().Attributes())
+		for _, ss := range rspan.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				start := span.StartTimestamp().AsTime()
+				rows = append(rows, SpanRow{
+					Timestamp:          start.UTC(),
+					TraceID:            span.TraceID().String(),
+					SpanID:             span.SpanID().String(),
+					ParentSpanID:       span.ParentSpanID().String(),
+					Name:               span.Name(),
+					Kind:               span.Kind().String(),
+					ServiceName:        rattr["service.name"],
+					Duration:           span.EndTimestamp().AsTime().Sub(start),
+					StatusCode:         span.Status().Code().String(),
+					StatusMessage:      span.Status().Message(),
+					ResourceAttributes: rattr,
+					Attributes:         attrStrings(span.Attributes()),
+				})
+			}
+		}
+	}
+	return rows
+}
+
+func numberValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+func attrStrings(m pcommon.Map) map[string]string {
+	ret := make(map[string]string, m.Len())
+	for k, v := range m.All() {
+		ret[k] = v.AsString()
+	}
+	return ret
+}
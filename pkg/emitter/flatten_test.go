@@ -0,0 +1,119 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// makeMixedMetrics returns a double gauge, an int sum, and a histogram.
+func makeMixedMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	g := ms.AppendEmpty()
+	g.SetName("cpu")
+	g.SetUnit("%")
+	dp := g.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1000, 0)))
+	dp.SetDoubleValue(42.5)
+	dp.Attributes().PutStr("host", "a")
+
+	s := ms.AppendEmpty()
+	s.SetName("requests")
+	s.SetUnit("1")
+	sdp := s.SetEmptySum().DataPoints().AppendEmpty()
+	sdp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1000, 5)))
+	sdp.SetIntValue(7)
+	sdp.Attributes().PutInt("code", 200)
+
+	h := ms.AppendEmpty()
+	h.SetName("latency")
+	hdp := h.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1000, 0)))
+	hdp.SetCount(3)
+	hdp.SetSum(1.5)
+	return md
+}
+
+// makeSpanTraces returns one server span with a parent and an error
+// status.
+func makeSpanTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	rspan := td.ResourceSpans().AppendEmpty()
+	rspan.Resource().Attributes().PutStr("service.name", "api")
+	span := rspan.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /")
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetParentSpanID(pcommon.SpanID{8, 7, 6, 5, 4, 3, 2, 1})
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Unix(1000, 0)))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(time.Unix(1000, 0).Add(25 * time.Millisecond)))
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Status().SetMessage("boom")
+	span.Attributes().PutStr("http.route", "/")
+	return td
+}
+
+func TestFlattenMetrics(t *testing.T) {
+	rattr := map[string]string{"service.name": "api"}
+	// The histogram has no single value, so it is skipped.
+	assert.Equal(t, []MetricRow{
+		{
+			Timestamp:          time.Unix(1000, 0).UTC(),
+			Name:               "cpu",
+			Type:               "Gauge",
+			Unit:               "%",
+			Value:              42.5,
+			ResourceAttributes: rattr,
+			Attributes:         map[string]string{"host": "a"},
+		},
+		{
+			Timestamp:          time.Unix(1000, 5).UTC(),
+			Name:               "requests",
+			Type:               "Sum",
+			Unit:               "1",
+			Value:              7,
+			ResourceAttributes: rattr,
+			Attributes:         map[string]string{"code": "200"},
+		},
+	}, FlattenMetrics(makeMixedMetrics()))
+}
+
+func TestFlattenTraces(t *testing.T) {
+	assert.Equal(t, []SpanRow{{
+		Timestamp:          time.Unix(1000, 0).UTC(),
+		TraceID:            "0102030405060708090a0b0c0d0e0f10",
+		SpanID:             "0102030405060708",
+		ParentSpanID:       "0807060504030201",
+		Name:               "GET /",
+		Kind:               "Server",
+		ServiceName:        "api",
+		Duration:           25 * time.Millisecond,
+		StatusCode:         "Error",
+		StatusMessage:      "boom",
+		ResourceAttributes: map[string]string{"service.name": "api"},
+		Attributes:         map[string]string{"http.route": "/"},
+	}}, FlattenTraces(makeSpanTraces()))
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+)
+
+// NewHTTPClient returns a client and base URL for endpoint.  Most
+// endpoints are returned unchanged with a plain client.  Endpoints of
+// the form unix:///path/to.sock, or unix:@name for a Linux abstract
+// socket, return a client that dials that socket and a base URL of
+// http://localhost, for agents and sidecars that only listen locally.
+// Any non-zero opts are applied to the client's transport.
+func NewHTTPClient(endpoint string, timeout time.Duration, opts config.HTTPTransport) (*http.Client, string, error) {
+	if !strings.HasPrefix(endpoint, "unix:") {
+		client := &http.Client{Timeout: timeout}
+		if opts != (config.HTTPTransport{}) {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			if opts.KeepAlive != 0 {
+				d := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: opts.KeepAlive}
+				transport.DialContext = d.DialContext
+			}
+			tuneTransport(transport, opts)
+			client.Transport = transport
+		}
+		return client, endpoint, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid unix socket endpoint %q: %w", endpoint, err)
+	}
+	socket := u.Opaque
+	if socket == "" {
+		socket = u.Path
+	}
+	if u.Host != "" || socket == "" {
+		return nil, "", fmt.Errorf("invalid unix socket endpoint %q: expected unix:///path or unix:@name", endpoint)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socket)
+	}
+	tuneTransport(transport, opts)
+	return &http.Client{Timeout: timeout, Transport: transport}, "http://localhost", nil
+}
+
+// tuneTransport applies the connection settings in opts other than
+// KeepAlive, which belongs to the dialer.
+func tuneTransport(t *http.Transport, opts config.HTTPTransport) {
+	if opts.ForceHTTP2 {
+		var p http.Protocols
+		p.SetHTTP2(true)
+		p.SetUnencryptedHTTP2(true)
+		t.Protocols = &p
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.PingInterval > 0 || opts.PingTimeout > 0 {
+		t.HTTP2 = &http.HTTP2Config{
+			SendPingTimeout: opts.PingInterval,
+			PingTimeout:     opts.PingTimeout,
+		}
+	}
+}
+
+// ConnStats counts the connections a client's requests were sent on,
+// so it shows whether they reuse pooled connections or pay for a new
+// handshake each time.
+type ConnStats struct {
+	next   http.RoundTripper
+	opened atomic.Int64
+	reused atomic.Int64
+}
+
+// TrackConns wraps client's transport to count its connections.
+func TrackConns(client *http.Client) *ConnStats {
+	s := &ConnStats{next: client.Transport}
+	if s.next == nil {
+		s.next = http.DefaultTransport
+	}
+	client.Transport = s
+	return s
+}
+
+func (s *ConnStats) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				s.reused.Add(1)
+			} else {
+				s.opened.Add(1)
+			}
+		},
+	}
+	return s.next.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
+
+// Counts returns how many requests opened a new connection and how
+// many reused one.
+func (s *ConnStats) Counts() (opened, reused int64) {
+	return s.opened.Load(), s.reused.Load()
+}
+
+// postBody POSTs body to url and returns the response body.  Non-2xx
+// responses are returned as errors that include the response body, since
+// that is usually where a backend explains why it rejected the data.
+func postBody(ctx context.Context, client *http.Client, url, contentType string, headers map[string]string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return respBody, fmt.Errorf("non-2xx response from %s: %s: %s", url, resp.Status, string(respBody))
+	}
+	return respBody, nil
+}
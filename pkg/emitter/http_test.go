@@ -0,0 +1,114 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	client, endpoint, err := NewHTTPClient("https://example.com:4318", time.Second, config.HTTPTransport{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com:4318", endpoint)
+	assert.Nil(t, client.Transport)
+	assert.Equal(t, time.Second, client.Timeout)
+
+	_, endpoint, err = NewHTTPClient("unix:@otelcol", time.Second, config.HTTPTransport{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost", endpoint)
+
+	_, _, err = NewHTTPClient("unix://host/otelcol.sock", time.Second, config.HTTPTransport{})
+	assert.Error(t, err)
+	_, _, err = NewHTTPClient("unix://", time.Second, config.HTTPTransport{})
+	assert.Error(t, err)
+}
+
+func TestNewHTTPClient_UnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "otelcol.sock")
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	var gotPath string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.Listener = ln
+	srv.Start()
+	defer srv.Close()
+
+	client, endpoint, err := NewHTTPClient("unix://"+socket, time.Second, config.HTTPTransport{})
+	require.NoError(t, err)
+	e, err := NewOTLPEmitter(client, endpoint, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics()))
+	assert.Equal(t, "/v1/metrics", gotPath)
+}
+
+func TestNewHTTPClient_Transport(t *testing.T) {
+	opts := config.HTTPTransport{
+		MaxIdleConnsPerHost: 32,
+		IdleConnTimeout:     time.Minute,
+		PingInterval:        15 * time.Second,
+		PingTimeout:         5 * time.Second,
+	}
+	client, _, err := NewHTTPClient("https://example.com:4318", time.Second, opts)
+	require.NoError(t, err)
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 32, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.Equal(t, 15*time.Second, transport.HTTP2.SendPingTimeout)
+	assert.Equal(t, 5*time.Second, transport.HTTP2.PingTimeout)
+}
+
+func TestNewHTTPClient_ForceHTTP2(t *testing.T) {
+	var protos []string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protos = append(protos, r.Proto)
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.Config.Protocols = &http.Protocols{}
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	defer srv.Close()
+
+	client, endpoint, err := NewHTTPClient(srv.URL, time.Second, config.HTTPTransport{ForceHTTP2: true})
+	require.NoError(t, err)
+	stats := TrackConns(client)
+	e, err := NewOTLPEmitter(client, endpoint, nil)
+	require.NoError(t, err)
+	for range 3 {
+		require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics()))
+	}
+	assert.Equal(t, []string{"HTTP/2.0", "HTTP/2.0", "HTTP/2.0"}, protos)
+
+	opened, reused := stats.Counts()
+	assert.Equal(t, int64(1), opened)
+	assert.Equal(t, int64(2), reused)
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Tag strategies for the Influx emitter, selecting which attributes
+// become tags.
+const (
+	InfluxTagsAll       = "all"
+	InfluxTagsDatapoint = "datapoint"
+	InfluxTagsResource  = "resource"
+)
+
+// InfluxEmitter writes datapoints in InfluxDB line protocol, one
+// measurement per metric with a single "value" field.  It supports the
+// v1 /write API (also accepted by Telegraf's influxdb_listener) and the
+// v2 /api/v2/write API.  Traces are not sent.
+type InfluxEmitter struct {
+	client      *http.Client
+	url         string
+	headers     map[string]string
+	tagStrategy string
+}
+
+var _ Emitter = (*InfluxEmitter)(nil)
+
+// InfluxOptions holds the connection settings for NewInfluxEmitter.
+// Database, Username, and Password apply to version 1; Org, Bucket,
+// and Token apply to version 2.
+type InfluxOptions struct {
+	Endpoint    string
+	Version     int
+	Database    string
+	Username    string
+	Password    string
+	Org         string
+	Bucket      string
+	Token       string
+	TagStrategy string
+}
+
+func NewInfluxEmitter(client *http.Client, opts InfluxOptions) (*InfluxEmitter, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	switch opts.TagStrategy {
+	case "":
+		opts.TagStrategy = InfluxTagsAll
+	case InfluxTagsAll, InfluxTagsDatapoint, InfluxTagsResource:
+	default:
+		return nil, fmt.Errorf("influx: unknown tagStrategy %q", opts.TagStrategy)
+	}
+
+	endpoint := strings.TrimRight(opts.Endpoint, "/")
+	query := url.Values{}
+	query.Set("precision", "ns")
+	headers := map[string]string{}
+	switch opts.Version {
+	case 0, 1:
+		if opts.Database == "" {
+			return nil, errors.New("influx: database is required for version 1")
+		}
+		query.Set("db", opts.Database)
+		endpoint += "/write"
+		if opts.Username != "" {
+			query.Set("u", opts.Username)
+			query.Set("p", opts.Password)
+		}
+	case 2:
+		if opts.Org == "" || opts.Bucket == "" {
+			return nil, errors.New("influx: org and bucket are required for version 2")
+		}
+		query.Set("org", opts.Org)
+		query.Set("bucket", opts.Bucket)
+		endpoint += "/api/v2/write"
+		if opts.Token != "" {
+			headers["Authorization"] = "Token " + opts.Token
+		}
+	default:
+		return nil, fmt.Errorf("influx: unsupported version %d", opts.Version)
+	}
+
+	return &InfluxEmitter{
+		client:      client,
+		url:         endpoint + "?" + query.Encode(),
+		headers:     headers,
+		tagStrategy: opts.TagStrategy,
+	}, nil
+}
+
+func (e *InfluxEmitter) EmitMetrics(ctx context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	skipped := 0
+	for _, row := range FlattenMetrics(md) {
+		if !e.writeLine(&buf, row) {
+			skipped++
+		}
+	}
+	if skipped > 0 {
+		slog.Warn("Skipping non-finite values, Influx cannot store them", "count", skipped)
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	if _, err := postBody(ctx, e.client, e.url, "text/plain; charset=utf-8", e.headers, buf.Bytes()); err != nil {
+		return fmt.Errorf("influx write failed: %w", err)
+	}
+	return nil
+}
+
+func (e *InfluxEmitter) EmitTraces(_ context.Context, _ *state.RunState, _ ptrace.Traces) error {
+	return nil
+}
+
+// writeLine appends row to buf in line protocol.  It reports false and
+// writes nothing when the value is NaN or infinite, which line protocol
+// has no representation for.
+func (e *InfluxEmitter) writeLine(buf *bytes.Buffer, row MetricRow) bool {
+	if math.IsNaN(row.Value) || math.IsInf(row.Value, 0) {
+		return false
+	}
+	tags := map[string]string{}
+	if e.tagStrategy != InfluxTagsDatapoint {
+		maps.Copy(tags, row.ResourceAttributes)
+	}
+	if e.tagStrategy != InfluxTagsResource {
+		maps.Copy(tags, row.Attributes)
+	}
+
+	buf.WriteString(influxMeasurementEscaper.Replace(row.Name))
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		// Influx rejects empty tag values.
+		if tags[k] == "" {
+			continue
+		}
+		buf.WriteByte(',')
+		buf.WriteString(influxTagEscaper.Replace(k))
+		buf.WriteByte('=')
+		buf.WriteString(influxTagEscaper.Replace(tags[k]))
+	}
+	buf.WriteString(" value=")
+	buf.WriteString(strconv.FormatFloat(row.Value, 'g', -1, 64))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(row.Timestamp.UnixNano(), 10))
+	buf.WriteByte('\n')
+	return true
+}
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	influxTagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
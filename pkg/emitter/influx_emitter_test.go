@@ -0,0 +1,107 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestNewInfluxEmitter(t *testing.T) {
+	e, err := NewInfluxEmitter(nil, InfluxOptions{Endpoint: "http://localhost:8086/", Database: "sim", Username: "u", Password: "p"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8086/write?db=sim&p=p&precision=ns&u=u", e.url)
+	assert.Empty(t, e.headers)
+
+	e, err = NewInfluxEmitter(nil, InfluxOptions{Endpoint: "http://localhost:8086", Version: 2, Org: "acme", Bucket: "sim", Token: "tok"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8086/api/v2/write?bucket=sim&org=acme&precision=ns", e.url)
+	assert.Equal(t, "Token tok", e.headers["Authorization"])
+
+	_, err = NewInfluxEmitter(nil, InfluxOptions{Version: 1})
+	assert.Error(t, err)
+	_, err = NewInfluxEmitter(nil, InfluxOptions{Version: 2, Org: "acme"})
+	assert.Error(t, err)
+	_, err = NewInfluxEmitter(nil, InfluxOptions{Version: 3})
+	assert.Error(t, err)
+	_, err = NewInfluxEmitter(nil, InfluxOptions{Database: "sim", TagStrategy: "bogus"})
+	assert.Error(t, err)
+}
+
+func TestInfluxEmitter_writeLine(t *testing.T) {
+	row := MetricRow{
+		Timestamp:          time.Unix(1000, 5),
+		Name:               "http requests",
+		Value:              1.5,
+		ResourceAttributes: map[string]string{"service.name": "api,v1", "empty": ""},
+		Attributes:         map[string]string{"route": "/a b", "k=": "x"},
+	}
+	tests := []struct {
+		strategy string
+		want     string
+	}{
+		{InfluxTagsAll, `http\ requests,k\==x,route=/a\ b,service.name=api\,v1 value=1.5 1000000000005` + "\n"},
+		{InfluxTagsDatapoint, `http\ requests,k\==x,route=/a\ b value=1.5 1000000000005` + "\n"},
+		{InfluxTagsResource, `http\ requests,service.name=api\,v1 value=1.5 1000000000005` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			e := &InfluxEmitter{tagStrategy: tt.strategy}
+			var buf bytes.Buffer
+			assert.True(t, e.writeLine(&buf, row))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		e := &InfluxEmitter{tagStrategy: InfluxTagsAll}
+		var buf bytes.Buffer
+		assert.False(t, e.writeLine(&buf, MetricRow{Timestamp: row.Timestamp, Name: "bad", Value: v}))
+		assert.Empty(t, buf.String(), "non-finite value %v", v)
+	}
+}
+
+func TestInfluxEmitter_EmitMetrics(t *testing.T) {
+	var gotBody string
+	posts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	e, err := NewInfluxEmitter(srv.Client(), InfluxOptions{Endpoint: srv.URL, Database: "sim"})
+	require.NoError(t, err)
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics()))
+	assert.Equal(t, "test.metric,service.name=test value=1 1000000000000\n", gotBody)
+
+	nan := makeTestMetrics()
+	nan.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).SetDoubleValue(math.NaN())
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, nan))
+	assert.Equal(t, 1, posts, "a batch of only non-finite values is not sent")
+}
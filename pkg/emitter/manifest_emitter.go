@@ -0,0 +1,218 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Manifest lists every series and trace resource a run wrote, with the
+// time range of its data, so cleanup jobs can purge it from shared
+// backends.
+type Manifest struct {
+	RunID   string           `json:"runID,omitempty"`
+	Start   time.Time        `json:"start"`
+	End     time.Time        `json:"end"`
+	Metrics []ManifestSeries `json:"metrics"`
+	Traces  []ManifestTraces `json:"traces"`
+}
+
+// ManifestSeries is one metric name, type and attribute combination.
+type ManifestSeries struct {
+	Name               string            `json:"name"`
+	Type               string            `json:"type"`
+	ResourceAttributes map[string]string `json:"resourceAttributes"`
+	Attributes         map[string]string `json:"attributes"`
+	First              time.Time         `json:"first"`
+	Last               time.Time         `json:"last"`
+	Datapoints         int               `json:"datapoints"`
+}
+
+// ManifestTraces is the spans written for one resource.
+type ManifestTraces struct {
+	ResourceAttributes map[string]string `json:"resourceAttributes"`
+	First              time.Time         `json:"first"`
+	Last               time.Time         `json:"last"`
+	Spans              int               `json:"spans"`
+}
+
+// ManifestEmitter records what is emitted and writes a Manifest as JSON
+// to a file when the run ends.
+type ManifestEmitter struct {
+	path   string
+	series map[string]*ManifestSeries
+	traces map[string]*ManifestTraces
+}
+
+var (
+	_ Emitter = (*ManifestEmitter)(nil)
+	_ Flusher = (*ManifestEmitter)(nil)
+)
+
+func NewManifestEmitter(path string) *ManifestEmitter {
+	return &ManifestEmitter{
+		path:   path,
+		series: map[string]*ManifestSeries{},
+		traces: map[string]*ManifestTraces{},
+	}
+}
+
+func (e *ManifestEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	for _, rm := range md.ResourceMetrics().All() {
+		rattr := attrStrings(rm.Resource().Attributes())
+		rkey := attrKey(rattr)
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				forEachDatapoint(m, func(attrs pcommon.Map, ts pcommon.Timestamp) {
+					dattr := attrStrings(attrs)
+					key := strings.Join([]string{m.Name(), m.Type().String(), rkey, attrKey(dattr)}, "\x01")
+					s, ok := e.series[key]
+					if !ok {
+						s = &ManifestSeries{
+							Name:               m.Name(),
+							Type:               m.Type().String(),
+							ResourceAttributes: rattr,
+							Attributes:         dattr,
+						}
+						e.series[key] = s
+					}
+					s.First, s.Last = widen(s.First, s.Last, ts.AsTime().UTC(), s.Datapoints == 0)
+					s.Datapoints++
+				})
+			}
+		}
+	}
+	return nil
+}
+
+func (e *ManifestEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	for _, rspan := range td.ResourceSpans().All() {
+		rattr := attrStrings(rspan.Resource().Attributes())
+		key := attrKey(rattr)
+		for _, ss := range rspan.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				r, ok := e.traces[key]
+				if !ok {
+					r = &ManifestTraces{ResourceAttributes: rattr}
+					e.traces[key] = r
+				}
+				r.First, r.Last = widen(r.First, r.Last, span.StartTimestamp().AsTime().UTC(), r.Spans == 0)
+				r.Spans++
+			}
+		}
+	}
+	return nil
+}
+
+// Flush writes the manifest, with series and resources in key order so
+// the file is stable across identical runs.
+func (e *ManifestEmitter) Flush(_ context.Context, rs *state.RunState) error {
+	m := Manifest{
+		RunID:   rs.RunID,
+		Metrics: []ManifestSeries{},
+		Traces:  []ManifestTraces{},
+	}
+	empty := true
+	include := func(first, last time.Time) {
+		m.Start, m.End = widen(m.Start, m.End, first, empty)
+		m.Start, m.End = widen(m.Start, m.End, last, false)
+		empty = false
+	}
+	for _, key := range slices.Sorted(maps.Keys(e.series)) {
+		s := e.series[key]
+		m.Metrics = append(m.Metrics, *s)
+		include(s.First, s.Last)
+	}
+	for _, key := range slices.Sorted(maps.Keys(e.traces)) {
+		r := e.traces[key]
+		m.Traces = append(m.Traces, *r)
+		include(r.First, r.Last)
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+	if err := os.WriteFile(e.path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	return nil
+}
+
+// widen extends [first, last] to include t, or starts the range at t.
+func widen(first, last, t time.Time, empty bool) (time.Time, time.Time) {
+	if empty {
+		return t, t
+	}
+	if t.Before(first) {
+		first = t
+	}
+	if t.After(last) {
+		last = t
+	}
+	return first, last
+}
+
+// attrKey is a canonical string for a set of attributes.
+func attrKey(attrs map[string]string) string {
+	var b strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(attrs[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// forEachDatapoint calls f with the attributes and timestamp of every
+// datapoint of any metric type.
+func forEachDatapoint(m pmetric.Metric, f func(attrs pcommon.Map, ts pcommon.Timestamp)) {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		for _, dp := range m.Gauge().DataPoints().All() {
+			f(dp.Attributes(), dp.Timestamp())
+		}
+	case pmetric.MetricTypeSum:
+		for _, dp := range m.Sum().DataPoints().All() {
+			f(dp.Attributes(), dp.Timestamp())
+		}
+	case pmetric.MetricTypeHistogram:
+		for _, dp := range m.Histogram().DataPoints().All() {
+			f(dp.Attributes(), dp.Timestamp())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		for _, dp := range m.ExponentialHistogram().DataPoints().All() {
+			f(dp.Attributes(), dp.Timestamp())
+		}
+	case pmetric.MetricTypeSummary:
+		for _, dp := range m.Summary().DataPoints().All() {
+			f(dp.Attributes(), dp.Timestamp())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestManifestEmitter(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{RunID: "run-1"}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	e := NewManifestEmitter(path)
+
+	md := makeTestMetrics()
+	require.NoError(t, e.EmitMetrics(ctx, rs, md))
+	md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).
+		Gauge().DataPoints().At(0).SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1060, 0)))
+	require.NoError(t, e.EmitMetrics(ctx, rs, md))
+
+	td := ptrace.NewTraces()
+	rspan := td.ResourceSpans().AppendEmpty()
+	rspan.Resource().Attributes().PutStr("service.name", "checkout")
+	span := rspan.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Unix(1030, 0)))
+	require.NoError(t, e.EmitTraces(ctx, rs, td))
+
+	require.NoError(t, e.Flush(ctx, rs))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var m Manifest
+	require.NoError(t, json.Unmarshal(b, &m))
+
+	assert.Equal(t, "run-1", m.RunID)
+	assert.Equal(t, time.Unix(1000, 0).UTC(), m.Start)
+	assert.Equal(t, time.Unix(1060, 0).UTC(), m.End)
+
+	require.Len(t, m.Metrics, 1)
+	s := m.Metrics[0]
+	assert.Equal(t, "test.metric", s.Name)
+	assert.Equal(t, "Gauge", s.Type)
+	assert.Equal(t, map[string]string{"service.name": "test"}, s.ResourceAttributes)
+	assert.Empty(t, s.Attributes)
+	assert.Equal(t, time.Unix(1000, 0).UTC(), s.First)
+	assert.Equal(t, time.Unix(1060, 0).UTC(), s.Last)
+	assert.Equal(t, 2, s.Datapoints)
+
+	require.Len(t, m.Traces, 1)
+	assert.Equal(t, map[string]string{"service.name": "checkout"}, m.Traces[0].ResourceAttributes)
+	assert.Equal(t, 1, m.Traces[0].Spans)
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// NullEmitter discards everything it is given, for benchmarking
+// generation without any output cost.
+type NullEmitter struct{}
+
+var _ Emitter = NullEmitter{}
+
+func NewNullEmitter() NullEmitter {
+	return NullEmitter{}
+}
+
+func (NullEmitter) EmitMetrics(_ context.Context, _ *state.RunState, _ pmetric.Metrics) error {
+	return nil
+}
+
+func (NullEmitter) EmitTraces(_ context.Context, _ *state.RunState, _ ptrace.Traces) error {
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	"github.com/cardinalhq/flutter/pkg/compression"
+	"github.com/cardinalhq/flutter/pkg/objectstore"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+const (
+	// DefaultObjectMaxBytes is the uncompressed batch size that
+	// triggers an upload.
+	DefaultObjectMaxBytes = 8 * 1024 * 1024
+	// DefaultObjectInterval is the simulated time after which a
+	// non-empty batch is uploaded regardless of size.
+	DefaultObjectInterval = 5 * time.Minute
+)
+
+// ObjectStoreEmitter accumulates metrics and traces and uploads each
+// batch as a gzip-compressed OTLP protobuf export request.  A batch is
+// uploaded when it reaches maxBytes, when interval of simulated time
+// has passed since it was started, or when the run ends.
+//
+// Objects are written to <prefix>/<signal>/YYYY/MM/DD/HH/<start>-<seq>.binpb.gz,
+// where the time is the simulated wallclock at the start of the batch.
+type ObjectStoreEmitter struct {
+	uploader objectstore.Uploader
+	prefix   string
+	maxBytes int
+	interval time.Duration
+	seq      int
+
+	metrics      pmetric.Metrics
+	metricsBytes int
+	metricsStart time.Time
+
+	traces      ptrace.Traces
+	tracesBytes int
+	tracesStart time.Time
+}
+
+var (
+	_ Emitter = (*ObjectStoreEmitter)(nil)
+	_ Flusher = (*ObjectStoreEmitter)(nil)
+)
+
+func NewObjectStoreEmitter(uploader objectstore.Uploader, prefix string, maxBytes int, interval time.Duration) *ObjectStoreEmitter {
+	if maxBytes <= 0 {
+		maxBytes = DefaultObjectMaxBytes
+	}
+	if interval <= 0 {
+		interval = DefaultObjectInterval
+	}
+	return &ObjectStoreEmitter{
+		uploader: uploader,
+		prefix:   prefix,
+		maxBytes: maxBytes,
+		interval: interval,
+		metrics:  pmetric.NewMetrics(),
+		traces:   ptrace.NewTraces(),
+	}
+}
+
+func (e *ObjectStoreEmitter) EmitMetrics(ctx context.Context, rs *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() > 0 {
+		if e.metrics.ResourceMetrics().Len() == 0 {
+			e.metricsStart = rs.Wallclock
+		}
+		for _, rm := range md.ResourceMetrics().All() {
+			rm.CopyTo(e.metrics.ResourceMetrics().AppendEmpty())
+		}
+		e.metricsBytes += (&pmetric.ProtoMarshaler{}).MetricsSize(md)
+	}
+	if e.metrics.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+	if e.metricsBytes >= e.maxBytes || rs.Wallclock.Sub(e.metricsStart) >= e.interval {
+		return e.flushMetrics(ctx)
+	}
+	return nil
+}
+
+func (e *ObjectStoreEmitter) EmitTraces(ctx context.Context, rs *state.RunState, td ptrace.Traces) error {
+	if td.SpanCount() > 0 {
+		if e.traces.ResourceSpans().Len() == 0 {
+			e.tracesStart = rs.Wallclock
+		}
+		for _, rspan := range td.ResourceSpans().All() {
+			rspan.CopyTo(e.traces.ResourceSpans().AppendEmpty())
+		}
+		e.tracesBytes += (&ptrace.ProtoMarshaler{}).TracesSize(td)
+	}
+	if e.traces.ResourceSpans().Len() == 0 {
+		return nil
+	}
+	if e.tracesBytes >= e.maxBytes || rs.Wallclock.Sub(e.tracesStart) >= e.interval {
+		return e.flushTraces(ctx)
+	}
+	return nil
+}
+
+func (e *ObjectStoreEmitter) Flush(ctx context.Context, _ *state.RunState) error {
+	if err := e.flushMetrics(ctx); err != nil {
+		return err
+	}
+	return e.flushTraces(ctx)
+}
+
+func (e *ObjectStoreEmitter) flushMetrics(ctx context.Context) error {
+	if e.metrics.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+	body, err := pmetricotlp.NewExportRequestFromMetrics(e.metrics).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics to protobuf: %w", err)
+	}
+	start := e.metricsStart
+	e.metrics = pmetric.NewMetrics()
+	e.metricsBytes = 0
+	return e.upload(ctx, "metrics", start, body)
+}
+
+func (e *ObjectStoreEmitter) flushTraces(ctx context.Context) error {
+	if e.traces.ResourceSpans().Len() == 0 {
+		return nil
+	}
+	body, err := ptraceotlp.NewExportRequestFromTraces(e.traces).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to marshal traces to protobuf: %w", err)
+	}
+	start := e.tracesStart
+	e.traces = ptrace.NewTraces()
+	e.tracesBytes = 0
+	return e.upload(ctx, "traces", start, body)
+}
+
+func (e *ObjectStoreEmitter) upload(ctx context.Context, signal string, start time.Time, body []byte) error {
+	body, err := compression.GZipBytes(body)
+	if err != nil {
+		return fmt.Errorf("failed to gzip %s: %w", signal, err)
+	}
+	e.seq++
+	start = start.UTC()
+	key := path.Join(e.prefix, signal, start.Format("2006/01/02/15"),
+		fmt.Sprintf("%s-%06d.binpb.gz", start.Format("20060102T150405Z"), e.seq))
+	if err := e.uploader.Upload(ctx, key, "application/gzip", body); err != nil {
+		return fmt.Errorf("failed to upload %s batch %q: %w", signal, key, err)
+	}
+	return nil
+}
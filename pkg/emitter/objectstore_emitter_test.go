@@ -0,0 +1,68 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type fakeUploader struct {
+	keys []string
+}
+
+func (f *fakeUploader) Upload(_ context.Context, key string, _ string, _ []byte) error {
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+func TestObjectStoreEmitter(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("uploads after interval", func(t *testing.T) {
+		up := &fakeUploader{}
+		e := NewObjectStoreEmitter(up, "sim", 0, time.Minute)
+		for i := range 61 {
+			rs := &state.RunState{Wallclock: start.Add(time.Duration(i) * time.Second)}
+			require.NoError(t, e.EmitMetrics(ctx, rs, makeTestMetrics()))
+		}
+		assert.Equal(t, []string{"sim/metrics/2025/01/02/03/20250102T030405Z-000001.binpb.gz"}, up.keys)
+	})
+
+	t.Run("uploads after size", func(t *testing.T) {
+		up := &fakeUploader{}
+		e := NewObjectStoreEmitter(up, "", 1, time.Hour)
+		require.NoError(t, e.EmitMetrics(ctx, &state.RunState{Wallclock: start}, makeTestMetrics()))
+		assert.Len(t, up.keys, 1)
+	})
+
+	t.Run("flush uploads remainder", func(t *testing.T) {
+		up := &fakeUploader{}
+		e := NewObjectStoreEmitter(up, "", 0, time.Hour)
+		require.NoError(t, e.EmitMetrics(ctx, &state.RunState{Wallclock: start}, makeTestMetrics()))
+		assert.Empty(t, up.keys)
+		require.NoError(t, e.Flush(ctx, &state.RunState{}))
+		assert.Len(t, up.keys, 1)
+		require.NoError(t, e.Flush(ctx, &state.RunState{}))
+		assert.Len(t, up.keys, 1, "empty batches are not uploaded")
+	})
+}
@@ -23,6 +23,8 @@ import (
 	"net/http"
 	"strings"
 
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -80,6 +82,22 @@ func (e *OTLPEmitter) EmitTraces(ctx context.Context, rs *state.RunState, td ptr
 	return e.sendRequest(ctx, url, body)
 }
 
+func (e *OTLPEmitter) EmitLogs(ctx context.Context, rs *state.RunState, ld plog.Logs) error {
+	if ld.LogRecordCount() == 0 {
+		return nil
+	}
+
+	req := plogotlp.NewExportRequestFromLogs(ld)
+
+	body, err := req.MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to marshal logs to protobuf: %w", err)
+	}
+
+	url := strings.TrimRight(e.endpoint, "/") + "/v1/logs"
+	return e.sendRequest(ctx, url, body)
+}
+
 func (e *OTLPEmitter) sendRequest(ctx context.Context, url string, body []byte) error {
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
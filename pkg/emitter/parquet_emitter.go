@@ -0,0 +1,189 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type parquetMetricRow struct {
+	Timestamp          int64             `parquet:"timestamp,timestamp(nanosecond)"`
+	MetricName         string            `parquet:"metric_name,dict"`
+	MetricType         string            `parquet:"metric_type,dict"`
+	Unit               string            `parquet:"unit,dict"`
+	Value              float64           `parquet:"value"`
+	ResourceAttributes map[string]string `parquet:"resource_attributes"`
+	Attributes         map[string]string `parquet:"attributes"`
+}
+
+type parquetSpanRow struct {
+	Timestamp          int64             `parquet:"timestamp,timestamp(nanosecond)"`
+	TraceID            string            `parquet:"trace_id"`
+	SpanID             string            `parquet:"span_id"`
+	ParentSpanID       string            `parquet:"parent_span_id"`
+	SpanName           string            `parquet:"span_name,dict"`
+	SpanKind           string            `parquet:"span_kind,dict"`
+	ServiceName        string            `parquet:"service_name,dict"`
+	DurationNs         int64             `parquet:"duration_ns"`
+	StatusCode         string            `parquet:"status_code,dict"`
+	StatusMessage      string            `parquet:"status_message"`
+	ResourceAttributes map[string]string `parquet:"resource_attributes"`
+	Attributes         map[string]string `parquet:"attributes"`
+}
+
+// parquetPartition is one open output file for a signal and hour.
+type parquetPartition[T any] struct {
+	hour   time.Time
+	file   *os.File
+	writer *parquet.GenericWriter[T]
+}
+
+func (p *parquetPartition[T]) close() error {
+	if p.writer == nil {
+		return nil
+	}
+	if err := p.writer.Close(); err != nil {
+		_ = p.file.Close()
+		return err
+	}
+	p.writer = nil
+	return p.file.Close()
+}
+
+// ParquetEmitter writes flattened datapoints and spans to local Parquet
+// files, using Hive-style partitioning by signal and hour of
+// simulated time:
+//
+//	<dir>/signal=metrics/hour=2025-01-02T03/part-000001.parquet
+//
+// so they can be read with DuckDB's read_parquet(..., hive_partitioning = true).
+// Files are only complete once the run ends and Flush closes them.
+type ParquetEmitter struct {
+	dir     string
+	seq     int
+	metrics parquetPartition[parquetMetricRow]
+	traces  parquetPartition[parquetSpanRow]
+}
+
+var (
+	_ Emitter = (*ParquetEmitter)(nil)
+	_ Flusher = (*ParquetEmitter)(nil)
+)
+
+func NewParquetEmitter(dir string) (*ParquetEmitter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create parquet output directory: %w", err)
+	}
+	return &ParquetEmitter{dir: dir}, nil
+}
+
+func openPartition[T any](e *ParquetEmitter, p *parquetPartition[T], signal string, hour time.Time) error {
+	if p.writer != nil && p.hour.Equal(hour) {
+		return nil
+	}
+	if err := p.close(); err != nil {
+		return fmt.Errorf("failed to close parquet file: %w", err)
+	}
+	dir := filepath.Join(e.dir, "signal="+signal, "hour="+hour.Format("2006-01-02T15"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create parquet partition: %w", err)
+	}
+	e.seq++
+	f, err := os.Create(filepath.Join(dir, fmt.Sprintf("part-%06d.parquet", e.seq)))
+	if err != nil {
+		return fmt.Errorf("failed to create parquet file: %w", err)
+	}
+	p.hour = hour
+	p.file = f
+	p.writer = parquet.NewGenericWriter[T](f)
+	return nil
+}
+
+func (e *ParquetEmitter) EmitMetrics(_ context.Context, rs *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	if err := openPartition(e, &e.metrics, "metrics", rs.Wallclock.UTC().Truncate(time.Hour)); err != nil {
+		return err
+	}
+	flat := FlattenMetrics(md)
+	rows := make([]parquetMetricRow, 0, len(flat))
+	for _, row := range flat {
+		rows = append(rows, parquetMetricRow{
+			Timestamp:          row.Timestamp.UnixNano(),
+			MetricName:         row.Name,
+			MetricType:         row.Type,
+			Unit:               row.Unit,
+			Value:              row.Value,
+			ResourceAttributes: row.ResourceAttributes,
+			Attributes:         row.Attributes,
+		})
+	}
+	if _, err := e.metrics.writer.Write(rows); err != nil {
+		return fmt.Errorf("failed to write parquet metrics: %w", err)
+	}
+	return nil
+}
+
+func (e *ParquetEmitter) EmitTraces(_ context.Context, rs *state.RunState, td ptrace.Traces) error {
+	if td.SpanCount() == 0 {
+		return nil
+	}
+	if err := openPartition(e, &e.traces, "traces", rs.Wallclock.UTC().Truncate(time.Hour)); err != nil {
+		return err
+	}
+	flat := FlattenTraces(td)
+	rows := make([]parquetSpanRow, 0, len(flat))
+	for _, row := range flat {
+		rows = append(rows, parquetSpanRow{
+			Timestamp:          row.Timestamp.UnixNano(),
+			TraceID:            row.TraceID,
+			SpanID:             row.SpanID,
+			ParentSpanID:       row.ParentSpanID,
+			SpanName:           row.Name,
+			SpanKind:           row.Kind,
+			ServiceName:        row.ServiceName,
+			DurationNs:         row.Duration.Nanoseconds(),
+			StatusCode:         row.StatusCode,
+			StatusMessage:      row.StatusMessage,
+			ResourceAttributes: row.ResourceAttributes,
+			Attributes:         row.Attributes,
+		})
+	}
+	if _, err := e.traces.writer.Write(rows); err != nil {
+		return fmt.Errorf("failed to write parquet traces: %w", err)
+	}
+	return nil
+}
+
+func (e *ParquetEmitter) Flush(_ context.Context, _ *state.RunState) error {
+	if err := e.metrics.close(); err != nil {
+		return fmt.Errorf("failed to close parquet metrics: %w", err)
+	}
+	if err := e.traces.close(); err != nil {
+		return fmt.Errorf("failed to close parquet traces: %w", err)
+	}
+	return nil
+}
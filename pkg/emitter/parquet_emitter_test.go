@@ -0,0 +1,54 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestParquetEmitter_PartitionsByHour(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	e, err := NewParquetEmitter(dir)
+	require.NoError(t, err)
+
+	start := time.Date(2025, 1, 2, 3, 59, 59, 0, time.UTC)
+	for i := range 2 {
+		rs := &state.RunState{Wallclock: start.Add(time.Duration(i) * time.Second)}
+		require.NoError(t, e.EmitMetrics(ctx, rs, makeTestMetrics()))
+	}
+	require.NoError(t, e.Flush(ctx, &state.RunState{}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "signal=metrics", "hour=*", "*.parquet"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Contains(t, files[0], "hour=2025-01-02T03")
+	assert.Contains(t, files[1], "hour=2025-01-02T04")
+
+	rows, err := parquet.ReadFile[parquetMetricRow](files[0])
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "test.metric", rows[0].MetricName)
+	assert.Equal(t, "test", rows[0].ResourceAttributes["service.name"])
+}
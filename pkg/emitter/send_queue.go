@@ -0,0 +1,105 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+)
+
+// Send queue policies for when a queue is full.
+const (
+	// OnFullBlock waits for room, slowing the tick to the destination.
+	OnFullBlock = "block"
+	// OnFullDrop discards the payload and carries on.
+	OnFullDrop = "drop"
+)
+
+// DefaultSendQueueSize is the queue size when SendQueue.Size is zero.
+const DefaultSendQueueSize = 64
+
+// sendQueue runs a destination's sends, one at a time and in order, on
+// its own goroutine.
+type sendQueue struct {
+	name    string
+	jobs    chan func()
+	drop    bool
+	pending sync.WaitGroup
+	// dropped is only touched by the goroutine calling send.
+	dropped int
+}
+
+func validateSendQueue(name string, q config.SendQueue) error {
+	switch q.OnFull {
+	case "", OnFullBlock, OnFullDrop:
+	default:
+		return fmt.Errorf("%s: unknown onFull policy %q", name, q.OnFull)
+	}
+	if q.Size < 0 {
+		return fmt.Errorf("%s: queue size must not be negative", name)
+	}
+	return nil
+}
+
+func newSendQueue(name string, cfg config.SendQueue) *sendQueue {
+	size := cfg.Size
+	if size == 0 {
+		size = DefaultSendQueueSize
+	}
+	q := &sendQueue{
+		name: name,
+		jobs: make(chan func(), size),
+		drop: cfg.OnFull == OnFullDrop,
+	}
+	go func() {
+		for job := range q.jobs {
+			job()
+			q.pending.Done()
+		}
+	}()
+	return q
+}
+
+// send queues job, or, when the queue is full, waits or drops it.
+func (q *sendQueue) send(job func()) {
+	q.pending.Add(1)
+	if !q.drop {
+		q.jobs <- job
+		return
+	}
+	select {
+	case q.jobs <- job:
+	default:
+		q.pending.Done()
+		q.dropped++
+		if q.dropped == 1 {
+			slog.Warn("Send queue full, dropping payloads", "destination", q.name, "size", cap(q.jobs))
+		}
+	}
+}
+
+// wait returns once every queued job has run.
+func (q *sendQueue) wait() {
+	q.pending.Wait()
+}
+
+// close stops the goroutine after the queued jobs have run.
+func (q *sendQueue) close() {
+	q.wait()
+	close(q.jobs)
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/compression"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// PayloadSizes is the OTLP protobuf volume one destination would have
+// been sent, before and after gzip.
+type PayloadSizes struct {
+	Destination string `json:"destination"`
+	Payloads    int64  `json:"payloads"`
+	Bytes       int64  `json:"bytes"`
+	GzipBytes   int64  `json:"gzipBytes"`
+	// MaxBytes is the largest single payload, uncompressed.
+	MaxBytes int64 `json:"maxBytes"`
+}
+
+// SizeEmitter encodes every payload as an OTLP export request, and
+// gzips it, only to measure it.  Dry runs put one in place of each
+// configured destination so bandwidth can be estimated before going
+// live.  Empty payloads are not counted.
+type SizeEmitter struct {
+	sizes PayloadSizes
+}
+
+var (
+	_ Emitter    = (*SizeEmitter)(nil)
+	_ LogEmitter = (*SizeEmitter)(nil)
+)
+
+func NewSizeEmitter(destination string) *SizeEmitter {
+	return &SizeEmitter{sizes: PayloadSizes{Destination: destination}}
+}
+
+func (e *SizeEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	b, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+	return e.add(b)
+}
+
+func (e *SizeEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	if td.SpanCount() == 0 {
+		return nil
+	}
+	b, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	if err != nil {
+		return fmt.Errorf("failed to marshal traces: %w", err)
+	}
+	return e.add(b)
+}
+
+func (e *SizeEmitter) EmitLogs(_ context.Context, _ *state.RunState, ld plog.Logs) error {
+	if ld.LogRecordCount() == 0 {
+		return nil
+	}
+	b, err := (&plog.ProtoMarshaler{}).MarshalLogs(ld)
+	if err != nil {
+		return fmt.Errorf("failed to marshal logs: %w", err)
+	}
+	return e.add(b)
+}
+
+func (e *SizeEmitter) add(b []byte) error {
+	gz, err := compression.GZipBytes(b)
+	if err != nil {
+		return fmt.Errorf("failed to gzip payload: %w", err)
+	}
+	e.sizes.Payloads++
+	e.sizes.Bytes += int64(len(b))
+	e.sizes.GzipBytes += int64(len(gz))
+	e.sizes.MaxBytes = max(e.sizes.MaxBytes, int64(len(b)))
+	return nil
+}
+
+// Sizes returns the volume measured so far.
+func (e *SizeEmitter) Sizes() PayloadSizes {
+	return e.sizes
+}
+
+// WritePayloadSizes prints sizes as an aligned table, with the gzipped
+// bandwidth each destination would need over a run of length d.
+func WritePayloadSizes(w io.Writer, sizes []PayloadSizes, d time.Duration) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DESTINATION\tPAYLOADS\tBYTES\tGZIP BYTES\tRATIO\tMAX PAYLOAD\tGZIP BYTES/S")
+	for _, s := range sizes {
+		var ratio, rate float64
+		if s.GzipBytes > 0 {
+			ratio = float64(s.Bytes) / float64(s.GzipBytes)
+		}
+		if d > 0 {
+			rate = float64(s.GzipBytes) / d.Seconds()
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.2f\t%d\t%.0f\n",
+			s.Destination, s.Payloads, s.Bytes, s.GzipBytes, ratio, s.MaxBytes, rate)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/compression"
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestSizeEmitter(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{}
+	e := NewSizeEmitter("otlp")
+
+	md := makeTestMetrics()
+	require.NoError(t, e.EmitMetrics(ctx, rs, md))
+	require.NoError(t, e.EmitMetrics(ctx, rs, pmetric.NewMetrics()))
+	require.NoError(t, e.EmitTraces(ctx, rs, ptrace.NewTraces()))
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+	require.NoError(t, e.EmitLogs(ctx, rs, ld))
+
+	mb, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+	require.NoError(t, err)
+	lb, err := (&plog.ProtoMarshaler{}).MarshalLogs(ld)
+	require.NoError(t, err)
+	mgz, err := compression.GZipBytes(mb)
+	require.NoError(t, err)
+	lgz, err := compression.GZipBytes(lb)
+	require.NoError(t, err)
+	assert.Equal(t, PayloadSizes{
+		Destination: "otlp",
+		Payloads:    2,
+		Bytes:       int64(len(mb) + len(lb)),
+		GzipBytes:   int64(len(mgz) + len(lgz)),
+		MaxBytes:    int64(max(len(mb), len(lb))),
+	}, e.Sizes())
+
+	var out bytes.Buffer
+	require.NoError(t, WritePayloadSizes(&out, []PayloadSizes{e.Sizes()}, 10*time.Second))
+	assert.Contains(t, out.String(), "GZIP BYTES/S")
+	assert.Contains(t, out.String(), "otlp ")
+}
+
+func TestDestinationNames(t *testing.T) {
+	assert.Empty(t, DestinationNames(config.DefaultConfig()))
+
+	cfg := config.DefaultConfig()
+	cfg.Carbon.Address = "localhost:2003"
+	cfg.OTLPDestination.Endpoint = "http://localhost:4318"
+	assert.Equal(t, []string{"otlp", "carbon"}, DestinationNames(cfg))
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// SplunkHECEmitter sends datapoints as Splunk metric events and spans
+// as JSON events to a Splunk HTTP Event Collector.  Each tick's payload
+// is one batched request per signal.
+type SplunkHECEmitter struct {
+	client     *http.Client
+	url        string
+	token      string
+	index      string
+	source     string
+	sourcetype string
+}
+
+var _ Emitter = (*SplunkHECEmitter)(nil)
+
+type splunkEvent struct {
+	Time       float64        `json:"time"`
+	Host       string         `json:"host,omitempty"`
+	Source     string         `json:"source,omitempty"`
+	SourceType string         `json:"sourcetype,omitempty"`
+	Index      string         `json:"index,omitempty"`
+	Event      any            `json:"event"`
+	Fields     map[string]any `json:"fields,omitempty"`
+}
+
+type splunkSpanEvent struct {
+	TraceID            string            `json:"trace_id"`
+	SpanID             string            `json:"span_id"`
+	ParentSpanID       string            `json:"parent_span_id,omitempty"`
+	Name               string            `json:"name"`
+	Kind               string            `json:"kind"`
+	ServiceName        string            `json:"service.name"`
+	DurationNs         int64             `json:"duration_ns"`
+	StatusCode         string            `json:"status_code"`
+	StatusMessage      string            `json:"status_message,omitempty"`
+	ResourceAttributes map[string]string `json:"resource"`
+	Attributes         map[string]string `json:"attributes"`
+}
+
+// NewSplunkHECEmitter creates an emitter for the HEC at endpoint,
+// such as https://splunk.example.com:8088.  index, source, and
+// sourcetype are optional and left to the token's defaults when empty.
+func NewSplunkHECEmitter(client *http.Client, endpoint, token, index, source, sourcetype string) (*SplunkHECEmitter, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if token == "" {
+		return nil, errors.New("splunk: token is required")
+	}
+	if source == "" {
+		source = "flutter"
+	}
+	return &SplunkHECEmitter{
+		client:     client,
+		url:        strings.TrimRight(endpoint, "/") + "/services/collector/event",
+		token:      token,
+		index:      index,
+		source:     source,
+		sourcetype: sourcetype,
+	}, nil
+}
+
+func (e *SplunkHECEmitter) EmitMetrics(ctx context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range FlattenMetrics(md) {
+		fields := make(map[string]any, len(row.ResourceAttributes)+len(row.Attributes)+1)
+		for k, v := range row.ResourceAttributes {
+			fields[k] = v
+		}
+		for k, v := range row.Attributes {
+			fields[k] = v
+		}
+		fields["metric_name:"+row.Name] = row.Value
+		if err := enc.Encode(e.event(row.Timestamp, row.ResourceAttributes, "metric", fields)); err != nil {
+			return fmt.Errorf("failed to encode splunk metric event: %w", err)
+		}
+	}
+	return e.send(ctx, buf.Bytes())
+}
+
+func (e *SplunkHECEmitter) EmitTraces(ctx context.Context, _ *state.RunState, td ptrace.Traces) error {
+	if td.SpanCount() == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range FlattenTraces(td) {
+		span := splunkSpanEvent{
+			TraceID:            row.TraceID,
+			SpanID:             row.SpanID,
+			ParentSpanID:       row.ParentSpanID,
+			Name:               row.Name,
+			Kind:               row.Kind,
+			ServiceName:        row.ServiceName,
+			DurationNs:         row.Duration.Nanoseconds(),
+			StatusCode:         row.StatusCode,
+			StatusMessage:      row.StatusMessage,
+			ResourceAttributes: row.ResourceAttributes,
+			Attributes:         row.Attributes,
+		}
+		if err := enc.Encode(e.event(row.Timestamp, row.ResourceAttributes, span, nil)); err != nil {
+			return fmt.Errorf("failed to encode splunk span event: %w", err)
+		}
+	}
+	return e.send(ctx, buf.Bytes())
+}
+
+func (e *SplunkHECEmitter) event(ts time.Time, rattr map[string]string, event any, fields map[string]any) splunkEvent {
+	return splunkEvent{
+		Time:       float64(ts.UnixMicro()) / 1e6,
+		Host:       rattr["host.name"],
+		Source:     e.source,
+		SourceType: e.sourcetype,
+		Index:      e.index,
+		Event:      event,
+		Fields:     fields,
+	}
+}
+
+func (e *SplunkHECEmitter) send(ctx context.Context, body []byte) error {
+	headers := map[string]string{"Authorization": "Splunk " + e.token}
+	if _, err := postBody(ctx, e.client, e.url, "application/json", headers, body); err != nil {
+		return fmt.Errorf("splunk HEC send failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestSplunkHECEmitter_EmitMetrics(t *testing.T) {
+	var gotBody, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/services/collector/event", r.URL.Path)
+		gotAuth = r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer srv.Close()
+
+	e, err := NewSplunkHECEmitter(srv.Client(), srv.URL, "token", "metrics", "", "")
+	require.NoError(t, err)
+	require.NoError(t, e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics()))
+
+	assert.Equal(t, "Splunk token", gotAuth)
+	assert.JSONEq(t, `{
+		"time": 1000,
+		"source": "flutter",
+		"index": "metrics",
+		"event": "metric",
+		"fields": {"service.name": "test", "metric_name:test.metric": 1}
+	}`, gotBody)
+}
+
+func TestSplunkHECEmitter_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"text":"Invalid token","code":4}`))
+	}))
+	defer srv.Close()
+
+	e, err := NewSplunkHECEmitter(srv.Client(), srv.URL, "bad", "", "", "")
+	require.NoError(t, err)
+	err = e.EmitMetrics(context.Background(), &state.RunState{}, makeTestMetrics())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid token")
+
+	_, err = NewSplunkHECEmitter(nil, srv.URL, "", "", "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,277 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Syslog networks for the syslog emitter.
+const (
+	SyslogUDP = "udp"
+	SyslogTCP = "tcp"
+	SyslogTLS = "tls"
+)
+
+const (
+	// syslogFacility is the user-level facility.
+	syslogFacility = 1
+	// syslogSDID names the structured data element carrying the log
+	// record's attributes.  32473 is the enterprise number RFC 5612
+	// reserves for documentation.
+	syslogSDID = "flutter@32473"
+)
+
+// SyslogEmitter sends log records as RFC 5424 syslog messages, one
+// datagram each over UDP, or octet-counted as RFC 6587 and RFC 5425
+// frame them over TCP and TLS.  The hostname, app name, and process ID
+// come from the host.name, service.name, and process.pid resource
+// attributes, the message ID from the event name, and the structured
+// data from the record's attributes and trace context.  Metrics and
+// traces are not sent.
+//
+// The connection is opened on first use and reopened on the next
+// tick after a write error.
+type SyslogEmitter struct {
+	address string
+	network string
+	tls     *tls.Config
+	timeout time.Duration
+	conn    net.Conn
+}
+
+var (
+	_ Emitter    = (*SyslogEmitter)(nil)
+	_ LogEmitter = (*SyslogEmitter)(nil)
+	_ Flusher    = (*SyslogEmitter)(nil)
+)
+
+func NewSyslogEmitter(cfg config.Syslog) (*SyslogEmitter, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("syslog address is required")
+	}
+	e := &SyslogEmitter{
+		address: cfg.Address,
+		network: cfg.Network,
+		timeout: cfg.Timeout,
+	}
+	if e.network == "" {
+		e.network = SyslogUDP
+	}
+	if e.timeout <= 0 {
+		e.timeout = 5 * time.Second
+	}
+	switch e.network {
+	case SyslogUDP, SyslogTCP:
+	case SyslogTLS:
+		host, _, err := net.SplitHostPort(cfg.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid syslog address %q: %w", cfg.Address, err)
+		}
+		e.tls = &tls.Config{ServerName: host, InsecureSkipVerify: cfg.InsecureSkipVerify}
+	default:
+		return nil, fmt.Errorf("unknown syslog network %q, want udp, tcp, or tls", cfg.Network)
+	}
+	return e, nil
+}
+
+func (e *SyslogEmitter) EmitMetrics(_ context.Context, _ *state.RunState, _ pmetric.Metrics) error {
+	return nil
+}
+
+func (e *SyslogEmitter) EmitTraces(_ context.Context, _ *state.RunState, _ ptrace.Traces) error {
+	return nil
+}
+
+func (e *SyslogEmitter) EmitLogs(ctx context.Context, _ *state.RunState, ld plog.Logs) error {
+	if ld.LogRecordCount() == 0 {
+		return nil
+	}
+	if e.conn == nil {
+		conn, err := e.dial(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog at %s: %w", e.address, err)
+		}
+		e.conn = conn
+	}
+	_ = e.conn.SetWriteDeadline(time.Now().Add(e.timeout))
+
+	var buf bytes.Buffer
+	for _, rl := range ld.ResourceLogs().All() {
+		for _, sl := range rl.ScopeLogs().All() {
+			for _, lr := range sl.LogRecords().All() {
+				msg := syslogMessage(rl.Resource().Attributes(), lr)
+				if e.network == SyslogUDP {
+					// Each datagram is one message.
+					if _, err := e.conn.Write(msg); err != nil {
+						return e.writeFailed(err)
+					}
+					continue
+				}
+				buf.WriteString(strconv.Itoa(len(msg)))
+				buf.WriteByte(' ')
+				buf.Write(msg)
+			}
+		}
+	}
+	if buf.Len() > 0 {
+		if _, err := e.conn.Write(buf.Bytes()); err != nil {
+			return e.writeFailed(err)
+		}
+	}
+	return nil
+}
+
+func (e *SyslogEmitter) dial(ctx context.Context) (net.Conn, error) {
+	d := net.Dialer{Timeout: e.timeout}
+	if e.tls != nil {
+		td := tls.Dialer{NetDialer: &d, Config: e.tls}
+		return td.DialContext(ctx, "tcp", e.address)
+	}
+	return d.DialContext(ctx, e.network, e.address)
+}
+
+func (e *SyslogEmitter) writeFailed(err error) error {
+	_ = e.conn.Close()
+	e.conn = nil
+	return fmt.Errorf("failed to write to syslog at %s: %w", e.address, err)
+}
+
+func (e *SyslogEmitter) Flush(_ context.Context, _ *state.RunState) error {
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	return err
+}
+
+// syslogMessage formats lr, from a resource with rattr, as
+// "<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG".
+func syslogMessage(rattr pcommon.Map, lr plog.LogRecord) []byte {
+	ts := lr.Timestamp()
+	if ts == 0 {
+		ts = lr.ObservedTimestamp()
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %s %s ",
+		syslogFacility*8+syslogSeverity(lr.SeverityNumber()),
+		ts.AsTime().UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(rattr, "host.name", 255),
+		syslogHeaderField(rattr, "service.name", 48),
+		syslogHeaderField(rattr, "process.pid", 128),
+		syslogField(lr.EventName(), 32),
+	)
+	writeStructuredData(&buf, lr)
+	if body := lr.Body().AsString(); body != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(body)
+	}
+	return buf.Bytes()
+}
+
+// syslogSeverity maps an OpenTelemetry severity to a syslog one, with
+// unset severities as informational.
+func syslogSeverity(n plog.SeverityNumber) int {
+	switch {
+	case n >= plog.SeverityNumberFatal:
+		return 2
+	case n >= plog.SeverityNumberError:
+		return 3
+	case n >= plog.SeverityNumberWarn:
+		return 4
+	case n >= plog.SeverityNumberInfo, n == plog.SeverityNumberUnspecified:
+		return 6
+	default:
+		return 7
+	}
+}
+
+func syslogHeaderField(attrs pcommon.Map, key string, maxLen int) string {
+	v, ok := attrs.Get(key)
+	if !ok {
+		return "-"
+	}
+	return syslogField(v.AsString(), maxLen)
+}
+
+// syslogField makes s a valid header field: printable ASCII without
+// spaces, at most maxLen long, and "-" when empty.
+func syslogField(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r > ' ' && r <= '~' {
+			return r
+		}
+		return '_'
+	}, s)
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+var (
+	sdNameReplacer  = strings.NewReplacer("=", "_", "]", "_", `"`, "_")
+	sdValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "]", `\]`)
+)
+
+// writeStructuredData writes the record's trace context and attributes
+// as one SD element, or "-" when there are none.
+func writeStructuredData(buf *bytes.Buffer, lr plog.LogRecord) {
+	params := map[string]string{}
+	if !lr.TraceID().IsEmpty() {
+		params["trace_id"] = lr.TraceID().String()
+	}
+	if !lr.SpanID().IsEmpty() {
+		params["span_id"] = lr.SpanID().String()
+	}
+	for k, v := range lr.Attributes().All() {
+		params[k] = v.AsString()
+	}
+	if len(params) == 0 {
+		buf.WriteByte('-')
+		return
+	}
+	buf.WriteString("[" + syslogSDID)
+	for _, k := range slices.Sorted(maps.Keys(params)) {
+		buf.WriteByte(' ')
+		buf.WriteString(sdNameReplacer.Replace(syslogField(k, 32)))
+		buf.WriteString(`="`)
+		buf.WriteString(sdValueReplacer.Replace(params[k]))
+		buf.WriteByte('"')
+	}
+	buf.WriteByte(']')
+}
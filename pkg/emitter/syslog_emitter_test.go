@@ -0,0 +1,152 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func makeSyslogLogs() plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutStr("host.name", "web 1")
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1000, 5000)))
+	lr.SetSeverityNumber(plog.SeverityNumberWarn)
+	lr.SetEventName("sla.breach")
+	lr.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	lr.Attributes().PutStr("alert.name", `slow "checkout"]`)
+	lr.Body().SetStr("GET /checkout exceeded its SLA")
+	return ld
+}
+
+const wantSyslogMessage = `<12>1 1970-01-01T00:16:40.000005Z web_1 checkout - sla.breach ` +
+	`[flutter@32473 alert.name="slow \"checkout\"\]" trace_id="0102030405060708090a0b0c0d0e0f10"] GET /checkout exceeded its SLA`
+
+func TestSyslogMessage(t *testing.T) {
+	lr := makeSyslogLogs().ResourceLogs().At(0)
+	assert.Equal(t, wantSyslogMessage, string(syslogMessage(lr.Resource().Attributes(), lr.ScopeLogs().At(0).LogRecords().At(0))))
+
+	bare := plog.NewLogRecord()
+	bare.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Unix(1000, 0)))
+	assert.Equal(t, "<14>1 1970-01-01T00:16:40.000000Z - - - - -", string(syslogMessage(pcommon.NewMap(), bare)))
+}
+
+func TestNewSyslogEmitter(t *testing.T) {
+	e, err := NewSyslogEmitter(config.Syslog{Address: "localhost:514"})
+	require.NoError(t, err)
+	assert.Equal(t, SyslogUDP, e.network)
+
+	_, err = NewSyslogEmitter(config.Syslog{})
+	assert.Error(t, err)
+	_, err = NewSyslogEmitter(config.Syslog{Address: "localhost:514", Network: "quic"})
+	assert.Error(t, err)
+	_, err = NewSyslogEmitter(config.Syslog{Address: "localhost", Network: SyslogTLS})
+	assert.Error(t, err)
+}
+
+func TestSyslogEmitter_UDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	e, err := NewSyslogEmitter(config.Syslog{Address: pc.LocalAddr().String()})
+	require.NoError(t, err)
+	require.NoError(t, e.EmitLogs(context.Background(), &state.RunState{}, makeSyslogLogs()))
+	require.NoError(t, e.Flush(context.Background(), &state.RunState{}))
+
+	buf := make([]byte, 2048)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, wantSyslogMessage, string(buf[:n]))
+}
+
+// readSyslogFrame reads one octet-counted message.
+func readSyslogFrame(r *bufio.Reader) (string, error) {
+	size, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(size, " "))
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, n)
+	_, err = io.ReadFull(r, msg)
+	return string(msg), err
+}
+
+func TestSyslogEmitter_Stream(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	cert := srv.TLS.Certificates[0]
+
+	for _, network := range []string{SyslogTCP, SyslogTLS} {
+		t.Run(network, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			if network == SyslogTLS {
+				ln = tls.NewListener(ln, &tls.Config{Certificates: []tls.Certificate{cert}})
+			}
+			defer ln.Close()
+
+			frames := make(chan []string, 1)
+			go func() {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				var got []string
+				for range 2 {
+					msg, err := readSyslogFrame(r)
+					if err != nil {
+						break
+					}
+					got = append(got, msg)
+				}
+				frames <- got
+			}()
+
+			e, err := NewSyslogEmitter(config.Syslog{Address: ln.Addr().String(), Network: network, InsecureSkipVerify: true})
+			require.NoError(t, err)
+			ld := makeSyslogLogs()
+			ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).CopyTo(ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().AppendEmpty())
+			require.NoError(t, e.EmitLogs(context.Background(), &state.RunState{}, ld))
+			assert.Equal(t, []string{wantSyslogMessage, wantSyslogMessage}, <-frames)
+			require.NoError(t, e.Flush(context.Background(), &state.RunState{}))
+		})
+	}
+}
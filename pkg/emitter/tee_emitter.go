@@ -0,0 +1,284 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Error policies for a tee branch.
+const (
+	// OnErrorFail returns the branch's error, which aborts the run.
+	OnErrorFail = "fail"
+	// OnErrorContinue logs the error and keeps going.
+	OnErrorContinue = "continue"
+)
+
+type teeBranch struct {
+	name    string
+	emitter Emitter
+	policy  config.ErrorPolicy
+	queue   *sendQueue
+
+	// mu guards the fields below, which a queued branch updates from
+	// its send goroutine.
+	mu       sync.Mutex
+	failures int
+	disabled bool
+	// pending is the error of a queued send, returned by the next call.
+	pending error
+}
+
+// TeeEmitter fans each payload out to several destinations, applying
+// each one's ErrorPolicy independently so a flaky secondary
+// destination does not stop the others or end the run.
+type TeeEmitter struct {
+	policies   map[string]config.ErrorPolicy
+	queues     map[string]config.SendQueue
+	transforms map[string]config.Transform
+	branches   []*teeBranch
+}
+
+var (
+	_ Emitter    = (*TeeEmitter)(nil)
+	_ Flusher    = (*TeeEmitter)(nil)
+	_ LogEmitter = (*TeeEmitter)(nil)
+)
+
+// NewTeeEmitter validates policies, which are keyed by the names later
+// passed to Add.  Destinations without a policy use OnErrorFail,
+// matching how emitters behave outside a tee.
+func NewTeeEmitter(policies map[string]config.ErrorPolicy) (*TeeEmitter, error) {
+	validated := make(map[string]config.ErrorPolicy, len(policies))
+	for name, policy := range policies {
+		policy, err := validateErrorPolicy(name, policy)
+		if err != nil {
+			return nil, err
+		}
+		validated[name] = policy
+	}
+	return &TeeEmitter{policies: validated}, nil
+}
+
+// validateErrorPolicy returns policy with its defaults filled in.
+func validateErrorPolicy(name string, policy config.ErrorPolicy) (config.ErrorPolicy, error) {
+	switch policy.OnError {
+	case "":
+		policy.OnError = OnErrorFail
+	case OnErrorFail, OnErrorContinue:
+	default:
+		return policy, fmt.Errorf("%s: unknown onError policy %q", name, policy.OnError)
+	}
+	if policy.Retries < 0 || policy.MaxFailures < 0 {
+		return policy, fmt.Errorf("%s: retries and maxFailures must not be negative", name)
+	}
+	return policy, nil
+}
+
+// SetQueues validates send queues, keyed like the policies.  Branches
+// added afterwards with a queue send from their own goroutine; errors
+// from those sends are handled by the branch's policy and returned by
+// the following call.
+func (t *TeeEmitter) SetQueues(queues map[string]config.SendQueue) error {
+	for name, q := range queues {
+		if err := validateSendQueue(name, q); err != nil {
+			return err
+		}
+	}
+	t.queues = queues
+	return nil
+}
+
+// SetTransforms validates transforms, keyed like the policies.
+// Branches added afterwards with a transform send a transformed copy
+// of each payload.
+func (t *TeeEmitter) SetTransforms(transforms map[string]config.Transform) error {
+	for name, tr := range transforms {
+		if err := validateTransform(name, tr); err != nil {
+			return err
+		}
+	}
+	t.transforms = transforms
+	return nil
+}
+
+// Add appends a destination using the policy, queue and transform for
+// name.
+func (t *TeeEmitter) Add(name string, e Emitter) {
+	if tr, ok := t.transforms[name]; ok {
+		e = &TransformEmitter{next: e, transform: tr, series: map[string]*sumSeries{}}
+	}
+	policy, ok := t.policies[name]
+	if !ok {
+		policy.OnError = OnErrorFail
+	}
+	b := &teeBranch{name: name, emitter: e, policy: policy}
+	if q, ok := t.queues[name]; ok {
+		b.queue = newSendQueue(name, q)
+	}
+	t.branches = append(t.branches, b)
+}
+
+// Len returns the number of destinations.
+func (t *TeeEmitter) Len() int {
+	return len(t.branches)
+}
+
+// UnusedPolicies returns the sorted names of policies, queues and
+// transforms that no added destination matched, which usually means a
+// typo.
+func (t *TeeEmitter) UnusedPolicies() []string {
+	var unused []string
+	names := slices.Concat(slices.Collect(maps.Keys(t.policies)), slices.Collect(maps.Keys(t.queues)), slices.Collect(maps.Keys(t.transforms)))
+	for _, name := range names {
+		if !slices.ContainsFunc(t.branches, func(b *teeBranch) bool { return b.name == name }) {
+			unused = append(unused, name)
+		}
+	}
+	slices.Sort(unused)
+	return slices.Compact(unused)
+}
+
+func (t *TeeEmitter) EmitMetrics(ctx context.Context, rs *state.RunState, md pmetric.Metrics) error {
+	return t.each(ctx, rs, true, func(e Emitter, rs *state.RunState) error {
+		return e.EmitMetrics(ctx, rs, md)
+	})
+}
+
+func (t *TeeEmitter) EmitTraces(ctx context.Context, rs *state.RunState, td ptrace.Traces) error {
+	return t.each(ctx, rs, true, func(e Emitter, rs *state.RunState) error {
+		return e.EmitTraces(ctx, rs, td)
+	})
+}
+
+func (t *TeeEmitter) EmitLogs(ctx context.Context, rs *state.RunState, ld plog.Logs) error {
+	return t.each(ctx, rs, true, func(e Emitter, rs *state.RunState) error {
+		return EmitLogs(ctx, e, rs, ld)
+	})
+}
+
+// Flush waits for queued sends, then flushes every branch in turn.
+func (t *TeeEmitter) Flush(ctx context.Context, rs *state.RunState) error {
+	for _, b := range t.branches {
+		if b.queue == nil {
+			continue
+		}
+		b.queue.wait()
+		if b.queue.dropped > 0 {
+			slog.Warn("Payloads dropped by a full send queue", "destination", b.name, "dropped", b.queue.dropped)
+		}
+	}
+	return t.each(ctx, rs, false, func(e Emitter, rs *state.RunState) error {
+		if f, ok := e.(Flusher); ok {
+			return f.Flush(ctx, rs)
+		}
+		return nil
+	})
+}
+
+// Close stops the send goroutines once their queues are empty.
+func (t *TeeEmitter) Close() {
+	for _, b := range t.branches {
+		if b.queue != nil {
+			b.queue.close()
+			b.queue = nil
+		}
+	}
+}
+
+// each calls fn for every enabled branch.  All branches are tried
+// even when one fails; the errors of "fail" branches are joined and
+// returned.  With async set, queued branches get fn, and a copy of rs
+// that later ticks do not change, on their send goroutine.
+func (t *TeeEmitter) each(ctx context.Context, rs *state.RunState, async bool, fn func(Emitter, *state.RunState) error) error {
+	var errs []error
+	for _, b := range t.branches {
+		b.mu.Lock()
+		disabled, pending := b.disabled, b.pending
+		b.pending = nil
+		b.mu.Unlock()
+		if pending != nil {
+			errs = append(errs, pending)
+		}
+		if disabled {
+			continue
+		}
+		if async && b.queue != nil {
+			snapshot := *rs
+			b.queue.send(func() {
+				if err := b.handle(b.call(ctx, func(e Emitter) error { return fn(e, &snapshot) })); err != nil {
+					b.mu.Lock()
+					b.pending = errors.Join(b.pending, err)
+					b.mu.Unlock()
+				}
+			})
+			continue
+		}
+		if err := b.handle(b.call(ctx, func(e Emitter) error { return fn(e, rs) })); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handle applies the branch's policy to the outcome of a call, and
+// returns the error if it should end the run.
+func (b *teeBranch) handle(err error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.failures = 0
+		return nil
+	}
+	if b.policy.OnError == OnErrorFail {
+		return fmt.Errorf("%s: %w", b.name, err)
+	}
+	b.failures++
+	slog.Warn("Destination failed, continuing", "destination", b.name, "consecutiveFailures", b.failures, "error", err)
+	if b.policy.MaxFailures > 0 && b.failures >= b.policy.MaxFailures {
+		slog.Warn("Destination disabled after repeated failures", "destination", b.name, "consecutiveFailures", b.failures)
+		b.disabled = true
+	}
+	return nil
+}
+
+func (b *teeBranch) call(ctx context.Context, fn func(Emitter) error) error {
+	err := fn(b.emitter)
+	for attempt := 0; err != nil && attempt < b.policy.Retries; attempt++ {
+		if b.policy.RetryDelay > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(b.policy.RetryDelay):
+			}
+		}
+		err = fn(b.emitter)
+	}
+	return err
+}
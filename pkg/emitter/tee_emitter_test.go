@@ -0,0 +1,213 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// failingEmitter fails its first failures calls and then succeeds.
+type failingEmitter struct {
+	failures int
+	calls    int
+}
+
+func (f *failingEmitter) EmitMetrics(_ context.Context, _ *state.RunState, _ pmetric.Metrics) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func (f *failingEmitter) EmitTraces(_ context.Context, _ *state.RunState, _ ptrace.Traces) error {
+	return nil
+}
+
+// blockingEmitter waits for release before each metrics call returns.
+type blockingEmitter struct {
+	release chan struct{}
+	calls   atomic.Int32
+	err     error
+}
+
+func (b *blockingEmitter) EmitMetrics(_ context.Context, _ *state.RunState, _ pmetric.Metrics) error {
+	<-b.release
+	b.calls.Add(1)
+	return b.err
+}
+
+func (b *blockingEmitter) EmitTraces(_ context.Context, _ *state.RunState, _ ptrace.Traces) error {
+	return nil
+}
+
+func TestNewTeeEmitter(t *testing.T) {
+	_, err := NewTeeEmitter(map[string]config.ErrorPolicy{"otlp": {OnError: "ignore"}})
+	assert.Error(t, err)
+	_, err = NewTeeEmitter(map[string]config.ErrorPolicy{"otlp": {Retries: -1}})
+	assert.Error(t, err)
+
+	tee, err := NewTeeEmitter(map[string]config.ErrorPolicy{"otlp": {}, "clickhouse": {}, "influx": {}})
+	require.NoError(t, err)
+	require.NoError(t, tee.SetQueues(map[string]config.SendQueue{"otlp": {}, "carbon": {}, "influx": {}}))
+	tee.Add("otlp", &captureEmitter{})
+	assert.Equal(t, []string{"carbon", "clickhouse", "influx"}, tee.UnusedPolicies())
+	tee.Close()
+
+	assert.Error(t, tee.SetQueues(map[string]config.SendQueue{"otlp": {OnFull: "wait"}}))
+	assert.Error(t, tee.SetQueues(map[string]config.SendQueue{"otlp": {Size: -1}}))
+}
+
+func TestTeeEmitter_SendQueue(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{}
+	md := makeTestMetrics()
+
+	t.Run("drop does not wait for a slow destination", func(t *testing.T) {
+		tee, err := NewTeeEmitter(nil)
+		require.NoError(t, err)
+		require.NoError(t, tee.SetQueues(map[string]config.SendQueue{"slow": {Size: 1, OnFull: OnFullDrop}}))
+		slow := &blockingEmitter{release: make(chan struct{})}
+		capture := &captureEmitter{}
+		tee.Add("slow", slow)
+		tee.Add("primary", capture)
+		defer tee.Close()
+
+		// The first payload may be taken by the send goroutine or wait in
+		// the queue, so at most two of the five are sent.
+		for range 5 {
+			require.NoError(t, tee.EmitMetrics(ctx, rs, md))
+		}
+		assert.Len(t, capture.metrics, 5)
+		close(slow.release)
+		require.NoError(t, tee.Flush(ctx, rs))
+		assert.LessOrEqual(t, slow.calls.Load(), int32(2))
+		assert.GreaterOrEqual(t, slow.calls.Load(), int32(1))
+	})
+
+	t.Run("block keeps every payload", func(t *testing.T) {
+		tee, err := NewTeeEmitter(nil)
+		require.NoError(t, err)
+		require.NoError(t, tee.SetQueues(map[string]config.SendQueue{"slow": {Size: 2}}))
+		slow := &blockingEmitter{release: make(chan struct{})}
+		tee.Add("slow", slow)
+		defer tee.Close()
+
+		close(slow.release)
+		for range 10 {
+			require.NoError(t, tee.EmitMetrics(ctx, rs, md))
+		}
+		require.NoError(t, tee.Flush(ctx, rs))
+		assert.Equal(t, int32(10), slow.calls.Load())
+	})
+
+	t.Run("errors of queued sends are returned later", func(t *testing.T) {
+		tee, err := NewTeeEmitter(nil)
+		require.NoError(t, err)
+		require.NoError(t, tee.SetQueues(map[string]config.SendQueue{"slow": {}}))
+		slow := &blockingEmitter{release: make(chan struct{}), err: errors.New("boom")}
+		tee.Add("slow", slow)
+		defer tee.Close()
+
+		close(slow.release)
+		require.NoError(t, tee.EmitMetrics(ctx, rs, md))
+		assert.ErrorContains(t, tee.Flush(ctx, rs), "slow: boom")
+	})
+}
+
+func TestTeeEmitter_EmitMetrics(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{}
+	md := makeTestMetrics()
+
+	t.Run("fail policy returns error but other branches still run", func(t *testing.T) {
+		tee, err := NewTeeEmitter(nil)
+		require.NoError(t, err)
+		capture := &captureEmitter{}
+		tee.Add("flaky", &failingEmitter{failures: 1})
+		tee.Add("primary", capture)
+		err = tee.EmitMetrics(ctx, rs, md)
+		assert.ErrorContains(t, err, "flaky: boom")
+		assert.Len(t, capture.metrics, 1)
+	})
+
+	t.Run("retries hide transient failures", func(t *testing.T) {
+		tee, err := NewTeeEmitter(map[string]config.ErrorPolicy{"flaky": {Retries: 2}})
+		require.NoError(t, err)
+		flaky := &failingEmitter{failures: 2}
+		tee.Add("flaky", flaky)
+		require.NoError(t, tee.EmitMetrics(ctx, rs, md))
+		assert.Equal(t, 3, flaky.calls)
+	})
+
+	t.Run("continue policy disables after max failures", func(t *testing.T) {
+		tee, err := NewTeeEmitter(map[string]config.ErrorPolicy{"flaky": {OnError: OnErrorContinue, MaxFailures: 2}})
+		require.NoError(t, err)
+		flaky := &failingEmitter{failures: 100}
+		tee.Add("flaky", flaky)
+		for range 5 {
+			require.NoError(t, tee.EmitMetrics(ctx, rs, md))
+		}
+		assert.Equal(t, 2, flaky.calls)
+	})
+
+	t.Run("success resets consecutive failures", func(t *testing.T) {
+		tee, err := NewTeeEmitter(map[string]config.ErrorPolicy{"flaky": {OnError: OnErrorContinue, MaxFailures: 2}})
+		require.NoError(t, err)
+		flaky := &failingEmitter{failures: 1}
+		tee.Add("flaky", flaky)
+		for range 5 {
+			require.NoError(t, tee.EmitMetrics(ctx, rs, md))
+		}
+		assert.Equal(t, 5, flaky.calls)
+	})
+}
+
+// logCountingEmitter counts the log records it is sent.
+type logCountingEmitter struct {
+	failingEmitter
+	records int
+}
+
+func (l *logCountingEmitter) EmitLogs(_ context.Context, _ *state.RunState, ld plog.Logs) error {
+	l.records += ld.LogRecordCount()
+	return nil
+}
+
+func TestTeeEmitter_EmitLogs(t *testing.T) {
+	tee, err := NewTeeEmitter(nil)
+	require.NoError(t, err)
+	logs := &logCountingEmitter{}
+	tee.Add("logs", logs)
+	// Destinations that do not send logs are skipped.
+	tee.Add("metrics", &failingEmitter{})
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	require.NoError(t, tee.EmitLogs(context.Background(), &state.RunState{}, ld))
+	assert.Equal(t, 1, logs.records)
+}
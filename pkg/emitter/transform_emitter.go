@@ -0,0 +1,274 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Sum temporalities a Transform converts to.
+const (
+	TemporalityDelta      = "delta"
+	TemporalityCumulative = "cumulative"
+)
+
+// TransformEmitter applies a config.Transform to a copy of every
+// payload before handing it to the wrapped emitter, so other
+// destinations of a tee still see the payload as generated.
+type TransformEmitter struct {
+	next      Emitter
+	transform config.Transform
+	series    map[string]*sumSeries
+}
+
+// sumSeries is what temporality conversion remembers about one sum
+// series.  The last output is kept so a retried payload, which carries
+// the same timestamps, converts to the same values again.
+type sumSeries struct {
+	// origin is the start time of the last point received, and start
+	// the start time sent with the last output.
+	origin pcommon.Timestamp
+	start  pcommon.Timestamp
+	last   pcommon.Timestamp
+	// input is the last value received, and output what it became.
+	input  float64
+	output float64
+	seen   bool
+	// dropped is set when the last point had no delta to send.
+	dropped bool
+}
+
+var (
+	_ Emitter    = (*TransformEmitter)(nil)
+	_ Flusher    = (*TransformEmitter)(nil)
+	_ LogEmitter = (*TransformEmitter)(nil)
+)
+
+func validateTransform(name string, t config.Transform) error {
+	switch t.Temporality {
+	case "", TemporalityDelta, TemporalityCumulative:
+	default:
+		return fmt.Errorf("%s: unknown temporality %q", name, t.Temporality)
+	}
+	return nil
+}
+
+// NewTransformEmitter wraps next.  It returns an error if transform is
+// invalid.
+func NewTransformEmitter(next Emitter, transform config.Transform) (*TransformEmitter, error) {
+	if err := validateTransform("transform", transform); err != nil {
+		return nil, err
+	}
+	return &TransformEmitter{next: next, transform: transform, series: map[string]*sumSeries{}}, nil
+}
+
+func (e *TransformEmitter) EmitMetrics(ctx context.Context, rs *state.RunState, md pmetric.Metrics) error {
+	out := pmetric.NewMetrics()
+	md.CopyTo(out)
+	for _, rm := range out.ResourceMetrics().All() {
+		rattr := rm.Resource().Attributes()
+		rkey := attrKey(attrStrings(rattr))
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				if m.Type() == pmetric.MetricTypeSum {
+					e.convertSum(m, rkey)
+				}
+				if e.transform.FlattenAttributes {
+					forEachDatapoint(m, func(attrs pcommon.Map, _ pcommon.Timestamp) {
+						flattenAttributes(attrs)
+					})
+				}
+			}
+		}
+		if e.transform.FlattenAttributes {
+			flattenAttributes(rattr)
+		}
+	}
+	return e.next.EmitMetrics(ctx, rs, out)
+}
+
+func (e *TransformEmitter) EmitTraces(ctx context.Context, rs *state.RunState, td ptrace.Traces) error {
+	if !e.transform.FlattenAttributes {
+		return e.next.EmitTraces(ctx, rs, td)
+	}
+	out := ptrace.NewTraces()
+	td.CopyTo(out)
+	for _, rspan := range out.ResourceSpans().All() {
+		flattenAttributes(rspan.Resource().Attributes())
+		for _, ss := range rspan.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				flattenAttributes(span.Attributes())
+				for _, ev := range span.Events().All() {
+					flattenAttributes(ev.Attributes())
+				}
+			}
+		}
+	}
+	return e.next.EmitTraces(ctx, rs, out)
+}
+
+func (e *TransformEmitter) EmitLogs(ctx context.Context, rs *state.RunState, ld plog.Logs) error {
+	if !e.transform.FlattenAttributes {
+		return EmitLogs(ctx, e.next, rs, ld)
+	}
+	out := plog.NewLogs()
+	ld.CopyTo(out)
+	for _, rl := range out.ResourceLogs().All() {
+		flattenAttributes(rl.Resource().Attributes())
+		for _, sl := range rl.ScopeLogs().All() {
+			for _, lr := range sl.LogRecords().All() {
+				flattenAttributes(lr.Attributes())
+			}
+		}
+	}
+	return EmitLogs(ctx, e.next, rs, out)
+}
+
+func (e *TransformEmitter) Flush(ctx context.Context, rs *state.RunState) error {
+	if f, ok := e.next.(Flusher); ok {
+		return f.Flush(ctx, rs)
+	}
+	return nil
+}
+
+// convertSum rewrites m's datapoints to the configured temporality.
+// Converting to delta has nothing to send for the first point of a
+// series, which is removed.
+func (e *TransformEmitter) convertSum(m pmetric.Metric, rkey string) {
+	sum := m.Sum()
+	var to pmetric.AggregationTemporality
+	switch e.transform.Temporality {
+	case TemporalityDelta:
+		to = pmetric.AggregationTemporalityDelta
+	case TemporalityCumulative:
+		to = pmetric.AggregationTemporalityCumulative
+	default:
+		return
+	}
+	if sum.AggregationTemporality() == to {
+		return
+	}
+	sum.SetAggregationTemporality(to)
+	sum.DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+		key := strings.Join([]string{m.Name(), rkey, attrKey(attrStrings(dp.Attributes()))}, "\x01")
+		s, ok := e.series[key]
+		if !ok {
+			s = &sumSeries{}
+			e.series[key] = s
+		}
+		if to == pmetric.AggregationTemporalityCumulative {
+			s.toCumulative(dp)
+			return false
+		}
+		return !s.toDelta(dp, sum.IsMonotonic())
+	})
+}
+
+// toCumulative adds dp to the running total of its series.  The total
+// starts at the first point's start time or, for points without one,
+// such as flutter's own delta sums, at its timestamp.
+func (s *sumSeries) toCumulative(dp pmetric.NumberDataPoint) {
+	if !s.seen {
+		s.seen = true
+		s.start = dp.StartTimestamp()
+		if s.start == 0 {
+			s.start = dp.Timestamp()
+		}
+	}
+	if dp.Timestamp() != s.last {
+		s.last = dp.Timestamp()
+		s.input = numberValue(dp)
+		s.output += s.input
+	}
+	dp.SetStartTimestamp(s.start)
+	setNumberValue(dp, s.output)
+}
+
+// toDelta replaces dp with the change since the previous point of its
+// series, and reports whether there is one to send.  A new start time,
+// or for monotonic sums a lower value, is a counter reset, after which
+// the whole value is the delta.
+func (s *sumSeries) toDelta(dp pmetric.NumberDataPoint, monotonic bool) bool {
+	if ts := dp.Timestamp(); ts != s.last {
+		value := numberValue(dp)
+		switch {
+		case !s.seen:
+			s.dropped = true
+		case dp.StartTimestamp() != s.origin || (monotonic && value < s.input):
+			s.dropped = false
+			s.output = value
+			s.start = dp.StartTimestamp()
+		default:
+			s.dropped = false
+			s.output = value - s.input
+			s.start = s.last
+		}
+		s.seen, s.origin, s.input, s.last = true, dp.StartTimestamp(), value, ts
+	}
+	if s.dropped {
+		return false
+	}
+	dp.SetStartTimestamp(s.start)
+	setNumberValue(dp, s.output)
+	return true
+}
+
+func setNumberValue(dp pmetric.NumberDataPoint, v float64) {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		dp.SetIntValue(int64(v))
+		return
+	}
+	dp.SetDoubleValue(v)
+}
+
+// flattenAttributes replaces each map value in attrs with its leaves,
+// keyed by their dotted path, and each slice with its JSON encoding.
+func flattenAttributes(attrs pcommon.Map) {
+	flat := pcommon.NewMap()
+	nested := false
+	for k, v := range attrs.All() {
+		switch v.Type() {
+		case pcommon.ValueTypeMap, pcommon.ValueTypeSlice:
+			nested = true
+		}
+		flattenValue(flat, k, v)
+	}
+	if nested {
+		flat.MoveTo(attrs)
+	}
+}
+
+func flattenValue(flat pcommon.Map, key string, v pcommon.Value) {
+	switch v.Type() {
+	case pcommon.ValueTypeMap:
+		for k, child := range v.Map().All() {
+			flattenValue(flat, key+"."+k, child)
+		}
+	case pcommon.ValueTypeSlice:
+		flat.PutStr(key, v.AsString())
+	default:
+		v.CopyTo(flat.PutEmpty(key))
+	}
+}
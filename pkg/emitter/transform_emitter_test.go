@@ -0,0 +1,193 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func makeSumMetrics(temporality pmetric.AggregationTemporality, start, ts time.Time, value float64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "test")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("requests")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(temporality)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetDoubleValue(value)
+	return md
+}
+
+func sumPoints(mds []pmetric.Metrics) []float64 {
+	var values []float64
+	for _, md := range mds {
+		for _, dp := range md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().All() {
+			values = append(values, dp.DoubleValue())
+		}
+	}
+	return values
+}
+
+func TestNewTransformEmitter(t *testing.T) {
+	_, err := NewTransformEmitter(&captureEmitter{}, config.Transform{Temporality: "bogus"})
+	assert.ErrorContains(t, err, `unknown temporality "bogus"`)
+
+	tee, err := NewTeeEmitter(nil)
+	require.NoError(t, err)
+	assert.ErrorContains(t, tee.SetTransforms(map[string]config.Transform{"otlp": {Temporality: "bogus"}}), "otlp:")
+}
+
+func TestTransformEmitter_ToCumulative(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{}
+	base := time.Unix(1000, 0)
+	capture := &captureEmitter{}
+	e, err := NewTransformEmitter(capture, config.Transform{Temporality: TemporalityCumulative})
+	require.NoError(t, err)
+
+	for i, v := range []float64{2, 3, 5} {
+		start := base.Add(time.Duration(i) * time.Second)
+		md := makeSumMetrics(pmetric.AggregationTemporalityDelta, start, start.Add(time.Second), v)
+		require.NoError(t, e.EmitMetrics(ctx, rs, md))
+		// a retried payload converts the same way
+		if i == 1 {
+			require.NoError(t, e.EmitMetrics(ctx, rs, md))
+		}
+		// the caller's payload is left as generated
+		assert.Equal(t, v, sumPoints([]pmetric.Metrics{md})[0])
+	}
+	assert.Equal(t, []float64{2, 5, 5, 10}, sumPoints(capture.metrics))
+	last := capture.metrics[3].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, last.AggregationTemporality())
+	assert.Equal(t, base, last.DataPoints().At(0).StartTimestamp().AsTime().Local())
+}
+
+func TestTransformEmitter_ToCumulativeWithoutStart(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{}
+	base := time.Unix(1000, 0)
+	capture := &captureEmitter{}
+	e, err := NewTransformEmitter(capture, config.Transform{Temporality: TemporalityCumulative})
+	require.NoError(t, err)
+
+	for i, v := range []float64{2, 3} {
+		md := makeSumMetrics(pmetric.AggregationTemporalityDelta, time.Time{}, base.Add(time.Duration(i)*time.Second), v)
+		md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0).SetStartTimestamp(0)
+		require.NoError(t, e.EmitMetrics(ctx, rs, md))
+	}
+	assert.Equal(t, []float64{2, 5}, sumPoints(capture.metrics))
+	for _, md := range capture.metrics {
+		dp := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+		assert.Equal(t, base, dp.StartTimestamp().AsTime().Local(), "the series starts at its first point")
+	}
+}
+
+func TestTransformEmitter_ToDelta(t *testing.T) {
+	ctx := context.Background()
+	rs := &state.RunState{}
+	base := time.Unix(1000, 0)
+	restart := base.Add(3 * time.Second)
+	capture := &captureEmitter{}
+	e, err := NewTransformEmitter(capture, config.Transform{Temporality: TemporalityDelta})
+	require.NoError(t, err)
+
+	points := []struct {
+		start time.Time
+		value float64
+	}{
+		{base, 2},
+		{base, 5},
+		{base, 9},
+		// a counter reset starts over from the new value
+		{restart, 4},
+		{restart, 6},
+	}
+	for i, p := range points {
+		ts := base.Add(time.Duration(i+1) * time.Second)
+		require.NoError(t, e.EmitMetrics(ctx, rs, makeSumMetrics(pmetric.AggregationTemporalityCumulative, p.start, ts, p.value)))
+	}
+	// the first point has no delta and is not sent
+	assert.Equal(t, []float64{3, 4, 4, 2}, sumPoints(capture.metrics))
+	second := capture.metrics[1].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+	assert.Equal(t, base.Add(time.Second), second.StartTimestamp().AsTime().Local())
+	assert.Equal(t, base.Add(2*time.Second), second.Timestamp().AsTime().Local())
+}
+
+func TestTransformEmitter_FlattenAttributes(t *testing.T) {
+	capture := &captureEmitter{}
+	e, err := NewTransformEmitter(capture, config.Transform{FlattenAttributes: true})
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.Attributes().PutStr("http.method", "GET")
+	user := span.Attributes().PutEmptyMap("user")
+	user.PutStr("id", "42")
+	user.PutEmptyMap("geo").PutStr("country", "NZ")
+	tags := span.Attributes().PutEmptySlice("tags")
+	tags.AppendEmpty().SetStr("a")
+	tags.AppendEmpty().SetStr("b")
+
+	require.NoError(t, e.EmitTraces(context.Background(), &state.RunState{}, td))
+	require.Len(t, capture.traces, 1)
+	got := capture.traces[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().AsRaw()
+	assert.Equal(t, map[string]any{
+		"http.method":      "GET",
+		"user.id":          "42",
+		"user.geo.country": "NZ",
+		"tags":             `["a","b"]`,
+	}, got)
+	// the caller's spans keep their nested attributes
+	_, ok := span.Attributes().Get("user")
+	assert.True(t, ok)
+}
+
+func TestTeeEmitter_Transforms(t *testing.T) {
+	tee, err := NewTeeEmitter(nil)
+	require.NoError(t, err)
+	require.NoError(t, tee.SetTransforms(map[string]config.Transform{
+		"prometheus": {Temporality: TemporalityCumulative},
+		"typo":       {FlattenAttributes: true},
+	}))
+	asGenerated := &captureEmitter{}
+	cumulative := &captureEmitter{}
+	tee.Add("otlp", asGenerated)
+	tee.Add("prometheus", cumulative)
+
+	base := time.Unix(1000, 0)
+	for i := range 2 {
+		start := base.Add(time.Duration(i) * time.Second)
+		md := makeSumMetrics(pmetric.AggregationTemporalityDelta, start, start.Add(time.Second), 1)
+		require.NoError(t, tee.EmitMetrics(context.Background(), &state.RunState{}, md))
+	}
+	assert.Equal(t, []float64{1, 1}, sumPoints(asGenerated.metrics))
+	assert.Equal(t, []float64{1, 2}, sumPoints(cumulative.metrics))
+	assert.Equal(t, []string{"typo"}, tee.UnusedPolicies())
+}
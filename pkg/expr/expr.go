@@ -0,0 +1,377 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package expr parses and evaluates small arithmetic expressions over
+// named values, such as "traffic_level * 40 + 5" or "max(a, b) / 2".
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"unicode"
+)
+
+// Expr is a parsed expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Func is a function callable from an expression.
+type Func func(args ...float64) (float64, error)
+
+// builtins are callable from every expression.
+var builtins = map[string]Func{
+	"abs": func(args ...float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, errors.New("abs takes one argument")
+		}
+		return math.Abs(args[0]), nil
+	},
+	"min": func(args ...float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, errors.New("min takes at least one argument")
+		}
+		return slices.Min(args), nil
+	},
+	"max": func(args ...float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, errors.New("max takes at least one argument")
+		}
+		return slices.Max(args), nil
+	},
+}
+
+type env struct {
+	vars  map[string]float64
+	funcs map[string]Func
+}
+
+type node interface {
+	eval(env *env) (float64, error)
+	names(into map[string]struct{})
+	calls(into map[string]struct{})
+}
+
+type number float64
+
+type variable string
+
+type negate struct {
+	x node
+}
+
+type binary struct {
+	op   rune
+	l, r node
+}
+
+type call struct {
+	fn   string
+	args []node
+}
+
+// Parse parses an expression made of numbers, names, the operators
+// + - * /, unary minus, parentheses, and function calls such as
+// max(a, b).  Names are letters, digits, '_' and '.', and must not
+// start with a digit.  abs, min, and max are always available.
+func Parse(src string) (*Expr, error) {
+	p := &parser{src: []rune(src)}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", src, err)
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("expression %q: unexpected %q at offset %d", src, p.src[p.pos], p.pos)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluates the expression, looking names up in vars.
+func (e *Expr) Eval(vars map[string]float64) (float64, error) {
+	return e.root.eval(&env{vars: vars})
+}
+
+// EvalFuncs is Eval with extra functions, which take precedence over
+// the builtins.
+func (e *Expr) EvalFuncs(vars map[string]float64, funcs map[string]Func) (float64, error) {
+	return e.root.eval(&env{vars: vars, funcs: funcs})
+}
+
+// Names returns the names the expression refers to, sorted.
+func (e *Expr) Names() []string {
+	names := map[string]struct{}{}
+	e.root.names(names)
+	return slices.Sorted(maps.Keys(names))
+}
+
+// Calls returns the functions the expression calls, sorted.
+func (e *Expr) Calls() []string {
+	calls := map[string]struct{}{}
+	e.root.calls(calls)
+	return slices.Sorted(maps.Keys(calls))
+}
+
+func (n number) eval(*env) (float64, error) {
+	return float64(n), nil
+}
+
+func (n number) names(map[string]struct{}) {}
+
+func (n number) calls(map[string]struct{}) {}
+
+func (v variable) eval(env *env) (float64, error) {
+	x, ok := env.vars[string(v)]
+	if !ok {
+		return 0, fmt.Errorf("unknown name %q", string(v))
+	}
+	return x, nil
+}
+
+func (v variable) names(into map[string]struct{}) {
+	into[string(v)] = struct{}{}
+}
+
+func (v variable) calls(map[string]struct{}) {}
+
+func (u negate) eval(env *env) (float64, error) {
+	x, err := u.x.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	return -x, nil
+}
+
+func (u negate) names(into map[string]struct{}) {
+	u.x.names(into)
+}
+
+func (u negate) calls(into map[string]struct{}) {
+	u.x.calls(into)
+}
+
+func (b binary) eval(env *env) (float64, error) {
+	l, err := b.l.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.r.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	default:
+		if r == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return l / r, nil
+	}
+}
+
+func (b binary) names(into map[string]struct{}) {
+	b.l.names(into)
+	b.r.names(into)
+}
+
+func (b binary) calls(into map[string]struct{}) {
+	b.l.calls(into)
+	b.r.calls(into)
+}
+
+func (c call) eval(env *env) (float64, error) {
+	f, ok := env.funcs[c.fn]
+	if !ok {
+		f, ok = builtins[c.fn]
+	}
+	if !ok {
+		return 0, fmt.Errorf("unknown function %q", c.fn)
+	}
+	args := make([]float64, len(c.args))
+	for i, a := range c.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+	return f(args...)
+}
+
+func (c call) names(into map[string]struct{}) {
+	for _, a := range c.args {
+		a.names(into)
+	}
+}
+
+func (c call) calls(into map[string]struct{}) {
+	into[c.fn] = struct{}{}
+	for _, a := range c.args {
+		a.calls(into)
+	}
+}
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space rune, or 0 at the end of the input.
+func (p *parser) peek() rune {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) parseSum() (node, error) {
+	l, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == '+' || op == '-'; op = p.peek() {
+		p.pos++
+		r, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseProduct() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == '*' || op == '/'; op = p.peek() {
+		p.pos++
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek() == '-' {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negate{x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, errors.New("unexpected end of expression")
+	case c == '(':
+		p.pos++
+		x, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ')' at offset %d", p.pos)
+		}
+		p.pos++
+		return x, nil
+	case unicode.IsDigit(c) || c == '.':
+		start := p.pos
+		for p.pos < len(p.src) && (unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '.' ||
+			p.src[p.pos] == 'e' || p.src[p.pos] == 'E' ||
+			((p.src[p.pos] == '+' || p.src[p.pos] == '-') && (p.src[p.pos-1] == 'e' || p.src[p.pos-1] == 'E'))) {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(string(p.src[start:p.pos]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", string(p.src[start:p.pos]))
+		}
+		return number(v), nil
+	case isNameRune(c, true):
+		start := p.pos
+		for p.pos < len(p.src) && isNameRune(p.src[p.pos], false) {
+			p.pos++
+		}
+		name := string(p.src[start:p.pos])
+		if p.peek() == '(' {
+			p.pos++
+			return p.parseCall(name)
+		}
+		return variable(name), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", c, p.pos)
+	}
+}
+
+func (p *parser) parseCall(fn string) (node, error) {
+	c := call{fn: fn}
+	if p.peek() == ')' {
+		p.pos++
+		return c, nil
+	}
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		c.args = append(c.args, arg)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return c, nil
+		default:
+			return nil, fmt.Errorf("missing ')' at offset %d", p.pos)
+		}
+	}
+}
+
+func isNameRune(c rune, first bool) bool {
+	if unicode.IsLetter(c) || c == '_' {
+		return true
+	}
+	return !first && (unicode.IsDigit(c) || c == '.')
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]float64{"traffic_level": 2, "error.rate": 0.5}
+	tests := []struct {
+		src      string
+		expected float64
+	}{
+		{"1", 1},
+		{"traffic_level * 40 + 5", 85},
+		{"5 + traffic_level * 40", 85},
+		{"(traffic_level + 1) * 10", 30},
+		{"-traffic_level - -1", -1},
+		{"10 / 4 / 5", 0.5},
+		{"1e2 * error.rate", 50},
+		{"2.5e-1 * 4", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src)
+			require.NoError(t, err)
+			v, err := e.Eval(vars)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, v, 1e-12)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{"", "1 +", "(1 + 2", "1 2", "3 $ 4", "1..2"} {
+		_, err := Parse(src)
+		assert.Error(t, err, src)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	e, err := Parse("a / b")
+	require.NoError(t, err)
+	_, err = e.Eval(map[string]float64{"a": 1})
+	assert.EqualError(t, err, `unknown name "b"`)
+	_, err = e.Eval(map[string]float64{"a": 1, "b": 0})
+	assert.EqualError(t, err, "division by zero")
+}
+
+func TestNames(t *testing.T) {
+	e, err := Parse("b * (a + b) - 3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, e.Names())
+	assert.Equal(t, "b * (a + b) - 3", e.String())
+}
+
+func TestCalls(t *testing.T) {
+	e, err := Parse("max(a, 2 * b) + abs(-1) + gen(c)")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, e.Names())
+	assert.Equal(t, []string{"abs", "gen", "max"}, e.Calls())
+
+	vars := map[string]float64{"a": 3, "b": 2, "c": 10}
+	_, err = e.Eval(vars)
+	assert.EqualError(t, err, `unknown function "gen"`)
+
+	double := func(args ...float64) (float64, error) { return 2 * args[0], nil }
+	v, err := e.EvalFuncs(vars, map[string]Func{"gen": double})
+	require.NoError(t, err)
+	assert.Equal(t, 4.0+1+20, v)
+
+	e, err = Parse("min()")
+	require.NoError(t, err)
+	_, err = e.Eval(nil)
+	assert.EqualError(t, err, "min takes at least one argument")
+
+	for _, src := range []string{"max(1,", "max(1 2)", "max(,1)"} {
+		_, err := Parse(src)
+		assert.Error(t, err, src)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package featuregate holds feature gates toggled with the Collector's
+// --feature-gates convention: a comma-separated list of gate IDs, each
+// optionally prefixed with '+' to enable or '-' to disable it.
+package featuregate
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+type gate struct {
+	enabled     bool
+	description string
+}
+
+// Registry is a set of feature gates.
+type Registry struct {
+	mu    sync.RWMutex
+	gates map[string]*gate
+}
+
+func NewRegistry() *Registry {
+	return &Registry{gates: map[string]*gate{}}
+}
+
+var global = NewRegistry()
+
+// GlobalRegistry returns the process-wide registry.
+func GlobalRegistry() *Registry {
+	return global
+}
+
+// Register adds a gate with its default state.
+func (r *Registry) Register(id string, enabled bool, description string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gates[id] = &gate{enabled: enabled, description: description}
+}
+
+// IsEnabled reports whether the gate is on.  Unknown gates are off.
+func (r *Registry) IsEnabled(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	g, ok := r.gates[id]
+	return ok && g.enabled
+}
+
+// Apply sets gates from --feature-gates values.  Gates this build does
+// not know are logged and ignored, so command lines shared with
+// collectors keep working.
+func (r *Registry) Apply(values []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, value := range values {
+		for item := range strings.SplitSeq(value, ",") {
+			item = strings.TrimSpace(item)
+			enabled := true
+			switch {
+			case strings.HasPrefix(item, "-"):
+				enabled = false
+				item = item[1:]
+			case strings.HasPrefix(item, "+"):
+				item = item[1:]
+			}
+			if item == "" {
+				return errors.New("empty feature gate name")
+			}
+			g, ok := r.gates[item]
+			if !ok {
+				slog.Warn("Ignoring unknown feature gate", "gate", item)
+				continue
+			}
+			g.enabled = enabled
+		}
+	}
+	return nil
+}
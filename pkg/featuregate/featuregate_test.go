@@ -0,0 +1,37 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featuregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	r := NewRegistry()
+	r.Register("flutter.a", false, "")
+	r.Register("flutter.b", true, "")
+	r.Register("flutter.c", false, "")
+
+	assert.NoError(t, r.Apply([]string{"flutter.a,-flutter.b", "+flutter.c,receiver.unknown"}))
+	assert.True(t, r.IsEnabled("flutter.a"))
+	assert.False(t, r.IsEnabled("flutter.b"))
+	assert.True(t, r.IsEnabled("flutter.c"))
+	assert.False(t, r.IsEnabled("receiver.unknown"))
+
+	assert.Error(t, r.Apply([]string{"flutter.a,,flutter.b"}))
+	assert.Error(t, r.Apply([]string{"-"}))
+}
@@ -0,0 +1,266 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fit estimates generator parameters from a recorded series, so
+// a realistic scenario can start from production data rather than
+// guesses.
+package fit
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/timeline"
+)
+
+// Series is a recorded series, with each sample's offset from the
+// first.
+type Series struct {
+	Offsets []time.Duration
+	Values  []float64
+}
+
+// ReadCSV reads timestamp,value rows, with timestamps in Unix seconds
+// or RFC 3339.  A header row is skipped, as are columns after the
+// second.
+func ReadCSV(r io.Reader) (*Series, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	s := &Series{}
+	var first time.Time
+	for i, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("row %d: want timestamp,value", i+1)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(row[1]), 64)
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("row %d: invalid value: %w", i+1, err)
+		}
+		ts, err := parseTimestamp(strings.TrimSpace(row[0]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		if len(s.Values) == 0 {
+			first = ts
+		}
+		s.Offsets = append(s.Offsets, ts.Sub(first))
+		s.Values = append(s.Values, v)
+	}
+	if len(s.Values) == 0 {
+		return nil, errors.New("series has no samples")
+	}
+	return s, nil
+}
+
+func parseTimestamp(v string) (time.Time, error) {
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Unix(0, int64(secs*float64(time.Second))), nil
+	}
+	ts, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", v)
+	}
+	return ts, nil
+}
+
+// ReadPrometheus reads the series matching labels from a saved
+// /api/v1/query_range response, as the prometheusReplay generator does.
+func ReadPrometheus(body []byte, labels map[string]string) (*Series, error) {
+	offsets, values, err := generator.ParseQueryRange(body, labels)
+	if err != nil {
+		return nil, err
+	}
+	return &Series{Offsets: offsets, Values: values}, nil
+}
+
+// Params are the estimated shape of a series: a Baseline, a sinusoidal
+// season of Amplitude over Period that peaks Peak after the first
+// sample, and normal noise of StdDev around that.  Span is how long
+// the recording lasts, and Step its mean sample interval.
+type Params struct {
+	Baseline  float64
+	Amplitude float64
+	Period    time.Duration
+	Peak      time.Duration
+	StdDev    float64
+	Span      time.Duration
+	Step      time.Duration
+}
+
+// Fit estimates Params by least squares.  The season is only fitted
+// when the series covers at least one whole period; otherwise
+// Amplitude is 0 and the noise takes up the rest.
+func Fit(s *Series, period time.Duration) (Params, error) {
+	n := len(s.Values)
+	if n < 3 {
+		return Params{}, fmt.Errorf("need at least 3 samples, got %d", n)
+	}
+	if period <= 0 {
+		return Params{}, fmt.Errorf("invalid period: %s", period)
+	}
+	p := Params{Period: period, Span: s.Offsets[n-1]}
+	p.Step = (p.Span / time.Duration(n-1)).Round(time.Second)
+
+	seasonal := p.Span >= period
+	cols := 1
+	if seasonal {
+		cols = 3
+	}
+	// Normal equations for value ≈ c0 + c1·sin(ωt) + c2·cos(ωt).
+	var ata [3][3]float64
+	var atb [3]float64
+	row := func(t time.Duration) [3]float64 {
+		x := 2 * math.Pi * float64(t) / float64(period)
+		return [3]float64{1, math.Sin(x), math.Cos(x)}
+	}
+	for i, v := range s.Values {
+		r := row(s.Offsets[i])
+		for j := range cols {
+			for k := range cols {
+				ata[j][k] += r[j] * r[k]
+			}
+			atb[j] += r[j] * v
+		}
+	}
+	c, err := solve(ata, atb, cols)
+	if err != nil {
+		return Params{}, err
+	}
+	p.Baseline = c[0]
+	if seasonal {
+		p.Amplitude = math.Hypot(c[1], c[2])
+		// c1·sin(x) + c2·cos(x) = A·sin(x + φ), which peaks at x = π/2 − φ.
+		phase := math.Atan2(c[2], c[1])
+		peak := math.Mod(math.Pi/2-phase, 2*math.Pi)
+		if peak < 0 {
+			peak += 2 * math.Pi
+		}
+		p.Peak = time.Duration(peak / (2 * math.Pi) * float64(period))
+	}
+
+	var ss float64
+	for i, v := range s.Values {
+		d := v - p.At(s.Offsets[i])
+		ss += d * d
+	}
+	p.StdDev = math.Sqrt(ss / float64(n-cols))
+	return p, nil
+}
+
+// At is the fitted value, without noise, at offset t.
+func (p Params) At(t time.Duration) float64 {
+	if p.Amplitude == 0 {
+		return p.Baseline
+	}
+	x := 2 * math.Pi * float64(t-p.Peak) / float64(p.Period)
+	return p.Baseline + p.Amplitude*math.Cos(x)
+}
+
+// solve solves the first n rows and columns of a·x = b by Gaussian
+// elimination with partial pivoting.
+func solve(a [3][3]float64, b [3]float64, n int) ([3]float64, error) {
+	for col := range n {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return [3]float64{}, errors.New("samples are too sparse to fit a season")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+		for r := col + 1; r < n; r++ {
+			f := a[r][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[r][k] -= f * a[col][k]
+			}
+			b[r] -= f * b[col]
+		}
+	}
+	var x [3]float64
+	for r := n - 1; r >= 0; r-- {
+		x[r] = b[r]
+		for k := r + 1; k < n; k++ {
+			x[r] -= a[r][k] * x[k]
+		}
+		x[r] /= a[r][r]
+	}
+	return x, nil
+}
+
+// Metric writes the fitted series as a timeline metric covering the
+// recorded span: ramps of segment length that follow the baseline and
+// season, with normal noise of the fitted stdDev on top, emitted at the
+// recording's own step.
+func (p Params) Metric(name, metricType string, segment time.Duration) (timeline.Metric, error) {
+	if segment <= 0 {
+		return timeline.Metric{}, fmt.Errorf("invalid segment: %s", segment)
+	}
+	span := max(p.Span, segment)
+	var segments []timeline.Segment
+	for at := time.Duration(0); at < span; at += segment {
+		end := min(at+segment, span)
+		seg := timeline.Segment{
+			Type:    "segment",
+			StartTs: config.DurationFromDuration(at),
+			EndTs:   config.DurationFromDuration(end),
+			Target:  round(p.At(end)),
+		}
+		if at == 0 {
+			start := round(p.At(0))
+			seg.Start = &start
+		}
+		segments = append(segments, seg)
+	}
+	return timeline.Metric{
+		Name:               name,
+		Type:               metricType,
+		Frequency:          config.DurationFromDuration(p.Step),
+		ResourceAttributes: map[string]any{},
+		Variants: []timeline.Variant{{
+			Attributes: map[string]any{},
+			Timeline:   segments,
+			Noise: &timeline.NoiseConfig{
+				Variation: round(3 * p.StdDev),
+				StdDev:    round(p.StdDev),
+			},
+		}},
+	}, nil
+}
+
+// round keeps written values readable.
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fit
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/timeline"
+)
+
+func TestFit(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	s := &Series{}
+	for at := time.Duration(0); at <= 48*time.Hour; at += 5 * time.Minute {
+		x := 2 * math.Pi * float64(at-14*time.Hour) / float64(24*time.Hour)
+		s.Offsets = append(s.Offsets, at)
+		s.Values = append(s.Values, 100+30*math.Cos(x)+5*r.NormFloat64())
+	}
+
+	p, err := Fit(s, 24*time.Hour)
+	require.NoError(t, err)
+	assert.InDelta(t, 100, p.Baseline, 1)
+	assert.InDelta(t, 30, p.Amplitude, 1)
+	assert.InDelta(t, float64(14*time.Hour), float64(p.Peak), float64(15*time.Minute))
+	assert.InDelta(t, 5, p.StdDev, 0.5)
+	assert.Equal(t, 48*time.Hour, p.Span)
+	assert.Equal(t, 5*time.Minute, p.Step)
+
+	m, err := p.Metric("rps", "gauge", 6*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, m.Frequency.Get())
+	segments := m.Variants[0].Timeline
+	require.Len(t, segments, 8)
+	assert.Equal(t, 42*time.Hour, segments[7].StartTs.Get())
+	assert.Equal(t, 48*time.Hour, segments[7].EndTs.Get())
+	require.NotNil(t, segments[0].Start)
+	assert.InDelta(t, p.At(0), *segments[0].Start, 0.001)
+	assert.InDelta(t, p.At(12*time.Hour), segments[1].Target, 0.001)
+	assert.InDelta(t, 3*p.StdDev, m.Variants[0].Noise.Variation, 0.001)
+}
+
+func TestFit_ShortSeries(t *testing.T) {
+	s := &Series{
+		Offsets: []time.Duration{0, time.Minute, 2 * time.Minute, 3 * time.Minute},
+		Values:  []float64{10, 12, 8, 10},
+	}
+	p, err := Fit(s, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, p.Baseline)
+	assert.Zero(t, p.Amplitude, "less than a period has no season")
+	assert.InDelta(t, math.Sqrt(8.0/3), p.StdDev, 1e-9)
+
+	_, err = Fit(&Series{Offsets: []time.Duration{0}, Values: []float64{1}}, time.Hour)
+	assert.EqualError(t, err, "need at least 3 samples, got 1")
+}
+
+func TestFitMetric_RoundTrips(t *testing.T) {
+	p := Params{Baseline: 50, StdDev: 2, Span: 90 * time.Minute}
+	m, err := p.Metric("queue.depth", "gauge", time.Hour)
+	require.NoError(t, err)
+	b, err := json.Marshal(timeline.Timeline{Metrics: []timeline.Metric{m}})
+	require.NoError(t, err)
+	tl, err := timeline.ParseTimeline(b)
+	require.NoError(t, err)
+	require.Len(t, tl.Metrics, 1)
+	assert.Equal(t, 90*time.Minute, tl.Metrics[0].Variants[0].Timeline[1].EndTs.Get())
+}
+
+func TestReadCSV(t *testing.T) {
+	s, err := ReadCSV(strings.NewReader("timestamp,value\n1700000000,1.5\n1700000060,2\n2023-11-14T22:15:20Z,NaN\n2023-11-14T22:16:20Z,3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{0, time.Minute, 3 * time.Minute}, s.Offsets)
+	assert.Equal(t, []float64{1.5, 2, 3}, s.Values)
+
+	_, err = ReadCSV(strings.NewReader("1700000000,1\nyesterday,2\n"))
+	assert.EqualError(t, err, `row 2: invalid timestamp "yesterday"`)
+	_, err = ReadCSV(strings.NewReader("1700000000\n"))
+	assert.EqualError(t, err, "row 1: want timestamp,value")
+}
+
+func TestReadPrometheus(t *testing.T) {
+	body := `{"status": "success", "data": {"resultType": "matrix", "result": [
+		{"metric": {"job": "api"}, "values": [[1700000000, "4"], [1700000030, "6"]]},
+		{"metric": {"job": "db"}, "values": [[1700000000, "9"]]}
+	]}}`
+	s, err := ReadPrometheus([]byte(body), map[string]string{"job": "api"})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{0, 30 * time.Second}, s.Offsets)
+	assert.Equal(t, []float64{4, 6}, s.Values)
+}
@@ -16,6 +16,8 @@ package generator
 
 import (
 	"errors"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/cardinalhq/flutter/pkg/scriptaction"
@@ -31,6 +33,33 @@ type MetricGeneratorSpec struct {
 	Type string `mapstructure:"type" yaml:"type" json:"type"`
 }
 
+// typeAliases maps renamed generator types to their current names, so
+// older scenarios keep working.
+var typeAliases = map[string]string{
+	"gaussianNoise": "normalNoise",
+}
+
+var (
+	aliasWarnMu sync.Mutex
+	aliasWarned = map[string]bool{}
+)
+
+// resolveAlias returns the current name for a generator type, warning
+// once per process for each deprecated name used.
+func resolveAlias(generatorType string) string {
+	current, ok := typeAliases[generatorType]
+	if !ok {
+		return generatorType
+	}
+	aliasWarnMu.Lock()
+	defer aliasWarnMu.Unlock()
+	if !aliasWarned[generatorType] {
+		aliasWarned[generatorType] = true
+		slog.Warn("Deprecated metricGenerator type, use the new name", "type", generatorType, "replacement", current)
+	}
+	return current
+}
+
 func CreateMetricGenerator(mes scriptaction.ScriptAction) (MetricGenerator, error) {
 	if mes.Spec == nil {
 		return nil, errors.New("missing spec in metric generator")
@@ -43,19 +72,70 @@ func CreateMetricGenerator(mes scriptaction.ScriptAction) (MetricGenerator, erro
 	if !ok {
 		return nil, errors.New("type in metric generator spec is not a string")
 	}
+	generatorType = resolveAlias(generatorType)
 	switch generatorType {
+	case "anomaly":
+		return NewMetricAnomaly(mes.At, mes.Spec)
+	case "brownian":
+		return NewMetricBrownian(mes.At, mes.Spec)
+	case "clamp":
+		return NewMetricClamp(mes.At, mes.Spec)
 	case "constant":
 		return NewMetricConstant(mes.At, mes.Spec)
+	case "diurnal":
+		return NewMetricDiurnal(mes.At, mes.Spec)
+	case "dropout":
+		return NewMetricDropout(mes.At, mes.Spec)
+	case "expression":
+		return NewMetricExpression(mes.At, mes.Spec)
+	case "follow":
+		return NewMetricFollow(mes.At, mes.Spec)
+	case "gammaNoise":
+		return NewMetricGammaNoise(mes.At, mes.Spec)
+	case "holtWinters":
+		return NewMetricHoltWinters(mes.At, mes.Spec)
+	case "lognormalNoise":
+		return NewMetricLognormalNoise(mes.At, mes.Spec)
+	case "markov":
+		return NewMetricMarkov(mes.At, mes.Spec)
 	case "normalNoise":
 		return NewMetricNormalNoise(mes.At, mes.Spec)
+	case "outlier":
+		return NewMetricOutlier(mes.At, mes.Spec)
+	case "pid":
+		return NewMetricPID(mes.At, mes.Spec)
 	case "poissonNoise":
 		return NewMetricPoissonNoise(mes.At, mes.Spec)
+	case "prometheusReplay":
+		return NewMetricPrometheusReplay(mes.At, mes.Spec)
+	case "quantize":
+		return NewMetricQuantize(mes.At, mes.Spec)
+	case "queue":
+		return NewMetricQueue(mes.At, mes.Spec)
 	case "randomWalk":
 		return NewMetricRandomWalk(mes.At, mes.Spec)
 	case "ramp":
 		return NewMetricRamp(mes.At, mes.Spec)
+	case "saturation":
+		return NewMetricSaturation(mes.At, mes.Spec)
+	case "scale":
+		return NewMetricScale(mes.At, mes.Spec)
+	case "smoothNoise":
+		return NewMetricSmoothNoise(mes.At, mes.Spec)
+	case "sine":
+		return NewMetricSine(mes.At, mes.Spec)
 	case "spikyNoise":
 		return NewMetricSpikyNoise(mes.At, mes.Spec)
+	case "step":
+		return NewMetricStep(mes.At, mes.Spec)
+	case "threshold":
+		return NewMetricThreshold(mes.At, mes.Spec)
+	case "timeOfDay":
+		return NewMetricTimeOfDay(mes.At, mes.Spec)
+	case "uniformNoise":
+		return NewMetricUniformNoise(mes.At, mes.Spec)
+	case "weekly":
+		return NewMetricWeekly(mes.At, mes.Spec)
 	default:
 		return nil, errors.New("unknown metricGenerator type: " + generatorType)
 	}
@@ -0,0 +1,231 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Anomaly is one scheduled disturbance.  At is an offset from when the
+// generator was defined.  A spike jumps by Magnitude and decays back
+// over Duration, a dip does the same downwards, and a levelShift adds
+// Magnitude for Duration, or for the rest of the run if Duration is 0.
+type Anomaly struct {
+	Kind      string        `mapstructure:"kind" yaml:"kind" json:"kind"`
+	At        time.Duration `mapstructure:"at" yaml:"at" json:"at"`
+	Duration  time.Duration `mapstructure:"duration" yaml:"duration" json:"duration"`
+	Magnitude float64       `mapstructure:"magnitude" yaml:"magnitude" json:"magnitude"`
+}
+
+// Range is a uniform distribution over [Min, Max].
+type Range struct {
+	Min float64 `mapstructure:"min" yaml:"min" json:"min"`
+	Max float64 `mapstructure:"max" yaml:"max" json:"max"`
+}
+
+type DurationRange struct {
+	Min time.Duration `mapstructure:"min" yaml:"min" json:"min"`
+	Max time.Duration `mapstructure:"max" yaml:"max" json:"max"`
+}
+
+// RandomAnomalies places Count anomalies uniformly between From and To,
+// each of a kind picked from Kinds with a magnitude and duration drawn
+// from the ranges.
+type RandomAnomalies struct {
+	Count     int           `mapstructure:"count" yaml:"count" json:"count"`
+	From      time.Duration `mapstructure:"from" yaml:"from" json:"from"`
+	To        time.Duration `mapstructure:"to" yaml:"to" json:"to"`
+	Kinds     []string      `mapstructure:"kinds" yaml:"kinds" json:"kinds"`
+	Magnitude Range         `mapstructure:"magnitude" yaml:"magnitude" json:"magnitude"`
+	Duration  DurationRange `mapstructure:"duration" yaml:"duration" json:"duration"`
+}
+
+type MetricAnomalySpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	// Label names the anomalies in the log, to match them to metrics
+	// when labelling a dataset.
+	Label     string           `mapstructure:"label" yaml:"label" json:"label"`
+	Anomalies []Anomaly        `mapstructure:"anomalies" yaml:"anomalies" json:"anomalies"`
+	Random    *RandomAnomalies `mapstructure:"random" yaml:"random" json:"random"`
+}
+
+// MetricAnomaly overlays spikes, dips, and level shifts, either at fixed
+// times or drawn at random within a window.  The full schedule is
+// logged when the first value is emitted, so runs with a fixed seed
+// produce a labelled dataset.
+type MetricAnomaly struct {
+	spec      MetricAnomalySpec
+	at        time.Duration
+	schedule  []Anomaly
+	scheduled bool
+}
+
+var _ MetricGenerator = (*MetricAnomaly)(nil)
+
+func NewMetricAnomaly(at time.Duration, is map[string]any) (*MetricAnomaly, error) {
+	spec := MetricAnomalySpec{}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateAnomalies(spec); err != nil {
+		return nil, err
+	}
+	return &MetricAnomaly{
+		spec: spec,
+		at:   at,
+	}, nil
+}
+
+// Reconfigure replaces the anomalies and redraws any random ones,
+// with offsets from the redefinition.
+func (m *MetricAnomaly) Reconfigure(at time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	if _, ok := is["anomalies"]; ok {
+		newSpec.Anomalies = nil
+	}
+	if _, ok := is["random"]; ok {
+		newSpec.Random = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateAnomalies(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	m.at = at
+	m.scheduled = false
+	return nil
+}
+
+func (m *MetricAnomaly) Emit(rs *state.RunState, incoming float64) float64 {
+	if !m.scheduled {
+		m.schedule = m.draw(rs)
+		m.scheduled = true
+	}
+	elapsed := rs.Tick - m.at
+	value := incoming
+	for _, a := range m.schedule {
+		if elapsed < a.At {
+			break
+		}
+		since := elapsed - a.At
+		switch a.Kind {
+		case "levelShift":
+			if a.Duration == 0 || since < a.Duration {
+				value += a.Magnitude
+			}
+		case "spike", "dip":
+			if since < a.Duration {
+				decay := 1 - float64(since)/float64(a.Duration)
+				if a.Kind == "dip" {
+					decay = -decay
+				}
+				value += a.Magnitude * decay
+			}
+		}
+	}
+	return value
+}
+
+// draw builds the schedule from the fixed anomalies and random draws,
+// sorted by start, and logs it.
+func (m *MetricAnomaly) draw(rs *state.RunState) []Anomaly {
+	schedule := slices.Clone(m.spec.Anomalies)
+	if r := m.spec.Random; r != nil {
+		kinds := r.Kinds
+		if len(kinds) == 0 {
+			kinds = []string{"spike", "dip", "levelShift"}
+		}
+		for range r.Count {
+			schedule = append(schedule, Anomaly{
+				Kind:      kinds[rs.RND.IntN(len(kinds))],
+				At:        r.From + time.Duration(rs.RND.Int64N(int64(r.To-r.From)+1)),
+				Duration:  r.Duration.Min + time.Duration(rs.RND.Int64N(int64(r.Duration.Max-r.Duration.Min)+1)),
+				Magnitude: r.Magnitude.Min + rs.RND.Float64()*(r.Magnitude.Max-r.Magnitude.Min),
+			})
+		}
+	}
+	slices.SortStableFunc(schedule, func(a, b Anomaly) int {
+		return cmp.Compare(a.At, b.At)
+	})
+	for _, a := range schedule {
+		slog.Info("Anomaly scheduled", "label", m.spec.Label, "kind", a.Kind,
+			"start", m.at+a.At, "duration", a.Duration, "magnitude", a.Magnitude)
+	}
+	return schedule
+}
+
+func validateAnomalies(spec MetricAnomalySpec) error {
+	if len(spec.Anomalies) == 0 && spec.Random == nil {
+		return errors.New("no anomalies")
+	}
+	for _, a := range spec.Anomalies {
+		if err := validateAnomalyKind(a.Kind); err != nil {
+			return err
+		}
+		if a.At < 0 || a.Duration < 0 {
+			return errors.New("anomaly at and duration must not be negative")
+		}
+		if a.Kind != "levelShift" && a.Duration == 0 {
+			return fmt.Errorf("%s needs a duration", a.Kind)
+		}
+	}
+	if r := spec.Random; r != nil {
+		for _, k := range r.Kinds {
+			if err := validateAnomalyKind(k); err != nil {
+				return err
+			}
+		}
+		if r.Count < 0 {
+			return errors.New("random count must not be negative")
+		}
+		if r.From < 0 || r.To < r.From {
+			return errors.New("random window must have 0 <= from <= to")
+		}
+		if r.Magnitude.Max < r.Magnitude.Min {
+			return errors.New("random magnitude max is less than min")
+		}
+		if r.Duration.Min <= 0 || r.Duration.Max < r.Duration.Min {
+			return errors.New("random duration must have 0 < min <= max")
+		}
+	}
+	return nil
+}
+
+func validateAnomalyKind(kind string) error {
+	switch kind {
+	case "spike", "dip", "levelShift":
+		return nil
+	default:
+		return fmt.Errorf("invalid anomaly kind: %q", kind)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricAnomaly_Scheduled(t *testing.T) {
+	m, err := NewMetricAnomaly(time.Minute, map[string]any{
+		"anomalies": []any{
+			map[string]any{"kind": "spike", "at": "10s", "duration": "10s", "magnitude": 50.0},
+			map[string]any{"kind": "dip", "at": "30s", "duration": "20s", "magnitude": 20.0},
+			map[string]any{"kind": "levelShift", "at": "40s", "magnitude": 5.0},
+		},
+	})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Hour, 1)
+	tests := map[time.Duration]float64{
+		0:                100,
+		10 * time.Second: 150,
+		15 * time.Second: 125,
+		20 * time.Second: 100,
+		30 * time.Second: 80,
+		40 * time.Second: 95, // half-way through the dip, plus the shift
+		50 * time.Second: 105,
+		time.Hour:        105,
+	}
+	for offset, want := range tests {
+		rs.Tick = time.Minute + offset
+		assert.InDelta(t, want, m.Emit(rs, 100), 1e-9, "offset %s", offset)
+	}
+}
+
+func TestMetricAnomaly_Random(t *testing.T) {
+	spec := map[string]any{
+		"random": map[string]any{
+			"count":     5,
+			"from":      "1m",
+			"to":        "10m",
+			"kinds":     []any{"spike"},
+			"magnitude": map[string]any{"min": 10.0, "max": 20.0},
+			"duration":  map[string]any{"min": "10s", "max": "30s"},
+		},
+	}
+	m, err := NewMetricAnomaly(0, spec)
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Hour, 7)
+	m.Emit(rs, 0)
+	require.Len(t, m.schedule, 5)
+	for i, a := range m.schedule {
+		assert.Equal(t, "spike", a.Kind)
+		assert.GreaterOrEqual(t, a.At, time.Minute)
+		assert.LessOrEqual(t, a.At, 10*time.Minute)
+		assert.GreaterOrEqual(t, a.Duration, 10*time.Second)
+		assert.LessOrEqual(t, a.Duration, 30*time.Second)
+		assert.GreaterOrEqual(t, a.Magnitude, 10.0)
+		assert.LessOrEqual(t, a.Magnitude, 20.0)
+		if i > 0 {
+			assert.GreaterOrEqual(t, a.At, m.schedule[i-1].At)
+		}
+	}
+
+	// The same seed draws the same schedule.
+	again, err := NewMetricAnomaly(0, spec)
+	require.NoError(t, err)
+	again.Emit(state.NewRunState(time.Hour, 7), 0)
+	assert.Equal(t, m.schedule, again.schedule)
+}
+
+func TestMetricAnomaly_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec map[string]any
+		err  string
+	}{
+		{"empty", map[string]any{}, "no anomalies"},
+		{"bad kind", map[string]any{"anomalies": []any{map[string]any{"kind": "wobble"}}}, `invalid anomaly kind: "wobble"`},
+		{"spike without duration", map[string]any{"anomalies": []any{map[string]any{"kind": "spike"}}}, "spike needs a duration"},
+		{"inverted window", map[string]any{"random": map[string]any{"from": "2m", "to": "1m"}}, "random window must have 0 <= from <= to"},
+		{"no duration", map[string]any{"random": map[string]any{"count": 1}}, "random duration must have 0 < min <= max"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMetricAnomaly(0, tt.spec)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
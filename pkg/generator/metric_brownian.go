@@ -0,0 +1,125 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricBrownianSpec drives Brownian motion with drift: a random walk
+// with no pull back to a target, for slowly trending metrics such as
+// disk usage.  Between emits dt apart, the internal state steps like:
+//
+//	x ← x + Drift·dt/Per + Volatility·√(dt/Per)·z,  z ~ Normal(0, 1)
+//
+// then clamps into [Min, Max] for whichever bounds are set.
+// Emit(in) returns in + x.
+type MetricBrownianSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+
+	// Start is the value when the generator is first defined.
+	Start float64 `mapstructure:"start" yaml:"start" json:"start"`
+	// Drift is the expected change over Per.
+	Drift float64 `mapstructure:"drift" yaml:"drift" json:"drift"`
+	// Volatility is the standard deviation of the change over Per.
+	Volatility float64 `mapstructure:"volatility" yaml:"volatility" json:"volatility"`
+	// Per is the period Drift and Volatility are given for.
+	Per time.Duration `mapstructure:"per" yaml:"per" json:"per"`
+	// Min and Max, when set, bound the state, such as a disk that
+	// cannot be less than empty.
+	Min *float64 `mapstructure:"min" yaml:"min,omitempty" json:"min,omitempty"`
+	Max *float64 `mapstructure:"max" yaml:"max,omitempty" json:"max,omitempty"`
+}
+
+type MetricBrownian struct {
+	spec     MetricBrownianSpec
+	current  float64
+	lastTick time.Duration
+	started  bool
+}
+
+var _ MetricGenerator = (*MetricBrownian)(nil)
+
+func NewMetricBrownian(_ time.Duration, is map[string]any) (*MetricBrownian, error) {
+	spec := MetricBrownianSpec{
+		Per: time.Hour,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateBrownian(spec); err != nil {
+		return nil, err
+	}
+	return &MetricBrownian{
+		spec:    spec,
+		current: spec.Start,
+	}, nil
+}
+
+// Reconfigure changes how the value moves from now on.  The value
+// itself carries over; Start only applies when first defined.
+func (m *MetricBrownian) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateBrownian(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricBrownian) Emit(rs *state.RunState, incoming float64) float64 {
+	if m.started && rs.Tick > m.lastTick {
+		dt := float64(rs.Tick-m.lastTick) / float64(m.spec.Per)
+		m.current += m.spec.Drift*dt + m.spec.Volatility*math.Sqrt(dt)*rs.RND.NormFloat64()
+	}
+	m.started = true
+	m.lastTick = rs.Tick
+	if m.spec.Min != nil {
+		m.current = max(m.current, *m.spec.Min)
+	}
+	if m.spec.Max != nil {
+		m.current = min(m.current, *m.spec.Max)
+	}
+	return incoming + m.current
+}
+
+func validateBrownian(spec MetricBrownianSpec) error {
+	if spec.Per <= 0 {
+		return fmt.Errorf("invalid per: %s", spec.Per)
+	}
+	if spec.Volatility < 0 {
+		return fmt.Errorf("invalid volatility: %v", spec.Volatility)
+	}
+	if spec.Min != nil && spec.Max != nil && *spec.Min > *spec.Max {
+		return fmt.Errorf("min %v is greater than max %v", *spec.Min, *spec.Max)
+	}
+	return nil
+}
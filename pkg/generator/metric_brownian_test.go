@@ -0,0 +1,81 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricBrownian_Drift(t *testing.T) {
+	m, err := NewMetricBrownian(0, map[string]any{"start": 40.0, "drift": 2.0, "per": "1h"})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(24*time.Hour, 1)
+	assert.Equal(t, 40.0, m.Emit(rs, 0))
+	rs.Tick = 30 * time.Minute
+	assert.InDelta(t, 41, m.Emit(rs, 0), 1e-9)
+	rs.Tick = 6 * time.Hour
+	assert.InDelta(t, 52, m.Emit(rs, 0), 1e-9)
+}
+
+func TestMetricBrownian_NoMeanReversion(t *testing.T) {
+	m, err := NewMetricBrownian(0, map[string]any{"volatility": 1.0, "per": "1m"})
+	require.NoError(t, err)
+
+	// The spread of a driftless walk grows like √t:  after 100 steps the
+	// standard deviation across runs is about 10, not held near zero.
+	var sumSq float64
+	const runs = 2000
+	for i := range runs {
+		m.current, m.started = 0, false
+		rs := state.NewRunState(time.Hour, uint64(i+1))
+		var v float64
+		for step := range 101 {
+			rs.Tick = time.Duration(step) * time.Minute
+			v = m.Emit(rs, 0)
+		}
+		sumSq += v * v
+	}
+	assert.InDelta(t, 100, sumSq/runs, 10)
+}
+
+func TestMetricBrownian_Bounds(t *testing.T) {
+	m, err := NewMetricBrownian(0, map[string]any{"start": 5.0, "drift": -100.0, "min": 0.0})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Hour, 1)
+	m.Emit(rs, 0)
+	rs.Tick = time.Hour
+	assert.Equal(t, 0.0, m.Emit(rs, 0))
+
+	require.NoError(t, m.Reconfigure(0, map[string]any{"drift": 10.0}))
+	rs.Tick = 2 * time.Hour
+	assert.InDelta(t, 10, m.Emit(rs, 0), 1e-9, "a redefinition carries the value over")
+}
+
+func TestMetricBrownian_Invalid(t *testing.T) {
+	_, err := NewMetricBrownian(0, map[string]any{"per": "0s"})
+	assert.EqualError(t, err, "invalid per: 0s")
+	_, err = NewMetricBrownian(0, map[string]any{"volatility": -1.0})
+	assert.EqualError(t, err, "invalid volatility: -1")
+	_, err = NewMetricBrownian(0, map[string]any{"min": 1.0, "max": 0.0})
+	assert.EqualError(t, err, "min 1 is greater than max 0")
+}
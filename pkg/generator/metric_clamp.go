@@ -0,0 +1,92 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricClampSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Min                 *float64 `mapstructure:"min" yaml:"min,omitempty" json:"min,omitempty"`
+	Max                 *float64 `mapstructure:"max" yaml:"max,omitempty" json:"max,omitempty"`
+}
+
+// MetricClamp limits the value built by the generators before it to
+// [Min, Max], so it only makes sense later in a chain.  Either bound
+// may be left out.
+type MetricClamp struct {
+	spec MetricClampSpec
+}
+
+var _ MetricGenerator = (*MetricClamp)(nil)
+
+func NewMetricClamp(_ time.Duration, is map[string]any) (*MetricClamp, error) {
+	spec := MetricClampSpec{}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateClamp(spec); err != nil {
+		return nil, err
+	}
+	return &MetricClamp{
+		spec: spec,
+	}, nil
+}
+
+func (m *MetricClamp) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateClamp(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricClamp) Emit(_ *state.RunState, incoming float64) float64 {
+	if m.spec.Min != nil {
+		incoming = max(incoming, *m.spec.Min)
+	}
+	if m.spec.Max != nil {
+		incoming = min(incoming, *m.spec.Max)
+	}
+	return incoming
+}
+
+func validateClamp(spec MetricClampSpec) error {
+	if spec.Min == nil && spec.Max == nil {
+		return errors.New("clamp needs min, max, or both")
+	}
+	if spec.Min != nil && spec.Max != nil && *spec.Min > *spec.Max {
+		return fmt.Errorf("min %v is greater than max %v", *spec.Min, *spec.Max)
+	}
+	return nil
+}
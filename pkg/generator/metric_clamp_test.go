@@ -0,0 +1,60 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricClamp_Emit(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     map[string]any
+		incoming float64
+		expected float64
+	}{
+		{"floor", map[string]any{"min": 0.0}, -3, 0},
+		{"floor leaves values above", map[string]any{"min": 0.0}, 7, 7},
+		{"ceiling", map[string]any{"max": 100.0}, 140, 100},
+		{"both, inside", map[string]any{"min": 0.0, "max": 100.0}, 42, 42},
+		{"both, below", map[string]any{"min": 10.0, "max": 100.0}, 2, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMetricClamp(0, tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m.Emit(&state.RunState{}, tt.incoming))
+		})
+	}
+}
+
+func TestMetricClamp_Invalid(t *testing.T) {
+	_, err := NewMetricClamp(0, map[string]any{})
+	assert.EqualError(t, err, "clamp needs min, max, or both")
+	_, err = NewMetricClamp(0, map[string]any{"min": 5.0, "max": 1.0})
+	assert.EqualError(t, err, "min 5 is greater than max 1")
+
+	m, err := NewMetricClamp(0, map[string]any{"min": 0.0})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"max": -1.0}))
+	require.NoError(t, m.Reconfigure(0, map[string]any{"max": 10.0}))
+	assert.Equal(t, 10.0, m.Emit(&state.RunState{}, 20))
+	assert.Equal(t, 0.0, m.Emit(&state.RunState{}, -20))
+}
@@ -24,7 +24,7 @@ import (
 )
 
 type MetricConstantSpec struct {
-	MetricGeneratorSpec `mapstructure:",squash"`
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
 	Value               float64 `mapstructure:"value" yaml:"value" json:"value"`
 }
 
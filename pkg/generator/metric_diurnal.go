@@ -0,0 +1,118 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricDiurnalSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Timezone            string  `mapstructure:"timezone" yaml:"timezone" json:"timezone"`
+	Peak                float64 `mapstructure:"peak" yaml:"peak" json:"peak"`
+	Trough              float64 `mapstructure:"trough" yaml:"trough" json:"trough"`
+	PeakHour            float64 `mapstructure:"peakHour" yaml:"peakHour" json:"peakHour"`
+	TroughHour          float64 `mapstructure:"troughHour" yaml:"troughHour" json:"troughHour"`
+}
+
+// MetricDiurnal models a daily traffic curve from the run's wallclock:
+// it bottoms out at TroughHour, rises smoothly to Peak at PeakHour, and
+// tapers back down, in the local time of Timezone.
+type MetricDiurnal struct {
+	spec MetricDiurnalSpec
+	loc  *time.Location
+}
+
+var _ MetricGenerator = (*MetricDiurnal)(nil)
+
+func NewMetricDiurnal(_ time.Duration, is map[string]any) (*MetricDiurnal, error) {
+	spec := MetricDiurnalSpec{
+		Timezone:   "UTC",
+		PeakHour:   13,
+		TroughHour: 4,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	loc, err := validateDiurnal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricDiurnal{
+		spec: spec,
+		loc:  loc,
+	}, nil
+}
+
+func (m *MetricDiurnal) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	loc, err := validateDiurnal(newSpec)
+	if err != nil {
+		return err
+	}
+	m.spec = newSpec
+	m.loc = loc
+	return nil
+}
+
+func (m *MetricDiurnal) Emit(rs *state.RunState, incoming float64) float64 {
+	local := rs.Wallclock.In(m.loc)
+	hour := float64(local.Hour()) + float64(local.Minute())/60 + float64(local.Second())/3600
+
+	rise := math.Mod(m.spec.PeakHour-m.spec.TroughHour+24, 24)
+	sinceTrough := math.Mod(hour-m.spec.TroughHour+24, 24)
+	span := m.spec.Peak - m.spec.Trough
+	if sinceTrough < rise {
+		return incoming + m.spec.Trough + span*easeInOut(sinceTrough/rise)
+	}
+	return incoming + m.spec.Peak - span*easeInOut((sinceTrough-rise)/(24-rise))
+}
+
+// easeInOut maps [0, 1] onto [0, 1] along half a cosine, so the curve
+// is flat at both ends.
+func easeInOut(frac float64) float64 {
+	return (1 - math.Cos(math.Pi*frac)) / 2
+}
+
+func validateDiurnal(spec MetricDiurnalSpec) (*time.Location, error) {
+	loc, err := time.LoadLocation(spec.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", spec.Timezone, err)
+	}
+	if spec.PeakHour < 0 || spec.PeakHour >= 24 || spec.TroughHour < 0 || spec.TroughHour >= 24 {
+		return nil, errors.New("peakHour and troughHour must be in [0, 24)")
+	}
+	if spec.PeakHour == spec.TroughHour {
+		return nil, errors.New("peakHour and troughHour must differ")
+	}
+	return loc, nil
+}
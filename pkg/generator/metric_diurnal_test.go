@@ -0,0 +1,72 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricDiurnal_Emit(t *testing.T) {
+	m, err := NewMetricDiurnal(0, map[string]any{
+		"timezone": "America/New_York",
+		"peak":     100.0,
+		"trough":   10.0,
+	})
+	require.NoError(t, err)
+
+	at := func(hour, minute int) *state.RunState {
+		loc, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		return &state.RunState{Wallclock: time.Date(2025, 6, 2, hour, minute, 0, 0, loc)}
+	}
+
+	assert.InDelta(t, 10.0, m.Emit(at(4, 0), 0), 1e-9)
+	assert.InDelta(t, 100.0, m.Emit(at(13, 0), 0), 1e-9)
+	assert.InDelta(t, 55.0, m.Emit(at(8, 30), 0), 1e-9)  // halfway up
+	assert.InDelta(t, 55.0, m.Emit(at(20, 30), 0), 1e-9) // halfway down
+	assert.InDelta(t, 105.0, m.Emit(at(13, 0), 5), 1e-9)
+
+	morning, midday, evening := m.Emit(at(7, 0), 0), m.Emit(at(11, 0), 0), m.Emit(at(22, 0), 0)
+	assert.Less(t, morning, midday)
+	assert.Less(t, evening, midday)
+}
+
+func TestMetricDiurnal_Timezone(t *testing.T) {
+	m, err := NewMetricDiurnal(0, map[string]any{"peak": 1.0, "trough": 0.0, "timezone": "Asia/Tokyo"})
+	require.NoError(t, err)
+	// 04:00 UTC is 13:00 in Tokyo.
+	rs := &state.RunState{Wallclock: time.Date(2025, 6, 2, 4, 0, 0, 0, time.UTC)}
+	assert.InDelta(t, 1.0, m.Emit(rs, 0), 1e-9)
+}
+
+func TestMetricDiurnal_Invalid(t *testing.T) {
+	_, err := NewMetricDiurnal(0, map[string]any{"timezone": "Nowhere/Special"})
+	assert.Error(t, err)
+	_, err = NewMetricDiurnal(0, map[string]any{"peakHour": 25.0})
+	assert.Error(t, err)
+	_, err = NewMetricDiurnal(0, map[string]any{"peakHour": 4.0})
+	assert.Error(t, err)
+
+	m, err := NewMetricDiurnal(0, map[string]any{"peak": 1.0})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"timezone": "bogus"}))
+	assert.Equal(t, "UTC", m.spec.Timezone)
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricDropoutSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	// Probability is the chance that any one datapoint is dropped.
+	Probability float64 `mapstructure:"probability" yaml:"probability" json:"probability"`
+	// Windows drop every datapoint between their at and at plus
+	// duration, in script time.
+	Windows []DropoutWindow `mapstructure:"windows" yaml:"windows,omitempty" json:"windows,omitempty"`
+}
+
+type DropoutWindow struct {
+	At       time.Duration `mapstructure:"at" yaml:"at" json:"at"`
+	Duration time.Duration `mapstructure:"duration" yaml:"duration" json:"duration"`
+}
+
+// MetricDropout passes the value built by the generators before it
+// through unchanged, but asks the metric producer to skip the
+// datapoint at random or during outage windows, for scrape gaps and
+// agent outages.
+type MetricDropout struct {
+	spec MetricDropoutSpec
+}
+
+var _ MetricGenerator = (*MetricDropout)(nil)
+
+func NewMetricDropout(_ time.Duration, is map[string]any) (*MetricDropout, error) {
+	m := &MetricDropout{}
+	if err := m.Reconfigure(0, is); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *MetricDropout) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	if _, ok := is["windows"]; ok {
+		newSpec.Windows = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if newSpec.Probability < 0 || newSpec.Probability > 1 {
+		return fmt.Errorf("probability must be between 0 and 1, got %v", newSpec.Probability)
+	}
+	for _, w := range newSpec.Windows {
+		if w.Duration <= 0 {
+			return fmt.Errorf("dropout window at %s needs a positive duration", w.At)
+		}
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricDropout) Emit(rs *state.RunState, incoming float64) float64 {
+	if m.inWindow(rs.Tick) || (m.spec.Probability > 0 && rs.RND.Float64() < m.spec.Probability) {
+		rs.DropDatapoint = true
+	}
+	return incoming
+}
+
+func (m *MetricDropout) inWindow(tick time.Duration) bool {
+	for _, w := range m.spec.Windows {
+		if tick >= w.At && tick < w.At+w.Duration {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricDropout_Windows(t *testing.T) {
+	m, err := NewMetricDropout(0, map[string]any{
+		"windows": []any{map[string]any{"at": "10m", "duration": "2m"}},
+	})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		tick time.Duration
+		drop bool
+	}{
+		{9 * time.Minute, false},
+		{10 * time.Minute, true},
+		{11*time.Minute + 59*time.Second, true},
+		{12 * time.Minute, false},
+	} {
+		rs := state.NewRunState(time.Hour, 1)
+		rs.Tick = tt.tick
+		assert.Equal(t, 42.0, m.Emit(rs, 42), "the value passes through")
+		assert.Equal(t, tt.drop, rs.DropDatapoint, tt.tick.String())
+	}
+}
+
+func TestMetricDropout_Probability(t *testing.T) {
+	m, err := NewMetricDropout(0, map[string]any{"probability": 0.2})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Hour, 1)
+	dropped := 0
+	const n = 10000
+	for range n {
+		rs.DropDatapoint = false
+		m.Emit(rs, 0)
+		if rs.DropDatapoint {
+			dropped++
+		}
+	}
+	assert.InDelta(t, 0.2, float64(dropped)/n, 0.02)
+}
+
+func TestMetricDropout_Invalid(t *testing.T) {
+	_, err := NewMetricDropout(0, map[string]any{"probability": 1.5})
+	assert.EqualError(t, err, "probability must be between 0 and 1, got 1.5")
+	_, err = NewMetricDropout(0, map[string]any{"windows": []any{map[string]any{"at": "1m"}}})
+	assert.EqualError(t, err, "dropout window at 1m0s needs a positive duration")
+}
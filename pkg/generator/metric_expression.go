@@ -0,0 +1,220 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/expr"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Linker is implemented by generators that read other generators.
+// Link is called once every generator in the script exists.
+type Linker interface {
+	Link(generators map[string]MetricGenerator) error
+	// References returns the IDs of the generators read.
+	References() []string
+}
+
+// LinkGenerators links every Linker in generators and rejects
+// reference cycles.
+func LinkGenerators(generators map[string]MetricGenerator) error {
+	for id, g := range generators {
+		if l, ok := g.(Linker); ok {
+			if err := l.Link(generators); err != nil {
+				return fmt.Errorf("generator %s: %w", id, err)
+			}
+		}
+	}
+	// Depth-first search, with 1 marking generators on the current
+	// path and 2 those fully explored.
+	marks := map[string]int{}
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch marks[id] {
+		case 1:
+			return fmt.Errorf("generator %s refers to itself", id)
+		case 2:
+			return nil
+		}
+		marks[id] = 1
+		if l, ok := generators[id].(Linker); ok {
+			for _, ref := range l.References() {
+				if err := visit(ref); err != nil {
+					return err
+				}
+			}
+		}
+		marks[id] = 2
+		return nil
+	}
+	for id := range generators {
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type MetricExpressionSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Expression          string `mapstructure:"expression" yaml:"expression" json:"expression"`
+}
+
+// MetricExpression evaluates a formula over other generators, written
+// gen(id).  gen(id) is the value id last produced for a metric, so a
+// metric derived from another stays consistent with it and the source
+// is not advanced twice.  A generator no metric has used yet is
+// emitted on demand, from zero and on its own random stream, once per
+// tick however often the formula names it.
+type MetricExpression struct {
+	spec       MetricExpressionSpec
+	expr       *expr.Expr
+	generators map[string]MetricGenerator
+
+	tick       time.Duration
+	values     map[string]float64
+	evaluating bool
+	warned     bool
+}
+
+var _ MetricGenerator = (*MetricExpression)(nil)
+var _ Linker = (*MetricExpression)(nil)
+
+func NewMetricExpression(_ time.Duration, is map[string]any) (*MetricExpression, error) {
+	spec := MetricExpressionSpec{}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	e, err := parseGeneratorExpression(spec.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricExpression{
+		spec: spec,
+		expr: e,
+	}, nil
+}
+
+func (m *MetricExpression) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	e, err := parseGeneratorExpression(newSpec.Expression)
+	if err != nil {
+		return err
+	}
+	if m.generators != nil {
+		if err := checkReferences(e, m.generators); err != nil {
+			return err
+		}
+	}
+	m.spec = newSpec
+	m.expr = e
+	m.warned = false
+	return nil
+}
+
+func (m *MetricExpression) Link(generators map[string]MetricGenerator) error {
+	if err := checkReferences(m.expr, generators); err != nil {
+		return err
+	}
+	m.generators = generators
+	return nil
+}
+
+func (m *MetricExpression) References() []string {
+	return m.expr.Names()
+}
+
+func (m *MetricExpression) Emit(rs *state.RunState, incoming float64) float64 {
+	// A redefinition can introduce a cycle that linking never saw.
+	if m.evaluating || m.generators == nil {
+		m.warnOnce(errors.New("expression is not linked, or refers to itself"))
+		return incoming
+	}
+	m.evaluating = true
+	defer func() { m.evaluating = false }()
+
+	if m.values == nil || rs.Tick != m.tick {
+		m.tick = rs.Tick
+		m.values = map[string]float64{}
+	}
+	for _, id := range m.expr.Names() {
+		if v, ok := rs.GeneratorValue(id); ok {
+			m.values[id] = v
+			continue
+		}
+		if _, ok := m.values[id]; !ok {
+			rs.Fork("generator/"+id, func() {
+				m.values[id] = m.generators[id].Emit(rs, 0)
+			})
+		}
+	}
+	v, err := m.expr.EvalFuncs(m.values, exprFuncs)
+	if err != nil {
+		m.warnOnce(err)
+		return incoming
+	}
+	return incoming + v
+}
+
+func (m *MetricExpression) warnOnce(err error) {
+	if !m.warned {
+		m.warned = true
+		slog.Warn("Expression generator cannot be evaluated", "expression", m.spec.Expression, "error", err)
+	}
+}
+
+// exprFuncs adds gen to the expression builtins.  Generator values are
+// bound to their IDs, so gen only marks the reference.
+var exprFuncs = map[string]expr.Func{
+	"gen": func(args ...float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, errors.New("gen takes one generator ID")
+		}
+		return args[0], nil
+	},
+}
+
+func parseGeneratorExpression(src string) (*expr.Expr, error) {
+	if src == "" {
+		return nil, errors.New("missing expression")
+	}
+	return expr.Parse(src)
+}
+
+func checkReferences(e *expr.Expr, generators map[string]MetricGenerator) error {
+	for _, id := range e.Names() {
+		if _, ok := generators[id]; !ok {
+			return errors.New("unknown generator: " + id)
+		}
+	}
+	return nil
+}
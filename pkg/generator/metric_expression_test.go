@@ -0,0 +1,74 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricExpression_Emit(t *testing.T) {
+	traffic, err := NewMetricRamp(0, map[string]any{"start": 100.0, "target": 200.0, "duration": "100s"})
+	require.NoError(t, err)
+	base, err := NewMetricConstant(0, map[string]any{"value": 3.0})
+	require.NoError(t, err)
+	errs, err := NewMetricExpression(0, map[string]any{"expression": "gen(traffic) * 0.02 + gen(base) * gen(base)"})
+	require.NoError(t, err)
+
+	gens := map[string]MetricGenerator{"traffic": traffic, "base": base, "errors": errs}
+	require.NoError(t, LinkGenerators(gens))
+	assert.Equal(t, []string{"base", "traffic"}, errs.References())
+
+	rs := &state.RunState{Tick: 50 * time.Second}
+	assert.InDelta(t, 1.0+3+9, errs.Emit(rs, 1), 1e-9)
+
+	require.NoError(t, errs.Reconfigure(0, map[string]any{"expression": "max(gen(traffic), 500)"}))
+	assert.Equal(t, 500.0, errs.Emit(rs, 0))
+	assert.EqualError(t, errs.Reconfigure(0, map[string]any{"expression": "gen(nope)"}), "unknown generator: nope")
+}
+
+func TestMetricExpression_OncePerTick(t *testing.T) {
+	walk, err := NewMetricRandomWalk(0, map[string]any{"stepSize": 1.0, "variation": 10.0})
+	require.NoError(t, err)
+	diff, err := NewMetricExpression(0, map[string]any{"expression": "gen(walk) - gen(walk)"})
+	require.NoError(t, err)
+	require.NoError(t, LinkGenerators(map[string]MetricGenerator{"walk": walk, "diff": diff}))
+
+	rs := state.NewRunState(time.Minute, 1)
+	for i := range 10 {
+		rs.Tick = time.Duration(i) * time.Second
+		assert.Equal(t, 0.0, diff.Emit(rs, 0))
+	}
+}
+
+func TestLinkGenerators_Errors(t *testing.T) {
+	a, err := NewMetricExpression(0, map[string]any{"expression": "gen(b) + 1"})
+	require.NoError(t, err)
+	b, err := NewMetricExpression(0, map[string]any{"expression": "gen(a) * 2"})
+	require.NoError(t, err)
+	assert.ErrorContains(t, LinkGenerators(map[string]MetricGenerator{"a": a, "b": b}), "refers to itself")
+
+	c, err := NewMetricExpression(0, map[string]any{"expression": "gen(missing)"})
+	require.NoError(t, err)
+	assert.EqualError(t, LinkGenerators(map[string]MetricGenerator{"c": c}), "generator c: unknown generator: missing")
+
+	_, err = NewMetricExpression(0, map[string]any{})
+	assert.EqualError(t, err, "missing expression")
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricFollowSpec adds a scaled, lagged copy of another metric
+// producer's emitted value, so metrics that move together in
+// production, such as CPU and request rate, move together here.
+// Emit(in) returns in + Factor·v, where v is the value Metric emitted
+// at or before Lag ago.  Values are recorded once every producer has
+// run for a tick, so with no lag a follower sees the value from the
+// followed metric's previous emit.
+type MetricFollowSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+
+	// Metric is the ID of the metric producer to follow.
+	Metric string `mapstructure:"metric" yaml:"metric" json:"metric"`
+	// Factor scales the followed value.
+	Factor float64 `mapstructure:"factor" yaml:"factor" json:"factor"`
+	// Lag delays the followed value, up to state.MaxMetricLag.
+	Lag time.Duration `mapstructure:"lag" yaml:"lag" json:"lag"`
+}
+
+type MetricFollow struct {
+	spec MetricFollowSpec
+}
+
+var _ MetricGenerator = (*MetricFollow)(nil)
+
+func NewMetricFollow(_ time.Duration, is map[string]any) (*MetricFollow, error) {
+	spec := MetricFollowSpec{
+		Factor: 1,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateFollow(spec); err != nil {
+		return nil, err
+	}
+	return &MetricFollow{
+		spec: spec,
+	}, nil
+}
+
+func (m *MetricFollow) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateFollow(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricFollow) Emit(rs *state.RunState, incoming float64) float64 {
+	v, ok := rs.MetricValue(m.spec.Metric, rs.Tick-m.spec.Lag)
+	if !ok {
+		return incoming
+	}
+	return incoming + m.spec.Factor*v
+}
+
+func validateFollow(spec MetricFollowSpec) error {
+	if spec.Metric == "" {
+		return errors.New("follow needs a metric")
+	}
+	if spec.Lag < 0 || spec.Lag > state.MaxMetricLag {
+		return fmt.Errorf("invalid lag: %s (want 0 to %s)", spec.Lag, state.MaxMetricLag)
+	}
+	return nil
+}
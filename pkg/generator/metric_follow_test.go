@@ -0,0 +1,63 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricFollow_Emit(t *testing.T) {
+	m, err := NewMetricFollow(0, map[string]any{"metric": "rps", "factor": 0.01, "lag": "20s"})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Hour, 1)
+	assert.Equal(t, 5.0, m.Emit(rs, 5), "nothing to follow yet")
+	for i, v := range []float64{100, 200, 300, 400} {
+		rs.Tick = time.Duration(i) * 10 * time.Second
+		rs.RecordMetric("rps", rs.Tick, v)
+	}
+	rs.Tick = 40 * time.Second
+	assert.InDelta(t, 8, m.Emit(rs, 5), 1e-9, "the value from 20s ago")
+	rs.Tick = 45 * time.Second
+	assert.InDelta(t, 8, m.Emit(rs, 5), 1e-9, "the last value at or before 25s")
+
+	require.NoError(t, m.Reconfigure(0, map[string]any{"lag": "0s", "factor": 1.0}))
+	assert.Equal(t, 400.0, m.Emit(rs, 0))
+}
+
+func TestMetricFollow_HistoryTrimmed(t *testing.T) {
+	rs := state.NewRunState(24*time.Hour, 1)
+	for tick := time.Duration(0); tick <= 3*time.Hour; tick += 10 * time.Second {
+		rs.RecordMetric("rps", tick, float64(tick/time.Second))
+	}
+	v, ok := rs.MetricValue("rps", 2*time.Hour)
+	assert.True(t, ok)
+	assert.Equal(t, 7200.0, v)
+	_, ok = rs.MetricValue("rps", 90*time.Minute)
+	assert.False(t, ok, "samples beyond the maximum lag are dropped")
+}
+
+func TestMetricFollow_Invalid(t *testing.T) {
+	_, err := NewMetricFollow(0, map[string]any{})
+	assert.EqualError(t, err, "follow needs a metric")
+	_, err = NewMetricFollow(0, map[string]any{"metric": "rps", "lag": "2h"})
+	assert.EqualError(t, err, "invalid lag: 2h0m0s (want 0 to 1h0m0s)")
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricHoltWintersSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	// Level is the value when the generator is defined, before
+	// seasonality and noise.
+	Level float64 `mapstructure:"level" yaml:"level" json:"level"`
+	// Trend is the change in level over Per.
+	Trend float64       `mapstructure:"trend" yaml:"trend" json:"trend"`
+	Per   time.Duration `mapstructure:"per" yaml:"per" json:"per"`
+	// Period is the length of one season.
+	Period time.Duration `mapstructure:"period" yaml:"period" json:"period"`
+	// Seasonal holds additive offsets spread evenly over Period and
+	// interpolated between.  When empty, the season is a sine wave of
+	// Amplitude.
+	Seasonal  []float64 `mapstructure:"seasonal" yaml:"seasonal,omitempty" json:"seasonal,omitempty"`
+	Amplitude float64   `mapstructure:"amplitude" yaml:"amplitude" json:"amplitude"`
+	// StdDev is the standard deviation of normal noise on top.
+	StdDev float64 `mapstructure:"stdDev" yaml:"stdDev" json:"stdDev"`
+}
+
+// MetricHoltWinters builds a long-horizon series from the three parts
+// a Holt-Winters forecast separates: a level with a linear trend, an
+// additive season, and noise.  The season follows the run's wallclock,
+// so a 24h period lines up with UTC days.
+type MetricHoltWinters struct {
+	spec MetricHoltWintersSpec
+	// level is the trended level at time at.
+	level float64
+	at    time.Duration
+}
+
+var _ MetricGenerator = (*MetricHoltWinters)(nil)
+
+func NewMetricHoltWinters(at time.Duration, is map[string]any) (*MetricHoltWinters, error) {
+	spec := MetricHoltWintersSpec{
+		Per:    time.Hour,
+		Period: 24 * time.Hour,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateHoltWinters(spec); err != nil {
+		return nil, err
+	}
+	return &MetricHoltWinters{
+		spec:  spec,
+		level: spec.Level,
+		at:    at,
+	}, nil
+}
+
+// Reconfigure carries the trended level over to the new spec, so a new
+// trend starts from where the series is, unless level is given.
+func (m *MetricHoltWinters) Reconfigure(at time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	if _, ok := is["seasonal"]; ok {
+		newSpec.Seasonal = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateHoltWinters(newSpec); err != nil {
+		return err
+	}
+	level := m.trended(at)
+	if _, ok := is["level"]; ok {
+		level = newSpec.Level
+	}
+	m.spec = newSpec
+	m.level = level
+	m.at = at
+	return nil
+}
+
+func (m *MetricHoltWinters) Emit(rs *state.RunState, incoming float64) float64 {
+	value := m.trended(rs.Tick) + m.season(rs.Wallclock)
+	if m.spec.StdDev > 0 {
+		value += m.spec.StdDev * rs.RND.NormFloat64()
+	}
+	return incoming + value
+}
+
+func (m *MetricHoltWinters) trended(tick time.Duration) float64 {
+	return m.level + m.spec.Trend*float64(tick-m.at)/float64(m.spec.Per)
+}
+
+// season returns the seasonal offset for t.
+func (m *MetricHoltWinters) season(t time.Time) float64 {
+	period := m.spec.Period
+	phase := float64(t.UnixNano()%int64(period)) / float64(period)
+	if phase < 0 {
+		phase++
+	}
+	if len(m.spec.Seasonal) == 0 {
+		return m.spec.Amplitude * math.Sin(2*math.Pi*phase)
+	}
+	n := len(m.spec.Seasonal)
+	pos := phase * float64(n)
+	i := int(pos) % n
+	frac := pos - math.Floor(pos)
+	return m.spec.Seasonal[i] + (m.spec.Seasonal[(i+1)%n]-m.spec.Seasonal[i])*frac
+}
+
+func validateHoltWinters(spec MetricHoltWintersSpec) error {
+	if spec.Per <= 0 {
+		return fmt.Errorf("invalid per: %s", spec.Per)
+	}
+	if spec.Period <= 0 {
+		return fmt.Errorf("invalid period: %s", spec.Period)
+	}
+	if spec.StdDev < 0 {
+		return fmt.Errorf("invalid stdDev: %v", spec.StdDev)
+	}
+	if len(spec.Seasonal) > 0 && spec.Amplitude != 0 {
+		return errors.New("seasonal and amplitude cannot both be set")
+	}
+	return nil
+}
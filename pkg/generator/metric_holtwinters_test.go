@@ -0,0 +1,99 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricHoltWinters_TrendAndSine(t *testing.T) {
+	m, err := NewMetricHoltWinters(0, map[string]any{
+		"level": 100.0, "trend": 24.0, "per": "24h", "period": "24h", "amplitude": 10.0,
+	})
+	require.NoError(t, err)
+
+	midnight := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rs := &state.RunState{Wallclock: midnight}
+	assert.InDelta(t, 100, m.Emit(rs, 0), 1e-9)
+
+	// A quarter of the way through the day the sine peaks.
+	rs.Tick, rs.Wallclock = 6*time.Hour, midnight.Add(6*time.Hour)
+	assert.InDelta(t, 100+6+10, m.Emit(rs, 0), 1e-9)
+
+	// The same phase a day later has a day's more trend.
+	rs.Tick, rs.Wallclock = 30*time.Hour, midnight.Add(30*time.Hour)
+	assert.InDelta(t, 100+30+10, m.Emit(rs, 0), 1e-9)
+}
+
+func TestMetricHoltWinters_Seasonal(t *testing.T) {
+	m, err := NewMetricHoltWinters(0, map[string]any{
+		"period": "4h", "seasonal": []any{0.0, 10.0, 20.0, 10.0},
+	})
+	require.NoError(t, err)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		offset time.Duration
+		want   float64
+	}{
+		{0, 0},
+		{30 * time.Minute, 5},
+		{2 * time.Hour, 20},
+		{3*time.Hour + 30*time.Minute, 5},
+		{4 * time.Hour, 0},
+	} {
+		rs := &state.RunState{Tick: tt.offset, Wallclock: start.Add(tt.offset)}
+		assert.InDelta(t, tt.want, m.Emit(rs, 0), 1e-9, "at %s", tt.offset)
+	}
+}
+
+func TestMetricHoltWinters_Reconfigure(t *testing.T) {
+	m, err := NewMetricHoltWinters(0, map[string]any{"level": 10.0, "trend": 1.0})
+	require.NoError(t, err)
+
+	// After 5h the level is 15; a flat trend holds it there.
+	require.NoError(t, m.Reconfigure(5*time.Hour, map[string]any{"trend": 0.0}))
+	rs := &state.RunState{Tick: 10 * time.Hour}
+	assert.InDelta(t, 15, m.Emit(rs, 0), 1e-9)
+
+	require.NoError(t, m.Reconfigure(10*time.Hour, map[string]any{"level": 50.0}))
+	assert.InDelta(t, 50, m.Emit(rs, 0), 1e-9)
+}
+
+func TestMetricHoltWinters_Noise(t *testing.T) {
+	m, err := NewMetricHoltWinters(0, map[string]any{"level": 100.0, "stdDev": 5.0})
+	require.NoError(t, err)
+	rs := state.NewRunState(time.Hour, 3)
+	var sum float64
+	for range 5000 {
+		sum += m.Emit(rs, 0)
+	}
+	assert.InDelta(t, 100, sum/5000, 0.5)
+}
+
+func TestMetricHoltWinters_Invalid(t *testing.T) {
+	_, err := NewMetricHoltWinters(0, map[string]any{"period": "0s"})
+	assert.EqualError(t, err, "invalid period: 0s")
+	_, err = NewMetricHoltWinters(0, map[string]any{"stdDev": -1.0})
+	assert.EqualError(t, err, "invalid stdDev: -1")
+	_, err = NewMetricHoltWinters(0, map[string]any{"seasonal": []any{1.0}, "amplitude": 2.0})
+	assert.EqualError(t, err, "seasonal and amplitude cannot both be set")
+}
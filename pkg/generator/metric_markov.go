@@ -0,0 +1,156 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Regime is one state of a MetricMarkov.
+type Regime struct {
+	Target float64 `mapstructure:"target" yaml:"target" json:"target"`
+	StdDev float64 `mapstructure:"stdDev" yaml:"stdDev" json:"stdDev"`
+}
+
+type MetricMarkovSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Regimes             map[string]Regime             `mapstructure:"regimes" yaml:"regimes" json:"regimes"`
+	Transitions         map[string]map[string]float64 `mapstructure:"transitions" yaml:"transitions" json:"transitions"`
+	Initial             string                        `mapstructure:"initial" yaml:"initial" json:"initial"`
+}
+
+// MetricMarkov switches between named regimes, such as "normal",
+// "degraded" and "outage", as a Markov chain.  On each Emit it first
+// moves from the current regime to another with the probability given
+// in Transitions, staying put otherwise, and then samples that regime's
+// Normal(Target, StdDev²).
+type MetricMarkov struct {
+	spec    MetricMarkovSpec
+	current string
+}
+
+var _ MetricGenerator = (*MetricMarkov)(nil)
+
+func NewMetricMarkov(_ time.Duration, is map[string]any) (*MetricMarkov, error) {
+	spec := MetricMarkovSpec{}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateMarkov(spec); err != nil {
+		return nil, err
+	}
+	return &MetricMarkov{
+		spec:    spec,
+		current: spec.Initial,
+	}, nil
+}
+
+// Reconfigure replaces the regimes and transitions given.  The chain
+// stays in its current regime if that still exists.
+func (m *MetricMarkov) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	if _, ok := is["regimes"]; ok {
+		newSpec.Regimes = nil
+	}
+	if _, ok := is["transitions"]; ok {
+		newSpec.Transitions = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateMarkov(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	if _, ok := newSpec.Regimes[m.current]; !ok {
+		m.current = newSpec.Initial
+	}
+	return nil
+}
+
+func (m *MetricMarkov) Emit(rs *state.RunState, incoming float64) float64 {
+	m.current = m.next(rs)
+	regime := m.spec.Regimes[m.current]
+	return incoming + regime.Target + regime.StdDev*rs.RND.NormFloat64()
+}
+
+// Regime returns the name of the current regime.
+func (m *MetricMarkov) Regime() string {
+	return m.current
+}
+
+func (m *MetricMarkov) next(rs *state.RunState) string {
+	row := m.spec.Transitions[m.current]
+	if len(row) == 0 {
+		return m.current
+	}
+	r := rs.RND.Float64()
+	// Walk the targets in name order so a seed always gives the same run.
+	for _, to := range slices.Sorted(maps.Keys(row)) {
+		if r < row[to] {
+			return to
+		}
+		r -= row[to]
+	}
+	return m.current
+}
+
+func validateMarkov(spec MetricMarkovSpec) error {
+	if len(spec.Regimes) == 0 {
+		return errors.New("no regimes")
+	}
+	if _, ok := spec.Regimes[spec.Initial]; !ok {
+		return fmt.Errorf("unknown initial regime %q", spec.Initial)
+	}
+	for name, regime := range spec.Regimes {
+		if regime.StdDev < 0 {
+			return fmt.Errorf("regime %q: stdDev must not be negative", name)
+		}
+	}
+	for from, row := range spec.Transitions {
+		if _, ok := spec.Regimes[from]; !ok {
+			return fmt.Errorf("transitions from unknown regime %q", from)
+		}
+		total := 0.0
+		for to, p := range row {
+			if _, ok := spec.Regimes[to]; !ok {
+				return fmt.Errorf("transition from %q to unknown regime %q", from, to)
+			}
+			if p < 0 {
+				return fmt.Errorf("transition from %q to %q has a negative probability", from, to)
+			}
+			total += p
+		}
+		if total > 1 {
+			return fmt.Errorf("transitions from %q add up to more than 1", from)
+		}
+	}
+	return nil
+}
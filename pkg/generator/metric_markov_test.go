@@ -0,0 +1,115 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func markovSpec() map[string]any {
+	return map[string]any{
+		"initial": "normal",
+		"regimes": map[string]any{
+			"normal":   map[string]any{"target": 100.0},
+			"degraded": map[string]any{"target": 300.0, "stdDev": 10.0},
+			"outage":   map[string]any{"target": 0.0},
+		},
+		"transitions": map[string]any{
+			"normal":   map[string]any{"degraded": 0.05},
+			"degraded": map[string]any{"normal": 0.2, "outage": 0.1},
+			"outage":   map[string]any{"normal": 0.5},
+		},
+	}
+}
+
+func TestMetricMarkov_Emit(t *testing.T) {
+	m, err := NewMetricMarkov(0, markovSpec())
+	require.NoError(t, err)
+	assert.Equal(t, "normal", m.Regime())
+
+	rs := state.NewRunState(time.Hour, 42)
+	seen := map[string]int{}
+	for range 2000 {
+		v := m.Emit(rs, 0)
+		seen[m.Regime()]++
+		switch m.Regime() {
+		case "normal":
+			assert.Equal(t, 100.0, v)
+		case "outage":
+			assert.Equal(t, 0.0, v)
+		}
+	}
+	assert.Len(t, seen, 3)
+	assert.Greater(t, seen["normal"], seen["degraded"])
+
+	// The same seed replays the same regimes.
+	a, err := NewMetricMarkov(0, markovSpec())
+	require.NoError(t, err)
+	b, err := NewMetricMarkov(0, markovSpec())
+	require.NoError(t, err)
+	ra, rb := state.NewRunState(time.Hour, 7), state.NewRunState(time.Hour, 7)
+	for range 200 {
+		assert.Equal(t, a.Emit(ra, 0), b.Emit(rb, 0))
+	}
+}
+
+func TestMetricMarkov_Invalid(t *testing.T) {
+	tests := map[string]func(map[string]any){
+		"unknown initial": func(s map[string]any) { s["initial"] = "bogus" },
+		"unknown target": func(s map[string]any) {
+			s["transitions"] = map[string]any{"normal": map[string]any{"bogus": 0.1}}
+		},
+		"over one": func(s map[string]any) {
+			s["transitions"] = map[string]any{"degraded": map[string]any{"normal": 0.7, "outage": 0.6}}
+		},
+		"negative": func(s map[string]any) {
+			s["transitions"] = map[string]any{"normal": map[string]any{"outage": -0.1}}
+		},
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := markovSpec()
+			mutate(spec)
+			_, err := NewMetricMarkov(0, spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMetricMarkov_Reconfigure(t *testing.T) {
+	m, err := NewMetricMarkov(0, markovSpec())
+	require.NoError(t, err)
+	m.current = "degraded"
+
+	require.NoError(t, m.Reconfigure(0, map[string]any{
+		"transitions": map[string]any{"degraded": map[string]any{"normal": 1.0}},
+	}))
+	assert.Equal(t, "degraded", m.Regime())
+	m.Emit(state.NewRunState(time.Hour, 1), 0)
+	assert.Equal(t, "normal", m.Regime())
+
+	require.NoError(t, m.Reconfigure(0, map[string]any{
+		"initial":     "calm",
+		"regimes":     map[string]any{"calm": map[string]any{"target": 1.0}},
+		"transitions": map[string]any{},
+	}))
+	assert.Equal(t, "calm", m.Regime())
+}
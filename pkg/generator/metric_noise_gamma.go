@@ -0,0 +1,117 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricGammaNoiseSpec drives skewed, positive-only noise.  On each
+// Emit(), it samples:
+//
+//	x ~ Gamma(Shape, Scale)
+//
+// whose mean is Shape·Scale.  Emit(in) returns in + x.
+type MetricGammaNoiseSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+
+	// Shape (k) sets the skew: below 1 most draws are near zero, and
+	// as it grows the distribution approaches a normal one.
+	Shape float64 `mapstructure:"shape" yaml:"shape" json:"shape"`
+	// Scale (θ) stretches the distribution.
+	Scale float64 `mapstructure:"scale" yaml:"scale" json:"scale"`
+}
+
+type MetricGammaNoise struct {
+	spec MetricGammaNoiseSpec
+}
+
+var _ MetricGenerator = (*MetricGammaNoise)(nil)
+
+func NewMetricGammaNoise(_ time.Duration, is map[string]any) (*MetricGammaNoise, error) {
+	spec := MetricGammaNoiseSpec{
+		Shape: 1,
+		Scale: 1,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateGamma(spec); err != nil {
+		return nil, err
+	}
+	return &MetricGammaNoise{spec: spec}, nil
+}
+
+func (m *MetricGammaNoise) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateGamma(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricGammaNoise) Emit(st *state.RunState, incoming float64) float64 {
+	return incoming + sampleGamma(m.spec.Shape, st.RND)*m.spec.Scale
+}
+
+func validateGamma(spec MetricGammaNoiseSpec) error {
+	if spec.Shape <= 0 {
+		return fmt.Errorf("invalid shape: %v", spec.Shape)
+	}
+	if spec.Scale <= 0 {
+		return fmt.Errorf("invalid scale: %v", spec.Scale)
+	}
+	return nil
+}
+
+// sampleGamma returns a Gamma(k, 1) variate, using Marsaglia and
+// Tsang's method.  For k < 1 it samples Gamma(k+1) and scales by U^(1/k).
+func sampleGamma(k float64, r *rand.Rand) float64 {
+	if k < 1 {
+		return sampleGamma(k+1, r) * math.Pow(r.Float64(), 1/k)
+	}
+	d := k - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := r.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := r.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestSampleGamma_Moments(t *testing.T) {
+	for _, k := range []float64{0.5, 2, 9} {
+		r := state.MakeRNG(5)
+		const n = 50000
+		var sum, sumSq float64
+		for range n {
+			x := sampleGamma(k, r)
+			require.Greater(t, x, 0.0)
+			sum += x
+			sumSq += x * x
+		}
+		mean := sum / n
+		variance := sumSq/n - mean*mean
+		// Gamma(k, 1) has mean and variance k
+		assert.InDelta(t, k, mean, 0.05*k+0.02, "mean for shape %v", k)
+		assert.InDelta(t, k, variance, 0.1*k+0.02, "variance for shape %v", k)
+	}
+}
+
+func TestMetricGammaNoise_Emit(t *testing.T) {
+	m, err := NewMetricGammaNoise(0, map[string]any{"shape": 2.0, "scale": 50.0})
+	require.NoError(t, err)
+
+	rs := &state.RunState{RND: state.MakeRNG(9)}
+	var sum float64
+	for range 20000 {
+		v := m.Emit(rs, 10)
+		require.Greater(t, v, 10.0)
+		sum += v - 10
+	}
+	assert.InDelta(t, 100, sum/20000, 3, "mean is shape·scale")
+}
+
+func TestMetricGammaNoise_Invalid(t *testing.T) {
+	_, err := NewMetricGammaNoise(0, map[string]any{"shape": 0.0})
+	assert.EqualError(t, err, "invalid shape: 0")
+	_, err = NewMetricGammaNoise(0, map[string]any{"scale": -2.0})
+	assert.EqualError(t, err, "invalid scale: -2")
+
+	m, err := NewMetricGammaNoise(0, map[string]any{})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"shape": -1.0}))
+	assert.Equal(t, 1.0, m.spec.Shape)
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricLognormalNoiseSpec drives a heavy right-tailed noise generator.
+// On each Emit(), it samples:
+//
+//	x = exp(Mu + Sigma·z),  z ~ Normal(0, 1)
+//
+// so the median is exp(Mu), then caps x at Max when it is set.
+// Emit(in) returns in + x.
+type MetricLognormalNoiseSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+
+	// Mu is the mean of the underlying normal distribution.
+	Mu float64 `mapstructure:"mu" yaml:"mu" json:"mu"`
+	// Sigma is the standard deviation of the underlying normal
+	// distribution; larger values give a longer tail.
+	Sigma float64 `mapstructure:"sigma" yaml:"sigma" json:"sigma"`
+	// Max caps the sample, if set.
+	Max *float64 `mapstructure:"max" yaml:"max,omitempty" json:"max,omitempty"`
+}
+
+type MetricLognormalNoise struct {
+	spec MetricLognormalNoiseSpec
+}
+
+var _ MetricGenerator = (*MetricLognormalNoise)(nil)
+
+func NewMetricLognormalNoise(_ time.Duration, is map[string]any) (*MetricLognormalNoise, error) {
+	spec := MetricLognormalNoiseSpec{
+		Sigma: 1,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateLognormal(spec); err != nil {
+		return nil, err
+	}
+	return &MetricLognormalNoise{spec: spec}, nil
+}
+
+func (m *MetricLognormalNoise) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateLognormal(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricLognormalNoise) Emit(st *state.RunState, incoming float64) float64 {
+	sample := math.Exp(m.spec.Mu + m.spec.Sigma*st.RND.NormFloat64())
+	if m.spec.Max != nil {
+		sample = min(sample, *m.spec.Max)
+	}
+	return incoming + sample
+}
+
+func validateLognormal(spec MetricLognormalNoiseSpec) error {
+	if spec.Sigma < 0 {
+		return fmt.Errorf("invalid sigma: %v", spec.Sigma)
+	}
+	if spec.Max != nil && *spec.Max <= 0 {
+		return fmt.Errorf("invalid max: %v", *spec.Max)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricLognormalNoise_Distribution(t *testing.T) {
+	m, err := NewMetricLognormalNoise(0, map[string]any{"mu": math.Log(100), "sigma": 0.5})
+	require.NoError(t, err)
+
+	rs := &state.RunState{RND: state.MakeRNG(42)}
+	samples := make([]float64, 20000)
+	var sum float64
+	for i := range samples {
+		samples[i] = m.Emit(rs, 0)
+		sum += samples[i]
+	}
+	slices.Sort(samples)
+
+	assert.Greater(t, samples[0], 0.0)
+	assert.InDelta(t, 100, samples[len(samples)/2], 3, "median is exp(mu)")
+	// the mean of a lognormal is exp(mu + sigma²/2), above the median
+	assert.InDelta(t, 100*math.Exp(0.125), sum/float64(len(samples)), 3)
+}
+
+func TestMetricLognormalNoise_Max(t *testing.T) {
+	m, err := NewMetricLognormalNoise(0, map[string]any{"mu": 0.0, "sigma": 3.0, "max": 50.0})
+	require.NoError(t, err)
+
+	rs := &state.RunState{RND: state.MakeRNG(7)}
+	for range 1000 {
+		v := m.Emit(rs, 10)
+		assert.Greater(t, v, 10.0)
+		assert.LessOrEqual(t, v, 60.0)
+	}
+}
+
+func TestMetricLognormalNoise_Invalid(t *testing.T) {
+	_, err := NewMetricLognormalNoise(0, map[string]any{"sigma": -1.0})
+	assert.EqualError(t, err, "invalid sigma: -1")
+	_, err = NewMetricLognormalNoise(0, map[string]any{"max": 0.0})
+	assert.EqualError(t, err, "invalid max: 0")
+
+	m, err := NewMetricLognormalNoise(0, map[string]any{})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"sigma": -0.5}))
+	assert.Equal(t, 1.0, m.spec.Sigma)
+}
@@ -31,7 +31,7 @@ import (
 // then clamps x into [Target-Variation, Target+Variation].
 // Emit(in) returns in + x.
 type MetricNormalNoiseSpec struct {
-	MetricGeneratorSpec `mapstructure:",squash"`
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
 
 	// Target is the mean around which Normal noise is drawn.
 	Target float64 `mapstructure:"target" yaml:"target" json:"target"`
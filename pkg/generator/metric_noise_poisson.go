@@ -34,7 +34,7 @@ var validPoissonDirs = []string{"positive", "negative", "both"}
 // then clamps it to [max(0,Target−Variation) … Target+Variation]
 // and applies directionality.
 type MetricPoissonNoiseSpec struct {
-	MetricGeneratorSpec `mapstructure:",squash"`
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
 
 	// Target is the expected events per Emit() interval.
 	Target float64 `mapstructure:"target" yaml:"target" json:"target"`
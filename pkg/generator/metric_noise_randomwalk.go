@@ -32,7 +32,7 @@ import (
 // then clamps into [target−variation, target+variation].
 // Emit(in) returns in + x.
 type MetricRandomWalkSpec struct {
-	MetricGeneratorSpec `mapstructure:",squash"`
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
 	Target              float64 `mapstructure:"target" yaml:"target" json:"target"`
 	Elasticity          float64 `mapstructure:"elasticity" yaml:"elasticity" json:"elasticity"`
 	StepSize            float64 `mapstructure:"stepSize" yaml:"stepSize" json:"stepSize"`
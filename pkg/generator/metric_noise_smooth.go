@@ -0,0 +1,120 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricSmoothNoiseSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Target              float64       `mapstructure:"target" yaml:"target" json:"target"`
+	Amplitude           float64       `mapstructure:"amplitude" yaml:"amplitude" json:"amplitude"`
+	Period              time.Duration `mapstructure:"period" yaml:"period" json:"period"`
+}
+
+// MetricSmoothNoise emits temporally-correlated value noise: a random
+// value in [Target-Amplitude, Target+Amplitude] is drawn every Period,
+// and samples in between ease smoothly from one to the next, so gauges
+// look like continuous measurements rather than independent draws.
+type MetricSmoothNoise struct {
+	spec MetricSmoothNoiseSpec
+	at   time.Duration
+	// knot is the index of the lattice point held in from; to is the
+	// value at the next one.
+	knot     int64
+	from, to float64
+	started  bool
+}
+
+var _ MetricGenerator = (*MetricSmoothNoise)(nil)
+
+func NewMetricSmoothNoise(at time.Duration, is map[string]any) (*MetricSmoothNoise, error) {
+	spec := MetricSmoothNoiseSpec{
+		Period: time.Minute,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateSmoothNoise(spec); err != nil {
+		return nil, err
+	}
+	return &MetricSmoothNoise{
+		spec: spec,
+		at:   at,
+	}, nil
+}
+
+// Reconfigure changes the shape of the noise.  The lattice keeps its
+// position, so the series does not jump.
+func (m *MetricSmoothNoise) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateSmoothNoise(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricSmoothNoise) Emit(rs *state.RunState, incoming float64) float64 {
+	elapsed := max(rs.Tick-m.at, 0)
+	knot := int64(elapsed / m.spec.Period)
+	if !m.started {
+		m.knot = knot
+		m.from = m.draw(rs)
+		m.to = m.draw(rs)
+		m.started = true
+	}
+	for m.knot < knot {
+		m.knot++
+		m.from = m.to
+		m.to = m.draw(rs)
+	}
+	frac := float64(elapsed-time.Duration(m.knot)*m.spec.Period) / float64(m.spec.Period)
+	// Smoothstep eases in and out of each lattice value.
+	frac = frac * frac * (3 - 2*frac)
+	noise := m.from + (m.to-m.from)*frac
+	return incoming + m.spec.Target + m.spec.Amplitude*noise
+}
+
+// draw returns a lattice value in [-1, 1].
+func (m *MetricSmoothNoise) draw(rs *state.RunState) float64 {
+	return rs.RND.Float64()*2 - 1
+}
+
+func validateSmoothNoise(spec MetricSmoothNoiseSpec) error {
+	if spec.Period <= 0 {
+		return errors.New("invalid period")
+	}
+	if spec.Amplitude < 0 {
+		return errors.New("amplitude must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricSmoothNoise_Emit(t *testing.T) {
+	m, err := NewMetricSmoothNoise(0, map[string]any{
+		"target":    50.0,
+		"amplitude": 10.0,
+		"period":    "1m",
+	})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Hour, 3)
+	prev := math.NaN()
+	maxStep := 0.0
+	for i := range 3600 {
+		rs.Tick = time.Duration(i) * time.Second
+		v := m.Emit(rs, 0)
+		assert.GreaterOrEqual(t, v, 40.0)
+		assert.LessOrEqual(t, v, 60.0)
+		if !math.IsNaN(prev) {
+			maxStep = max(maxStep, math.Abs(v-prev))
+		}
+		prev = v
+	}
+	// Adjacent one-second samples move at most 1.5 * 20 / 60 per second,
+	// the steepest slope of a smoothstep across a full swing.
+	assert.LessOrEqual(t, maxStep, 0.5+1e-9)
+	assert.Positive(t, maxStep)
+}
+
+func TestMetricSmoothNoise_Invalid(t *testing.T) {
+	_, err := NewMetricSmoothNoise(0, map[string]any{"period": 0})
+	assert.EqualError(t, err, "invalid period")
+	_, err = NewMetricSmoothNoise(0, map[string]any{"amplitude": -1.0})
+	assert.Error(t, err)
+
+	m, err := NewMetricSmoothNoise(0, map[string]any{"amplitude": 1.0})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"period": "-1s"}))
+	assert.NoError(t, m.Reconfigure(0, map[string]any{"amplitude": 4.0}))
+	assert.Equal(t, 4.0, m.spec.Amplitude)
+	assert.Equal(t, time.Minute, m.spec.Period)
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricUniformNoiseSpec drives bounded jitter with no central
+// tendency.  On each Emit(), it samples:
+//
+//	x ~ Uniform(Min, Max)
+//
+// Emit(in) returns in + x.
+type MetricUniformNoiseSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+
+	// Min is the smallest value drawn.
+	Min float64 `mapstructure:"min" yaml:"min" json:"min"`
+	// Max is the largest value drawn.
+	Max float64 `mapstructure:"max" yaml:"max" json:"max"`
+}
+
+type MetricUniformNoise struct {
+	spec MetricUniformNoiseSpec
+}
+
+var _ MetricGenerator = (*MetricUniformNoise)(nil)
+
+func NewMetricUniformNoise(_ time.Duration, is map[string]any) (*MetricUniformNoise, error) {
+	spec := MetricUniformNoiseSpec{}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if spec.Min > spec.Max {
+		return nil, fmt.Errorf("min %v is greater than max %v", spec.Min, spec.Max)
+	}
+	return &MetricUniformNoise{spec: spec}, nil
+}
+
+func (m *MetricUniformNoise) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if newSpec.Min > newSpec.Max {
+		return fmt.Errorf("min %v is greater than max %v", newSpec.Min, newSpec.Max)
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricUniformNoise) Emit(st *state.RunState, incoming float64) float64 {
+	return incoming + m.spec.Min + st.RND.Float64()*(m.spec.Max-m.spec.Min)
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricUniformNoise_Emit(t *testing.T) {
+	m, err := NewMetricUniformNoise(0, map[string]any{"min": -5.0, "max": 5.0})
+	require.NoError(t, err)
+
+	rs := &state.RunState{RND: state.MakeRNG(11)}
+	var sum float64
+	var below, above int
+	for range 10000 {
+		v := m.Emit(rs, 100)
+		require.GreaterOrEqual(t, v, 95.0)
+		require.Less(t, v, 105.0)
+		sum += v
+		// uniform draws fill the tails as much as the middle
+		if v < 96 {
+			below++
+		}
+		if v >= 104 {
+			above++
+		}
+	}
+	assert.InDelta(t, 100, sum/10000, 0.2)
+	assert.InDelta(t, 1000, below, 150)
+	assert.InDelta(t, 1000, above, 150)
+}
+
+func TestMetricUniformNoise_Invalid(t *testing.T) {
+	_, err := NewMetricUniformNoise(0, map[string]any{"min": 2.0, "max": 1.0})
+	assert.EqualError(t, err, "min 2 is greater than max 1")
+
+	m, err := NewMetricUniformNoise(0, map[string]any{"max": 1.0})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"min": 3.0}))
+	require.NoError(t, m.Reconfigure(0, map[string]any{"min": 1.0}))
+	assert.Equal(t, 11.0, m.Emit(&state.RunState{RND: state.MakeRNG(1)}, 10))
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricOutlierSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	// Probability is the chance that any one value is an outlier.
+	Probability float64 `mapstructure:"probability" yaml:"probability" json:"probability"`
+	// Multiplier scales the incoming value into an outlier.
+	Multiplier *float64 `mapstructure:"multiplier" yaml:"multiplier,omitempty" json:"multiplier,omitempty"`
+	// Value replaces the incoming value outright.
+	Value *float64 `mapstructure:"value" yaml:"value,omitempty" json:"value,omitempty"`
+}
+
+// MetricOutlier now and then replaces the value built by the generators
+// before it with an extreme one, to test outlier handling downstream.
+// Without Multiplier or Value, outliers are ten times the value.
+type MetricOutlier struct {
+	spec MetricOutlierSpec
+}
+
+var _ MetricGenerator = (*MetricOutlier)(nil)
+
+// defaultOutlierMultiplier is used when neither multiplier nor value
+// is set.
+const defaultOutlierMultiplier = 10
+
+func NewMetricOutlier(_ time.Duration, is map[string]any) (*MetricOutlier, error) {
+	m := &MetricOutlier{}
+	if err := m.Reconfigure(0, is); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *MetricOutlier) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if newSpec.Probability < 0 || newSpec.Probability > 1 {
+		return fmt.Errorf("probability must be between 0 and 1, got %v", newSpec.Probability)
+	}
+	if newSpec.Multiplier != nil && newSpec.Value != nil {
+		return errors.New("an outlier has a multiplier or a value, not both")
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricOutlier) Emit(rs *state.RunState, incoming float64) float64 {
+	if m.spec.Probability == 0 || rs.RND.Float64() >= m.spec.Probability {
+		return incoming
+	}
+	if m.spec.Value != nil {
+		return *m.spec.Value
+	}
+	multiplier := float64(defaultOutlierMultiplier)
+	if m.spec.Multiplier != nil {
+		multiplier = *m.spec.Multiplier
+	}
+	return incoming * multiplier
+}
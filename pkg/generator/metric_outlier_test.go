@@ -0,0 +1,67 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricOutlier_Emit(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     map[string]any
+		expected float64
+	}{
+		{"default multiplier", map[string]any{"probability": 1.0}, 500},
+		{"multiplier", map[string]any{"probability": 1.0, "multiplier": -3.0}, -150},
+		{"value", map[string]any{"probability": 1.0, "value": 1e9}, 1e9},
+		{"never", map[string]any{"value": 1e9}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMetricOutlier(0, tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m.Emit(state.NewRunState(time.Hour, 1), 50))
+		})
+	}
+}
+
+func TestMetricOutlier_Probability(t *testing.T) {
+	m, err := NewMetricOutlier(0, map[string]any{"probability": 0.01, "value": -1.0})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Hour, 1)
+	outliers := 0
+	const n = 100000
+	for range n {
+		if m.Emit(rs, 10) == -1 {
+			outliers++
+		}
+	}
+	assert.InDelta(t, 0.01, float64(outliers)/n, 0.002)
+}
+
+func TestMetricOutlier_Invalid(t *testing.T) {
+	_, err := NewMetricOutlier(0, map[string]any{"probability": -0.1})
+	assert.EqualError(t, err, "probability must be between 0 and 1, got -0.1")
+	_, err = NewMetricOutlier(0, map[string]any{"multiplier": 2.0, "value": 1.0})
+	assert.EqualError(t, err, "an outlier has a multiplier or a value, not both")
+}
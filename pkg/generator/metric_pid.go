@@ -0,0 +1,138 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricPIDSpec drives a value toward a target with a PID controller,
+// as an autoscaler drives replica count or utilization.  Each second
+// the error e = target − value moves the value by
+//
+//	Kp·e + Ki·∫e dt + Kd·de/dt
+//
+// so with the integral term the value overshoots the target and
+// oscillates before settling.  Target, when unset, is the value built
+// by the generators before it, so the controller chases a moving
+// demand.
+type MetricPIDSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+
+	// Start is the value when the generator is first defined.
+	Start float64 `mapstructure:"start" yaml:"start" json:"start"`
+	// Target is the setpoint.  When set, the controlled value is
+	// added to the incoming value instead of tracking it.
+	Target *float64 `mapstructure:"target" yaml:"target,omitempty" json:"target,omitempty"`
+	Kp     float64  `mapstructure:"kp" yaml:"kp" json:"kp"`
+	Ki     float64  `mapstructure:"ki" yaml:"ki" json:"ki"`
+	Kd     float64  `mapstructure:"kd" yaml:"kd" json:"kd"`
+}
+
+type MetricPID struct {
+	spec     MetricPIDSpec
+	value    float64
+	integral float64
+	lastErr  float64
+	lastTick time.Duration
+	started  bool
+}
+
+var _ MetricGenerator = (*MetricPID)(nil)
+
+// pidStep is the controller's update interval.  Longer gaps between
+// emits are stepped through at this interval, so a slow metric settles
+// the same way as a fast one.
+const pidStep = time.Second
+
+func NewMetricPID(_ time.Duration, is map[string]any) (*MetricPID, error) {
+	spec := MetricPIDSpec{
+		Kp: 0.2,
+		Ki: 0.04,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validatePID(spec); err != nil {
+		return nil, err
+	}
+	return &MetricPID{
+		spec:  spec,
+		value: spec.Start,
+	}, nil
+}
+
+// Reconfigure changes the target or gains from now on.  The value and
+// the controller's integral carry over, so a new target is approached
+// from wherever the value is.
+func (m *MetricPID) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validatePID(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricPID) Emit(rs *state.RunState, incoming float64) float64 {
+	target, base := incoming, 0.0
+	if m.spec.Target != nil {
+		target, base = *m.spec.Target, incoming
+	}
+	if !m.started {
+		m.started = true
+		m.lastTick = rs.Tick
+		m.lastErr = target - m.value
+	}
+	for ; m.lastTick+pidStep <= rs.Tick; m.lastTick += pidStep {
+		m.step(target, pidStep.Seconds())
+	}
+	return base + m.value
+}
+
+func (m *MetricPID) step(target, dt float64) {
+	e := target - m.value
+	m.integral += e * dt
+	derivative := (e - m.lastErr) / dt
+	m.lastErr = e
+	m.value += (m.spec.Kp*e + m.spec.Ki*m.integral + m.spec.Kd*derivative) * dt
+}
+
+func validatePID(spec MetricPIDSpec) error {
+	if spec.Kp < 0 || spec.Ki < 0 || spec.Kd < 0 {
+		return fmt.Errorf("PID gains must not be negative, got kp %v, ki %v, kd %v", spec.Kp, spec.Ki, spec.Kd)
+	}
+	// Stepped once a second, the controller diverges instead of
+	// settling unless this holds.
+	if spec.Kp+2*spec.Kd >= 2 {
+		return fmt.Errorf("kp + 2*kd must be less than 2 for the value to settle, got %v", spec.Kp+2*spec.Kd)
+	}
+	return nil
+}
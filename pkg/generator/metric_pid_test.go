@@ -0,0 +1,88 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// runPID emits m once a frequency for duration, returning the values.
+func runPID(m *MetricPID, incoming float64, frequency, duration time.Duration) []float64 {
+	var values []float64
+	rs := &state.RunState{}
+	for tick := time.Duration(0); tick <= duration; tick += frequency {
+		rs.Tick = tick
+		values = append(values, m.Emit(rs, incoming))
+	}
+	return values
+}
+
+func TestMetricPID_OvershootsAndSettles(t *testing.T) {
+	m, err := NewMetricPID(0, map[string]any{"start": 0.0})
+	require.NoError(t, err)
+	values := runPID(m, 100, time.Second, 10*time.Minute)
+
+	assert.Equal(t, 0.0, values[0])
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	assert.Greater(t, peak, 110.0, "expected the value to overshoot")
+	assert.InDelta(t, 100, values[len(values)-1], 0.01, "expected the value to settle on the target")
+}
+
+func TestMetricPID_Target(t *testing.T) {
+	m, err := NewMetricPID(0, map[string]any{"target": 50.0, "kp": 0.5, "ki": 0.0})
+	require.NoError(t, err)
+	values := runPID(m, 1000, time.Second, 5*time.Minute)
+	// A fixed target is added to the incoming value.
+	assert.InDelta(t, 1050, values[len(values)-1], 0.01)
+}
+
+func TestMetricPID_SlowFrequency(t *testing.T) {
+	fast, err := NewMetricPID(0, map[string]any{})
+	require.NoError(t, err)
+	slow, err := NewMetricPID(0, map[string]any{})
+	require.NoError(t, err)
+	f := runPID(fast, 100, time.Second, time.Minute)
+	s := runPID(slow, 100, 15*time.Second, time.Minute)
+	// The controller steps once a second however often it is emitted.
+	assert.InDelta(t, f[len(f)-1], s[len(s)-1], 1e-9)
+}
+
+func TestMetricPID_Reconfigure(t *testing.T) {
+	m, err := NewMetricPID(0, map[string]any{"target": 10.0})
+	require.NoError(t, err)
+	runPID(m, 0, time.Second, 10*time.Minute)
+
+	require.NoError(t, m.Reconfigure(0, map[string]any{"target": 20.0}))
+	rs := &state.RunState{Tick: 10*time.Minute + time.Second}
+	v := m.Emit(rs, 0)
+	assert.Greater(t, v, 10.0)
+	assert.Less(t, v, 20.0, "expected the new target to be approached from the old value")
+}
+
+func TestMetricPID_Invalid(t *testing.T) {
+	_, err := NewMetricPID(0, map[string]any{"ki": -1.0})
+	assert.Error(t, err)
+	_, err = NewMetricPID(0, map[string]any{"kp": 0.5, "kd": 0.9})
+	assert.EqualError(t, err, "kp + 2*kd must be less than 2 for the value to settle, got 2.3")
+}
@@ -0,0 +1,308 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricPrometheusReplaySpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	// File is a saved /api/v1/query_range response.
+	File string `mapstructure:"file" yaml:"file" json:"file"`
+	// URL is a Prometheus server queried once, when the generator is
+	// defined, for Query over the Lookback ending then.
+	URL      string        `mapstructure:"url" yaml:"url" json:"url"`
+	Query    string        `mapstructure:"query" yaml:"query" json:"query"`
+	Lookback time.Duration `mapstructure:"lookback" yaml:"lookback" json:"lookback"`
+	Step     time.Duration `mapstructure:"step" yaml:"step" json:"step"`
+	// Labels selects the first series having all of these labels.
+	// Without it, the first series in the response is used.
+	Labels map[string]string `mapstructure:"labels" yaml:"labels" json:"labels"`
+	// Interpolation is "linear" (the default) or "step".
+	Interpolation string `mapstructure:"interpolation" yaml:"interpolation" json:"interpolation"`
+	// Loop restarts the series after its last sample, rather than
+	// holding the last value.
+	Loop bool `mapstructure:"loop" yaml:"loop" json:"loop"`
+}
+
+type replaySample struct {
+	offset time.Duration
+	value  float64
+}
+
+// MetricPrometheusReplay replays a recorded Prometheus series, with the
+// first sample at the time the generator was defined.
+type MetricPrometheusReplay struct {
+	spec    MetricPrometheusReplaySpec
+	at      time.Duration
+	samples []replaySample
+	period  time.Duration
+}
+
+var _ MetricGenerator = (*MetricPrometheusReplay)(nil)
+
+// promQueryTimeout bounds the startup query against a server.
+const promQueryTimeout = 30 * time.Second
+
+func NewMetricPrometheusReplay(at time.Duration, is map[string]any) (*MetricPrometheusReplay, error) {
+	spec := MetricPrometheusReplaySpec{
+		Lookback:      time.Hour,
+		Step:          time.Minute,
+		Interpolation: "linear",
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	m := &MetricPrometheusReplay{at: at}
+	if err := m.load(spec); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reconfigure restarts the replay.  The series is only read again when
+// where it comes from changes, so redefining, say, the interpolation
+// does not query the server again.
+func (m *MetricPrometheusReplay) Reconfigure(at time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	if _, ok := is["labels"]; ok {
+		newSpec.Labels = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := m.load(newSpec); err != nil {
+		return err
+	}
+	m.at = at
+	return nil
+}
+
+func (m *MetricPrometheusReplay) load(spec MetricPrometheusReplaySpec) error {
+	switch spec.Interpolation {
+	case "linear", "step":
+	default:
+		return fmt.Errorf("invalid interpolation: %q", spec.Interpolation)
+	}
+	if m.samples != nil && sameSource(m.spec, spec) {
+		m.spec = spec
+		return nil
+	}
+
+	var body []byte
+	var err error
+	switch {
+	case spec.File != "" && spec.URL != "":
+		return errors.New("only one of file and url may be set")
+	case spec.File != "":
+		body, err = os.ReadFile(spec.File)
+	case spec.URL != "":
+		body, err = queryRange(spec)
+	default:
+		return errors.New("one of file or url is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	samples, err := parseQueryRange(body, spec.Labels)
+	if err != nil {
+		return err
+	}
+	m.spec = spec
+	m.samples = samples
+	// A loop lasts one sample interval past the last sample, so the
+	// series wraps at its own cadence.
+	m.period = samples[len(samples)-1].offset + time.Second
+	if len(samples) > 1 {
+		m.period = samples[len(samples)-1].offset + samples[1].offset - samples[0].offset
+	}
+	return nil
+}
+
+// sameSource reports whether a and b read the same series.
+func sameSource(a, b MetricPrometheusReplaySpec) bool {
+	return a.File == b.File && a.URL == b.URL && a.Query == b.Query &&
+		a.Lookback == b.Lookback && a.Step == b.Step && maps.Equal(a.Labels, b.Labels)
+}
+
+func (m *MetricPrometheusReplay) Emit(rs *state.RunState, incoming float64) float64 {
+	elapsed := max(rs.Tick-m.at, 0)
+	if m.spec.Loop {
+		elapsed %= m.period
+	}
+	i, found := slices.BinarySearchFunc(m.samples, elapsed, func(s replaySample, t time.Duration) int {
+		return cmp.Compare(s.offset, t)
+	})
+	if found {
+		return incoming + m.samples[i].value
+	}
+	if i == len(m.samples) {
+		return incoming + m.samples[i-1].value
+	}
+	prev, next := m.samples[i-1], m.samples[i]
+	if m.spec.Interpolation == "step" {
+		return incoming + prev.value
+	}
+	frac := float64(elapsed-prev.offset) / float64(next.offset-prev.offset)
+	return incoming + prev.value + (next.value-prev.value)*frac
+}
+
+func queryRange(spec MetricPrometheusReplaySpec) ([]byte, error) {
+	if spec.Query == "" {
+		return nil, errors.New("query is required with url")
+	}
+	if spec.Lookback <= 0 || spec.Step <= 0 {
+		return nil, errors.New("lookback and step must be positive")
+	}
+	end := time.Now()
+	params := url.Values{}
+	params.Set("query", spec.Query)
+	params.Set("start", strconv.FormatInt(end.Add(-spec.Lookback).Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	params.Set("step", strconv.FormatFloat(spec.Step.Seconds(), 'f', -1, 64))
+
+	client := &http.Client{Timeout: promQueryTimeout}
+	resp, err := client.Get(strings.TrimSuffix(spec.URL, "/") + "/api/v1/query_range?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("querying prometheus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("querying prometheus: %w", err)
+	}
+	// Prometheus reports query errors in the body, which
+	// parseQueryRange surfaces, so only other failures are caught here.
+	if resp.StatusCode >= 300 && !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return nil, fmt.Errorf("querying prometheus: %s", resp.Status)
+	}
+	return body, nil
+}
+
+type queryRangeResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string             `json:"resultType"`
+		Result     []queryRangeSeries `json:"result"`
+	} `json:"data"`
+}
+
+type queryRangeSeries struct {
+	Metric map[string]string `json:"metric"`
+	// Values are [unix seconds, "value"] pairs.
+	Values [][2]any `json:"values"`
+}
+
+// ParseQueryRange reads a series from a query_range response as
+// prometheusReplay does, returning each sample's offset from the first
+// and its value.
+func ParseQueryRange(body []byte, labels map[string]string) ([]time.Duration, []float64, error) {
+	samples, err := parseQueryRange(body, labels)
+	if err != nil {
+		return nil, nil, err
+	}
+	offsets := make([]time.Duration, len(samples))
+	values := make([]float64, len(samples))
+	for i, s := range samples {
+		offsets[i], values[i] = s.offset, s.value
+	}
+	return offsets, values, nil
+}
+
+// parseQueryRange picks a series from a query_range response and
+// returns its samples, offset from the first one.  NaN and infinite
+// samples, such as stale markers, are dropped.
+func parseQueryRange(body []byte, labels map[string]string) ([]replaySample, error) {
+	var resp queryRangeResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid query_range response: %w", err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", resp.Error)
+	}
+	if resp.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("expected a matrix result, got %q", resp.Data.ResultType)
+	}
+
+	idx := slices.IndexFunc(resp.Data.Result, func(r queryRangeSeries) bool {
+		for k, v := range labels {
+			if r.Metric[k] != v {
+				return false
+			}
+		}
+		return true
+	})
+	if idx < 0 {
+		return nil, errors.New("no series matches labels")
+	}
+
+	var samples []replaySample
+	var first float64
+	for _, pair := range resp.Data.Result[idx].Values {
+		ts, ok := pair[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid sample timestamp: %v", pair[0])
+		}
+		s, ok := pair[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid sample value: %v", pair[1])
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sample value: %w", err)
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		if len(samples) == 0 {
+			first = ts
+		}
+		samples = append(samples, replaySample{
+			offset: time.Duration((ts - first) * float64(time.Second)),
+			value:  v,
+		})
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("series has no samples")
+	}
+	return samples, nil
+}
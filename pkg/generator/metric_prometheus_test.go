@@ -0,0 +1,174 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+const testQueryRange = `{
+  "status": "success",
+  "data": {
+    "resultType": "matrix",
+    "result": [
+      {"metric": {"pod": "a"}, "values": [[1700000000, "1"], [1700000060, "2"]]},
+      {"metric": {"pod": "b"}, "values": [[1700000000, "10"], [1700000060, "NaN"], [1700000120, "30"], [1700000180, "40"]]}
+    ]
+  }
+}`
+
+func TestMetricPrometheusReplay_File(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "range.json")
+	require.NoError(t, os.WriteFile(fname, []byte(testQueryRange), 0o600))
+
+	tests := []struct {
+		name     string
+		spec     map[string]any
+		expected map[time.Duration]float64
+	}{
+		{
+			name: "first series, linear, holds the last value",
+			spec: map[string]any{"file": fname},
+			expected: map[time.Duration]float64{
+				0:                1,
+				30 * time.Second: 1.5,
+				time.Minute:      2,
+				time.Hour:        2,
+			},
+		},
+		{
+			name: "labels select a series, NaN is skipped",
+			spec: map[string]any{"file": fname, "labels": map[string]any{"pod": "b"}},
+			expected: map[time.Duration]float64{
+				time.Minute:     20,
+				2 * time.Minute: 30,
+			},
+		},
+		{
+			name: "step interpolation",
+			spec: map[string]any{"file": fname, "interpolation": "step"},
+			expected: map[time.Duration]float64{
+				59 * time.Second: 1,
+			},
+		},
+		{
+			name: "loop wraps one interval after the last sample",
+			spec: map[string]any{"file": fname, "loop": true},
+			expected: map[time.Duration]float64{
+				2 * time.Minute:                1,
+				2*time.Minute + 30*time.Second: 1.5,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMetricPrometheusReplay(10*time.Second, tt.spec)
+			require.NoError(t, err)
+			for offset, want := range tt.expected {
+				rs := &state.RunState{Tick: 10*time.Second + offset}
+				assert.InDelta(t, want, m.Emit(rs, 0), 1e-9, "offset %s", offset)
+			}
+		})
+	}
+}
+
+func TestMetricPrometheusReplay_URL(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		assert.Equal(t, "30", r.URL.Query().Get("step"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testQueryRange))
+	}))
+	defer srv.Close()
+
+	m, err := NewMetricPrometheusReplay(0, map[string]any{
+		"url":   srv.URL,
+		"query": "rate(http_requests_total[5m])",
+		"step":  "30s",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "rate(http_requests_total[5m])", query)
+	assert.Equal(t, 2.0, m.Emit(&state.RunState{Tick: time.Minute}, 0))
+}
+
+func TestMetricPrometheusReplay_ReconfigureQueries(t *testing.T) {
+	queries := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testQueryRange))
+	}))
+	defer srv.Close()
+
+	m, err := NewMetricPrometheusReplay(0, map[string]any{"url": srv.URL, "query": "up"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, queries)
+
+	// Settings that do not change the series reuse it.
+	require.NoError(t, m.Reconfigure(time.Minute, map[string]any{"interpolation": "step", "loop": true}))
+	require.NoError(t, m.Reconfigure(2*time.Minute, map[string]any{"url": srv.URL, "query": "up"}))
+	assert.Equal(t, 1, queries)
+	assert.Equal(t, 1.0, m.Emit(&state.RunState{Tick: 2*time.Minute + 30*time.Second}, 0))
+
+	for _, spec := range []map[string]any{
+		{"query": "down"},
+		{"labels": map[string]any{"pod": "b"}},
+		{"lookback": "2h"},
+		{"step": "30s"},
+	} {
+		before := queries
+		require.NoError(t, m.Reconfigure(0, spec))
+		assert.Equal(t, before+1, queries, "%v", spec)
+	}
+	assert.Equal(t, 10.0, m.Emit(&state.RunState{}, 0))
+}
+
+func TestMetricPrometheusReplay_Invalid(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "range.json")
+	require.NoError(t, os.WriteFile(fname, []byte(testQueryRange), 0o600))
+	failed := filepath.Join(t.TempDir(), "failed.json")
+	require.NoError(t, os.WriteFile(failed, []byte(`{"status":"error","error":"bad query"}`), 0o600))
+
+	tests := []struct {
+		name string
+		spec map[string]any
+		err  string
+	}{
+		{"no source", map[string]any{}, "one of file or url is required"},
+		{"both sources", map[string]any{"file": fname, "url": "http://x"}, "only one of file and url may be set"},
+		{"url without query", map[string]any{"url": "http://x"}, "query is required with url"},
+		{"unknown labels", map[string]any{"file": fname, "labels": map[string]any{"pod": "c"}}, "no series matches labels"},
+		{"bad interpolation", map[string]any{"file": fname, "interpolation": "cubic"}, `invalid interpolation: "cubic"`},
+		{"failed query", map[string]any{"file": failed}, "prometheus query failed: bad query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMetricPrometheusReplay(0, tt.spec)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricQuantizeSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Step                float64 `mapstructure:"step" yaml:"step" json:"step"`
+	Mode                string  `mapstructure:"mode" yaml:"mode" json:"mode"`
+}
+
+// MetricQuantize rounds the value built by the generators before it to
+// a multiple of Step, so counts do not come out as 42.7.
+type MetricQuantize struct {
+	spec MetricQuantizeSpec
+}
+
+var _ MetricGenerator = (*MetricQuantize)(nil)
+
+var quantizeModes = map[string]func(float64) float64{
+	"round": math.Round,
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+}
+
+func NewMetricQuantize(_ time.Duration, is map[string]any) (*MetricQuantize, error) {
+	spec := MetricQuantizeSpec{
+		Step: 1,
+		Mode: "round",
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateQuantize(spec); err != nil {
+		return nil, err
+	}
+	return &MetricQuantize{
+		spec: spec,
+	}, nil
+}
+
+func (m *MetricQuantize) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateQuantize(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricQuantize) Emit(_ *state.RunState, incoming float64) float64 {
+	return quantizeModes[m.spec.Mode](incoming/m.spec.Step) * m.spec.Step
+}
+
+func validateQuantize(spec MetricQuantizeSpec) error {
+	if spec.Step <= 0 {
+		return fmt.Errorf("step must be positive, got %v", spec.Step)
+	}
+	if _, ok := quantizeModes[spec.Mode]; !ok {
+		return fmt.Errorf("unknown quantize mode %q (want round, floor, or ceil)", spec.Mode)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricQuantize_Emit(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     map[string]any
+		incoming float64
+		expected float64
+	}{
+		{"integers by default", map[string]any{}, 42.7, 43},
+		{"negative", map[string]any{}, -2.4, -2},
+		{"step", map[string]any{"step": 5.0}, 42.7, 45},
+		{"fractional step", map[string]any{"step": 0.5}, 1.3, 1.5},
+		{"floor", map[string]any{"mode": "floor"}, 42.7, 42},
+		{"ceil with step", map[string]any{"step": 10.0, "mode": "ceil"}, 41, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMetricQuantize(0, tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m.Emit(&state.RunState{}, tt.incoming))
+		})
+	}
+}
+
+func TestMetricQuantize_Invalid(t *testing.T) {
+	_, err := NewMetricQuantize(0, map[string]any{"step": 0.0})
+	assert.EqualError(t, err, "step must be positive, got 0")
+	_, err = NewMetricQuantize(0, map[string]any{"mode": "truncate"})
+	assert.EqualError(t, err, `unknown quantize mode "truncate" (want round, floor, or ceil)`)
+
+	m, err := NewMetricQuantize(0, map[string]any{})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"step": -1.0}))
+	require.NoError(t, m.Reconfigure(0, map[string]any{"step": 100.0}))
+	assert.Equal(t, 300.0, m.Emit(&state.RunState{}, 251))
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricQueueSpec models a queue, or leaky bucket.  The value built by
+// the generators before it is the arrival rate, and Drain is how fast
+// the queue is worked off, both per Per.  Between emits dt apart the
+// backlog steps like:
+//
+//	backlog ← clamp(backlog + (arrivals − Drain)·dt/Per, 0, Capacity)
+//
+// Emit returns the backlog, or with Output "age", how long the newest
+// item will wait at the current drain rate.
+type MetricQueueSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+
+	// Start is the backlog when the generator is first defined.
+	Start float64 `mapstructure:"start" yaml:"start" json:"start"`
+	// Drain is how many items leave the queue per Per.
+	Drain float64 `mapstructure:"drain" yaml:"drain" json:"drain"`
+	// Per is the period arrivals and Drain are rates over.
+	Per time.Duration `mapstructure:"per" yaml:"per" json:"per"`
+	// Capacity, when set, bounds the backlog; arrivals beyond it are
+	// dropped.
+	Capacity *float64 `mapstructure:"capacity" yaml:"capacity,omitempty" json:"capacity,omitempty"`
+	// Output is "depth" (the default) for the backlog, or "age" for
+	// the backlog's wait in seconds.
+	Output string `mapstructure:"output" yaml:"output" json:"output"`
+}
+
+type MetricQueue struct {
+	spec     MetricQueueSpec
+	backlog  float64
+	lastTick time.Duration
+	started  bool
+}
+
+var _ MetricGenerator = (*MetricQueue)(nil)
+
+func NewMetricQueue(_ time.Duration, is map[string]any) (*MetricQueue, error) {
+	spec := MetricQueueSpec{
+		Per:    time.Second,
+		Output: "depth",
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateQueue(spec); err != nil {
+		return nil, err
+	}
+	return &MetricQueue{
+		spec:    spec,
+		backlog: spec.Start,
+	}, nil
+}
+
+// Reconfigure changes the drain rate or capacity from now on.  The
+// backlog carries over; Start only applies when first defined.
+func (m *MetricQueue) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateQueue(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricQueue) Emit(rs *state.RunState, incoming float64) float64 {
+	if m.started && rs.Tick > m.lastTick {
+		dt := float64(rs.Tick-m.lastTick) / float64(m.spec.Per)
+		m.backlog += (max(incoming, 0) - m.spec.Drain) * dt
+	}
+	m.started = true
+	m.lastTick = rs.Tick
+	m.backlog = max(m.backlog, 0)
+	if m.spec.Capacity != nil {
+		m.backlog = min(m.backlog, *m.spec.Capacity)
+	}
+	if m.spec.Output == "age" {
+		if m.spec.Drain == 0 {
+			return 0
+		}
+		return m.backlog / m.spec.Drain * m.spec.Per.Seconds()
+	}
+	return m.backlog
+}
+
+func validateQueue(spec MetricQueueSpec) error {
+	if spec.Per <= 0 {
+		return fmt.Errorf("invalid per: %s", spec.Per)
+	}
+	if spec.Drain < 0 {
+		return fmt.Errorf("invalid drain: %v", spec.Drain)
+	}
+	if spec.Start < 0 {
+		return fmt.Errorf("invalid start: %v", spec.Start)
+	}
+	if spec.Capacity != nil && *spec.Capacity < 0 {
+		return fmt.Errorf("invalid capacity: %v", *spec.Capacity)
+	}
+	switch spec.Output {
+	case "depth", "age":
+	default:
+		return fmt.Errorf("unknown queue output %q (want depth or age)", spec.Output)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricQueue_Backlog(t *testing.T) {
+	m, err := NewMetricQueue(0, map[string]any{"drain": 100.0})
+	require.NoError(t, err)
+
+	rs := &state.RunState{}
+	assert.Equal(t, 0.0, m.Emit(rs, 150))
+	rs.Tick = 10 * time.Second
+	assert.Equal(t, 500.0, m.Emit(rs, 150), "50/s over drain for 10s")
+	rs.Tick = 20 * time.Second
+	assert.Equal(t, 300.0, m.Emit(rs, 80))
+	rs.Tick = 60 * time.Second
+	assert.Equal(t, 0.0, m.Emit(rs, 0), "the backlog does not go negative")
+}
+
+func TestMetricQueue_CapacityAndAge(t *testing.T) {
+	m, err := NewMetricQueue(0, map[string]any{"drain": 10.0, "capacity": 200.0, "output": "age"})
+	require.NoError(t, err)
+
+	rs := &state.RunState{}
+	m.Emit(rs, 0)
+	rs.Tick = time.Minute
+	assert.Equal(t, 20.0, m.Emit(rs, 100), "a full queue of 200 at 10/s is 20s old")
+
+	require.NoError(t, m.Reconfigure(0, map[string]any{"drain": 50.0}))
+	rs.Tick = time.Minute + 2*time.Second
+	assert.Equal(t, 2.0, m.Emit(rs, 0), "a redefinition carries the backlog over")
+
+	require.NoError(t, m.Reconfigure(0, map[string]any{"drain": 0.0}))
+	assert.Equal(t, 0.0, m.Emit(rs, 0))
+}
+
+func TestMetricQueue_Invalid(t *testing.T) {
+	_, err := NewMetricQueue(0, map[string]any{"per": "0s"})
+	assert.EqualError(t, err, "invalid per: 0s")
+	_, err = NewMetricQueue(0, map[string]any{"drain": -1.0})
+	assert.EqualError(t, err, "invalid drain: -1")
+	_, err = NewMetricQueue(0, map[string]any{"output": "size"})
+	assert.EqualError(t, err, `unknown queue output "size" (want depth or age)`)
+}
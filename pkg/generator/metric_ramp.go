@@ -16,6 +16,8 @@ package generator
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/cardinalhq/flutter/pkg/config"
@@ -23,11 +25,14 @@ import (
 )
 
 type MetricRampSpec struct {
-	MetricGeneratorSpec `mapstructure:",squash"`
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
 	Start               float64       `mapstructure:"start" yaml:"start" json:"start"`
 	Target              float64       `mapstructure:"target" yaml:"target" json:"target"`
 	Duration            time.Duration `mapstructure:"duration" yaml:"duration" json:"duration"`
 	PostEndZero         bool          `mapstructure:"postend_zero" yaml:"postend_zero" json:"postend_zero"`
+	// Easing shapes the ramp: linear (the default), cubic, sigmoid,
+	// or exponential.
+	Easing string `mapstructure:"easing" yaml:"easing,omitempty" json:"easing,omitempty"`
 }
 
 type MetricRamp struct {
@@ -49,6 +54,9 @@ func NewMetricRamp(at time.Duration, is map[string]any) (*MetricRamp, error) {
 	if spec.Duration <= 0 {
 		return nil, errors.New("invalid duration")
 	}
+	if err := validateEasing(spec.Easing); err != nil {
+		return nil, err
+	}
 	state := MetricRamp{
 		spec: spec,
 		at:   at,
@@ -71,6 +79,9 @@ func (m *MetricRamp) Reconfigure(at time.Duration, is map[string]any) error {
 	if newSpec.Duration <= 0 {
 		return errors.New("invalid duration")
 	}
+	if err := validateEasing(newSpec.Easing); err != nil {
+		return err
+	}
 
 	if at <= oldAt {
 		m.spec = newSpec
@@ -84,7 +95,8 @@ func (m *MetricRamp) Reconfigure(at time.Duration, is map[string]any) error {
 		oldAt,
 		at,
 		oldSpec.Duration,
-		oldSpec.PostEndZero)
+		oldSpec.PostEndZero,
+		oldSpec.Easing)
 
 	m.spec = newSpec
 	m.spec.Start = current
@@ -94,13 +106,13 @@ func (m *MetricRamp) Reconfigure(at time.Duration, is map[string]any) error {
 }
 
 func (m *MetricRamp) Emit(rs *state.RunState, value float64) float64 {
-	v := intrerpolate(m.spec.Start, m.spec.Target, m.at, rs.Tick, m.spec.Duration, m.spec.PostEndZero)
+	v := intrerpolate(m.spec.Start, m.spec.Target, m.at, rs.Tick, m.spec.Duration, m.spec.PostEndZero, m.spec.Easing)
 	return v + value
 }
 
-// intrerpolate linearly interpolates from start → target over the given duration,
-// beginning at offset startAt, and evaluated at offset at.
-func intrerpolate(start, target float64, startAt, now, duration time.Duration, postZero bool) float64 {
+// intrerpolate interpolates from start → target over the given duration,
+// beginning at offset startAt, and evaluated at offset at, shaped by easing.
+func intrerpolate(start, target float64, startAt, now, duration time.Duration, postZero bool, easing string) float64 {
 	elapsed := now - startAt
 	if elapsed <= 0 { // If we haven't started yet, return 0
 		return 0
@@ -109,11 +121,44 @@ func intrerpolate(start, target float64, startAt, now, duration time.Duration, p
 		return target
 	}
 	if elapsed >= duration { // If we've passed the duration, return target or 0
-		if postZero {
+		// The end tick itself still emits target, since the next ramp
+		// emits 0 on its own start tick.
+		if postZero && elapsed > duration {
 			return 0
 		}
 		return target
 	}
-	frac := float64(elapsed) / float64(duration)
+	frac := ease(easing, float64(elapsed)/float64(duration))
 	return start + (target-start)*frac
 }
+
+func validateEasing(easing string) error {
+	switch easing {
+	case "", "linear", "cubic", "sigmoid", "exponential":
+		return nil
+	}
+	return fmt.Errorf("invalid easing %q, must be linear, cubic, sigmoid, or exponential", easing)
+}
+
+// ease maps the fraction of the ramp done, 0 to 1, to the fraction of
+// the way from start to target.  Each shape starts at 0 and ends at 1.
+func ease(easing string, frac float64) float64 {
+	switch easing {
+	case "cubic":
+		// Slow to start and to settle, like a gradual rollout.
+		if frac < 0.5 {
+			return 4 * frac * frac * frac
+		}
+		return 1 - math.Pow(2-2*frac, 3)/2
+	case "sigmoid":
+		// A logistic curve, rescaled to pass through 0 and 1.
+		s := func(x float64) float64 { return 1 / (1 + math.Exp(-10*(x-0.5))) }
+		return (s(frac) - s(0)) / (s(1) - s(0))
+	case "exponential":
+		// Barely moves at first, then accelerates, like adoption
+		// spreading.
+		return (math.Pow(2, 10*frac) - 1) / 1023
+	default:
+		return frac
+	}
+}
@@ -161,6 +161,26 @@ func TestInterpolate(t *testing.T) {
 			duration: 0,
 			expected: 100, // Zero duration, directly returns target
 		},
+		{
+			name:     "Interpolate at target with postZero",
+			start:    0,
+			target:   100,
+			startAt:  0,
+			now:      10 * time.Minute,
+			duration: 10 * time.Minute,
+			postZero: true,
+			expected: 100, // The end tick still emits target
+		},
+		{
+			name:     "Interpolate beyond target with postZero",
+			start:    0,
+			target:   100,
+			startAt:  0,
+			now:      11 * time.Minute,
+			duration: 10 * time.Minute,
+			postZero: true,
+			expected: 0, // After the end, drops to zero
+		},
 		{
 			name:     "Interpolate with negative elapsed time",
 			start:    0,
@@ -174,7 +194,7 @@ func TestInterpolate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := intrerpolate(tt.start, tt.target, tt.startAt, tt.now, tt.duration, tt.postZero)
+			result := intrerpolate(tt.start, tt.target, tt.startAt, tt.now, tt.duration, tt.postZero, "")
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -313,3 +333,40 @@ func TestMetricRamp_Reconfigure(t *testing.T) {
 		})
 	}
 }
+
+func TestEase(t *testing.T) {
+	for _, easing := range []string{"", "linear", "cubic", "sigmoid", "exponential"} {
+		t.Run(easing, func(t *testing.T) {
+			assert.NoError(t, validateEasing(easing))
+			assert.InDelta(t, 0, ease(easing, 0), 1e-9)
+			assert.InDelta(t, 1, ease(easing, 1), 1e-9)
+			prev := 0.0
+			for i := 1; i <= 100; i++ {
+				v := ease(easing, float64(i)/100)
+				assert.GreaterOrEqual(t, v, prev, "easing must not turn back")
+				prev = v
+			}
+		})
+	}
+	assert.InDelta(t, 0.5, ease("cubic", 0.5), 1e-9)
+	assert.InDelta(t, 0.5, ease("sigmoid", 0.5), 1e-9)
+	assert.Less(t, ease("cubic", 0.1), 0.1)
+	assert.Less(t, ease("exponential", 0.5), 0.05)
+}
+
+func TestMetricRamp_Easing(t *testing.T) {
+	m, err := NewMetricRamp(0, map[string]any{
+		"start":    0.0,
+		"target":   100.0,
+		"duration": "10m",
+		"easing":   "cubic",
+	})
+	assert.NoError(t, err)
+	rs := &state.RunState{Tick: 5 * time.Minute}
+	assert.InDelta(t, 50, m.Emit(rs, 0), 1e-9)
+	rs.Tick = time.Minute
+	assert.InDelta(t, 0.4, m.Emit(rs, 0), 1e-9)
+
+	_, err = NewMetricRamp(0, map[string]any{"duration": "10m", "easing": "bounce"})
+	assert.EqualError(t, err, `invalid easing "bounce", must be linear, cubic, sigmoid, or exponential`)
+}
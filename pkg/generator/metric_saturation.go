@@ -0,0 +1,125 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricSaturationSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Model               string   `mapstructure:"model" yaml:"model" json:"model"`
+	Base                float64  `mapstructure:"base" yaml:"base" json:"base"`
+	Capacity            float64  `mapstructure:"capacity" yaml:"capacity" json:"capacity"`
+	Max                 *float64 `mapstructure:"max,omitempty" yaml:"max,omitempty" json:"max,omitempty"`
+	Steepness           float64  `mapstructure:"steepness" yaml:"steepness" json:"steepness"`
+}
+
+// MetricSaturation treats the value built by the generators before it
+// as load and emits the response of a system with the given capacity,
+// such as latency that stays near Base until load nears Capacity and
+// then climbs steeply.
+type MetricSaturation struct {
+	spec MetricSaturationSpec
+}
+
+var _ MetricGenerator = (*MetricSaturation)(nil)
+
+// maxUtilization caps an uncapped mm1 curve, which would otherwise be
+// infinite at capacity, at 100 times its base.
+const maxUtilization = 0.99
+
+func NewMetricSaturation(_ time.Duration, is map[string]any) (*MetricSaturation, error) {
+	spec := MetricSaturationSpec{
+		Model:     "mm1",
+		Steepness: 10,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateSaturation(spec); err != nil {
+		return nil, err
+	}
+	return &MetricSaturation{
+		spec: spec,
+	}, nil
+}
+
+func (m *MetricSaturation) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateSaturation(newSpec); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricSaturation) Emit(_ *state.RunState, incoming float64) float64 {
+	load := max(incoming, 0) / m.spec.Capacity
+	switch m.spec.Model {
+	case "logistic":
+		// Halfway between base and max at capacity.
+		return m.spec.Base + (*m.spec.Max-m.spec.Base)/(1+math.Exp(-m.spec.Steepness*(load-1)))
+	default:
+		// Response time of an M/M/1 queue at utilization load.
+		v := m.spec.Base / (1 - min(load, maxUtilization))
+		if m.spec.Max != nil {
+			v = min(v, *m.spec.Max)
+		}
+		return v
+	}
+}
+
+func validateSaturation(spec MetricSaturationSpec) error {
+	switch spec.Model {
+	case "mm1":
+	case "logistic":
+		if spec.Max == nil {
+			return errors.New("a logistic saturation curve needs a max")
+		}
+		if spec.Steepness <= 0 {
+			return fmt.Errorf("steepness must be positive, got %v", spec.Steepness)
+		}
+	default:
+		return fmt.Errorf("unknown saturation model %q (want mm1 or logistic)", spec.Model)
+	}
+	if spec.Capacity <= 0 {
+		return fmt.Errorf("capacity must be positive, got %v", spec.Capacity)
+	}
+	if spec.Base < 0 {
+		return fmt.Errorf("base must not be negative, got %v", spec.Base)
+	}
+	if spec.Max != nil && *spec.Max < spec.Base {
+		return fmt.Errorf("max %v must not be below base %v", *spec.Max, spec.Base)
+	}
+	return nil
+}
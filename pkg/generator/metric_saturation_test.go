@@ -0,0 +1,75 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricSaturation_Emit(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     map[string]any
+		incoming float64
+		expected float64
+	}{
+		{"mm1 idle", map[string]any{"base": 50.0, "capacity": 100.0}, 0, 50},
+		{"mm1 half loaded", map[string]any{"base": 50.0, "capacity": 100.0}, 50, 100},
+		{"mm1 nearly saturated", map[string]any{"base": 50.0, "capacity": 100.0}, 90, 500},
+		{"mm1 overloaded is capped", map[string]any{"base": 50.0, "capacity": 100.0}, 150, 5000},
+		{"mm1 max", map[string]any{"base": 50.0, "capacity": 100.0, "max": 2000.0}, 99, 2000},
+		{"negative load is idle", map[string]any{"base": 50.0, "capacity": 100.0}, -10, 50},
+		{"logistic at capacity", map[string]any{"model": "logistic", "base": 50.0, "capacity": 100.0, "max": 1050.0}, 100, 550},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMetricSaturation(0, tt.spec)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, m.Emit(&state.RunState{}, tt.incoming), 1e-9)
+		})
+	}
+}
+
+func TestMetricSaturation_Logistic(t *testing.T) {
+	m, err := NewMetricSaturation(0, map[string]any{"model": "logistic", "base": 10.0, "capacity": 100.0, "max": 110.0})
+	require.NoError(t, err)
+	low := m.Emit(&state.RunState{}, 0)
+	high := m.Emit(&state.RunState{}, 300)
+	assert.InDelta(t, 10, low, 0.01)
+	assert.InDelta(t, 110, high, 0.01)
+	assert.Less(t, m.Emit(&state.RunState{}, 90), m.Emit(&state.RunState{}, 110))
+}
+
+func TestMetricSaturation_Invalid(t *testing.T) {
+	_, err := NewMetricSaturation(0, map[string]any{"base": 50.0})
+	assert.EqualError(t, err, "capacity must be positive, got 0")
+	_, err = NewMetricSaturation(0, map[string]any{"model": "linear", "capacity": 1.0})
+	assert.EqualError(t, err, `unknown saturation model "linear" (want mm1 or logistic)`)
+	_, err = NewMetricSaturation(0, map[string]any{"model": "logistic", "capacity": 1.0})
+	assert.EqualError(t, err, "a logistic saturation curve needs a max")
+	_, err = NewMetricSaturation(0, map[string]any{"base": 50.0, "capacity": 1.0, "max": 10.0})
+	assert.EqualError(t, err, "max 10 must not be below base 50")
+
+	m, err := NewMetricSaturation(0, map[string]any{"base": 50.0, "capacity": 100.0})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"capacity": 0.0}))
+	require.NoError(t, m.Reconfigure(0, map[string]any{"capacity": 200.0}))
+	assert.InDelta(t, 100, m.Emit(&state.RunState{}, 100), 1e-9)
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricScaleSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	// Start is the factor when the generator is first defined; later
+	// redefinitions start from the factor in effect instead.
+	Start  float64 `mapstructure:"start" yaml:"start" json:"start"`
+	Factor float64 `mapstructure:"factor" yaml:"factor" json:"factor"`
+	// Duration is how long the factor takes to move from Start to
+	// Factor.  Zero applies Factor at once.
+	Duration time.Duration `mapstructure:"duration" yaml:"duration" json:"duration"`
+}
+
+// MetricScale multiplies the value built by the generators before it,
+// so a whole chain can grow or shrink together.
+type MetricScale struct {
+	spec MetricScaleSpec
+	at   time.Duration
+}
+
+var _ MetricGenerator = (*MetricScale)(nil)
+
+func NewMetricScale(at time.Duration, is map[string]any) (*MetricScale, error) {
+	spec := MetricScaleSpec{
+		Start:  1,
+		Factor: 1,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if spec.Duration < 0 {
+		return nil, errors.New("invalid duration")
+	}
+	return &MetricScale{
+		spec: spec,
+		at:   at,
+	}, nil
+}
+
+func (m *MetricScale) Reconfigure(at time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if newSpec.Duration < 0 {
+		return errors.New("invalid duration")
+	}
+	if at > m.at {
+		newSpec.Start = m.factor(at)
+	}
+	m.spec = newSpec
+	m.at = at
+	return nil
+}
+
+func (m *MetricScale) Emit(rs *state.RunState, incoming float64) float64 {
+	return incoming * m.factor(rs.Tick)
+}
+
+func (m *MetricScale) factor(now time.Duration) float64 {
+	elapsed := now - m.at
+	if elapsed <= 0 {
+		return m.spec.Start
+	}
+	if elapsed >= m.spec.Duration {
+		return m.spec.Factor
+	}
+	frac := float64(elapsed) / float64(m.spec.Duration)
+	return m.spec.Start + (m.spec.Factor-m.spec.Start)*frac
+}
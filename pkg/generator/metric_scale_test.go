@@ -0,0 +1,59 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricScale_Emit(t *testing.T) {
+	m, err := NewMetricScale(0, map[string]any{"factor": 3.0})
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, m.Emit(&state.RunState{Tick: 5 * time.Second}, 10))
+
+	m, err = NewMetricScale(10*time.Second, map[string]any{"factor": 2.0, "duration": "10s"})
+	require.NoError(t, err)
+	tests := map[time.Duration]float64{
+		0:                100,
+		10 * time.Second: 100,
+		15 * time.Second: 150,
+		20 * time.Second: 200,
+		time.Hour:        200,
+	}
+	for tick, want := range tests {
+		assert.InDelta(t, want, m.Emit(&state.RunState{Tick: tick}, 100), 1e-9, "tick %s", tick)
+	}
+}
+
+func TestMetricScale_Reconfigure(t *testing.T) {
+	m, err := NewMetricScale(0, map[string]any{"factor": 2.0, "duration": "100s"})
+	require.NoError(t, err)
+
+	// Half-way up, head back to 1x over 10s, starting from the current 1.5x.
+	require.NoError(t, m.Reconfigure(50*time.Second, map[string]any{"factor": 1.0, "duration": "10s"}))
+	assert.InDelta(t, 15.0, m.Emit(&state.RunState{Tick: 50 * time.Second}, 10), 1e-9)
+	assert.InDelta(t, 12.5, m.Emit(&state.RunState{Tick: 55 * time.Second}, 10), 1e-9)
+	assert.InDelta(t, 10.0, m.Emit(&state.RunState{Tick: 60 * time.Second}, 10), 1e-9)
+
+	assert.Error(t, m.Reconfigure(time.Minute, map[string]any{"duration": "-1s"}))
+	_, err = NewMetricScale(0, map[string]any{"duration": "-1s"})
+	assert.EqualError(t, err, "invalid duration")
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricSineSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Amplitude           float64       `mapstructure:"amplitude" yaml:"amplitude" json:"amplitude"`
+	Period              time.Duration `mapstructure:"period" yaml:"period" json:"period"`
+	Phase               float64       `mapstructure:"phase" yaml:"phase" json:"phase"`
+	Baseline            float64       `mapstructure:"baseline" yaml:"baseline" json:"baseline"`
+}
+
+// MetricSine emits baseline + amplitude*sin(2π*t/period + phase), where t
+// is the script time.  Using the script time rather than the time the
+// generator was defined keeps the wave continuous across reconfigurations.
+type MetricSine struct {
+	spec MetricSineSpec
+}
+
+var _ MetricGenerator = (*MetricSine)(nil)
+
+func NewMetricSine(_ time.Duration, is map[string]any) (*MetricSine, error) {
+	spec := MetricSineSpec{}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if spec.Period <= 0 {
+		return nil, errors.New("invalid period")
+	}
+	return &MetricSine{
+		spec: spec,
+	}, nil
+}
+
+func (m *MetricSine) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if newSpec.Period <= 0 {
+		return errors.New("invalid period")
+	}
+	m.spec = newSpec
+	return nil
+}
+
+func (m *MetricSine) Emit(rs *state.RunState, incoming float64) float64 {
+	cycles := float64(rs.Tick) / float64(m.spec.Period)
+	return incoming + m.spec.Baseline + m.spec.Amplitude*math.Sin(2*math.Pi*cycles+m.spec.Phase)
+}
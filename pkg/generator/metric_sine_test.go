@@ -0,0 +1,79 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricSine_Emit(t *testing.T) {
+	m, err := NewMetricSine(0, map[string]any{
+		"amplitude": 10.0,
+		"period":    "4m",
+		"baseline":  50.0,
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		tick     time.Duration
+		expected float64
+	}{
+		{0, 50},
+		{time.Minute, 60},
+		{2 * time.Minute, 50},
+		{3 * time.Minute, 40},
+		{4 * time.Minute, 50},
+	}
+	for _, tt := range tests {
+		rs := &state.RunState{Tick: tt.tick}
+		assert.InDelta(t, tt.expected, m.Emit(rs, 0), 1e-9, "tick %s", tt.tick)
+	}
+	assert.InDelta(t, 65.0, m.Emit(&state.RunState{Tick: time.Minute}, 5), 1e-9)
+}
+
+func TestMetricSine_Phase(t *testing.T) {
+	m, err := NewMetricSine(0, map[string]any{
+		"amplitude": 1.0,
+		"period":    "1h",
+		"phase":     math.Pi / 2,
+	})
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0, m.Emit(&state.RunState{Tick: 0}, 0), 1e-9)
+}
+
+func TestMetricSine_InvalidPeriod(t *testing.T) {
+	_, err := NewMetricSine(0, map[string]any{"amplitude": 1.0})
+	assert.EqualError(t, err, "invalid period")
+
+	m, err := NewMetricSine(0, map[string]any{"period": "1m"})
+	assert.NoError(t, err)
+	assert.EqualError(t, m.Reconfigure(0, map[string]any{"period": 0}), "invalid period")
+	assert.Equal(t, time.Minute, m.spec.Period)
+}
+
+func TestMetricSine_Reconfigure(t *testing.T) {
+	m, err := NewMetricSine(0, map[string]any{"amplitude": 1.0, "period": "1m"})
+	assert.NoError(t, err)
+	assert.NoError(t, m.Reconfigure(time.Minute, map[string]any{"amplitude": 3.0}))
+	assert.Equal(t, 3.0, m.spec.Amplitude)
+	assert.Equal(t, time.Minute, m.spec.Period)
+	assert.Error(t, m.Reconfigure(time.Minute, map[string]any{"bogus": 1}))
+}
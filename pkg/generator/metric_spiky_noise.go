@@ -30,7 +30,7 @@ var validSpikyDirs = []string{"positive", "negative", "both"}
 // MetricSpikyNoiseSpec configures a mostly‐zero generator that randomly
 // spikes with Poisson‐distributed counts when “ON”.
 type MetricSpikyNoiseSpec struct {
-	MetricGeneratorSpec `mapstructure:",squash"`
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
 
 	// PStart: chance per interval to transition from OFF→ON (0–1).
 	PStart float64 `mapstructure:"pStart" yaml:"pStart" json:"pStart"`
@@ -0,0 +1,110 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type Step struct {
+	At    time.Duration `mapstructure:"at" yaml:"at" json:"at"`
+	Value float64       `mapstructure:"value" yaml:"value" json:"value"`
+}
+
+type MetricStepSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Steps               []Step `mapstructure:"steps" yaml:"steps" json:"steps"`
+}
+
+// MetricStep emits the value of the latest step reached, jumping
+// instantly between steps.  Step times are offsets from when the
+// generator was defined, and 0 is emitted before the first step.
+type MetricStep struct {
+	spec MetricStepSpec
+	at   time.Duration
+}
+
+var _ MetricGenerator = (*MetricStep)(nil)
+
+func NewMetricStep(at time.Duration, is map[string]any) (*MetricStep, error) {
+	spec := MetricStepSpec{}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return nil, err
+	}
+	if err := validateSteps(spec.Steps); err != nil {
+		return nil, err
+	}
+	return &MetricStep{
+		spec: spec,
+		at:   at,
+	}, nil
+}
+
+func (m *MetricStep) Reconfigure(at time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := validateSteps(newSpec.Steps); err != nil {
+		return err
+	}
+	m.spec = newSpec
+	m.at = at
+	return nil
+}
+
+func (m *MetricStep) Emit(rs *state.RunState, incoming float64) float64 {
+	elapsed := rs.Tick - m.at
+	// The steps are sorted, so find the last one at or before elapsed.
+	i, found := slices.BinarySearchFunc(m.spec.Steps, elapsed, func(s Step, t time.Duration) int {
+		return cmp.Compare(s.At, t)
+	})
+	if found {
+		return incoming + m.spec.Steps[i].Value
+	}
+	if i == 0 {
+		return incoming
+	}
+	return incoming + m.spec.Steps[i-1].Value
+}
+
+func validateSteps(steps []Step) error {
+	if len(steps) == 0 {
+		return errors.New("no steps")
+	}
+	for i, s := range steps {
+		if s.At < 0 {
+			return errors.New("step at must not be negative")
+		}
+		if i > 0 && s.At <= steps[i-1].At {
+			return errors.New("steps must be in increasing at order")
+		}
+	}
+	return nil
+}
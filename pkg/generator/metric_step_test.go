@@ -0,0 +1,86 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricStep_Emit(t *testing.T) {
+	m, err := NewMetricStep(time.Minute, map[string]any{
+		"steps": []any{
+			map[string]any{"at": "0s", "value": 3},
+			map[string]any{"at": "5m", "value": 6},
+			map[string]any{"at": "10m", "value": 2},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		tick     time.Duration
+		expected float64
+	}{
+		{0, 0},
+		{time.Minute, 3},
+		{5 * time.Minute, 3},
+		{6 * time.Minute, 6},
+		{10 * time.Minute, 6},
+		{11 * time.Minute, 2},
+		{time.Hour, 2},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, m.Emit(&state.RunState{Tick: tt.tick}, 0), "tick %s", tt.tick)
+	}
+	assert.Equal(t, 7.0, m.Emit(&state.RunState{Tick: time.Hour}, 5))
+}
+
+func TestMetricStep_Invalid(t *testing.T) {
+	_, err := NewMetricStep(0, map[string]any{})
+	assert.EqualError(t, err, "no steps")
+
+	_, err = NewMetricStep(0, map[string]any{
+		"steps": []any{
+			map[string]any{"at": "5m", "value": 1},
+			map[string]any{"at": "1m", "value": 2},
+		},
+	})
+	assert.EqualError(t, err, "steps must be in increasing at order")
+}
+
+func TestMetricStep_Reconfigure(t *testing.T) {
+	m, err := NewMetricStep(0, map[string]any{
+		"steps": []any{map[string]any{"at": "0s", "value": 1}},
+	})
+	require.NoError(t, err)
+
+	err = m.Reconfigure(10*time.Minute, map[string]any{
+		"steps": []any{
+			map[string]any{"at": "0s", "value": 4},
+			map[string]any{"at": "1m", "value": 8},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, m.Emit(&state.RunState{Tick: 10 * time.Minute}, 0))
+	assert.Equal(t, 8.0, m.Emit(&state.RunState{Tick: 11 * time.Minute}, 0))
+
+	assert.Error(t, m.Reconfigure(0, map[string]any{"steps": []any{}}))
+	assert.Equal(t, 10*time.Minute, m.at)
+}
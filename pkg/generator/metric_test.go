@@ -0,0 +1,38 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+)
+
+func TestCreateMetricGenerator_Alias(t *testing.T) {
+	g, err := CreateMetricGenerator(scriptaction.ScriptAction{
+		Spec: map[string]any{"type": "gaussianNoise", "target": 0.0, "variation": 8.0},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &MetricNormalNoise{}, g)
+	assert.True(t, aliasWarned["gaussianNoise"])
+
+	_, err = CreateMetricGenerator(scriptaction.ScriptAction{
+		Spec: map[string]any{"type": "noSuchNoise"},
+	})
+	assert.EqualError(t, err, "unknown metricGenerator type: noSuchNoise")
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricThresholdSpec switches between two chains of generators on the
+// value built by the generators before it, so one signal can drive
+// another, such as errors appearing once load passes a limit.  The
+// Above chain takes over when the incoming value rises past Threshold,
+// and Below takes back over once it falls under Threshold − Hysteresis,
+// so noise around the threshold does not flap between them.
+type MetricThresholdSpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Threshold           float64 `mapstructure:"threshold" yaml:"threshold" json:"threshold"`
+	Hysteresis          float64 `mapstructure:"hysteresis" yaml:"hysteresis" json:"hysteresis"`
+	// Above and Below are generator IDs, run in order from zero like a
+	// metric's generators.  An empty chain emits zero.
+	Above []string `mapstructure:"above" yaml:"above" json:"above"`
+	Below []string `mapstructure:"below" yaml:"below" json:"below"`
+}
+
+// MetricThreshold emits the value of whichever chain is active in place
+// of the incoming value.  Only the active chain runs, so stateful
+// generators in the other pause until it is switched back.
+type MetricThreshold struct {
+	spec       MetricThresholdSpec
+	generators map[string]MetricGenerator
+	above      bool
+	evaluating bool
+	warned     bool
+}
+
+var _ MetricGenerator = (*MetricThreshold)(nil)
+var _ Linker = (*MetricThreshold)(nil)
+
+func NewMetricThreshold(_ time.Duration, is map[string]any) (*MetricThreshold, error) {
+	m := &MetricThreshold{}
+	if err := m.Reconfigure(0, is); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reconfigure changes the threshold or chains.  Which chain is active
+// carries over.
+func (m *MetricThreshold) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	if _, ok := is["above"]; ok {
+		newSpec.Above = nil
+	}
+	if _, ok := is["below"]; ok {
+		newSpec.Below = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if newSpec.Hysteresis < 0 {
+		return fmt.Errorf("hysteresis must not be negative, got %v", newSpec.Hysteresis)
+	}
+	if len(newSpec.Above) == 0 && len(newSpec.Below) == 0 {
+		return errors.New("threshold needs an above or below chain")
+	}
+	if m.generators != nil {
+		if err := checkChains(newSpec, m.generators); err != nil {
+			return err
+		}
+	}
+	m.spec = newSpec
+	m.warned = false
+	return nil
+}
+
+func (m *MetricThreshold) Link(generators map[string]MetricGenerator) error {
+	if err := checkChains(m.spec, generators); err != nil {
+		return err
+	}
+	m.generators = generators
+	return nil
+}
+
+func (m *MetricThreshold) References() []string {
+	refs := slices.Concat(m.spec.Above, m.spec.Below)
+	slices.Sort(refs)
+	return slices.Compact(refs)
+}
+
+func (m *MetricThreshold) Emit(rs *state.RunState, incoming float64) float64 {
+	// A redefinition can introduce a cycle that linking never saw.
+	if m.evaluating || m.generators == nil {
+		if !m.warned {
+			m.warned = true
+			slog.Warn("Threshold generator is not linked, or refers to itself")
+		}
+		return incoming
+	}
+	m.evaluating = true
+	defer func() { m.evaluating = false }()
+
+	switch {
+	case !m.above && incoming > m.spec.Threshold:
+		m.above = true
+	case m.above && incoming < m.spec.Threshold-m.spec.Hysteresis:
+		m.above = false
+	}
+	chain := m.spec.Below
+	if m.above {
+		chain = m.spec.Above
+	}
+	value := 0.0
+	for _, id := range chain {
+		value = m.generators[id].Emit(rs, value)
+	}
+	return value
+}
+
+func checkChains(spec MetricThresholdSpec, generators map[string]MetricGenerator) error {
+	for _, id := range slices.Concat(spec.Above, spec.Below) {
+		if _, ok := generators[id]; !ok {
+			return errors.New("unknown generator: " + id)
+		}
+	}
+	return nil
+}
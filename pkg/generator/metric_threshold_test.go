@@ -0,0 +1,61 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricThreshold_Hysteresis(t *testing.T) {
+	errs, err := NewMetricConstant(0, map[string]any{"value": 5.0})
+	require.NoError(t, err)
+	doubled, err := NewMetricScale(0, map[string]any{"start": 2.0, "factor": 2.0})
+	require.NoError(t, err)
+	m, err := NewMetricThreshold(0, map[string]any{
+		"threshold":  100.0,
+		"hysteresis": 20.0,
+		"above":      []any{"errors", "doubled"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, LinkGenerators(map[string]MetricGenerator{"errors": errs, "doubled": doubled, "switch": m}))
+
+	rs := &state.RunState{}
+	var got []float64
+	for _, load := range []float64{50, 100, 101, 90, 81, 79, 95, 120} {
+		got = append(got, m.Emit(rs, load))
+	}
+	// On above 100, off again only under 80.
+	assert.Equal(t, []float64{0, 0, 10, 10, 10, 0, 0, 10}, got)
+}
+
+func TestMetricThreshold_Invalid(t *testing.T) {
+	_, err := NewMetricThreshold(0, map[string]any{"threshold": 1.0})
+	assert.EqualError(t, err, "threshold needs an above or below chain")
+	_, err = NewMetricThreshold(0, map[string]any{"above": []any{"x"}, "hysteresis": -1.0})
+	assert.EqualError(t, err, "hysteresis must not be negative, got -1")
+
+	m, err := NewMetricThreshold(0, map[string]any{"above": []any{"missing"}})
+	require.NoError(t, err)
+	assert.EqualError(t, LinkGenerators(map[string]MetricGenerator{"switch": m}), "generator switch: unknown generator: missing")
+
+	loop, err := NewMetricThreshold(0, map[string]any{"below": []any{"loop"}})
+	require.NoError(t, err)
+	assert.EqualError(t, LinkGenerators(map[string]MetricGenerator{"loop": loop}), "generator loop refers to itself")
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type MetricTimeOfDaySpec struct {
+	MetricGeneratorSpec `mapstructure:",squash" yaml:",inline"`
+	Base                float64   `mapstructure:"base" yaml:"base" json:"base"`
+	Multipliers         []float64 `mapstructure:"multipliers" yaml:"multipliers" json:"multipliers"`
+	Interpolation       string    `mapstructure:"interpolation" yaml:"interpolation" json:"interpolation"`
+	Timezone            string    `mapstructure:"timezone" yaml:"timezone" json:"timezone"`
+}
+
+// MetricTimeOfDay emits Base scaled by a lookup table over the day of
+// the run's wallclock.  The day is split into one equal bucket per
+// multiplier, so 24 are hourly and 96 are quarter hours.  With "step"
+// interpolation each bucket holds its multiplier; with "linear" the
+// multiplier moves between bucket starts.
+type MetricTimeOfDay struct {
+	spec MetricTimeOfDaySpec
+	loc  *time.Location
+}
+
+var _ MetricGenerator = (*MetricTimeOfDay)(nil)
+
+func NewMetricTimeOfDay(_ time.Duration, is map[string]any) (*MetricTimeOfDay, error) {
+	m := &MetricTimeOfDay{
+		spec: MetricTimeOfDaySpec{
+			Interpolation: "step",
+			Timezone:      "UTC",
+		},
+	}
+	if err := m.Reconfigure(0, is); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *MetricTimeOfDay) Reconfigure(_ time.Duration, is map[string]any) error {
+	newSpec := m.spec
+	if _, ok := is["multipliers"]; ok {
+		newSpec.Multipliers = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if len(newSpec.Multipliers) == 0 {
+		return errors.New("timeOfDay needs at least one multiplier")
+	}
+	switch newSpec.Interpolation {
+	case "step", "linear":
+	default:
+		return fmt.Errorf("invalid interpolation: %q", newSpec.Interpolation)
+	}
+	loc, err := time.LoadLocation(newSpec.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", newSpec.Timezone, err)
+	}
+
+	m.spec = newSpec
+	m.loc = loc
+	return nil
+}
+
+func (m *MetricTimeOfDay) Emit(rs *state.RunState, incoming float64) float64 {
+	now := rs.Wallclock.In(m.loc)
+	sinceMidnight := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute +
+		time.Duration(now.Second())*time.Second + time.Duration(now.Nanosecond())
+	n := len(m.spec.Multipliers)
+	pos := float64(sinceMidnight) / float64(24*time.Hour) * float64(n)
+	i := min(int(pos), n-1)
+	mult := m.spec.Multipliers[i]
+	if m.spec.Interpolation == "linear" {
+		next := m.spec.Multipliers[(i+1)%n]
+		mult += (next - mult) * (pos - float64(i))
+	}
+	return incoming + m.spec.Base*mult
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricTimeOfDay_Emit(t *testing.T) {
+	hourly := make([]any, 24)
+	for h := range hourly {
+		hourly[h] = float64(h) / 10
+	}
+	m, err := NewMetricTimeOfDay(0, map[string]any{"base": 100.0, "multipliers": hourly})
+	require.NoError(t, err)
+
+	at := func(hour, minute int) *state.RunState {
+		return &state.RunState{Wallclock: time.Date(2025, 6, 2, hour, minute, 0, 0, time.UTC)}
+	}
+	assert.InDelta(t, 0.0, m.Emit(at(0, 0), 0), 1e-9)
+	assert.InDelta(t, 130.0, m.Emit(at(13, 59), 0), 1e-9)
+	assert.InDelta(t, 235.0, m.Emit(at(23, 30), 5), 1e-9)
+
+	require.NoError(t, m.Reconfigure(0, map[string]any{"multipliers": []any{1.0, 3.0}, "interpolation": "linear"}))
+	assert.InDelta(t, 200.0, m.Emit(at(6, 0), 0), 1e-9)
+	assert.InDelta(t, 200.0, m.Emit(at(18, 0), 0), 1e-9, "the last bucket moves back to the first")
+}
+
+func TestMetricTimeOfDay_Timezone(t *testing.T) {
+	m, err := NewMetricTimeOfDay(0, map[string]any{"base": 1.0, "multipliers": []any{0.0, 1.0}, "timezone": "America/New_York"})
+	require.NoError(t, err)
+	// 14:00 UTC is 10:00 in New York, in the morning bucket.
+	rs := &state.RunState{Wallclock: time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC)}
+	assert.Equal(t, 0.0, m.Emit(rs, 0))
+}
+
+func TestMetricTimeOfDay_Invalid(t *testing.T) {
+	_, err := NewMetricTimeOfDay(0, map[string]any{"base": 1.0})
+	assert.EqualError(t, err, "timeOfDay needs at least one multiplier")
+	_, err = NewMetricTimeOfDay(0, map[string]any{"multipliers": []any{1.0}, "interpolation": "cubic"})
+	assert.EqualError(t, err, `invalid interpolation: "cubic"`)
+	_, err = NewMetricTimeOfDay(0, map[string]any{"multipliers": []any{1.0}, "timezone": "Nowhere/Special"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricWeekly_Emit(t *testing.T) {
+	m, err := NewMetricWeekly(0, map[string]any{
+		"base":         100.0,
+		"weekdayScale": 1.2,
+		"weekendScale": 0.3,
+		"days":         map[string]any{"Friday": 0.9},
+	})
+	require.NoError(t, err)
+
+	// 2025-06-02 was a Monday.
+	day := func(offset int) *state.RunState {
+		return &state.RunState{Wallclock: time.Date(2025, 6, 2+offset, 12, 0, 0, 0, time.UTC)}
+	}
+	assert.InDelta(t, 120.0, m.Emit(day(0), 0), 1e-9)
+	assert.InDelta(t, 90.0, m.Emit(day(4), 0), 1e-9)
+	assert.InDelta(t, 30.0, m.Emit(day(5), 0), 1e-9)
+	assert.InDelta(t, 35.0, m.Emit(day(6), 5), 1e-9)
+}
+
+func TestMetricWeekly_Timezone(t *testing.T) {
+	m, err := NewMetricWeekly(0, map[string]any{"base": 1.0, "weekendScale": 0.0, "timezone": "Pacific/Auckland"})
+	require.NoError(t, err)
+	// Friday 20:00 UTC is already Saturday in Auckland.
+	rs := &state.RunState{Wallclock: time.Date(2025, 6, 6, 20, 0, 0, 0, time.UTC)}
+	assert.Equal(t, 0.0, m.Emit(rs, 0))
+}
+
+func TestMetricWeekly_Invalid(t *testing.T) {
+	_, err := NewMetricWeekly(0, map[string]any{"days": map[string]any{"someday": 1.0}})
+	assert.Error(t, err)
+	_, err = NewMetricWeekly(0, map[string]any{"timezone": "Nowhere/Special"})
+	assert.Error(t, err)
+
+	m, err := NewMetricWeekly(0, map[string]any{"base": 10.0})
+	require.NoError(t, err)
+	assert.Error(t, m.Reconfigure(0, map[string]any{"days": map[string]any{"funday": 2.0}}))
+	assert.Equal(t, 10.0, m.spec.Base)
+}
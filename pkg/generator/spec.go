@@ -0,0 +1,87 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+// SpecReporter is implemented by generators that can report the spec
+// they run with, after decoding, defaults, and any redefinitions.
+type SpecReporter interface {
+	Spec() any
+}
+
+func (m *MetricAnomaly) Spec() any          { return m.spec }
+func (m *MetricBrownian) Spec() any         { return m.spec }
+func (m *MetricClamp) Spec() any            { return m.spec }
+func (m *MetricConstant) Spec() any         { return m.spec }
+func (m *MetricDiurnal) Spec() any          { return m.spec }
+func (m *MetricDropout) Spec() any          { return m.spec }
+func (m *MetricExpression) Spec() any       { return m.spec }
+func (m *MetricFollow) Spec() any           { return m.spec }
+func (m *MetricGammaNoise) Spec() any       { return m.spec }
+func (m *MetricHoltWinters) Spec() any      { return m.spec }
+func (m *MetricLognormalNoise) Spec() any   { return m.spec }
+func (m *MetricMarkov) Spec() any           { return m.spec }
+func (m *MetricNormalNoise) Spec() any      { return m.spec }
+func (m *MetricOutlier) Spec() any          { return m.spec }
+func (m *MetricPID) Spec() any              { return m.spec }
+func (m *MetricPoissonNoise) Spec() any     { return m.spec }
+func (m *MetricPrometheusReplay) Spec() any { return m.spec }
+func (m *MetricQuantize) Spec() any         { return m.spec }
+func (m *MetricQueue) Spec() any            { return m.spec }
+func (m *MetricRamp) Spec() any             { return m.spec }
+func (m *MetricRandomWalk) Spec() any       { return m.spec }
+func (m *MetricSaturation) Spec() any       { return m.spec }
+func (m *MetricScale) Spec() any            { return m.spec }
+func (m *MetricSine) Spec() any             { return m.spec }
+func (m *MetricSmoothNoise) Spec() any      { return m.spec }
+func (m *MetricSpikyNoise) Spec() any       { return m.spec }
+func (m *MetricStep) Spec() any             { return m.spec }
+func (m *MetricThreshold) Spec() any        { return m.spec }
+func (m *MetricTimeOfDay) Spec() any        { return m.spec }
+func (m *MetricUniformNoise) Spec() any     { return m.spec }
+func (m *MetricWeekly) Spec() any           { return m.spec }
+
+var (
+	_ SpecReporter = (*MetricAnomaly)(nil)
+	_ SpecReporter = (*MetricBrownian)(nil)
+	_ SpecReporter = (*MetricClamp)(nil)
+	_ SpecReporter = (*MetricConstant)(nil)
+	_ SpecReporter = (*MetricDiurnal)(nil)
+	_ SpecReporter = (*MetricDropout)(nil)
+	_ SpecReporter = (*MetricExpression)(nil)
+	_ SpecReporter = (*MetricFollow)(nil)
+	_ SpecReporter = (*MetricGammaNoise)(nil)
+	_ SpecReporter = (*MetricHoltWinters)(nil)
+	_ SpecReporter = (*MetricLognormalNoise)(nil)
+	_ SpecReporter = (*MetricMarkov)(nil)
+	_ SpecReporter = (*MetricNormalNoise)(nil)
+	_ SpecReporter = (*MetricOutlier)(nil)
+	_ SpecReporter = (*MetricPID)(nil)
+	_ SpecReporter = (*MetricPoissonNoise)(nil)
+	_ SpecReporter = (*MetricPrometheusReplay)(nil)
+	_ SpecReporter = (*MetricQuantize)(nil)
+	_ SpecReporter = (*MetricQueue)(nil)
+	_ SpecReporter = (*MetricRamp)(nil)
+	_ SpecReporter = (*MetricRandomWalk)(nil)
+	_ SpecReporter = (*MetricSaturation)(nil)
+	_ SpecReporter = (*MetricScale)(nil)
+	_ SpecReporter = (*MetricSine)(nil)
+	_ SpecReporter = (*MetricSmoothNoise)(nil)
+	_ SpecReporter = (*MetricSpikyNoise)(nil)
+	_ SpecReporter = (*MetricStep)(nil)
+	_ SpecReporter = (*MetricThreshold)(nil)
+	_ SpecReporter = (*MetricTimeOfDay)(nil)
+	_ SpecReporter = (*MetricUniformNoise)(nil)
+	_ SpecReporter = (*MetricWeekly)(nil)
+)
@@ -0,0 +1,124 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health serves a health endpoint in the style of the
+// OpenTelemetry Collector's health_check extension, so flutter can be
+// probed like the collectors it runs alongside.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultAddr is the health_check extension's default endpoint.
+const DefaultAddr = ":13133"
+
+// Server reports 200 while the simulation is running and 503 before it
+// starts and after it stops.
+type Server struct {
+	mu      sync.Mutex
+	ready   bool
+	upSince time.Time
+	srv     *http.Server
+	ln      net.Listener
+}
+
+type status struct {
+	Status  string    `json:"status"`
+	UpSince time.Time `json:"upSince,omitzero"`
+	Uptime  string    `json:"uptime,omitempty"`
+}
+
+func NewServer(addr string) *Server {
+	s := &Server{}
+	mux := http.NewServeMux()
+	mux.Handle("/", s)
+	s.srv = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Handle adds another handler to the server, such as a debug page.
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.srv.Handler.(*http.ServeMux).Handle(pattern, h)
+}
+
+// Start listens and serves in the background.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Health server failed", "error", err)
+		}
+	}()
+	slog.Info("Health server listening", "addr", ln.Addr().String())
+	return nil
+}
+
+// Addr returns the address the server listens on, once started.
+func (s *Server) Addr() string {
+	if s.ln == nil {
+		return s.srv.Addr
+	}
+	return s.ln.Addr().String()
+}
+
+// SetReady marks the simulation as running or stopped.
+func (s *Server) SetReady(ready bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ready && !s.ready {
+		s.upSince = time.Now()
+	}
+	s.ready = ready
+}
+
+// Shutdown stops the server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.SetReady(false)
+	return s.srv.Shutdown(ctx)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	st := status{Status: "Server not available"}
+	code := http.StatusServiceUnavailable
+	if s.ready {
+		st = status{
+			Status:  "Server available",
+			UpSince: s.upSince,
+			Uptime:  time.Since(s.upSince).Round(time.Millisecond).String(),
+		}
+		code = http.StatusOK
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(st)
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	s := NewServer("127.0.0.1:0")
+	require.NoError(t, s.Start())
+	defer func() { _ = s.Shutdown(context.Background()) }()
+
+	get := func() (int, status) {
+		resp, err := http.Get("http://" + s.Addr() + "/")
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var st status
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&st))
+		return resp.StatusCode, st
+	}
+
+	code, st := get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "Server not available", st.Status)
+
+	s.SetReady(true)
+	code, st = get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Server available", st.Status)
+	assert.False(t, st.UpSince.IsZero())
+
+	s.SetReady(false)
+	code, _ = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
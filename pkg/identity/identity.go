@@ -0,0 +1,185 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package identity gives each simulated entity one stable set of
+// identifying resource attributes, such as host.id and k8s.pod.uid,
+// whichever signal it appears in, so backends can correlate its
+// metrics and traces.
+package identity
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// namespace seeds the IDs derived for entities, so they do not
+// collide with other name-based UUIDs.
+var namespace = uuid.MustParse("4f6c1a52-8d3e-4b7a-9c21-0e5d7f9a3b64")
+
+// Kind is a type of entity, recognised by the attribute that names it.
+type Kind struct {
+	// Name is the kind, such as "k8s.pod".
+	Name string
+	// NameAttribute names an entity of this kind, such as k8s.pod.name.
+	NameAttribute string
+	// ScopeAttributes, when present, tell apart entities with the same
+	// name, such as pods of one name in two namespaces.
+	ScopeAttributes []string
+	// IDAttribute is the identifier the registry assigns, such as
+	// k8s.pod.uid.
+	IDAttribute string
+}
+
+// Kinds are the entities the registry knows, following the
+// OpenTelemetry semantic conventions.
+var Kinds = []Kind{
+	{Name: "host", NameAttribute: "host.name", IDAttribute: "host.id"},
+	{Name: "k8s.node", NameAttribute: "k8s.node.name", ScopeAttributes: []string{"k8s.cluster.name"}, IDAttribute: "k8s.node.uid"},
+	{Name: "k8s.pod", NameAttribute: "k8s.pod.name", ScopeAttributes: []string{"k8s.cluster.name", "k8s.namespace.name"}, IDAttribute: "k8s.pod.uid"},
+}
+
+// Entity is one entity seen by the registry.
+type Entity struct {
+	Kind string
+	// IDAttribute is the attribute in Attributes holding its ID.
+	IDAttribute string
+	// Attributes are the entity's naming, scope, and ID attributes.
+	Attributes map[string]string
+}
+
+// Event is an entity appearing or disappearing.
+type Event struct {
+	Entity Entity
+	// Deleted is false when the entity appeared and true when it
+	// disappeared.
+	Deleted bool
+	// At is the tick the entity was first seen, or last seen before
+	// it disappeared.
+	At time.Duration
+}
+
+// entry is an entity and when the registry saw it.
+type entry struct {
+	Entity
+	// seen is set by Apply and cleared by Events.
+	seen     bool
+	live     bool
+	lastSeen time.Duration
+}
+
+// Registry remembers the ID of every entity it has seen.  Resources
+// naming an entity but missing its ID get the one already assigned: the
+// first ID set explicitly, or else one derived from the entity's name
+// and scope, which is the same in every run.
+type Registry struct {
+	mu       sync.Mutex
+	entities map[string]*entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{entities: map[string]*entry{}}
+}
+
+// Apply adds the IDs of every entity attrs names that it is missing.
+func (r *Registry) Apply(attrs pcommon.Map) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, kind := range Kinds {
+		name, ok := attrs.Get(kind.NameAttribute)
+		if !ok {
+			continue
+		}
+		ident := map[string]string{kind.NameAttribute: name.AsString()}
+		for _, k := range kind.ScopeAttributes {
+			if v, ok := attrs.Get(k); ok {
+				ident[k] = v.AsString()
+			}
+		}
+		key := entityKey(kind.Name, ident)
+		e, ok := r.entities[key]
+		if !ok {
+			id := uuid.NewSHA1(namespace, []byte(key)).String()
+			if v, ok := attrs.Get(kind.IDAttribute); ok {
+				id = v.AsString()
+			}
+			ident[kind.IDAttribute] = id
+			e = &entry{Entity: Entity{Kind: kind.Name, IDAttribute: kind.IDAttribute, Attributes: ident}}
+			r.entities[key] = e
+		}
+		e.seen = true
+		if _, ok := attrs.Get(kind.IDAttribute); !ok {
+			attrs.PutStr(kind.IDAttribute, e.Attributes[kind.IDAttribute])
+		}
+	}
+}
+
+// Entities returns every entity seen so far, ordered by kind and then
+// by name and scope.
+func (r *Registry) Entities() []Entity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entities := make([]Entity, 0, len(r.entities))
+	for _, key := range slices.Sorted(maps.Keys(r.entities)) {
+		entities = append(entities, r.entities[key].clone())
+	}
+	return entities
+}
+
+// Events returns the entities first seen, or seen again after
+// disappearing, since the last call, and those last seen deleteAfter
+// or more before tick, which have disappeared.  Events come in the
+// order of Entities.
+func (r *Registry) Events(tick, deleteAfter time.Duration) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []Event
+	for _, key := range slices.Sorted(maps.Keys(r.entities)) {
+		e := r.entities[key]
+		switch {
+		case e.seen:
+			if !e.live {
+				events = append(events, Event{Entity: e.clone(), At: tick})
+			}
+			e.seen, e.live, e.lastSeen = false, true, tick
+		case e.live && tick-e.lastSeen >= deleteAfter:
+			events = append(events, Event{Entity: e.clone(), Deleted: true, At: e.lastSeen})
+			e.live = false
+		}
+	}
+	return events
+}
+
+func (e *entry) clone() Entity {
+	return Entity{Kind: e.Kind, IDAttribute: e.IDAttribute, Attributes: maps.Clone(e.Attributes)}
+}
+
+// entityKey is a canonical string for an entity of kind with the
+// identifying attributes ident.
+func entityKey(kind string, ident map[string]string) string {
+	var b strings.Builder
+	b.WriteString(kind)
+	for _, k := range slices.Sorted(maps.Keys(ident)) {
+		b.WriteByte(0)
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(ident[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func resource(t *testing.T, attrs map[string]any) pcommon.Map {
+	m := pcommon.NewMap()
+	require.NoError(t, m.FromRaw(attrs))
+	return m
+}
+
+func get(m pcommon.Map, k string) string {
+	v, _ := m.Get(k)
+	return v.AsString()
+}
+
+func TestRegistry_Apply(t *testing.T) {
+	r := NewRegistry()
+	a := resource(t, map[string]any{"k8s.namespace.name": "shop", "k8s.pod.name": "web-1", "host.name": "node-a"})
+	b := resource(t, map[string]any{"k8s.namespace.name": "shop", "k8s.pod.name": "web-1", "service.name": "web"})
+	other := resource(t, map[string]any{"k8s.namespace.name": "admin", "k8s.pod.name": "web-1"})
+	r.Apply(a)
+	r.Apply(b)
+	r.Apply(other)
+
+	assert.NotEmpty(t, get(a, "k8s.pod.uid"))
+	assert.Equal(t, get(a, "k8s.pod.uid"), get(b, "k8s.pod.uid"))
+	assert.NotEqual(t, get(a, "k8s.pod.uid"), get(other, "k8s.pod.uid"), "pods in different namespaces are different entities")
+	assert.NotEmpty(t, get(a, "host.id"))
+	_, ok := b.Get("host.id")
+	assert.False(t, ok, "no host is named, so no host ID is added")
+
+	// IDs are derived from the entity alone, so every run agrees.
+	again := resource(t, map[string]any{"k8s.namespace.name": "shop", "k8s.pod.name": "web-1"})
+	NewRegistry().Apply(again)
+	assert.Equal(t, get(a, "k8s.pod.uid"), get(again, "k8s.pod.uid"))
+}
+
+func TestRegistry_ExplicitID(t *testing.T) {
+	r := NewRegistry()
+	explicit := resource(t, map[string]any{"host.name": "db-1", "host.id": "i-0abc"})
+	implicit := resource(t, map[string]any{"host.name": "db-1"})
+	r.Apply(explicit)
+	r.Apply(implicit)
+	assert.Equal(t, "i-0abc", get(explicit, "host.id"))
+	assert.Equal(t, "i-0abc", get(implicit, "host.id"), "the first explicit ID is shared")
+}
+
+func TestRegistry_Entities(t *testing.T) {
+	r := NewRegistry()
+	r.Apply(resource(t, map[string]any{"k8s.pod.name": "web-1", "k8s.node.name": "node-a", "k8s.cluster.name": "prod"}))
+	r.Apply(resource(t, map[string]any{"k8s.pod.name": "web-1", "k8s.node.name": "node-a", "k8s.cluster.name": "prod"}))
+
+	entities := r.Entities()
+	require.Len(t, entities, 2)
+	assert.Equal(t, "k8s.node", entities[0].Kind)
+	assert.Equal(t, "node-a", entities[0].Attributes["k8s.node.name"])
+	assert.Equal(t, "prod", entities[0].Attributes["k8s.cluster.name"])
+	assert.NotEmpty(t, entities[0].Attributes["k8s.node.uid"])
+	assert.Equal(t, "k8s.pod", entities[1].Kind)
+}
+
+func TestRegistry_Events(t *testing.T) {
+	r := NewRegistry()
+	pod := map[string]any{"k8s.pod.name": "web-1"}
+
+	r.Apply(resource(t, pod))
+	events := r.Events(time.Second, time.Minute)
+	require.Len(t, events, 1)
+	assert.False(t, events[0].Deleted)
+	assert.Equal(t, time.Second, events[0].At)
+	assert.Equal(t, "k8s.pod.uid", events[0].Entity.IDAttribute)
+
+	r.Apply(resource(t, pod))
+	assert.Empty(t, r.Events(10*time.Second, time.Minute), "an entity still seen has no events")
+	assert.Empty(t, r.Events(69*time.Second, time.Minute))
+
+	events = r.Events(70*time.Second, time.Minute)
+	require.Len(t, events, 1)
+	assert.True(t, events[0].Deleted)
+	assert.Equal(t, 10*time.Second, events[0].At, "deleted as of when it was last seen")
+	assert.Empty(t, r.Events(80*time.Second, time.Minute))
+
+	r.Apply(resource(t, pod))
+	events = r.Events(90*time.Second, time.Minute)
+	require.Len(t, events, 1)
+	assert.False(t, events[0].Deleted, "an entity seen again is created again")
+}
@@ -16,9 +16,12 @@ package metricproducer
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/cardinalhq/oteltools/signalbuilder"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 
 	"github.com/cardinalhq/flutter/pkg/generator"
 	"github.com/cardinalhq/flutter/pkg/scriptaction"
@@ -45,8 +48,16 @@ type MetricProducerSpec struct {
 	Type       string        `mapstructure:"type" yaml:"type" json:"type"`
 	Name       string        `mapstructure:"name" yaml:"name" json:"name"`
 	Disabled   bool          `mapstructure:"disabled,omitempty" yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	// Min and Max, when set, clamp each emitted value so noise cannot
+	// push it outside a physically plausible range.
+	Min *float64 `mapstructure:"min,omitempty" yaml:"min,omitempty" json:"min,omitempty"`
+	Max *float64 `mapstructure:"max,omitempty" yaml:"max,omitempty" json:"max,omitempty"`
 
 	lastEmitted time.Duration
+	lastValue   float64
+	samples     int
+	clamped     int
+	clampWarned bool
 }
 
 type MetricProducerInterface interface {
@@ -55,6 +66,9 @@ type MetricProducerInterface interface {
 	Enable()
 	Disable()
 	IsDisabled() bool
+	LastEmit() (at time.Duration, value float64, ok bool)
+	// GeneratorIDs returns every generator the producer runs.
+	GeneratorIDs() []string
 }
 
 func (m *MetricProducerSpec) GetAttributes() Attributes {
@@ -66,13 +80,73 @@ func (m *MetricProducerSpec) ShouldEmit(state *state.RunState) bool {
 }
 
 func (m *MetricProducerSpec) emitDueToFrequency(state *state.RunState) bool {
-	return state.Tick >= m.lastEmitted+m.Frequency
+	return state.Tick >= m.lastEmitted+max(m.Frequency*time.Duration(state.DegradeFactor()), state.MinFrequency)
 }
 
 func (m *MetricProducerSpec) emitDueToTo(state *state.RunState) bool {
 	return m.To == 0 || state.Tick <= m.To
 }
 
+// datapointTimestamp returns the timestamp for a datapoint emitted
+// on this tick.  When the run aligns timestamps, the wallclock is
+// truncated to a multiple of Frequency, like a scrape would be.
+func (m *MetricProducerSpec) datapointTimestamp(state *state.RunState) pcommon.Timestamp {
+	ts := state.Wallclock
+	if state.AlignTimestamps && m.Frequency > 0 {
+		ts = ts.Truncate(m.Frequency)
+	}
+	return pcommon.NewTimestampFromTime(ts)
+}
+
+const (
+	// clampWarnSamples is how many samples are taken before judging
+	// whether a metric is clamped too often.
+	clampWarnSamples = 20
+	// clampWarnFraction is the fraction of clamped samples that is
+	// reported as a warning, once per metric.
+	clampWarnFraction = 0.25
+)
+
+func (m *MetricProducerSpec) validateBounds() error {
+	if m.Min != nil && m.Max != nil && *m.Min > *m.Max {
+		return fmt.Errorf("min %v is greater than max %v", *m.Min, *m.Max)
+	}
+	return nil
+}
+
+// clamp limits value to [Min, Max].  If clamping happens often, the
+// generators are probably out of line with the bounds, so it warns once.
+func (m *MetricProducerSpec) clamp(value float64) float64 {
+	m.samples++
+	clamped := value
+	if m.Min != nil && clamped < *m.Min {
+		clamped = *m.Min
+	}
+	if m.Max != nil && clamped > *m.Max {
+		clamped = *m.Max
+	}
+	if clamped != value {
+		m.clamped++
+	}
+	m.lastValue = clamped
+	if !m.clampWarned && m.samples >= clampWarnSamples && float64(m.clamped) >= clampWarnFraction*float64(m.samples) {
+		m.clampWarned = true
+		slog.Warn("Metric values are frequently clamped", "metric", m.Name, "clamped", m.clamped, "samples", m.samples)
+	}
+	return clamped
+}
+
+// LastEmit returns the tick and value of the most recent emit.  For
+// distributions the value is the median.  ok is false until the first
+// emit.
+func (m *MetricProducerSpec) LastEmit() (time.Duration, float64, bool) {
+	return m.lastEmitted, m.lastValue, m.samples > 0
+}
+
+func (m *MetricProducerSpec) GeneratorIDs() []string {
+	return m.Generators
+}
+
 func (m *MetricProducerSpec) Enable() {
 	m.Disabled = false
 }
@@ -105,6 +179,10 @@ func CreateMetricExporter(generators map[string]generator.MetricGenerator, name
 		return NewMetricGauge(generators, name, mes)
 	case "sum":
 		return NewMetricSum(generators, name, mes)
+	case "histogram":
+		return NewMetricHistogram(generators, name, mes)
+	case "percentiles":
+		return NewMetricPercentiles(generators, name, mes)
 	default:
 		return nil, errors.New("unknown metric exporter type: " + exporterType)
 	}
@@ -54,6 +54,9 @@ func NewMetricGauge(generators map[string]generator.MetricGenerator, name string
 		return nil, &brokenwing.DecodeError{Name: name, Err: err}
 	}
 
+	if err := gaugeSpec.validateBounds(); err != nil {
+		return nil, fmt.Errorf("metric %s: %w", name, err)
+	}
 	if len(gaugeSpec.Generators) == 0 {
 		return nil, fmt.Errorf("%w: %s", brokenwing.ErrNoGenerators, name)
 	}
@@ -74,6 +77,9 @@ func (m *MetricGauge) Reconfigure(generators map[string]generator.MetricGenerato
 	if err := decoder.Decode(spec); err != nil {
 		return &brokenwing.DecodeError{Name: m.Name, Err: err}
 	}
+	if err := m.validateBounds(); err != nil {
+		return fmt.Errorf("metric %s: %w", m.Name, err)
+	}
 	for _, generatorName := range m.Generators {
 		if _, ok := generators[generatorName]; !ok {
 			return fmt.Errorf("%w: %s", brokenwing.ErrUnknownGenerator, generatorName)
@@ -87,11 +93,16 @@ func (m *MetricGauge) Emit(generators map[string]generator.MetricGenerator, stat
 		return nil
 	}
 	m.lastEmitted = state.Tick
+	state.DropDatapoint = false
 
 	value, err := calculateValue(generators, m.Generators, state)
 	if err != nil {
 		return err
 	}
+	value = m.clamp(value)
+	if state.DropDatapoint {
+		return nil
+	}
 
 	rattr := pcommon.NewMap()
 	if err := rattr.FromRaw(m.Attributes.Resource); err != nil {
@@ -115,7 +126,7 @@ func (m *MetricGauge) Emit(generators map[string]generator.MetricGenerator, stat
 		return fmt.Errorf("failed to create datapoint attributes: %w", err)
 	}
 
-	dp, _, _ := mm.Datapoint(dattr, pcommon.NewTimestampFromTime(state.Wallclock))
+	dp, _, _ := mm.Datapoint(dattr, m.datapointTimestamp(state))
 	dp.SetDoubleValue(value)
 
 	return nil
@@ -0,0 +1,207 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricproducer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/cardinalhq/oteltools/signalbuilder"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// DefaultHistogramBounds are latency-style bucket bounds, in milliseconds.
+var DefaultHistogramBounds = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// MetricHistogram emits a delta histogram of Samples draws per emission
+// from a lognormal distribution.  The generators give its median and the
+// optional SigmaGenerators its shape, so both can drift along timeline
+// segments and produce a smoothly moving latency heatmap.
+type MetricHistogram struct {
+	MetricProducerSpec `mapstructure:",squash" yaml:",inline" json:",inline"`
+	SigmaGenerators    []string  `mapstructure:"sigmaGenerators,omitempty" yaml:"sigmaGenerators,omitempty" json:"sigmaGenerators,omitempty"`
+	Sigma              float64   `mapstructure:"sigma,omitempty" yaml:"sigma,omitempty" json:"sigma,omitempty"`
+	Bounds             []float64 `mapstructure:"bounds,omitempty" yaml:"bounds,omitempty" json:"bounds,omitempty"`
+	Samples            int       `mapstructure:"samples,omitempty" yaml:"samples,omitempty" json:"samples,omitempty"`
+
+	// lastTimestamp is the previous datapoint's timestamp, where the
+	// next delta starts.
+	lastTimestamp pcommon.Timestamp
+}
+
+var _ MetricProducer = (*MetricHistogram)(nil)
+
+func NewMetricHistogram(generators map[string]generator.MetricGenerator, name string, mes scriptaction.ScriptAction) (*MetricHistogram, error) {
+	spec := MetricHistogram{
+		MetricProducerSpec: MetricProducerSpec{
+			Frequency: DefaultFrequency,
+			Name:      name,
+			To:        mes.To,
+		},
+		Sigma:   0.5,
+		Bounds:  slices.Clone(DefaultHistogramBounds),
+		Samples: 100,
+	}
+	if name == "" {
+		return nil, errors.New("invalid metric name: " + name)
+	}
+
+	if _, ok := mes.Spec["bounds"]; ok {
+		spec.Bounds = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(mes.Spec); err != nil {
+		return nil, fmt.Errorf("unable to decode MetricHistogram for %q: %w", name, err)
+	}
+	if err := spec.validate(generators); err != nil {
+		return nil, fmt.Errorf("metric %s: %w", name, err)
+	}
+	return &spec, nil
+}
+
+func (m *MetricHistogram) Reconfigure(generators map[string]generator.MetricGenerator, spec map[string]any) error {
+	newSpec := *m
+	if _, ok := spec["bounds"]; ok {
+		newSpec.Bounds = nil
+	}
+	if _, ok := spec["sigmaGenerators"]; ok {
+		newSpec.SigmaGenerators = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(spec); err != nil {
+		return fmt.Errorf("unable to decode MetricHistogram for %q: %w", m.Name, err)
+	}
+	if err := newSpec.validate(generators); err != nil {
+		return fmt.Errorf("metric %s: %w", m.Name, err)
+	}
+	*m = newSpec
+	return nil
+}
+
+func (m *MetricHistogram) validate(generators map[string]generator.MetricGenerator) error {
+	if err := m.validateBounds(); err != nil {
+		return err
+	}
+	if m.Sigma < 0 {
+		return errors.New("sigma must not be negative")
+	}
+	if m.Samples <= 0 {
+		return errors.New("samples must be positive")
+	}
+	if len(m.Bounds) == 0 {
+		return errors.New("no bounds")
+	}
+	for i := 1; i < len(m.Bounds); i++ {
+		if m.Bounds[i] <= m.Bounds[i-1] {
+			return errors.New("bounds must be increasing")
+		}
+	}
+	if len(m.Generators) == 0 {
+		return errors.New("no generators")
+	}
+	for _, generatorName := range append(slices.Clone(m.Generators), m.SigmaGenerators...) {
+		if _, ok := generators[generatorName]; !ok {
+			return errors.New("unknown generator: " + generatorName)
+		}
+	}
+	return nil
+}
+
+func (m *MetricHistogram) GeneratorIDs() []string {
+	return append(slices.Clone(m.Generators), m.SigmaGenerators...)
+}
+
+func (m *MetricHistogram) Emit(generators map[string]generator.MetricGenerator, state *state.RunState, mb *signalbuilder.MetricsBuilder) error {
+	if !m.ShouldEmit(state) {
+		return nil
+	}
+	m.lastEmitted = state.Tick
+	state.DropDatapoint = false
+
+	median, err := calculateValue(generators, m.Generators, state)
+	if err != nil {
+		return err
+	}
+	median = max(m.clamp(median), 0)
+	sigma := m.Sigma
+	if len(m.SigmaGenerators) > 0 {
+		if sigma, err = calculateValue(generators, m.SigmaGenerators, state); err != nil {
+			return err
+		}
+		sigma = max(sigma, 0)
+	}
+	if state.DropDatapoint {
+		return nil
+	}
+
+	rattr := pcommon.NewMap()
+	if err := rattr.FromRaw(m.Attributes.Resource); err != nil {
+		return fmt.Errorf("failed to create resource attributes: %w", err)
+	}
+	r := mb.Resource(rattr)
+
+	sattr := pcommon.NewMap()
+	if err := sattr.FromRaw(m.Attributes.Scope); err != nil {
+		return fmt.Errorf("failed to create scope attributes: %w", err)
+	}
+	s := r.Scope(sattr)
+
+	dattr := pcommon.NewMap()
+	if err := dattr.FromRaw(m.Attributes.Datapoint); err != nil {
+		return fmt.Errorf("failed to create datapoint attributes: %w", err)
+	}
+
+	ts := m.datapointTimestamp(state)
+	dp := s.Histogram(m.Name).Datapoint(dattr, ts)
+	// Resolution tiers and budgets can stretch the interval, so each
+	// delta starts at the previous emit rather than a Frequency ago.
+	start := m.lastTimestamp
+	if start == 0 {
+		start = pcommon.NewTimestampFromTime(ts.AsTime().Add(-m.Frequency))
+	}
+	m.lastTimestamp = ts
+	dp.SetStartTimestamp(start)
+	dp.ExplicitBounds().FromRaw(m.Bounds)
+
+	counts := make([]uint64, len(m.Bounds)+1)
+	total, lo, hi := 0.0, math.Inf(1), math.Inf(-1)
+	for range m.Samples {
+		v := median * math.Exp(sigma*state.RND.NormFloat64())
+		counts[sort.SearchFloat64s(m.Bounds, v)]++
+		total += v
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	dp.BucketCounts().FromRaw(counts)
+	dp.SetCount(uint64(m.Samples))
+	dp.SetSum(total)
+	dp.SetMin(lo)
+	dp.SetMax(hi)
+	return nil
+}
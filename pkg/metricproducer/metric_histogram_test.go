@@ -0,0 +1,127 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricproducer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cardinalhq/oteltools/signalbuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricHistogram_Emit(t *testing.T) {
+	median, err := generator.NewMetricConstant(0, map[string]any{"value": 100.0})
+	require.NoError(t, err)
+	sigma, err := generator.NewMetricRamp(0, map[string]any{"start": 0.0, "target": 1.0, "duration": "10m"})
+	require.NoError(t, err)
+	generators := map[string]generator.MetricGenerator{"median": median, "sigma": sigma}
+
+	h, err := NewMetricHistogram(generators, "http.duration", scriptaction.ScriptAction{
+		Spec: map[string]any{
+			"generators":      []string{"median"},
+			"sigmaGenerators": []string{"sigma"},
+			"bounds":          []float64{50, 100, 200},
+			"samples":         500,
+			"frequency":       "1m",
+		},
+	})
+	require.NoError(t, err)
+
+	emit := func(tick time.Duration) pmetric.HistogramDataPoint {
+		rs := state.NewRunState(time.Hour, 1)
+		rs.Tick = tick
+		rs.Wallclock = time.Unix(1700000000, 0).Add(tick)
+		mb := signalbuilder.NewMetricsBuilder()
+		h.lastEmitted = -time.Hour
+		require.NoError(t, h.Emit(generators, rs, mb))
+		m := mb.Build().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		require.Equal(t, pmetric.MetricTypeHistogram, m.Type())
+		return m.Histogram().DataPoints().At(0)
+	}
+
+	// With no spread every sample is the median, in the (50, 100] bucket.
+	narrow := emit(0)
+	assert.Equal(t, uint64(500), narrow.Count())
+	assert.Equal(t, []uint64{0, 500, 0, 0}, narrow.BucketCounts().AsRaw())
+	assert.Equal(t, []float64{50, 100, 200}, narrow.ExplicitBounds().AsRaw())
+
+	// Once sigma has ramped up, the samples spread into the outer buckets.
+	wide := emit(10 * time.Minute)
+	counts := wide.BucketCounts().AsRaw()
+	assert.Equal(t, uint64(500), counts[0]+counts[1]+counts[2]+counts[3])
+	assert.Positive(t, counts[0])
+	assert.Positive(t, counts[3])
+	assert.Less(t, wide.Min(), 100.0)
+	assert.Greater(t, wide.Max(), 100.0)
+}
+
+func TestMetricHistogram_StartTimestamp(t *testing.T) {
+	median, err := generator.NewMetricConstant(0, map[string]any{"value": 100.0})
+	require.NoError(t, err)
+	generators := map[string]generator.MetricGenerator{"median": median}
+	h, err := NewMetricHistogram(generators, "http.duration", scriptaction.ScriptAction{
+		Spec: map[string]any{"generators": []string{"median"}, "frequency": "10s"},
+	})
+	require.NoError(t, err)
+
+	// The first three minutes are in a 1m resolution tier.
+	start := time.Unix(1700000000, 0).UTC()
+	rs := state.NewRunState(time.Hour, 1)
+	var intervals []time.Duration
+	var last time.Time
+	for tick := range 241 {
+		rs.Tick = time.Duration(tick) * time.Second
+		rs.Wallclock = start.Add(rs.Tick)
+		rs.MinFrequency = 0
+		if rs.Tick < 3*time.Minute {
+			rs.MinFrequency = time.Minute
+		}
+		mb := signalbuilder.NewMetricsBuilder()
+		require.NoError(t, h.Emit(generators, rs, mb))
+		md := mb.Build()
+		if md.DataPointCount() == 0 {
+			continue
+		}
+		dp := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+		if !last.IsZero() {
+			assert.Equal(t, last, dp.StartTimestamp().AsTime(), "tick %d", tick)
+		}
+		last = dp.Timestamp().AsTime()
+		intervals = append(intervals, dp.Timestamp().AsTime().Sub(dp.StartTimestamp().AsTime()))
+	}
+	// The first delta is a Frequency long, those in the tier a minute,
+	// and those after it the Frequency again.
+	assert.Equal(t, []time.Duration{10 * time.Second, time.Minute, time.Minute, 10 * time.Second, 10 * time.Second}, intervals[:5])
+}
+
+func TestMetricHistogram_Invalid(t *testing.T) {
+	generators := map[string]generator.MetricGenerator{"median": nil}
+	for _, spec := range []map[string]any{
+		{},
+		{"generators": []string{"median"}, "sigmaGenerators": []string{"missing"}},
+		{"generators": []string{"median"}, "bounds": []float64{10, 5}},
+		{"generators": []string{"median"}, "samples": 0},
+	} {
+		_, err := NewMetricHistogram(generators, "http.duration", scriptaction.ScriptAction{Spec: spec})
+		assert.Error(t, err, spec)
+	}
+}
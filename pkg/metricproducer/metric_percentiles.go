@@ -0,0 +1,174 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricproducer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/cardinalhq/oteltools/signalbuilder"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// MetricPercentiles emits one gauge per quantile, such as name.p50,
+// name.p90 and name.p99, all derived from a single latency distribution.
+// The generators give the median, and the other quantiles follow from a
+// lognormal with shape Sigma, so the percentiles never cross.
+type MetricPercentiles struct {
+	MetricProducerSpec `mapstructure:",squash" yaml:",inline" json:",inline"`
+	Sigma              float64   `mapstructure:"sigma,omitempty" yaml:"sigma,omitempty" json:"sigma,omitempty"`
+	Quantiles          []float64 `mapstructure:"quantiles,omitempty" yaml:"quantiles,omitempty" json:"quantiles,omitempty"`
+}
+
+var _ MetricProducer = (*MetricPercentiles)(nil)
+
+func NewMetricPercentiles(generators map[string]generator.MetricGenerator, name string, mes scriptaction.ScriptAction) (*MetricPercentiles, error) {
+	spec := MetricPercentiles{
+		MetricProducerSpec: MetricProducerSpec{
+			Frequency: DefaultFrequency,
+			Name:      name,
+			To:        mes.To,
+		},
+		Sigma:     0.5,
+		Quantiles: []float64{0.5, 0.9, 0.99},
+	}
+	if name == "" {
+		return nil, errors.New("invalid metric name: " + name)
+	}
+
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(mes.Spec); err != nil {
+		return nil, fmt.Errorf("unable to decode MetricPercentiles for %q: %w", name, err)
+	}
+	if err := spec.validate(generators); err != nil {
+		return nil, fmt.Errorf("metric %s: %w", name, err)
+	}
+	return &spec, nil
+}
+
+func (m *MetricPercentiles) Reconfigure(generators map[string]generator.MetricGenerator, spec map[string]any) error {
+	newSpec := *m
+	if _, ok := spec["quantiles"]; ok {
+		// Decode into a fresh slice rather than over the current one.
+		newSpec.Quantiles = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&newSpec)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+	if err := decoder.Decode(spec); err != nil {
+		return fmt.Errorf("unable to decode MetricPercentiles for %q: %w", m.Name, err)
+	}
+	if err := newSpec.validate(generators); err != nil {
+		return fmt.Errorf("metric %s: %w", m.Name, err)
+	}
+	*m = newSpec
+	return nil
+}
+
+func (m *MetricPercentiles) validate(generators map[string]generator.MetricGenerator) error {
+	if err := m.validateBounds(); err != nil {
+		return err
+	}
+	if m.Sigma < 0 {
+		return errors.New("sigma must not be negative")
+	}
+	if len(m.Quantiles) == 0 {
+		return errors.New("no quantiles")
+	}
+	for _, q := range m.Quantiles {
+		if q <= 0 || q >= 1 {
+			return fmt.Errorf("quantile %v must be between 0 and 1", q)
+		}
+	}
+	if len(m.Generators) == 0 {
+		return errors.New("no generators")
+	}
+	for _, generatorName := range m.Generators {
+		if _, ok := generators[generatorName]; !ok {
+			return errors.New("unknown generator: " + generatorName)
+		}
+	}
+	return nil
+}
+
+func (m *MetricPercentiles) Emit(generators map[string]generator.MetricGenerator, state *state.RunState, mb *signalbuilder.MetricsBuilder) error {
+	if !m.ShouldEmit(state) {
+		return nil
+	}
+	m.lastEmitted = state.Tick
+	state.DropDatapoint = false
+
+	median, err := calculateValue(generators, m.Generators, state)
+	if err != nil {
+		return err
+	}
+	median = max(m.clamp(median), 0)
+	if state.DropDatapoint {
+		return nil
+	}
+
+	rattr := pcommon.NewMap()
+	if err := rattr.FromRaw(m.Attributes.Resource); err != nil {
+		return fmt.Errorf("failed to create resource attributes: %w", err)
+	}
+	r := mb.Resource(rattr)
+
+	sattr := pcommon.NewMap()
+	if err := sattr.FromRaw(m.Attributes.Scope); err != nil {
+		return fmt.Errorf("failed to create scope attributes: %w", err)
+	}
+	s := r.Scope(sattr)
+
+	dattr := pcommon.NewMap()
+	if err := dattr.FromRaw(m.Attributes.Datapoint); err != nil {
+		return fmt.Errorf("failed to create datapoint attributes: %w", err)
+	}
+
+	ts := m.datapointTimestamp(state)
+	for _, q := range slices.Sorted(slices.Values(m.Quantiles)) {
+		mm, err := s.Metric(m.Name+"."+quantileSuffix(q), "unit", pmetric.MetricTypeGauge)
+		if err != nil {
+			return fmt.Errorf("failed to create metric: %w", err)
+		}
+		dp, _, _ := mm.Datapoint(dattr, ts)
+		dp.SetDoubleValue(lognormalQuantile(median, m.Sigma, q))
+	}
+	return nil
+}
+
+// lognormalQuantile returns the q quantile of a lognormal distribution
+// with the given median and shape sigma.
+func lognormalQuantile(median, sigma, q float64) float64 {
+	z := math.Sqrt2 * math.Erfinv(2*q-1)
+	return median * math.Exp(sigma*z)
+}
+
+// quantileSuffix names a quantile the usual way, such as p50 or p99.9.
+func quantileSuffix(q float64) string {
+	return "p" + strconv.FormatFloat(math.Round(q*100*1e6)/1e6, 'f', -1, 64)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricproducer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cardinalhq/oteltools/signalbuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricPercentiles_Emit(t *testing.T) {
+	noise, err := generator.NewMetricNormalNoise(0, map[string]any{"target": 120.0, "variation": 100.0, "stdDev": 60.0})
+	require.NoError(t, err)
+	generators := map[string]generator.MetricGenerator{"latency": noise}
+
+	p, err := NewMetricPercentiles(generators, "http.latency", scriptaction.ScriptAction{
+		Spec: map[string]any{
+			"generators": []string{"latency"},
+			"frequency":  "1s",
+			"sigma":      0.8,
+		},
+	})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Minute, 1)
+	for i := range 50 {
+		rs.Tick = time.Duration(i+1) * time.Second
+		mb := signalbuilder.NewMetricsBuilder()
+		require.NoError(t, p.Emit(generators, rs, mb))
+
+		metrics := mb.Build().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		require.Equal(t, 3, metrics.Len())
+		values := map[string]float64{}
+		for j := range metrics.Len() {
+			values[metrics.At(j).Name()] = metrics.At(j).Gauge().DataPoints().At(0).DoubleValue()
+		}
+		assert.LessOrEqual(t, values["http.latency.p50"], values["http.latency.p90"])
+		assert.LessOrEqual(t, values["http.latency.p90"], values["http.latency.p99"])
+	}
+}
+
+func TestLognormalQuantile(t *testing.T) {
+	assert.InDelta(t, 100.0, lognormalQuantile(100, 0.5, 0.5), 1e-9)
+	assert.InDelta(t, 100*1.8980, lognormalQuantile(100, 0.5, 0.9), 0.01)
+	assert.Equal(t, "p50", quantileSuffix(0.5))
+	assert.Equal(t, "p99", quantileSuffix(0.99))
+	assert.Equal(t, "p99.9", quantileSuffix(0.999))
+}
+
+func TestMetricPercentiles_Invalid(t *testing.T) {
+	generators := map[string]generator.MetricGenerator{"latency": nil}
+	for _, spec := range []map[string]any{
+		{},
+		{"generators": []string{"missing"}},
+		{"generators": []string{"latency"}, "sigma": -1.0},
+		{"generators": []string{"latency"}, "quantiles": []float64{0.5, 1.0}},
+	} {
+		_, err := NewMetricPercentiles(generators, "http.latency", scriptaction.ScriptAction{Spec: spec})
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestMetricPercentiles_Reconfigure(t *testing.T) {
+	generators := map[string]generator.MetricGenerator{"latency": nil}
+	p, err := NewMetricPercentiles(generators, "http.latency", scriptaction.ScriptAction{
+		Spec: map[string]any{"generators": []string{"latency"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.Reconfigure(generators, map[string]any{"quantiles": []float64{0.75}}))
+	assert.Equal(t, []float64{0.75}, p.Quantiles)
+	assert.Error(t, p.Reconfigure(generators, map[string]any{"sigma": -2.0}))
+	assert.Equal(t, 0.5, p.Sigma)
+}
@@ -17,6 +17,7 @@ package metricproducer
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cardinalhq/oteltools/signalbuilder"
 	"github.com/mitchellh/mapstructure"
@@ -31,6 +32,20 @@ import (
 
 type MetricSum struct {
 	MetricProducerSpec `mapstructure:",squash" yaml:",inline" json:",inline"`
+	// CumulativeName, if set, also emits the running total of this
+	// (delta) sum as a cumulative sum under that name, so the two
+	// temporalities can be compared for the same logical counter.
+	CumulativeName string `mapstructure:"cumulativeName,omitempty" yaml:"cumulativeName,omitempty" json:"cumulativeName,omitempty"`
+	// ResetEvery, if set, drops the cumulative total back to zero this
+	// often, as a process restart would, with a new StartTimestamp.
+	ResetEvery time.Duration `mapstructure:"resetEvery,omitempty" yaml:"resetEvery,omitempty" json:"resetEvery,omitempty"`
+
+	total     float64
+	startTime pcommon.Timestamp
+	lastTime  pcommon.Timestamp
+	lastReset time.Duration
+	// lastSpacing is the emit spacing in force at the previous emit.
+	lastSpacing time.Duration
 }
 
 var _ MetricProducer = (*MetricSum)(nil)
@@ -55,6 +70,12 @@ func NewMetricSum(generators map[string]generator.MetricGenerator, name string,
 		return nil, fmt.Errorf("unable to decode MetricSumSpec for %q: %w", name, err)
 	}
 
+	if err := sumSpec.validateBounds(); err != nil {
+		return nil, fmt.Errorf("metric %s: %w", name, err)
+	}
+	if err := sumSpec.validateReset(); err != nil {
+		return nil, fmt.Errorf("metric %s: %w", name, err)
+	}
 	if len(sumSpec.Generators) == 0 {
 		return nil, errors.New("no generators specified for metric sum: " + name)
 	}
@@ -71,6 +92,12 @@ func (m *MetricSum) Reconfigure(generators map[string]generator.MetricGenerator,
 	if err := mapstructure.Decode(spec, m); err != nil {
 		return err
 	}
+	if err := m.validateBounds(); err != nil {
+		return fmt.Errorf("metric %s: %w", m.Name, err)
+	}
+	if err := m.validateReset(); err != nil {
+		return fmt.Errorf("metric %s: %w", m.Name, err)
+	}
 	for _, generatorName := range m.Generators {
 		if _, ok := generators[generatorName]; !ok {
 			return errors.New("unknown generator: " + generatorName)
@@ -83,12 +110,22 @@ func (m *MetricSum) Emit(generators map[string]generator.MetricGenerator, state
 	if !m.ShouldEmit(state) {
 		return nil
 	}
+	since := state.Tick - m.lastEmitted
 	m.lastEmitted = state.Tick
+	state.DropDatapoint = false
 
 	value, err := calculateValue(generators, m.Generators, state)
 	if err != nil {
 		return err
 	}
+	value = m.clamp(value)
+	value *= m.stretch(since, state)
+	m.resetIfDue(state.Tick)
+	if state.DropDatapoint {
+		// The counter keeps counting through a gap, as a real one would.
+		m.total += value
+		return nil
+	}
 
 	rattr := pcommon.NewMap()
 	if err := rattr.FromRaw(m.Attributes.Resource); err != nil {
@@ -112,8 +149,75 @@ func (m *MetricSum) Emit(generators map[string]generator.MetricGenerator, state
 		return fmt.Errorf("failed to create datapoint attributes: %w", err)
 	}
 
-	dp, _, _ := mm.Datapoint(dattr, pcommon.NewTimestampFromTime(state.Wallclock))
+	ts := m.datapointTimestamp(state)
+	dp, _, _ := mm.Datapoint(dattr, ts)
 	dp.SetDoubleValue(value)
 
+	if m.CumulativeName != "" {
+		if err := m.emitCumulative(s, dattr, ts, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stretch returns how many Frequency intervals one emit covers when a
+// resolution tier or degradation spaces the emits out, so the
+// per-emit value keeps the rate it has at full resolution.  The
+// interval counted is at most the spacing in force now or at the
+// previous emit, so a longer gap, such as the metric being disabled,
+// does not add up.
+func (m *MetricSum) stretch(since time.Duration, state *state.RunState) float64 {
+	spacing := max(m.Frequency*time.Duration(state.DegradeFactor()), state.MinFrequency)
+	interval := min(since, max(spacing, m.lastSpacing))
+	m.lastSpacing = spacing
+	if m.Frequency <= 0 || interval <= m.Frequency {
+		return 1
+	}
+	return float64(interval) / float64(m.Frequency)
+}
+
+func (m *MetricSum) validateReset() error {
+	if m.ResetEvery < 0 {
+		return fmt.Errorf("resetEvery %s must not be negative", m.ResetEvery)
+	}
+	if m.ResetEvery > 0 && m.CumulativeName == "" {
+		return errors.New("resetEvery needs a cumulativeName to reset")
+	}
+	return nil
+}
+
+// resetIfDue restarts the cumulative series when a ResetEvery boundary
+// has passed since the last restart.  The new series starts at the
+// last reported point, so the restart falls between two points, and
+// backends see the changed StartTimestamp as a reset rather than a
+// drop in a monotonic counter.
+func (m *MetricSum) resetIfDue(tick time.Duration) {
+	if m.ResetEvery <= 0 || tick < m.lastReset+m.ResetEvery {
+		return
+	}
+	m.lastReset = tick - tick%m.ResetEvery
+	m.total = 0
+	m.startTime = m.lastTime
+}
+
+func (m *MetricSum) emitCumulative(s *signalbuilder.MetricScopeBuilder, dattr pcommon.Map, ts pcommon.Timestamp, delta float64) error {
+	if m.startTime == 0 {
+		m.startTime = ts
+	}
+	m.total += delta
+
+	cm, err := s.Metric(m.CumulativeName, "unit", pmetric.MetricTypeSum)
+	if err != nil {
+		return fmt.Errorf("failed to create cumulative metric: %w", err)
+	}
+	if sb, ok := cm.(*signalbuilder.MetricSumBuilder); ok {
+		sb.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	}
+	dp, _, _ := cm.Datapoint(dattr, ts)
+	dp.SetStartTimestamp(m.startTime)
+	dp.SetDoubleValue(m.total)
+	m.lastTime = ts
 	return nil
 }
@@ -0,0 +1,198 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricproducer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cardinalhq/oteltools/signalbuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func TestMetricSum_CumulativeName(t *testing.T) {
+	constant, err := generator.NewMetricConstant(0, map[string]any{"value": 5.0})
+	require.NoError(t, err)
+	generators := map[string]generator.MetricGenerator{"five": constant}
+
+	sum, err := NewMetricSum(generators, "requests", scriptaction.ScriptAction{
+		Spec: map[string]any{
+			"generators":     []string{"five"},
+			"cumulativeName": "requests.cumulative",
+			"frequency":      "1s",
+		},
+	})
+	require.NoError(t, err)
+
+	start := time.Unix(1000, 0).UTC()
+	var md pmetric.Metrics
+	// The first emission happens one frequency after tick 0.
+	for i := range 4 {
+		rs := &state.RunState{
+			Tick:      time.Duration(i) * time.Second,
+			Wallclock: start.Add(time.Duration(i) * time.Second),
+		}
+		mb := signalbuilder.NewMetricsBuilder()
+		require.NoError(t, sum.Emit(generators, rs, mb))
+		md = mb.Build()
+	}
+
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	delta := metrics.At(0)
+	assert.Equal(t, "requests", delta.Name())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, delta.Sum().AggregationTemporality())
+	assert.Equal(t, 5.0, delta.Sum().DataPoints().At(0).DoubleValue())
+
+	cumulative := metrics.At(1)
+	assert.Equal(t, "requests.cumulative", cumulative.Name())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, cumulative.Sum().AggregationTemporality())
+	dp := cumulative.Sum().DataPoints().At(0)
+	assert.Equal(t, 15.0, dp.DoubleValue())
+	assert.Equal(t, start.Add(time.Second), dp.StartTimestamp().AsTime())
+}
+
+func TestMetricSum_Dropout(t *testing.T) {
+	constant, err := generator.NewMetricConstant(0, map[string]any{"value": 5.0})
+	require.NoError(t, err)
+	dropout, err := generator.NewMetricDropout(0, map[string]any{
+		"windows": []any{map[string]any{"at": "2s", "duration": "2s"}},
+	})
+	require.NoError(t, err)
+	generators := map[string]generator.MetricGenerator{"five": constant, "gap": dropout}
+
+	sum, err := NewMetricSum(generators, "requests", scriptaction.ScriptAction{
+		Spec: map[string]any{
+			"generators":     []string{"five", "gap"},
+			"cumulativeName": "requests.cumulative",
+			"frequency":      "1s",
+		},
+	})
+	require.NoError(t, err)
+
+	var totals []float64
+	for i := range 6 {
+		rs := state.NewRunState(time.Minute, 1)
+		rs.Tick = time.Duration(i) * time.Second
+		rs.Wallclock = time.Unix(1000, 0).Add(rs.Tick)
+		mb := signalbuilder.NewMetricsBuilder()
+		require.NoError(t, sum.Emit(generators, rs, mb))
+		md := mb.Build()
+		if md.DataPointCount() == 0 {
+			continue
+		}
+		metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		totals = append(totals, metrics.At(1).Sum().DataPoints().At(0).DoubleValue())
+	}
+	// Ticks 2s and 3s are dropped, but the counter keeps counting.
+	assert.Equal(t, []float64{5, 20, 25}, totals)
+}
+
+func TestMetricSum_Tiered(t *testing.T) {
+	constant, err := generator.NewMetricConstant(0, map[string]any{"value": 5.0})
+	require.NoError(t, err)
+	generators := map[string]generator.MetricGenerator{"five": constant}
+	sum, err := NewMetricSum(generators, "requests", scriptaction.ScriptAction{
+		Spec: map[string]any{
+			"generators":     []string{"five"},
+			"cumulativeName": "requests.cumulative",
+			"frequency":      "10s",
+		},
+	})
+	require.NoError(t, err)
+
+	// The first three minutes are in a 1m resolution tier.
+	rs := state.NewRunState(time.Hour, 1)
+	var deltas []float64
+	var total float64
+	for tick := range 241 {
+		rs.Tick = time.Duration(tick) * time.Second
+		rs.Wallclock = time.Unix(1700000000, 0).Add(rs.Tick)
+		rs.MinFrequency = 0
+		if rs.Tick < 3*time.Minute {
+			rs.MinFrequency = time.Minute
+		}
+		mb := signalbuilder.NewMetricsBuilder()
+		require.NoError(t, sum.Emit(generators, rs, mb))
+		md := mb.Build()
+		if md.DataPointCount() == 0 {
+			continue
+		}
+		metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		deltas = append(deltas, metrics.At(0).Sum().DataPoints().At(0).DoubleValue())
+		total = metrics.At(1).Sum().DataPoints().At(0).DoubleValue()
+	}
+	// A minute in the tier counts what six 10s emits would have.
+	assert.Equal(t, []float64{30, 30, 30, 5, 5}, deltas[:5])
+	assert.Equal(t, 5.0*24, total, "four minutes at 5 per 10s")
+}
+
+func TestMetricSum_ResetEvery(t *testing.T) {
+	constant, err := generator.NewMetricConstant(0, map[string]any{"value": 5.0})
+	require.NoError(t, err)
+	generators := map[string]generator.MetricGenerator{"five": constant}
+
+	sum, err := NewMetricSum(generators, "requests", scriptaction.ScriptAction{
+		Spec: map[string]any{
+			"generators":     []string{"five"},
+			"cumulativeName": "requests.cumulative",
+			"frequency":      "1s",
+			"resetEvery":     "3s",
+		},
+	})
+	require.NoError(t, err)
+
+	start := time.Unix(1000, 0).UTC()
+	var totals []float64
+	var starts []time.Duration
+	for i := range 8 {
+		rs := &state.RunState{
+			Tick:      time.Duration(i) * time.Second,
+			Wallclock: start.Add(time.Duration(i) * time.Second),
+		}
+		mb := signalbuilder.NewMetricsBuilder()
+		require.NoError(t, sum.Emit(generators, rs, mb))
+		md := mb.Build()
+		if md.DataPointCount() == 0 {
+			continue
+		}
+		dp := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(1).Sum().DataPoints().At(0)
+		totals = append(totals, dp.DoubleValue())
+		starts = append(starts, dp.StartTimestamp().AsTime().Sub(start))
+	}
+	// The process restarts at 3s and 6s; each new series starts at the
+	// last point of the one before.
+	assert.Equal(t, []float64{5, 10, 5, 10, 15, 5, 10}, totals)
+	s := time.Second
+	assert.Equal(t, []time.Duration{s, s, 2 * s, 2 * s, 2 * s, 5 * s, 5 * s}, starts)
+}
+
+func TestMetricSum_ResetEveryInvalid(t *testing.T) {
+	constant, err := generator.NewMetricConstant(0, map[string]any{"value": 5.0})
+	require.NoError(t, err)
+	generators := map[string]generator.MetricGenerator{"five": constant}
+
+	_, err = NewMetricSum(generators, "requests", scriptaction.ScriptAction{
+		Spec: map[string]any{"generators": []string{"five"}, "resetEvery": "1m"},
+	})
+	assert.EqualError(t, err, "metric requests: resetEvery needs a cumulativeName to reset")
+}
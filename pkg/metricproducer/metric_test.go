@@ -110,6 +110,54 @@ func TestEmitDueToFrequency(t *testing.T) {
 			},
 			expectedEmit: true,
 		},
+		{
+			name: "Should not emit at 'lastEmitted + Frequency' when degraded",
+			spec: MetricProducerSpec{
+				Frequency:   10 * time.Second,
+				lastEmitted: 5 * time.Second,
+			},
+			runState: state.RunState{
+				Tick:    15 * time.Second,
+				Degrade: 1,
+			},
+			expectedEmit: false,
+		},
+		{
+			name: "Should emit at 'lastEmitted + Frequency * 2' when degraded once",
+			spec: MetricProducerSpec{
+				Frequency:   10 * time.Second,
+				lastEmitted: 5 * time.Second,
+			},
+			runState: state.RunState{
+				Tick:    25 * time.Second,
+				Degrade: 1,
+			},
+			expectedEmit: true,
+		},
+		{
+			name: "Should not emit before a resolution tier's minimum frequency",
+			spec: MetricProducerSpec{
+				Frequency:   10 * time.Second,
+				lastEmitted: 5 * time.Second,
+			},
+			runState: state.RunState{
+				Tick:         15 * time.Second,
+				MinFrequency: time.Minute,
+			},
+			expectedEmit: false,
+		},
+		{
+			name: "Should emit at 'lastEmitted + MinFrequency' in a resolution tier",
+			spec: MetricProducerSpec{
+				Frequency:   10 * time.Second,
+				lastEmitted: 5 * time.Second,
+			},
+			runState: state.RunState{
+				Tick:         65 * time.Second,
+				MinFrequency: time.Minute,
+			},
+			expectedEmit: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,3 +167,62 @@ func TestEmitDueToFrequency(t *testing.T) {
 		})
 	}
 }
+
+func TestDatapointTimestamp(t *testing.T) {
+	wallclock := time.Date(2025, 1, 1, 12, 0, 7, 500, time.UTC)
+	tests := []struct {
+		name      string
+		frequency time.Duration
+		align     bool
+		expected  time.Time
+	}{
+		{
+			name:      "Unaligned uses the wallclock",
+			frequency: 10 * time.Second,
+			align:     false,
+			expected:  wallclock,
+		},
+		{
+			name:      "Aligned truncates to the frequency",
+			frequency: 10 * time.Second,
+			align:     true,
+			expected:  time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Aligned with zero frequency uses the wallclock",
+			frequency: 0,
+			align:     true,
+			expected:  wallclock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := MetricProducerSpec{Frequency: tt.frequency}
+			rs := state.RunState{Wallclock: wallclock, AlignTimestamps: tt.align}
+			assert.Equal(t, tt.expected, spec.datapointTimestamp(&rs).AsTime())
+		})
+	}
+}
+
+func TestClamp(t *testing.T) {
+	lo, hi := 0.0, 100.0
+	m := MetricProducerSpec{Name: "cpu", Min: &lo, Max: &hi}
+	assert.Equal(t, 0.0, m.clamp(-3))
+	assert.Equal(t, 100.0, m.clamp(140))
+	assert.Equal(t, 42.0, m.clamp(42))
+	assert.Equal(t, 2, m.clamped)
+	assert.Equal(t, 3, m.samples)
+	assert.False(t, m.clampWarned)
+
+	for range clampWarnSamples {
+		m.clamp(-1)
+	}
+	assert.True(t, m.clampWarned)
+
+	unbounded := MetricProducerSpec{}
+	assert.Equal(t, -5.0, unbounded.clamp(-5))
+
+	inverted := MetricProducerSpec{Min: &hi, Max: &lo}
+	assert.Error(t, inverted.validateBounds())
+}
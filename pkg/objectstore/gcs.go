@@ -0,0 +1,59 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const defaultGCSEndpoint = "https://storage.googleapis.com"
+
+// GCSUploader writes objects using the GCS XML API with an OAuth2
+// bearer token, such as one from `gcloud auth print-access-token`.
+type GCSUploader struct {
+	client   *http.Client
+	token    string
+	bucket   string
+	endpoint string
+}
+
+var _ Uploader = (*GCSUploader)(nil)
+
+func NewGCSUploader(client *http.Client, token, bucket, endpoint string) (*GCSUploader, error) {
+	if token == "" {
+		return nil, errors.New("gcs: GCS_ACCESS_TOKEN or GOOGLE_OAUTH_ACCESS_TOKEN is required")
+	}
+	if endpoint == "" {
+		endpoint = defaultGCSEndpoint
+	}
+	return &GCSUploader{
+		client:   client,
+		token:    token,
+		bucket:   bucket,
+		endpoint: strings.TrimRight(endpoint, "/"),
+	}, nil
+}
+
+func (u *GCSUploader) Upload(ctx context.Context, key string, contentType string, body []byte) error {
+	req, err := newPut(ctx, u.endpoint+"/"+u.bucket+"/"+escapePath(key), contentType, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+u.token)
+	return doPut(u.client, req)
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Uploader writes a single object to a bucket.
+type Uploader interface {
+	Upload(ctx context.Context, key string, contentType string, body []byte) error
+}
+
+// NewUploader returns an uploader for the named provider ("s3" or "gcs"),
+// taking credentials from the usual environment variables.
+func NewUploader(client *http.Client, provider, bucket, region, endpoint string) (Uploader, error) {
+	if bucket == "" {
+		return nil, errors.New("object storage bucket is required")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	switch provider {
+	case "s3":
+		return NewS3Uploader(client, S3Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, bucket, region, endpoint)
+	case "gcs":
+		token := os.Getenv("GCS_ACCESS_TOKEN")
+		if token == "" {
+			token = os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+		}
+		return NewGCSUploader(client, token, bucket, endpoint)
+	default:
+		return nil, fmt.Errorf("unknown object storage provider: %q", provider)
+	}
+}
+
+func doPut(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to upload object: %s: %s", resp.Status, string(respBody))
+	}
+	return nil
+}
+
+func newPut(ctx context.Context, url, contentType string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	return req, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3Uploader writes objects with a signed (SigV4) PUT.  If endpoint is
+// set, path-style addressing is used against it, which suits
+// S3-compatible stores such as MinIO.
+type S3Uploader struct {
+	client   *http.Client
+	creds    S3Credentials
+	bucket   string
+	region   string
+	endpoint string
+	now      func() time.Time
+}
+
+var _ Uploader = (*S3Uploader)(nil)
+
+func NewS3Uploader(client *http.Client, creds S3Credentials, bucket, region, endpoint string) (*S3Uploader, error) {
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, errors.New("s3: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Uploader{
+		client:   client,
+		creds:    creds,
+		bucket:   bucket,
+		region:   region,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		now:      time.Now,
+	}, nil
+}
+
+func (u *S3Uploader) objectURL(key string) string {
+	path := "/" + escapePath(key)
+	if u.endpoint != "" {
+		return u.endpoint + "/" + u.bucket + path
+	}
+	return "https://" + u.bucket + ".s3." + u.region + ".amazonaws.com" + path
+}
+
+func (u *S3Uploader) Upload(ctx context.Context, key string, contentType string, body []byte) error {
+	req, err := newPut(ctx, u.objectURL(key), contentType, body)
+	if err != nil {
+		return err
+	}
+	u.sign(req, body)
+	return doPut(u.client, req)
+}
+
+// sign adds AWS Signature Version 4 headers to req.
+func (u *S3Uploader) sign(req *http.Request, body []byte) {
+	payloadHash := sha256Hex(body)
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", u.now().UTC().Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signed := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if u.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", u.creds.SessionToken)
+		signed = append(signed, "x-amz-security-token")
+	}
+	u.authorize(req, payloadHash, signed)
+}
+
+// authorize sets the Authorization header, signing req's method, path,
+// query, and the named headers, which are lower case and sorted, as of
+// its X-Amz-Date.
+func (u *S3Uploader) authorize(req *http.Request, payloadHash string, signed []string) {
+	amzDate := req.Header.Get("X-Amz-Date")
+	dateStamp := amzDate[:8]
+
+	var canonicalHeaders strings.Builder
+	for _, h := range signed {
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(req.Header.Get(h)) + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := dateStamp + "/" + u.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+u.creds.SecretAccessKey), dateStamp)
+	key = hmacSHA256(key, u.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+u.creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// escapePath URI-encodes each segment of an object key, keeping the
+// "/" separators.  As SigV4 requires, only the RFC 3986 unreserved
+// characters are left as they are, so the path sent is the one signed.
+func escapePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c == '/' || unreserved(c) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func unreserved(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '~'
+}
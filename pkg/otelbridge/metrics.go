@@ -0,0 +1,212 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelbridge
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/cardinalhq/flutter/pkg/emitter"
+)
+
+// MetricExporter is an sdkmetric.Exporter that sends metrics to a
+// flutter emitter, typically used with sdkmetric.NewPeriodicReader.
+// Gauges, sums, and explicit and exponential histograms are
+// converted; summaries, which the Go SDK never produces, are dropped.
+type MetricExporter struct {
+	bridge
+	temporality sdkmetric.TemporalitySelector
+}
+
+var _ sdkmetric.Exporter = (*MetricExporter)(nil)
+
+// NewMetricExporter creates an exporter for e.  A nil temporality
+// uses the SDK default, which is cumulative for every instrument.
+func NewMetricExporter(e emitter.Emitter, temporality sdkmetric.TemporalitySelector) *MetricExporter {
+	if temporality == nil {
+		temporality = sdkmetric.DefaultTemporalitySelector
+	}
+	return &MetricExporter{
+		bridge:      bridge{emitter: e},
+		temporality: temporality,
+	}
+}
+
+func (x *MetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return x.temporality(kind)
+}
+
+func (x *MetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (x *MetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	md := convertMetrics(rm)
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.shutdown {
+		return nil
+	}
+	return x.emitter.EmitMetrics(ctx, runState(), md)
+}
+
+func (x *MetricExporter) ForceFlush(ctx context.Context) error {
+	return x.flush(ctx)
+}
+
+// Shutdown flushes the emitter if it buffers.  Later exports are
+// dropped.
+func (x *MetricExporter) Shutdown(ctx context.Context) error {
+	return x.close(ctx)
+}
+
+func convertMetrics(rm *metricdata.ResourceMetrics) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	if rm == nil {
+		return md
+	}
+	prm := md.ResourceMetrics().AppendEmpty()
+	putResource(prm.Resource(), rm.Resource)
+	for _, sm := range rm.ScopeMetrics {
+		psm := prm.ScopeMetrics().AppendEmpty()
+		putScope(psm.Scope(), sm.Scope)
+		for _, m := range sm.Metrics {
+			pm := pmetric.NewMetric()
+			pm.SetName(m.Name)
+			pm.SetDescription(m.Description)
+			pm.SetUnit(m.Unit)
+			if convertAggregation(pm, m.Data) {
+				pm.MoveTo(psm.Metrics().AppendEmpty())
+			}
+		}
+	}
+	return md
+}
+
+// convertAggregation fills in pm's data and reports whether the
+// aggregation type is supported.
+func convertAggregation(pm pmetric.Metric, data metricdata.Aggregation) bool {
+	switch a := data.(type) {
+	case metricdata.Gauge[int64]:
+		putNumberPoints(pm.SetEmptyGauge().DataPoints(), a.DataPoints)
+	case metricdata.Gauge[float64]:
+		putNumberPoints(pm.SetEmptyGauge().DataPoints(), a.DataPoints)
+	case metricdata.Sum[int64]:
+		putSum(pm, a)
+	case metricdata.Sum[float64]:
+		putSum(pm, a)
+	case metricdata.Histogram[int64]:
+		putHistogram(pm, a)
+	case metricdata.Histogram[float64]:
+		putHistogram(pm, a)
+	case metricdata.ExponentialHistogram[int64]:
+		putExponentialHistogram(pm, a)
+	case metricdata.ExponentialHistogram[float64]:
+		putExponentialHistogram(pm, a)
+	default:
+		return false
+	}
+	return true
+}
+
+func temporality(t metricdata.Temporality) pmetric.AggregationTemporality {
+	if t == metricdata.DeltaTemporality {
+		return pmetric.AggregationTemporalityDelta
+	}
+	return pmetric.AggregationTemporalityCumulative
+}
+
+func putSum[N int64 | float64](pm pmetric.Metric, a metricdata.Sum[N]) {
+	sum := pm.SetEmptySum()
+	sum.SetAggregationTemporality(temporality(a.Temporality))
+	sum.SetIsMonotonic(a.IsMonotonic)
+	putNumberPoints(sum.DataPoints(), a.DataPoints)
+}
+
+func putNumberPoints[N int64 | float64](dst pmetric.NumberDataPointSlice, points []metricdata.DataPoint[N]) {
+	dst.EnsureCapacity(len(points))
+	for _, p := range points {
+		dp := dst.AppendEmpty()
+		putAttributes(dp.Attributes(), p.Attributes.ToSlice())
+		if !p.StartTime.IsZero() {
+			dp.SetStartTimestamp(pcommon.NewTimestampFromTime(p.StartTime))
+		}
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(p.Time))
+		switch v := any(p.Value).(type) {
+		case int64:
+			dp.SetIntValue(v)
+		case float64:
+			dp.SetDoubleValue(v)
+		}
+	}
+}
+
+func putHistogram[N int64 | float64](pm pmetric.Metric, a metricdata.Histogram[N]) {
+	hist := pm.SetEmptyHistogram()
+	hist.SetAggregationTemporality(temporality(a.Temporality))
+	for _, p := range a.DataPoints {
+		dp := hist.DataPoints().AppendEmpty()
+		putAttributes(dp.Attributes(), p.Attributes.ToSlice())
+		if !p.StartTime.IsZero() {
+			dp.SetStartTimestamp(pcommon.NewTimestampFromTime(p.StartTime))
+		}
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(p.Time))
+		dp.SetCount(p.Count)
+		dp.SetSum(float64(p.Sum))
+		dp.ExplicitBounds().FromRaw(p.Bounds)
+		dp.BucketCounts().FromRaw(p.BucketCounts)
+		if v, ok := p.Min.Value(); ok {
+			dp.SetMin(float64(v))
+		}
+		if v, ok := p.Max.Value(); ok {
+			dp.SetMax(float64(v))
+		}
+	}
+}
+
+func putExponentialHistogram[N int64 | float64](pm pmetric.Metric, a metricdata.ExponentialHistogram[N]) {
+	hist := pm.SetEmptyExponentialHistogram()
+	hist.SetAggregationTemporality(temporality(a.Temporality))
+	for _, p := range a.DataPoints {
+		dp := hist.DataPoints().AppendEmpty()
+		putAttributes(dp.Attributes(), p.Attributes.ToSlice())
+		if !p.StartTime.IsZero() {
+			dp.SetStartTimestamp(pcommon.NewTimestampFromTime(p.StartTime))
+		}
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(p.Time))
+		dp.SetCount(p.Count)
+		dp.SetSum(float64(p.Sum))
+		dp.SetScale(p.Scale)
+		dp.SetZeroCount(p.ZeroCount)
+		dp.SetZeroThreshold(p.ZeroThreshold)
+		dp.Positive().SetOffset(p.PositiveBucket.Offset)
+		dp.Positive().BucketCounts().FromRaw(p.PositiveBucket.Counts)
+		dp.Negative().SetOffset(p.NegativeBucket.Offset)
+		dp.Negative().BucketCounts().FromRaw(p.NegativeBucket.Counts)
+		if v, ok := p.Min.Value(); ok {
+			dp.SetMin(float64(v))
+		}
+		if v, ok := p.Max.Value(); ok {
+			dp.SetMax(float64(v))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelbridge adapts flutter emitters to the OpenTelemetry Go
+// SDK exporter interfaces, so a Go program's real telemetry can be sent
+// through the same destinations as simulated data.
+package otelbridge
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/cardinalhq/flutter/pkg/emitter"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// bridge holds what the span and metric exporters share.  Emitters
+// are not safe for concurrent use, so calls are serialized.
+type bridge struct {
+	mu       sync.Mutex
+	emitter  emitter.Emitter
+	shutdown bool
+}
+
+// runState returns the RunState passed to the emitter.  Real
+// telemetry has no simulated clock, so the wallclock is now.
+func runState() *state.RunState {
+	return &state.RunState{Wallclock: time.Now()}
+}
+
+func (b *bridge) flush(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if f, ok := b.emitter.(emitter.Flusher); ok {
+		return f.Flush(ctx, runState())
+	}
+	return nil
+}
+
+func (b *bridge) close(ctx context.Context) error {
+	err := b.flush(ctx)
+	b.mu.Lock()
+	b.shutdown = true
+	b.mu.Unlock()
+	return err
+}
+
+func putResource(dst pcommon.Resource, res *resource.Resource) {
+	if res == nil {
+		return
+	}
+	putAttributes(dst.Attributes(), res.Attributes())
+}
+
+func putScope(dst pcommon.InstrumentationScope, scope instrumentation.Scope) {
+	dst.SetName(scope.Name)
+	dst.SetVersion(scope.Version)
+	putAttributes(dst.Attributes(), scope.Attributes.ToSlice())
+}
+
+func putAttributes(dst pcommon.Map, attrs []attribute.KeyValue) {
+	dst.EnsureCapacity(len(attrs))
+	for _, kv := range attrs {
+		putValue(dst.PutEmpty(string(kv.Key)), kv.Value)
+	}
+}
+
+func putValue(dst pcommon.Value, v attribute.Value) {
+	switch v.Type() {
+	case attribute.BOOL:
+		dst.SetBool(v.AsBool())
+	case attribute.INT64:
+		dst.SetInt(v.AsInt64())
+	case attribute.FLOAT64:
+		dst.SetDouble(v.AsFloat64())
+	case attribute.STRING:
+		dst.SetStr(v.AsString())
+	case attribute.BOOLSLICE:
+		s := dst.SetEmptySlice()
+		for _, b := range v.AsBoolSlice() {
+			s.AppendEmpty().SetBool(b)
+		}
+	case attribute.INT64SLICE:
+		s := dst.SetEmptySlice()
+		for _, i := range v.AsInt64Slice() {
+			s.AppendEmpty().SetInt(i)
+		}
+	case attribute.FLOAT64SLICE:
+		s := dst.SetEmptySlice()
+		for _, f := range v.AsFloat64Slice() {
+			s.AppendEmpty().SetDouble(f)
+		}
+	case attribute.STRINGSLICE:
+		s := dst.SetEmptySlice()
+		for _, str := range v.AsStringSlice() {
+			s.AppendEmpty().SetStr(str)
+		}
+	case attribute.EMPTY:
+	default:
+		dst.SetStr(v.Emit())
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelbridge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+type captureEmitter struct {
+	metrics []pmetric.Metrics
+	traces  []ptrace.Traces
+	flushes int
+}
+
+func (c *captureEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	c.metrics = append(c.metrics, md)
+	return nil
+}
+
+func (c *captureEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	c.traces = append(c.traces, td)
+	return nil
+}
+
+func (c *captureEmitter) Flush(_ context.Context, _ *state.RunState) error {
+	c.flushes++
+	return nil
+}
+
+func TestSpanExporter(t *testing.T) {
+	ctx := context.Background()
+	capture := &captureEmitter{}
+	res := resource.NewSchemaless(attribute.String("service.name", "checkout"))
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(NewSpanExporter(capture)),
+		sdktrace.WithResource(res),
+	)
+	tracer := tp.Tracer("test-scope")
+
+	ctx, parent := tracer.Start(ctx, "parent", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tracer.Start(ctx, "child", trace.WithAttributes(attribute.Int64("retries", 2), attribute.StringSlice("tags", []string{"a", "b"})))
+	child.AddEvent("retry")
+	child.SetStatus(codes.Error, "timeout")
+	child.End()
+	parent.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	require.Len(t, capture.traces, 2)
+	assert.Equal(t, 1, capture.flushes)
+
+	rs := capture.traces[0].ResourceSpans().At(0)
+	name, _ := rs.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "checkout", name.Str())
+	ss := rs.ScopeSpans().At(0)
+	assert.Equal(t, "test-scope", ss.Scope().Name())
+
+	span := ss.Spans().At(0)
+	assert.Equal(t, "child", span.Name())
+	assert.Equal(t, ptrace.SpanKindInternal, span.Kind())
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, "timeout", span.Status().Message())
+	retries, _ := span.Attributes().Get("retries")
+	assert.Equal(t, int64(2), retries.Int())
+	tags, _ := span.Attributes().Get("tags")
+	assert.Equal(t, []any{"a", "b"}, tags.Slice().AsRaw())
+	assert.Equal(t, "retry", span.Events().At(0).Name())
+
+	parentSpan := capture.traces[1].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, ptrace.SpanKindServer, parentSpan.Kind())
+	assert.Equal(t, parentSpan.SpanID(), span.ParentSpanID())
+	assert.Equal(t, parentSpan.TraceID(), span.TraceID())
+	assert.True(t, parentSpan.ParentSpanID().IsEmpty())
+}
+
+func TestMetricExporter(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter := mp.Meter("test-scope")
+
+	counter, err := meter.Int64Counter("requests", metric.WithUnit("{request}"))
+	require.NoError(t, err)
+	counter.Add(ctx, 3, metric.WithAttributes(attribute.String("route", "/")))
+	hist, err := meter.Float64Histogram("latency", metric.WithExplicitBucketBoundaries(1, 10))
+	require.NoError(t, err)
+	hist.Record(ctx, 0.5)
+	hist.Record(ctx, 5)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	capture := &captureEmitter{}
+	x := NewMetricExporter(capture, nil)
+	assert.Equal(t, metricdata.CumulativeTemporality, x.Temporality(sdkmetric.InstrumentKindCounter))
+	require.NoError(t, x.Export(ctx, &rm))
+	require.Len(t, capture.metrics, 1)
+
+	metrics := capture.metrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	sum := metrics.At(0)
+	assert.Equal(t, "requests", sum.Name())
+	assert.Equal(t, "{request}", sum.Unit())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, sum.Sum().AggregationTemporality())
+	assert.True(t, sum.Sum().IsMonotonic())
+	assert.Equal(t, int64(3), sum.Sum().DataPoints().At(0).IntValue())
+	route, _ := sum.Sum().DataPoints().At(0).Attributes().Get("route")
+	assert.Equal(t, "/", route.Str())
+
+	h := metrics.At(1).Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(2), h.Count())
+	assert.InDelta(t, 5.5, h.Sum(), 1e-9)
+	assert.Equal(t, []float64{1, 10}, h.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 1, 0}, h.BucketCounts().AsRaw())
+	assert.InDelta(t, 0.5, h.Min(), 1e-9)
+	assert.InDelta(t, 5, h.Max(), 1e-9)
+
+	require.NoError(t, x.Shutdown(ctx))
+	assert.Equal(t, 1, capture.flushes)
+	require.NoError(t, x.Export(ctx, &rm))
+	assert.Len(t, capture.metrics, 1, "exports after shutdown are dropped")
+}
+
+func TestMetricExporter_ZeroStartTime(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	rm := &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Metrics: []metricdata.Metrics{
+				{
+					Name: "queue.depth",
+					Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Time: now, Value: 4}}},
+				},
+				{
+					Name: "latency",
+					Data: metricdata.Histogram[float64]{DataPoints: []metricdata.HistogramDataPoint[float64]{{Time: now, Count: 1}}},
+				},
+				{
+					Name: "size",
+					Data: metricdata.ExponentialHistogram[float64]{DataPoints: []metricdata.ExponentialHistogramDataPoint[float64]{{Time: now, Count: 1}}},
+				},
+			},
+		}},
+	}
+
+	capture := &captureEmitter{}
+	require.NoError(t, NewMetricExporter(capture, nil).Export(ctx, rm))
+	require.Len(t, capture.metrics, 1)
+
+	metrics := capture.metrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+	ts := pcommon.NewTimestampFromTime(now)
+
+	gauge := metrics.At(0).Gauge().DataPoints().At(0)
+	assert.Equal(t, pcommon.Timestamp(0), gauge.StartTimestamp())
+	assert.Equal(t, ts, gauge.Timestamp())
+	hist := metrics.At(1).Histogram().DataPoints().At(0)
+	assert.Equal(t, pcommon.Timestamp(0), hist.StartTimestamp())
+	assert.Equal(t, ts, hist.Timestamp())
+	exp := metrics.At(2).ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, pcommon.Timestamp(0), exp.StartTimestamp())
+	assert.Equal(t, ts, exp.Timestamp())
+}
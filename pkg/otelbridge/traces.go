@@ -0,0 +1,137 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelbridge
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/cardinalhq/flutter/pkg/emitter"
+)
+
+// SpanExporter is an sdktrace.SpanExporter that sends spans to a
+// flutter emitter.  Use it with sdktrace.WithBatcher so spans are
+// grouped into larger payloads.
+type SpanExporter struct {
+	bridge
+}
+
+var _ sdktrace.SpanExporter = (*SpanExporter)(nil)
+
+func NewSpanExporter(e emitter.Emitter) *SpanExporter {
+	return &SpanExporter{bridge: bridge{emitter: e}}
+}
+
+func (x *SpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	td := convertSpans(spans)
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.shutdown {
+		return nil
+	}
+	return x.emitter.EmitTraces(ctx, runState(), td)
+}
+
+// Shutdown flushes the emitter if it buffers.  Later exports are
+// dropped.
+func (x *SpanExporter) Shutdown(ctx context.Context) error {
+	return x.close(ctx)
+}
+
+type scopeKey struct {
+	res   *resource.Resource
+	scope instrumentation.Scope
+}
+
+func convertSpans(spans []sdktrace.ReadOnlySpan) ptrace.Traces {
+	td := ptrace.NewTraces()
+	resources := map[*resource.Resource]ptrace.ResourceSpans{}
+	scopes := map[scopeKey]ptrace.SpanSlice{}
+	for _, span := range spans {
+		res := span.Resource()
+		rs, ok := resources[res]
+		if !ok {
+			rs = td.ResourceSpans().AppendEmpty()
+			putResource(rs.Resource(), res)
+			resources[res] = rs
+		}
+		key := scopeKey{res: res, scope: span.InstrumentationScope()}
+		dst, ok := scopes[key]
+		if !ok {
+			ss := rs.ScopeSpans().AppendEmpty()
+			putScope(ss.Scope(), key.scope)
+			dst = ss.Spans()
+			scopes[key] = dst
+		}
+		convertSpan(dst.AppendEmpty(), span)
+	}
+	return td
+}
+
+func convertSpan(dst ptrace.Span, span sdktrace.ReadOnlySpan) {
+	sc := span.SpanContext()
+	dst.SetTraceID(pcommon.TraceID(sc.TraceID()))
+	dst.SetSpanID(pcommon.SpanID(sc.SpanID()))
+	dst.TraceState().FromRaw(sc.TraceState().String())
+	dst.SetFlags(uint32(sc.TraceFlags()))
+	if parent := span.Parent(); parent.IsValid() {
+		dst.SetParentSpanID(pcommon.SpanID(parent.SpanID()))
+	}
+	dst.SetName(span.Name())
+	// The SDK and OTLP use the same numbering for span kinds.
+	dst.SetKind(ptrace.SpanKind(span.SpanKind()))
+	dst.SetStartTimestamp(pcommon.NewTimestampFromTime(span.StartTime()))
+	dst.SetEndTimestamp(pcommon.NewTimestampFromTime(span.EndTime()))
+	putAttributes(dst.Attributes(), span.Attributes())
+	dst.SetDroppedAttributesCount(uint32(span.DroppedAttributes()))
+
+	for _, ev := range span.Events() {
+		pev := dst.Events().AppendEmpty()
+		pev.SetName(ev.Name)
+		pev.SetTimestamp(pcommon.NewTimestampFromTime(ev.Time))
+		putAttributes(pev.Attributes(), ev.Attributes)
+		pev.SetDroppedAttributesCount(uint32(ev.DroppedAttributeCount))
+	}
+	dst.SetDroppedEventsCount(uint32(span.DroppedEvents()))
+
+	for _, link := range span.Links() {
+		pl := dst.Links().AppendEmpty()
+		pl.SetTraceID(pcommon.TraceID(link.SpanContext.TraceID()))
+		pl.SetSpanID(pcommon.SpanID(link.SpanContext.SpanID()))
+		pl.TraceState().FromRaw(link.SpanContext.TraceState().String())
+		putAttributes(pl.Attributes(), link.Attributes)
+		pl.SetDroppedAttributesCount(uint32(link.DroppedAttributeCount))
+	}
+	dst.SetDroppedLinksCount(uint32(span.DroppedLinks()))
+
+	// Status codes are numbered differently in the SDK and OTLP.
+	status := span.Status()
+	switch status.Code {
+	case codes.Ok:
+		dst.Status().SetCode(ptrace.StatusCodeOk)
+	case codes.Error:
+		dst.Status().SetCode(ptrace.StatusCodeError)
+		dst.Status().SetMessage(status.Description)
+	}
+}
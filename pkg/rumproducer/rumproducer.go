@@ -0,0 +1,344 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rumproducer emits browser-style telemetry for synthetic
+// real-user-monitoring sessions: a page load trace per session, and
+// Web-Vitals-like histograms per page and browser.
+package rumproducer
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/cardinalhq/oteltools/signalbuilder"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+	"github.com/cardinalhq/flutter/pkg/traceproducer"
+)
+
+const (
+	DefaultService   = "frontend"
+	DefaultHost      = "www.example.com"
+	DefaultResources = 8
+	DefaultLoadTime  = 1200 * time.Millisecond
+)
+
+var DefaultBrowsers = []string{"Chrome", "Safari", "Firefox", "Edge"}
+
+// The Web Vitals histogram bounds include the good and poor thresholds
+// of each vital, so the buckets show how many loads met them.
+var (
+	VitalBoundsMs  = []float64{50, 100, 200, 500, 800, 1000, 1800, 2500, 3000, 4000, 6000, 10000}
+	VitalBoundsCLS = []float64{0.01, 0.025, 0.05, 0.1, 0.15, 0.25, 0.5, 1}
+)
+
+// vitals are the Web Vitals recorded for each session, in the order
+// sessionVitals returns them.
+var vitals = []struct {
+	name   string
+	unit   string
+	bounds []float64
+}{
+	{"browser.web_vital.ttfb", "ms", VitalBoundsMs},
+	{"browser.web_vital.fcp", "ms", VitalBoundsMs},
+	{"browser.web_vital.lcp", "ms", VitalBoundsMs},
+	{"browser.web_vital.cls", "1", VitalBoundsCLS},
+	{"browser.web_vital.inp", "ms", VitalBoundsMs},
+}
+
+type RUMProducerSpec struct {
+	At        time.Duration   `mapstructure:"at,omitempty" yaml:"at,omitempty" json:"at,omitempty"`
+	To        time.Duration   `mapstructure:"to,omitempty" yaml:"to,omitempty" json:"to,omitempty"`
+	Rate      float64         `mapstructure:"rate" yaml:"rate" json:"rate"`
+	Service   string          `mapstructure:"service,omitempty" yaml:"service,omitempty" json:"service,omitempty"`
+	Host      string          `mapstructure:"host,omitempty" yaml:"host,omitempty" json:"host,omitempty"`
+	Pages     []string        `mapstructure:"pages,omitempty" yaml:"pages,omitempty" json:"pages,omitempty"`
+	Browsers  []string        `mapstructure:"browsers,omitempty" yaml:"browsers,omitempty" json:"browsers,omitempty"`
+	Resources int             `mapstructure:"resources,omitempty" yaml:"resources,omitempty" json:"resources,omitempty"`
+	LoadTime  config.Duration `mapstructure:"loadTime,omitempty" yaml:"loadTime,omitempty" json:"loadTime,omitempty"`
+	Disabled  bool            `mapstructure:"disabled,omitempty" yaml:"disabled,omitempty" json:"disabled,omitempty"`
+}
+
+// RUMProducer starts Rate sessions per second between At and To.
+// Fractional rates carry over between ticks, so a rate of 0.25 starts
+// a session every four seconds.
+type RUMProducer struct {
+	spec  RUMProducerSpec
+	carry float64
+	// lastVitals is when the vitals were last emitted, the start of
+	// the next delta.
+	lastVitals time.Time
+}
+
+// session is one synthetic page view.
+type session struct {
+	id      string
+	page    string
+	browser string
+	start   time.Time
+	ttfb    time.Duration
+	load    time.Duration
+}
+
+func CreateRUMProducer(action scriptaction.ScriptAction) (*RUMProducer, error) {
+	spec := RUMProducerSpec{
+		At: action.At,
+		To: action.To,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(action.Spec); err != nil {
+		return nil, err
+	}
+	return NewRUMProducer(spec)
+}
+
+func NewRUMProducer(spec RUMProducerSpec) (*RUMProducer, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return &RUMProducer{spec: spec}, nil
+}
+
+func (spec *RUMProducerSpec) validate() error {
+	if spec.Rate < 0 {
+		return errors.New("rum rate must not be negative")
+	}
+	if spec.Resources < 0 {
+		return errors.New("rum resources must not be negative")
+	}
+	if spec.LoadTime.Get() < 0 {
+		return errors.New("rum loadTime must not be negative")
+	}
+	return nil
+}
+
+// Reconfigure applies a partial RUMProducerSpec, such as a new rate.
+func (p *RUMProducer) Reconfigure(is map[string]any) error {
+	spec := p.spec
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	if err := spec.validate(); err != nil {
+		return err
+	}
+	p.spec = spec
+	return nil
+}
+
+// Spec returns the decoded spec, with defaults applied.
+func (p *RUMProducer) Spec() RUMProducerSpec {
+	return p.spec
+}
+
+// Emit starts this tick's sessions, adding a page load trace for each
+// to tb.  Their Web Vitals go to mb as delta histograms per page and
+// browser, since a series per session would grow without bound.
+func (p *RUMProducer) Emit(rs *state.RunState, tb *signalbuilder.TracesBuilder, mb *signalbuilder.MetricsBuilder) error {
+	if p.spec.Disabled || rs.Tick < p.spec.At || (p.spec.To != 0 && rs.Tick > p.spec.To) {
+		return nil
+	}
+	p.carry += p.spec.Rate
+	count := int(p.carry)
+	p.carry -= float64(count)
+
+	rattr := pcommon.NewMap()
+	rattr.PutStr("service.name", p.service())
+	rattr.PutStr("telemetry.sdk.language", "webjs")
+	sattr := pcommon.NewMap()
+	sattr.PutStr("otel.scope.name", "@opentelemetry/instrumentation-document-load")
+
+	var samples vitalSamples
+	for range count {
+		s := p.newSession(rs)
+		if err := p.emitTrace(rs.RND, tb.Resource(rattr).Scope(sattr), s); err != nil {
+			return err
+		}
+		samples.add(s, sessionVitals(rs.RND, s))
+	}
+	if count == 0 {
+		return nil
+	}
+	start := p.lastVitals
+	if start.IsZero() {
+		start = rs.Wallclock.Add(-time.Second)
+	}
+	p.lastVitals = rs.Wallclock
+	samples.emit(mb.Resource(rattr).Scope(sattr), start, rs.Wallclock)
+	return nil
+}
+
+func (p *RUMProducer) service() string {
+	if p.spec.Service != "" {
+		return p.spec.Service
+	}
+	return DefaultService
+}
+
+func (p *RUMProducer) newSession(rs *state.RunState) session {
+	pages := p.spec.Pages
+	if len(pages) == 0 {
+		pages = []string{"/"}
+	}
+	browsers := p.spec.Browsers
+	if len(browsers) == 0 {
+		browsers = DefaultBrowsers
+	}
+	median := p.spec.LoadTime.Get()
+	if median == 0 {
+		median = DefaultLoadTime
+	}
+	// Page loads have a long right tail, so draw them lognormally.
+	load := time.Duration(float64(median) * math.Exp(rs.RND.NormFloat64()*0.4))
+	return session{
+		id:      hex.EncodeToString(randomBytes(rs.RND, 16)),
+		page:    pages[rs.RND.IntN(len(pages))],
+		browser: browsers[rs.RND.IntN(len(browsers))],
+		start:   rs.Wallclock.Add(-time.Second + time.Duration(rs.RND.Int64N(int64(time.Second)))),
+		ttfb:    time.Duration(float64(load) * (0.1 + 0.1*rs.RND.Float64())),
+		load:    load,
+	}
+}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.IntN(256))
+	}
+	return b
+}
+
+func (p *RUMProducer) emitTrace(r *rand.Rand, sb *signalbuilder.TraceScopeBuilder, s session) error {
+	traceID := traceproducer.NewTraceID(r)
+	host := p.spec.Host
+	if host == "" {
+		host = DefaultHost
+	}
+	addSpan := func(name string, parent pcommon.SpanID, start time.Time, d time.Duration, url string) pcommon.SpanID {
+		span := sb.AddSpan()
+		spanID := traceproducer.NewSpanID()
+		span.SetTraceID(traceID)
+		span.SetSpanID(spanID)
+		span.SetParentSpanID(parent)
+		span.SetName(name)
+		span.SetKind(ptrace.SpanKindInternal)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(d)))
+		span.Status().SetCode(ptrace.StatusCodeOk)
+		span.Attributes().PutStr("session.id", s.id)
+		span.Attributes().PutStr("browser.name", s.browser)
+		span.Attributes().PutStr("url.full", url)
+		return spanID
+	}
+
+	pageURL := "https://" + host + s.page
+	root := addSpan("documentLoad", pcommon.NewSpanIDEmpty(), s.start, s.load, pageURL)
+	addSpan("documentFetch", root, s.start, s.ttfb, pageURL)
+	resources := p.spec.Resources
+	if resources == 0 {
+		resources = DefaultResources
+	}
+	window := s.load - s.ttfb
+	for i := range resources {
+		offset := s.ttfb + time.Duration(r.Float64()*0.5*float64(window))
+		d := time.Duration(r.Float64() * float64(s.load-offset))
+		addSpan("resourceFetch", root, s.start.Add(offset), d, fmt.Sprintf("https://%s/static/asset-%d.js", host, i))
+	}
+	return nil
+}
+
+// sessionVitals draws the session's Web Vitals.  Largest contentful
+// paint lands near the end of the load, first contentful paint
+// between first byte and then, and layout shift and interaction delay
+// are drawn independently.
+func sessionVitals(r *rand.Rand, s session) []float64 {
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	lcp := time.Duration(float64(s.load) * (0.7 + 0.3*r.Float64()))
+	fcp := s.ttfb + time.Duration(r.Float64()*float64(lcp-s.ttfb))
+	return []float64{
+		ms(s.ttfb),
+		ms(fcp),
+		ms(lcp),
+		math.Abs(r.NormFloat64()) * 0.05,
+		40 * math.Exp(r.NormFloat64()*0.6),
+	}
+}
+
+type vitalSeries struct {
+	page    string
+	browser string
+}
+
+// vitalSamples are a tick's vitals, by page and browser in the order
+// first seen.
+type vitalSamples struct {
+	series []vitalSeries
+	values map[vitalSeries][][]float64
+}
+
+func (v *vitalSamples) add(s session, values []float64) {
+	key := vitalSeries{page: s.page, browser: s.browser}
+	if v.values == nil {
+		v.values = map[vitalSeries][][]float64{}
+	}
+	if _, ok := v.values[key]; !ok {
+		v.series = append(v.series, key)
+	}
+	v.values[key] = append(v.values[key], values)
+}
+
+// emit writes a histogram datapoint for each vital and series.
+func (v *vitalSamples) emit(sb *signalbuilder.MetricScopeBuilder, start, end time.Time) {
+	for i, vital := range vitals {
+		h := sb.Histogram(vital.name)
+		h.SetUnit(vital.unit)
+		for _, key := range v.series {
+			dattr := pcommon.NewMap()
+			dattr.PutStr("browser.name", key.browser)
+			dattr.PutStr("url.path", key.page)
+			dp := h.Datapoint(dattr, pcommon.NewTimestampFromTime(end))
+			dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+			dp.ExplicitBounds().FromRaw(vital.bounds)
+			counts := make([]uint64, len(vital.bounds)+1)
+			total, lo, hi := 0.0, math.Inf(1), math.Inf(-1)
+			for _, values := range v.values[key] {
+				x := values[i]
+				counts[sort.SearchFloat64s(vital.bounds, x)]++
+				total += x
+				lo = min(lo, x)
+				hi = max(hi, x)
+			}
+			dp.BucketCounts().FromRaw(counts)
+			dp.SetCount(uint64(len(v.values[key])))
+			dp.SetSum(total)
+			dp.SetMin(lo)
+			dp.SetMax(hi)
+		}
+	}
+}
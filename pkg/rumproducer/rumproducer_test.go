@@ -0,0 +1,163 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rumproducer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cardinalhq/oteltools/signalbuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+func emitTick(t *testing.T, p *RUMProducer, rs *state.RunState) (spans, datapoints int) {
+	t.Helper()
+	tb := signalbuilder.NewTracesBuilder()
+	mb := signalbuilder.NewMetricsBuilder()
+	require.NoError(t, p.Emit(rs, tb, mb))
+	return tb.Build().SpanCount(), mb.Build().DataPointCount()
+}
+
+func TestRUMProducer_Sessions(t *testing.T) {
+	p, err := CreateRUMProducer(scriptaction.ScriptAction{
+		ID:   "shop",
+		Type: "rum",
+		Spec: map[string]any{
+			"rate":      2.0,
+			"pages":     []any{"/", "/cart"},
+			"resources": 3,
+			"loadTime":  "800ms",
+		},
+	})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Tick = time.Second
+	rs.Wallclock = time.Unix(1700000000, 0)
+
+	tb := signalbuilder.NewTracesBuilder()
+	mb := signalbuilder.NewMetricsBuilder()
+	require.NoError(t, p.Emit(rs, tb, mb))
+	td := tb.Build()
+	md := mb.Build()
+
+	// Each session is a document load, a document fetch, and 3 resource fetches.
+	assert.Equal(t, 2*5, td.SpanCount())
+
+	// The vitals are histograms per page and browser, not per session.
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 5, metrics.Len())
+	for i := range metrics.Len() {
+		m := metrics.At(i)
+		require.Equal(t, pmetric.MetricTypeHistogram, m.Type(), m.Name())
+		assert.Equal(t, pmetric.AggregationTemporalityDelta, m.Histogram().AggregationTemporality())
+		var count uint64
+		for _, dp := range m.Histogram().DataPoints().All() {
+			count += dp.Count()
+			_, ok := dp.Attributes().Get("session.id")
+			assert.False(t, ok, "session.id on %s", m.Name())
+			_, ok = dp.Attributes().Get("browser.name")
+			assert.True(t, ok)
+			_, ok = dp.Attributes().Get("url.path")
+			assert.True(t, ok)
+			assert.Equal(t, rs.Wallclock.UTC(), dp.Timestamp().AsTime())
+			assert.Equal(t, rs.Wallclock.Add(-time.Second).UTC(), dp.StartTimestamp().AsTime())
+		}
+		assert.Equal(t, uint64(2), count, m.Name())
+	}
+
+	spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	roots := map[string]bool{}
+	for i := range spans.Len() {
+		span := spans.At(i)
+		if span.ParentSpanID().IsEmpty() {
+			assert.Equal(t, "documentLoad", span.Name())
+			sid, ok := span.Attributes().Get("session.id")
+			require.True(t, ok)
+			roots[sid.Str()] = true
+		}
+	}
+	assert.Len(t, roots, 2)
+
+	service, ok := td.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, DefaultService, service.Str())
+}
+
+func TestRUMProducer_FractionalRate(t *testing.T) {
+	p, err := NewRUMProducer(RUMProducerSpec{Rate: 0.25, Resources: 1})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Wallclock = time.Unix(1700000000, 0)
+	total := 0
+	for tick := range 8 {
+		rs.Tick = time.Duration(tick) * time.Second
+		spans, _ := emitTick(t, p, rs)
+		total += spans
+	}
+	// Two sessions of three spans each.
+	assert.Equal(t, 6, total)
+}
+
+func TestRUMProducer_Reconfigure(t *testing.T) {
+	p, err := NewRUMProducer(RUMProducerSpec{Rate: 1, Resources: 1})
+	require.NoError(t, err)
+	require.NoError(t, p.Reconfigure(map[string]any{"rate": 0.0}))
+
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Wallclock = time.Unix(1700000000, 0)
+	spans, datapoints := emitTick(t, p, rs)
+	assert.Zero(t, spans)
+	assert.Zero(t, datapoints)
+
+	assert.Error(t, p.Reconfigure(map[string]any{"rate": -1.0}))
+	assert.Error(t, p.Reconfigure(map[string]any{"sessions": 1}))
+}
+
+func TestRUMProducer_VitalHistograms(t *testing.T) {
+	p, err := NewRUMProducer(RUMProducerSpec{Rate: 3, Resources: 1, Browsers: []string{"Chrome"}})
+	require.NoError(t, err)
+
+	rs := state.NewRunState(time.Minute, 1)
+	start := time.Unix(1700000000, 0).UTC()
+	var starts []time.Time
+	for tick := range 3 {
+		rs.Tick = time.Duration(tick) * 5 * time.Second
+		rs.Wallclock = start.Add(rs.Tick)
+		mb := signalbuilder.NewMetricsBuilder()
+		require.NoError(t, p.Emit(rs, signalbuilder.NewTracesBuilder(), mb))
+		md := mb.Build()
+		// One page and browser, so one datapoint per vital.
+		require.Equal(t, 5, md.DataPointCount())
+		m := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		dp := m.Histogram().DataPoints().At(0)
+		assert.Equal(t, uint64(3), dp.Count())
+		var buckets uint64
+		for _, n := range dp.BucketCounts().All() {
+			buckets += n
+		}
+		assert.Equal(t, uint64(3), buckets)
+		assert.Equal(t, VitalBoundsMs, dp.ExplicitBounds().AsRaw())
+		starts = append(starts, dp.StartTimestamp().AsTime())
+	}
+	// Each delta starts where the last ended.
+	assert.Equal(t, []time.Time{start.Add(-time.Second), start, start.Add(5 * time.Second)}, starts)
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scenariotest runs timelines in memory so scenario repositories
+// can assert on the telemetry they produce from ordinary Go tests.
+package scenariotest
+
+import (
+	"context"
+	"maps"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/emitter"
+	"github.com/cardinalhq/flutter/pkg/script"
+	"github.com/cardinalhq/flutter/pkg/state"
+	"github.com/cardinalhq/flutter/pkg/timeline"
+)
+
+// DefaultSeed is used when Options.Seed is zero, so runs are
+// reproducible unless a test asks otherwise.
+const DefaultSeed = 1
+
+// DefaultStart is the simulated wallclock at tick zero when
+// Options.Start is zero.
+var DefaultStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Options controls a run.  Zero values use DefaultSeed, DefaultStart,
+// and the duration implied by the timelines.  Warmup runs the first
+// part of the timelines without emitting; datapoint offsets then count
+// from the end of the warm-up.
+type Options struct {
+	Seed     uint64
+	Start    time.Time
+	Duration time.Duration
+	Warmup   time.Duration
+}
+
+// Result holds everything emitted by a run.
+type Result struct {
+	Start   time.Time
+	Metrics []pmetric.Metrics
+	Traces  []ptrace.Traces
+}
+
+// Point is one datapoint of a series, at an offset from the start of
+// the run.
+type Point struct {
+	At    time.Duration
+	Value float64
+}
+
+type memoryEmitter struct {
+	result *Result
+}
+
+func (m *memoryEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	if md.DataPointCount() > 0 {
+		m.result.Metrics = append(m.result.Metrics, md)
+	}
+	return nil
+}
+
+func (m *memoryEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	if td.SpanCount() > 0 {
+		m.result.Traces = append(m.result.Traces, td)
+	}
+	return nil
+}
+
+// Run simulates the given JSON timelines without sleeping between
+// ticks and returns what was emitted.  Failures stop the test.
+func Run(t testing.TB, opts Options, timelines ...[]byte) *Result {
+	t.Helper()
+	if opts.Seed == 0 {
+		opts.Seed = DefaultSeed
+	}
+	if opts.Start.IsZero() {
+		opts.Start = DefaultStart
+	}
+
+	rscript := script.NewScript()
+	for i, b := range timelines {
+		tl, err := timeline.ParseTimeline(b)
+		require.NoErrorf(t, err, "parsing timeline %d", i)
+		require.NoErrorf(t, tl.MergeIntoScript(rscript), "merging timeline %d", i)
+	}
+
+	result := &Result{Start: opts.Start}
+	rscript.AddEmitter(&memoryEmitter{result: result})
+	cfg := &config.Config{
+		Seed:           opts.Seed,
+		WallclockStart: opts.Start,
+		Duration:       opts.Duration,
+		Warmup:         opts.Warmup,
+		Dryrun:         true,
+	}
+	require.NoError(t, script.Simulate(context.Background(), cfg, rscript, 0))
+	return result
+}
+
+// RunFiles is Run with timelines read from files.
+func RunFiles(t testing.TB, opts Options, paths ...string) *Result {
+	t.Helper()
+	timelines := make([][]byte, 0, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		timelines = append(timelines, b)
+	}
+	return Run(t, opts, timelines...)
+}
+
+// Series returns the datapoints of the named metric whose resource
+// and datapoint attributes include every entry in attrs, in emission
+// order.  A nil attrs matches every series with that name.
+func (r *Result) Series(name string, attrs map[string]string) []Point {
+	var points []Point
+	for _, md := range r.Metrics {
+		for _, row := range emitter.FlattenMetrics(md) {
+			if row.Name != name || !matches(row, attrs) {
+				continue
+			}
+			points = append(points, Point{At: row.Timestamp.Sub(r.Start), Value: row.Value})
+		}
+	}
+	return points
+}
+
+// MetricNames returns the sorted names of every metric emitted.
+func (r *Result) MetricNames() []string {
+	names := map[string]bool{}
+	for _, md := range r.Metrics {
+		for _, row := range emitter.FlattenMetrics(md) {
+			names[row.Name] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(names))
+}
+
+func matches(row emitter.MetricRow, attrs map[string]string) bool {
+	for k, want := range attrs {
+		got, ok := row.Attributes[k]
+		if !ok {
+			got, ok = row.ResourceAttributes[k]
+		}
+		if !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// RequireSeries fails the test unless the series has at least one
+// datapoint, and returns its points.
+func (r *Result) RequireSeries(t testing.TB, name string, attrs map[string]string) []Point {
+	t.Helper()
+	points := r.Series(name, attrs)
+	require.NotEmptyf(t, points, "no datapoints for %s %v; emitted metrics: %v", name, attrs, r.MetricNames())
+	return points
+}
+
+// ValueAt returns the most recent value of the series at or before at.
+func (r *Result) ValueAt(name string, attrs map[string]string, at time.Duration) (float64, bool) {
+	var value float64
+	found := false
+	for _, p := range r.Series(name, attrs) {
+		if p.At <= at {
+			value = p.Value
+			found = true
+		}
+	}
+	return value, found
+}
+
+// RequireValueAt fails the test unless the series' most recent value
+// at or before at is within [lo, hi].
+func (r *Result) RequireValueAt(t testing.TB, name string, attrs map[string]string, at time.Duration, lo, hi float64) {
+	t.Helper()
+	r.RequireSeries(t, name, attrs)
+	value, ok := r.ValueAt(name, attrs, at)
+	require.Truef(t, ok, "no datapoint for %s %v at or before %s", name, attrs, at)
+	require.GreaterOrEqualf(t, value, lo, "%s %v at %s", name, attrs, at)
+	require.LessOrEqualf(t, value, hi, "%s %v at %s", name, attrs, at)
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scenariotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTimeline = `
+{
+	"metrics": [
+		{
+			"name": "queue.depth",
+			"type": "gauge",
+			"frequency": "10s",
+			"resourceAttributes": {"service.name": "worker"},
+			"variants": [
+				{
+					"attributes": {"queue": "orders"},
+					"timeline": [
+						{"start_ts": "0s", "end_ts": "60s", "start": 0, "target": 60}
+					]
+				}
+			]
+		}
+	],
+	"traces": [
+		{
+			"name": "checkout",
+			"exemplar": {
+				"name": "POST /checkout",
+				"kind": "server",
+				"duration": "100ms",
+				"resourceAttributes": {"service.name": "frontend"},
+				"children": [
+					{"name": "charge", "duration": "20ms", "children": [{"name": "db.query", "duration": "5ms"}]},
+					{"name": "reserve", "duration": "10ms"}
+				]
+			},
+			"variants": [
+				{"timeline": [{"type": "segment", "start_ts": "0s", "end_ts": "60s", "start": 1, "target": 1}]}
+			]
+		}
+	]
+}`
+
+func TestRun(t *testing.T) {
+	result := Run(t, Options{}, []byte(testTimeline))
+
+	assert.Equal(t, []string{"queue.depth"}, result.MetricNames())
+	points := result.RequireSeries(t, "queue.depth", map[string]string{"queue": "orders", "service.name": "worker"})
+	assert.Equal(t, 10*time.Second, points[0].At)
+	assert.Empty(t, result.Series("queue.depth", map[string]string{"queue": "payments"}))
+
+	result.RequireValueAt(t, "queue.depth", nil, 30*time.Second, 25, 35)
+	_, ok := result.ValueAt("queue.depth", nil, 5*time.Second)
+	assert.False(t, ok)
+
+	result.RequireTraceShape(t, Shape{
+		Name: "POST /checkout",
+		Children: []Shape{
+			{Name: "reserve"},
+			{Name: "charge", Children: []Shape{{Name: "db.query"}}},
+		},
+	})
+}
+
+func TestRun_Reproducible(t *testing.T) {
+	a := Run(t, Options{Seed: 7}, []byte(testTimeline))
+	b := Run(t, Options{Seed: 7}, []byte(testTimeline))
+	assert.Equal(t, a.Series("queue.depth", nil), b.Series("queue.depth", nil))
+	assert.Equal(t, len(a.TraceShapes()), len(b.TraceShapes()))
+}
+
+func TestRun_Warmup(t *testing.T) {
+	result := Run(t, Options{Warmup: 30 * time.Second}, []byte(testTimeline))
+
+	points := result.RequireSeries(t, "queue.depth", nil)
+	// The ramp reaches 30 during warm-up, and the first datapoint
+	// after it is at offset zero.
+	assert.Equal(t, time.Duration(0), points[0].At)
+	assert.InDelta(t, 30, points[0].Value, 5)
+	for _, p := range points {
+		assert.GreaterOrEqual(t, p.At, time.Duration(0))
+	}
+}
+
+func TestShape_String(t *testing.T) {
+	s := Shape{Name: "root", Children: []Shape{{Name: "b"}, {Name: "a", Children: []Shape{{Name: "c"}}}}}
+	assert.Equal(t, "root(a(c),b)", s.String())
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scenariotest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/emitter"
+)
+
+// Shape describes a span tree by span name, ignoring sibling order,
+// IDs, and timing.
+type Shape struct {
+	Name     string
+	Children []Shape
+}
+
+// String renders the shape as name(child,child(grandchild)) with
+// children sorted, so equal shapes have equal strings.
+func (s Shape) String() string {
+	if len(s.Children) == 0 {
+		return s.Name
+	}
+	children := make([]string, 0, len(s.Children))
+	for _, c := range s.Children {
+		children = append(children, c.String())
+	}
+	slices.Sort(children)
+	return s.Name + "(" + strings.Join(children, ",") + ")"
+}
+
+// TraceShapes returns the shape of every trace emitted, one per root
+// span.
+func (r *Result) TraceShapes() []Shape {
+	var shapes []Shape
+	for _, td := range r.Traces {
+		rows := emitter.FlattenTraces(td)
+		byTrace := map[string][]emitter.SpanRow{}
+		var order []string
+		for _, row := range rows {
+			if _, ok := byTrace[row.TraceID]; !ok {
+				order = append(order, row.TraceID)
+			}
+			byTrace[row.TraceID] = append(byTrace[row.TraceID], row)
+		}
+		for _, id := range order {
+			spans := byTrace[id]
+			for _, span := range spans {
+				if span.ParentSpanID == "" {
+					shapes = append(shapes, buildShape(span, spans))
+				}
+			}
+		}
+	}
+	return shapes
+}
+
+func buildShape(span emitter.SpanRow, spans []emitter.SpanRow) Shape {
+	shape := Shape{Name: span.Name}
+	for _, child := range spans {
+		if child.ParentSpanID == span.SpanID {
+			shape.Children = append(shape.Children, buildShape(child, spans))
+		}
+	}
+	return shape
+}
+
+// RequireTraceShape fails the test unless at least one emitted trace
+// has the given shape.
+func (r *Result) RequireTraceShape(t testing.TB, want Shape) {
+	t.Helper()
+	got := map[string]bool{}
+	for _, shape := range r.TraceShapes() {
+		if shape.String() == want.String() {
+			return
+		}
+		got[shape.String()] = true
+	}
+	seen := make([]string, 0, len(got))
+	for s := range got {
+		seen = append(seen, s)
+	}
+	slices.Sort(seen)
+	require.Failf(t, "trace shape not found", "want %s, emitted shapes: %v", want, seen)
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"log/slog"
+	"runtime/metrics"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+const (
+	// budgetCheckInterval is how often, in simulated time, the
+	// footprint is compared to the budget.
+	budgetCheckInterval = 10 * time.Second
+	// maxDegrade caps how far output is reduced: 1/16th.
+	maxDegrade = 4
+)
+
+// footprint watches flutter's own memory and CPU use and raises the
+// run's Degrade level when either is over budget.
+type footprint struct {
+	budget config.Budget
+	// read returns the memory held by the Go runtime, in bytes, and
+	// the CPU time used so far, in seconds.
+	read     func() (uint64, float64)
+	now      func() time.Time
+	lastCPU  float64
+	lastWall time.Time
+}
+
+// newFootprint returns nil when the budget sets no limits.  The CPU
+// limit is dropped when the run goes as fast as it can.
+func newFootprint(budget config.Budget, fast bool) *footprint {
+	if fast {
+		budget.MaxCPU = 0
+	}
+	if budget.MaxMemoryMB <= 0 && budget.MaxCPU <= 0 {
+		return nil
+	}
+	f := &footprint{
+		budget: budget,
+		read:   readRuntimeFootprint,
+		now:    time.Now,
+	}
+	_, f.lastCPU = f.read()
+	f.lastWall = f.now()
+	return f
+}
+
+// check raises rs.Degrade one level if a limit was exceeded since the
+// last check.
+func (f *footprint) check(rs *state.RunState) {
+	mem, cpu := f.read()
+	wall := f.now()
+	cores := 0.0
+	if elapsed := wall.Sub(f.lastWall).Seconds(); elapsed > 0 {
+		cores = (cpu - f.lastCPU) / elapsed
+	}
+	f.lastCPU, f.lastWall = cpu, wall
+
+	memMB := int(mem >> 20)
+	overMem := f.budget.MaxMemoryMB > 0 && memMB > f.budget.MaxMemoryMB
+	overCPU := f.budget.MaxCPU > 0 && cores > f.budget.MaxCPU
+	if !overMem && !overCPU {
+		return
+	}
+	if rs.Degrade >= maxDegrade {
+		return
+	}
+	rs.Degrade++
+	slog.Warn("Over footprint budget, reducing output",
+		"memoryMB", memMB, "maxMemoryMB", f.budget.MaxMemoryMB,
+		"cpu", cores, "maxCPU", f.budget.MaxCPU,
+		"degradeFactor", rs.DegradeFactor())
+}
+
+func readRuntimeFootprint() (uint64, float64) {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/cpu/classes/total:cpu-seconds"},
+		{Name: "/cpu/classes/idle:cpu-seconds"},
+	}
+	metrics.Read(samples)
+	return samples[0].Value.Uint64(), samples[1].Value.Float64() - samples[2].Value.Float64()
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// DebugPath is where the debug page is conventionally mounted.
+const DebugPath = "/debug/flutterz"
+
+// recordingGenerator remembers what a generator added to the value on
+// its most recent emit, for the debug page, and keeps running
+// statistics of those contributions for the dry-run summary.
+type recordingGenerator struct {
+	generator.MetricGenerator
+	id      string
+	value   float64
+	at      time.Duration
+	emitted bool
+	stats   runningStats
+}
+
+// Emit runs the generator on its own random stream, so adding or
+// removing a generator leaves the draws of every other one unchanged.
+func (r *recordingGenerator) Emit(rs *state.RunState, incoming float64) float64 {
+	var out float64
+	rs.Fork("generator/"+r.id, func() {
+		out = r.MetricGenerator.Emit(rs, incoming)
+	})
+	rs.RecordGenerator(r.id, out)
+	r.value = out - incoming
+	r.at = rs.Tick
+	r.emitted = true
+	r.stats.add(r.value)
+	return out
+}
+
+// DebugSnapshot is the state of a running script at one tick.
+type DebugSnapshot struct {
+	Tick       time.Duration     `json:"tick"`
+	Duration   time.Duration     `json:"duration"`
+	Generators []ComponentStatus `json:"generators"`
+	Metrics    []ComponentStatus `json:"metrics"`
+	Traces     []ComponentStatus `json:"traces"`
+}
+
+// ComponentStatus describes one generator or producer.  For a generator
+// Value is what it contributed on its last emit; for a metric it is the
+// value emitted.  Spec is the effective spec, with every redefinition
+// applied.
+type ComponentStatus struct {
+	ID       string         `json:"id"`
+	Type     string         `json:"type,omitempty"`
+	Value    *float64       `json:"value,omitempty"`
+	LastEmit *time.Duration `json:"lastEmit,omitempty"`
+	Disabled bool           `json:"disabled,omitempty"`
+	Spec     map[string]any `json:"spec,omitempty"`
+}
+
+// recordSpec folds a redefinition into the effective spec shown on the
+// debug page, the way Reconfigure folds it into the component.
+func (s *Script) recordSpec(action scriptaction.ScriptAction) {
+	var specs map[string]map[string]any
+	switch action.Type {
+	case "metricGenerator":
+		specs = s.generatorSpecs
+	case "metric":
+		specs = s.metricSpecs
+	case "trace", "traceProducer", "rum":
+		specs = s.traceSpecs
+	default:
+		return
+	}
+	spec := maps.Clone(specs[action.ID])
+	if spec == nil {
+		spec = map[string]any{}
+	}
+	maps.Copy(spec, action.Spec)
+	specs[action.ID] = spec
+}
+
+// Snapshot returns the current state of the script.  It is safe to call
+// while the script runs.
+func (s *Script) Snapshot() DebugSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := DebugSnapshot{
+		Tick:     s.tick,
+		Duration: s.duration,
+	}
+	for _, id := range slices.Sorted(maps.Keys(s.metricGenerators)) {
+		st := ComponentStatus{ID: id, Spec: s.generatorSpecs[id]}
+		st.Type, _ = st.Spec["type"].(string)
+		if r, ok := s.metricGenerators[id].(*recordingGenerator); ok && r.emitted {
+			st.Value, st.LastEmit = &r.value, &r.at
+		}
+		snap.Generators = append(snap.Generators, st)
+	}
+	for _, id := range slices.Sorted(maps.Keys(s.metricProducers)) {
+		p := s.metricProducers[id]
+		st := ComponentStatus{ID: id, Disabled: p.IsDisabled(), Spec: s.metricSpecs[id]}
+		st.Type, _ = st.Spec["type"].(string)
+		if at, v, ok := p.LastEmit(); ok {
+			st.Value, st.LastEmit = &v, &at
+		}
+		snap.Metrics = append(snap.Metrics, st)
+	}
+	for _, id := range slices.Sorted(maps.Keys(s.traceProducers)) {
+		snap.Traces = append(snap.Traces, ComponentStatus{
+			ID:       id,
+			Disabled: s.traceProducers[id].IsDisabled(),
+			Spec:     s.traceSpecs[id],
+		})
+	}
+	for _, id := range slices.Sorted(maps.Keys(s.rumProducers)) {
+		snap.Traces = append(snap.Traces, ComponentStatus{ID: id, Type: "rum", Spec: s.traceSpecs[id]})
+	}
+	return snap
+}
+
+// DebugHandler serves a zpages-style page listing every generator and
+// producer, refreshed every few seconds.  Add ?format=json for the raw
+// snapshot.
+func (s *Script) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snap := s.Snapshot()
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(snap)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := debugPage.Execute(w, snap); err != nil {
+			slog.Error("Failed to render debug page", "error", err)
+		}
+	})
+}
+
+var debugPage = template.Must(template.New("debug").Funcs(template.FuncMap{
+	"spec": func(spec map[string]any) string {
+		if len(spec) == 0 {
+			return ""
+		}
+		b, err := json.Marshal(spec)
+		if err != nil {
+			return err.Error()
+		}
+		return string(b)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta http-equiv="refresh" content="2">
+<title>flutter</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; vertical-align: top; }
+th { background: #eee; }
+td.spec { font-family: monospace; font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>flutter</h1>
+<p>Tick {{.Tick}} of {{.Duration}}</p>
+{{define "table"}}
+<table>
+<tr><th>ID</th><th>Type</th><th>Value</th><th>Last emit</th><th>Disabled</th><th>Spec</th></tr>
+{{range .}}<tr><td>{{.ID}}</td><td>{{.Type}}</td><td>{{with .Value}}{{printf "%.6g" .}}{{end}}</td><td>{{with .LastEmit}}{{.}}{{end}}</td><td>{{if .Disabled}}yes{{end}}</td><td class="spec">{{spec .Spec}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Metrics</h2>
+{{template "table" .Metrics}}
+<h2>Generators</h2>
+{{template "table" .Generators}}
+<h2>Traces</h2>
+{{template "table" .Traces}}
+</body>
+</html>
+`))
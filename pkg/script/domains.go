@@ -0,0 +1,185 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+)
+
+// The resource attributes that place a resource in a failure domain.
+const (
+	zoneAttribute   = "cloud.availability_zone"
+	regionAttribute = "cloud.region"
+)
+
+// outage is a zone or region that is down from at until to, or until
+// the end of the run if to is zero.  With both set, only the zone in
+// that region is down.
+type outage struct {
+	zone   string
+	region string
+	at     time.Duration
+	to     time.Duration
+}
+
+type outageSpec struct {
+	Zone   string `mapstructure:"zone"`
+	Region string `mapstructure:"region"`
+}
+
+func newOutage(action scriptaction.ScriptAction) (outage, error) {
+	var spec outageSpec
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return outage{}, err
+	}
+	if err := decoder.Decode(action.Spec); err != nil {
+		return outage{}, err
+	}
+	if spec.Zone == "" && spec.Region == "" {
+		return outage{}, errors.New("an outage needs a zone or a region")
+	}
+	return outage{zone: spec.Zone, region: spec.Region, at: action.At, to: action.To}, nil
+}
+
+func (o outage) active(tick time.Duration) bool {
+	return tick >= o.at && (o.to == 0 || tick < o.to)
+}
+
+func (o outage) contains(attrs pcommon.Map) bool {
+	if o.zone != "" && !hasValue(attrs, zoneAttribute, o.zone) {
+		return false
+	}
+	return o.region == "" || hasValue(attrs, regionAttribute, o.region)
+}
+
+// message is the status message of spans failed by the outage.
+func (o outage) message() string {
+	if o.zone != "" {
+		return o.zone + " outage"
+	}
+	return o.region + " outage"
+}
+
+func hasValue(attrs pcommon.Map, k, want string) bool {
+	v, ok := attrs.Get(k)
+	return ok && v.AsString() == want
+}
+
+// placeInDomain gives attrs the zone and region of the first failure
+// domain it matches, keeping any it sets itself.
+func placeInDomain(domains []config.FailureDomain, attrs pcommon.Map) {
+	for _, d := range domains {
+		if !inDomain(d, attrs) {
+			continue
+		}
+		if _, ok := attrs.Get(zoneAttribute); !ok && d.Zone != "" {
+			attrs.PutStr(zoneAttribute, d.Zone)
+		}
+		if _, ok := attrs.Get(regionAttribute); !ok && d.Region != "" {
+			attrs.PutStr(regionAttribute, d.Region)
+		}
+		return
+	}
+}
+
+func inDomain(d config.FailureDomain, attrs pcommon.Map) bool {
+	for k, values := range d.Resources {
+		v, ok := attrs.Get(k)
+		if !ok {
+			continue
+		}
+		for _, want := range values {
+			if v.AsString() == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// downBy returns the active outage that takes down a resource with
+// attrs, if there is one.
+func (s *Script) downBy(tick time.Duration, attrs pcommon.Map) (outage, bool) {
+	for _, o := range s.outages {
+		if o.active(tick) && o.contains(attrs) {
+			return o, true
+		}
+	}
+	return outage{}, false
+}
+
+// applyDomainsToMetrics places every resource in its failure domain
+// and drops the metrics of those that are down.
+func (s *Script) applyDomainsToMetrics(tick time.Duration, md pmetric.Metrics) {
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		placeInDomain(s.failureDomains, rm.Resource().Attributes())
+		_, down := s.downBy(tick, rm.Resource().Attributes())
+		return down
+	})
+}
+
+// applyDomainsToTraces places every resource in its failure domain
+// and fails the spans of those that are down.  The failure carries up
+// to every ancestor of a failed span, as callers see their calls into
+// the outage fail.
+func (s *Script) applyDomainsToTraces(tick time.Duration, td ptrace.Traces) {
+	parents := map[pcommon.SpanID]pcommon.SpanID{}
+	failed := map[pcommon.SpanID]string{}
+	var downSpans []pcommon.SpanID
+	for _, rspans := range td.ResourceSpans().All() {
+		placeInDomain(s.failureDomains, rspans.Resource().Attributes())
+		o, down := s.downBy(tick, rspans.Resource().Attributes())
+		for _, ss := range rspans.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				parents[span.SpanID()] = span.ParentSpanID()
+				if down {
+					failed[span.SpanID()] = o.message()
+					downSpans = append(downSpans, span.SpanID())
+				}
+			}
+		}
+	}
+	if len(downSpans) == 0 {
+		return
+	}
+	for _, id := range downSpans {
+		message := failed[id]
+		for parent := parents[id]; !parent.IsEmpty(); parent = parents[parent] {
+			if _, ok := failed[parent]; ok {
+				break
+			}
+			failed[parent] = message
+		}
+	}
+	for _, rspans := range td.ResourceSpans().All() {
+		for _, ss := range rspans.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				if message, ok := failed[span.SpanID()]; ok {
+					span.Status().SetCode(ptrace.StatusCodeError)
+					span.Status().SetMessage(message)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// DumpFormats are the formats DumpFormat accepts.
+var DumpFormats = []string{"json", "yaml", "mermaid"}
+
+// DumpFormat writes the actions as JSON lines, as a YAML list, or as a
+// Mermaid Gantt chart of each component's action windows.
+func (s *Script) DumpFormat(out io.Writer, format string) error {
+	switch format {
+	case "", "json":
+		return s.Dump(out)
+	case "yaml":
+		return s.dumpYAML(out)
+	case "mermaid":
+		return s.dumpMermaid(out)
+	default:
+		return fmt.Errorf("unknown dump format %q, expected one of %s", format, strings.Join(DumpFormats, ", "))
+	}
+}
+
+func (s *Script) dumpYAML(out io.Writer) error {
+	if len(s.actions) == 0 {
+		return errors.New("no script actions found in config")
+	}
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(s.actions); err != nil {
+		return fmt.Errorf("error encoding actions: %w", err)
+	}
+	return enc.Close()
+}
+
+// dumpMermaid draws one section per component ID.  An action without a
+// To lasts until the next action for the same ID, or the end of the
+// script, and is at least a second wide so it stays visible.
+func (s *Script) dumpMermaid(out io.Writer) error {
+	if len(s.actions) == 0 {
+		return errors.New("no script actions found in config")
+	}
+	end := time.Duration(0)
+	for _, a := range s.actions {
+		end = max(end, a.At, a.To)
+	}
+
+	byID := map[string][]scriptaction.ScriptAction{}
+	var ids []string
+	for _, a := range s.actions {
+		if _, ok := byID[a.ID]; !ok {
+			ids = append(ids, a.ID)
+		}
+		byID[a.ID] = append(byID[a.ID], a)
+	}
+	slices.Sort(ids)
+
+	var b strings.Builder
+	b.WriteString("gantt\n")
+	b.WriteString("  title Script actions\n")
+	b.WriteString("  dateFormat X\n")
+	b.WriteString("  axisFormat %H:%M:%S\n")
+	for _, id := range ids {
+		actions := byID[id]
+		slices.SortStableFunc(actions, func(a, b scriptaction.ScriptAction) int {
+			return cmp.Compare(a.At, b.At)
+		})
+		fmt.Fprintf(&b, "  section %s\n", mermaidText(id))
+		for i, a := range actions {
+			to := a.To
+			if to <= a.At {
+				to = end
+				if i+1 < len(actions) {
+					to = actions[i+1].At
+				}
+			}
+			to = max(to, a.At+time.Second)
+			fmt.Fprintf(&b, "    %s :%d, %d\n", mermaidText(actionLabel(a)), int64(a.At.Seconds()), int64(to.Seconds()))
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// actionLabel names a bar by action type, and by the generator or
+// metric type when the spec has one.
+func actionLabel(a scriptaction.ScriptAction) string {
+	if t, ok := a.Spec["type"].(string); ok && t != "" {
+		return a.Type + " " + t
+	}
+	return a.Type
+}
+
+// mermaidText removes the characters Mermaid treats as syntax in
+// section and task names.
+func mermaidText(s string) string {
+	return strings.NewReplacer(":", "-", "#", "-", ";", "-", "\n", " ").Replace(s)
+}
+
+// resolvedAction is an action with the spec of the component it leaves
+// behind, rather than the raw map it was written as.
+type resolvedAction struct {
+	ID   string        `yaml:"id" json:"id"`
+	At   time.Duration `yaml:"at" json:"at"`
+	To   time.Duration `yaml:"to,omitempty" json:"to,omitempty"`
+	Type string        `yaml:"type" json:"type"`
+	Spec any           `yaml:"spec" json:"spec"`
+}
+
+// DumpResolved prepares the script and applies each action in order,
+// as a run would but without emitting anything, then writes the
+// decoded and defaulted spec of the component after each one.  The
+// format is "json" (one line per action) or "yaml".  The script is
+// used up and cannot be run afterwards.
+func (s *Script) DumpResolved(out io.Writer, cfg *config.Config, format string) error {
+	switch format {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("resolved specs can be dumped as json or yaml, not %q", format)
+	}
+	if err := s.Prepare(cfg); err != nil {
+		return fmt.Errorf("error creating running config: %w", err)
+	}
+
+	// Each spec is encoded as its action is applied, since producers
+	// are reconfigured in place by later actions.
+	rs := state.NewRunState(s.duration, cfg.Seed)
+	var lines [][]byte
+	var nodes []*yaml.Node
+	for _, action := range s.actions {
+		rs.Tick = action.At
+		if err := applyAction(s, rs, action); err != nil {
+			return err
+		}
+		r := resolvedAction{
+			ID:   action.ID,
+			At:   action.At,
+			To:   action.To,
+			Type: action.Type,
+			Spec: s.resolvedSpec(action),
+		}
+		if format == "yaml" {
+			n := &yaml.Node{}
+			if err := n.Encode(r); err != nil {
+				return fmt.Errorf("error encoding action: %w", err)
+			}
+			nodes = append(nodes, n)
+			continue
+		}
+		b, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("error encoding action: %w", err)
+		}
+		lines = append(lines, append(b, '\n'))
+	}
+
+	if format == "yaml" {
+		enc := yaml.NewEncoder(out)
+		enc.SetIndent(2)
+		if err := enc.Encode(nodes); err != nil {
+			return fmt.Errorf("error encoding actions: %w", err)
+		}
+		return enc.Close()
+	}
+	for _, line := range lines {
+		if _, err := out.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Script) resolvedSpec(action scriptaction.ScriptAction) any {
+	switch action.Type {
+	case "metricGenerator":
+		g := s.metricGenerators[action.ID]
+		if r, ok := g.(*recordingGenerator); ok {
+			g = r.MetricGenerator
+		}
+		if sr, ok := g.(generator.SpecReporter); ok {
+			return sr.Spec()
+		}
+	case "metric", "disableMetric", "enableMetric":
+		if p, ok := s.metricProducers[action.ID]; ok {
+			return p
+		}
+	case "trace", "traceProducer", "traceRate", "disableTrace", "enableTrace":
+		if p, ok := s.traceProducers[action.ID]; ok {
+			return p
+		}
+	case "rum":
+		if p, ok := s.rumProducers[action.ID]; ok {
+			return p.Spec()
+		}
+	}
+	return action.Spec
+}
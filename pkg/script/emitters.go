@@ -0,0 +1,113 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/cardinalhq/flutter/pkg/emitter"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// checkEmitterActions decodes every addEmitter spec, and checks that
+// each removeEmitter follows an addEmitter of the same ID, so mistakes
+// are reported before the run rather than when they are reached.
+// Actions must already be sorted.
+func (s *Script) checkEmitterActions() error {
+	added := map[string]bool{}
+	for _, action := range s.actions {
+		switch action.Type {
+		case "addEmitter":
+			if _, err := emitter.DecodeEmitterSpec(action.Spec); err != nil {
+				return fmt.Errorf("invalid addEmitter %s: %w", action.ID, err)
+			}
+			added[action.ID] = true
+		case "removeEmitter":
+			if !added[action.ID] {
+				return fmt.Errorf("removeEmitter %s: no earlier addEmitter with this ID", action.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// applyEmitterAction attaches or detaches an emitter.  An emitter that
+// cannot be created, say because its file cannot be written, is logged
+// and the run goes on without it; removing it later does nothing.
+// Dry runs do not attach OTLP emitters, as they send nothing.
+func applyEmitterAction(ctx context.Context, rscript *Script, rs *state.RunState, action scriptaction.ScriptAction) error {
+	switch action.Type {
+	case "addEmitter":
+		spec, err := emitter.DecodeEmitterSpec(action.Spec)
+		if err != nil {
+			return fmt.Errorf("invalid addEmitter %s: %w", action.ID, err)
+		}
+		if rscript.dryrun && spec.Type == emitter.AttachOTLP {
+			slog.Info("Dry run, not attaching OTLP emitter", "id", action.ID, "endpoint", spec.Endpoint)
+			return nil
+		}
+		if err := rscript.detachEmitter(ctx, rs, action.ID); err != nil {
+			return err
+		}
+		a, err := emitter.NewAttached(action.ID, spec, rscript.httpTransport)
+		if err != nil {
+			slog.Warn("Could not attach emitter, continuing without it", "id", action.ID, "type", spec.Type, "error", err)
+			return nil
+		}
+		slog.Info("Attached emitter", "id", action.ID, "type", spec.Type, "tick", rs.Tick)
+		rscript.attached[action.ID] = a
+		rscript.emitters = append(rscript.emitters, a)
+	case "removeEmitter":
+		return rscript.detachEmitter(ctx, rs, action.ID)
+	}
+	return nil
+}
+
+// detachEmitter flushes and closes the emitter attached as id, if any.
+func (s *Script) detachEmitter(ctx context.Context, rs *state.RunState, id string) error {
+	a, ok := s.attached[id]
+	if !ok {
+		return nil
+	}
+	delete(s.attached, id)
+	s.emitters = slices.DeleteFunc(s.emitters, func(e emitter.Emitter) bool { return e == a })
+	slog.Info("Detached emitter", "id", id, "tick", rs.Tick)
+	if err := a.Flush(ctx, rs); err != nil {
+		_ = a.Close()
+		return fmt.Errorf("error flushing emitter %s: %w", id, err)
+	}
+	if err := a.Close(); err != nil {
+		return fmt.Errorf("error closing emitter %s: %w", id, err)
+	}
+	return nil
+}
+
+// closeAttached closes the emitters still attached when the run ends,
+// after they have been flushed with the others.
+func (s *Script) closeAttached() error {
+	var errs []error
+	for id, a := range s.attached {
+		if err := a.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("error closing emitter %s: %w", id, err))
+		}
+	}
+	clear(s.attached)
+	return errors.Join(errs...)
+}
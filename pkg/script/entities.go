@@ -0,0 +1,100 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/cardinalhq/flutter/pkg/emitter"
+	"github.com/cardinalhq/flutter/pkg/identity"
+	"github.com/cardinalhq/flutter/pkg/state"
+)
+
+// Entity event attributes, following the collector's entity events.
+const (
+	entityEventTypeAttribute  = "otel.entity.event.type"
+	entityTypeAttribute       = "otel.entity.type"
+	entityIDAttribute         = "otel.entity.id"
+	entityAttributesAttribute = "otel.entity.attributes"
+)
+
+// emitEntityEvents sends a log event for every entity that appeared
+// or disappeared this tick to the emitters that send logs.
+func emitEntityEvents(ctx context.Context, rscript *Script, rs *state.RunState) error {
+	// Entities seen before the first emitted tick are reported as
+	// created on it.
+	if rs.Tick < rscript.from {
+		return nil
+	}
+	events := rscript.identities.Events(rs.Tick, rscript.entityEvents.DeleteAfter)
+	if len(events) == 0 {
+		return nil
+	}
+	ld := entityLogs(rs, events)
+	rscript.exported += ld.LogRecordCount()
+	for _, e := range rscript.emitters {
+		if err := emitter.EmitLogs(ctx, e, rs, ld); err != nil {
+			return fmt.Errorf("error emitting entity events: %w", err)
+		}
+	}
+	return nil
+}
+
+// entityLogs builds one log record per event, under a resource with
+// the entity's attributes, stamped when the entity was first or last
+// seen.
+func entityLogs(rs *state.RunState, events []identity.Event) plog.Logs {
+	ld := plog.NewLogs()
+	observed := pcommon.NewTimestampFromTime(rs.Wallclock)
+	for _, ev := range events {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rattr := rl.Resource().Attributes()
+		for _, k := range slices.Sorted(maps.Keys(ev.Entity.Attributes)) {
+			rattr.PutStr(k, ev.Entity.Attributes[k])
+		}
+		if rs.RunID != "" {
+			rattr.PutStr(RunIDAttribute, rs.RunID)
+		}
+
+		lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(rs.Wallclock.Add(ev.At - rs.Tick)))
+		lr.SetObservedTimestamp(observed)
+		change, eventType := "created", "entity_state"
+		if ev.Deleted {
+			change, eventType = "deleted", "entity_delete"
+		}
+		lr.SetEventName(ev.Entity.Kind + "." + change)
+		lr.Body().SetStr(ev.Entity.Kind + " " + ev.Entity.Attributes[ev.Entity.IDAttribute] + " " + change)
+
+		attrs := lr.Attributes()
+		attrs.PutStr(entityEventTypeAttribute, eventType)
+		attrs.PutStr(entityTypeAttribute, ev.Entity.Kind)
+		id := attrs.PutEmptyMap(entityIDAttribute)
+		id.PutStr(ev.Entity.IDAttribute, ev.Entity.Attributes[ev.Entity.IDAttribute])
+		described := attrs.PutEmptyMap(entityAttributesAttribute)
+		for _, k := range slices.Sorted(maps.Keys(ev.Entity.Attributes)) {
+			if k != ev.Entity.IDAttribute {
+				described.PutStr(k, ev.Entity.Attributes[k])
+			}
+		}
+	}
+	return ld
+}
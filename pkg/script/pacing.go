@@ -0,0 +1,108 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// progressInterval is how often, in real time, a run that is not
+// slept through logs its progress and ETA.
+const progressInterval = 10 * time.Second
+
+// pacer holds a run that is not slept through, a backfill or dry run,
+// to its maximum export rate, and logs how far along it is.
+type pacer struct {
+	// rate is the datapoints and spans allowed per second; zero is
+	// unlimited.
+	rate       float64
+	duration   time.Duration
+	now        func() time.Time
+	sleep      func(ctx context.Context, d time.Duration)
+	start      time.Time
+	next       time.Time
+	lastReport time.Time
+	exported   int
+}
+
+func newPacer(rate float64, duration time.Duration) *pacer {
+	p := &pacer{
+		rate:     rate,
+		duration: duration,
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+	p.start = p.now()
+	p.next = p.start
+	p.lastReport = p.start
+	return p
+}
+
+// wait is called after each tick with the datapoints and spans it
+// exported.  It sleeps long enough that, averaged over the run, no
+// more than rate are sent each second, then logs progress when due.
+func (p *pacer) wait(ctx context.Context, tick time.Duration, exported int) {
+	p.exported += exported
+	now := p.now()
+	if p.rate > 0 {
+		// Time not used while exporting little is not saved up, so a
+		// quiet stretch cannot be followed by a burst.
+		if p.next.Before(now) {
+			p.next = now
+		}
+		p.next = p.next.Add(time.Duration(float64(exported) / p.rate * float64(time.Second)))
+		if d := p.next.Sub(now); d > 0 {
+			p.sleep(ctx, d)
+			now = p.now()
+		}
+	}
+	if now.Sub(p.lastReport) < progressInterval {
+		return
+	}
+	p.lastReport = now
+	elapsed := now.Sub(p.start)
+	slog.Info("Run progress",
+		"tick", tick,
+		"duration", p.duration,
+		"percent", progressPercent(tick, p.duration),
+		"exportRate", int(float64(p.exported)/elapsed.Seconds()),
+		"eta", eta(elapsed, tick, p.duration))
+}
+
+func progressPercent(tick, duration time.Duration) int {
+	if duration <= 0 {
+		return 100
+	}
+	return int(100 * tick / duration)
+}
+
+// eta estimates the real time left, assuming the rest of the run goes
+// at the pace of the part done so far.
+func eta(elapsed, tick, duration time.Duration) time.Duration {
+	if tick <= 0 {
+		return 0
+	}
+	left := float64(elapsed) * float64(duration-tick) / float64(tick)
+	return time.Duration(left).Round(time.Second)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
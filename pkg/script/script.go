@@ -15,35 +15,78 @@
 package script
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cardinalhq/oteltools/signalbuilder"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/pdata/plog"
 
 	"github.com/cardinalhq/flutter/pkg/config"
 	"github.com/cardinalhq/flutter/pkg/emitter"
 	"github.com/cardinalhq/flutter/pkg/generator"
+	"github.com/cardinalhq/flutter/pkg/identity"
 	"github.com/cardinalhq/flutter/pkg/metricproducer"
+	"github.com/cardinalhq/flutter/pkg/rumproducer"
 	"github.com/cardinalhq/flutter/pkg/scriptaction"
 	"github.com/cardinalhq/flutter/pkg/state"
 	"github.com/cardinalhq/flutter/pkg/traceproducer"
 )
 
+// RunIDAttribute is the resource attribute that carries the run ID.
+const RunIDAttribute = "flutter.run_id"
+
 type Script struct {
 	actions          []scriptaction.ScriptAction
 	metricGenerators map[string]generator.MetricGenerator
 	metricProducers  map[string]metricproducer.MetricProducer
 	traceProducers   map[string]traceproducer.TraceProducer
+	rumProducers     map[string]*rumproducer.RUMProducer
 	emitters         []emitter.Emitter
+	onStart          []func()
+	onStop           []func()
+	onTick           []func(context.Context, time.Duration) error
 	duration         time.Duration
 	from             time.Duration
+	// exported counts the datapoints and spans passed to the emitters.
+	exported int
+	// identities gives every resource naming the same entity the
+	// same IDs, across metrics, traces, and RUM.
+	identities *identity.Registry
+	// entityEvents, when enabled, reports entities appearing and
+	// disappearing as log events.
+	entityEvents config.EntityEvents
+	// failureDomains and outages take down everything in a zone or
+	// region at once.
+	failureDomains []config.FailureDomain
+	outages        []outage
+	// attached are the emitters addEmitter actions created, by ID.
+	// They are also in emitters while attached.
+	attached      map[string]*emitter.Attached
+	httpTransport config.HTTPTransport
+	dryrun        bool
+	// shard of shards is this script's part of a sharded run, and owned
+	// the stream names of the producers it emits; nil emits them all.
+	shard, shards int
+	owned         map[string]bool
+
+	// mu guards the fields above while the script runs, so the debug
+	// page can read them between ticks.
+	mu             sync.Mutex
+	tick           time.Duration
+	generatorSpecs map[string]map[string]any
+	metricSpecs    map[string]map[string]any
+	traceSpecs     map[string]map[string]any
 }
 
 func NewScript() *Script {
@@ -52,6 +95,12 @@ func NewScript() *Script {
 		metricGenerators: map[string]generator.MetricGenerator{},
 		metricProducers:  map[string]metricproducer.MetricProducer{},
 		traceProducers:   map[string]traceproducer.TraceProducer{},
+		rumProducers:     map[string]*rumproducer.RUMProducer{},
+		identities:       identity.NewRegistry(),
+		attached:         map[string]*emitter.Attached{},
+		generatorSpecs:   map[string]map[string]any{},
+		metricSpecs:      map[string]map[string]any{},
+		traceSpecs:       map[string]map[string]any{},
 	}
 }
 
@@ -63,10 +112,28 @@ func (s *Script) AddEmitter(emitter emitter.Emitter) {
 	s.emitters = append(s.emitters, emitter)
 }
 
+// OnStart adds a hook that runs once the script is prepared, just
+// before the first tick.
+func (s *Script) OnStart(f func()) {
+	s.onStart = append(s.onStart, f)
+}
+
+// OnStop adds a hook that runs when the run ends, whether it finished,
+// failed, or was cancelled.
+func (s *Script) OnStop(f func()) {
+	s.onStop = append(s.onStop, f)
+}
+
 func (s *Script) AddTraceProducer(id string, producer traceproducer.TraceProducer) {
 	s.traceProducers[id] = producer
 }
 
+// Actions returns a copy of the actions added so far, in the order
+// they were added.
+func (s *Script) Actions() []scriptaction.ScriptAction {
+	return slices.Clone(s.actions)
+}
+
 func (s *Script) Duration() time.Duration {
 	return s.duration
 }
@@ -88,7 +155,27 @@ func Simulate(ctx context.Context, cfg *config.Config, rscript *Script, from tim
 	if err := rscript.Prepare(cfg); err != nil {
 		return fmt.Errorf("error creating running config: %w", err)
 	}
-	rscript.from = from
+	if cfg.Warmup < 0 || (cfg.Warmup > 0 && cfg.Warmup >= rscript.duration) {
+		return fmt.Errorf("warmup %s must be at least zero and shorter than the run's duration %s", cfg.Warmup, rscript.duration)
+	}
+	if err := validateTiers(cfg.ResolutionTiers); err != nil {
+		return err
+	}
+	if cfg.EntityEvents.DeleteAfter < 0 {
+		return fmt.Errorf("entityEvents deleteAfter %s must not be negative", cfg.EntityEvents.DeleteAfter)
+	}
+	if cfg.MaxExportRate < 0 {
+		return fmt.Errorf("maxExportRate %v must not be negative", cfg.MaxExportRate)
+	}
+	// Nothing is emitted until both --from and the warm-up have passed.
+	rscript.from = max(from, cfg.Warmup)
+	rscript.entityEvents = cfg.EntityEvents
+	rscript.failureDomains = cfg.FailureDomains
+	rscript.httpTransport = cfg.HTTPTransport
+	rscript.dryrun = cfg.Dryrun
+	if rscript.entityEvents.DeleteAfter == 0 {
+		rscript.entityEvents.DeleteAfter = config.DefaultEntityDeleteAfter
+	}
 	return run(ctx, cfg, rscript)
 }
 
@@ -106,6 +193,15 @@ func (s *Script) Prepare(cfg *config.Config) error {
 		}
 		return strings.Compare(a.ID, b.ID)
 	})
+	if err := s.checkEmitterActions(); err != nil {
+		return err
+	}
+
+	switch cfg.TimestampAlignment {
+	case "", "tick", "scrape":
+	default:
+		return fmt.Errorf("invalid timestampAlignment: %q", cfg.TimestampAlignment)
+	}
 
 	var err error
 	s.duration, err = calculateDuration(cfg.Duration, s.actions)
@@ -113,7 +209,10 @@ func (s *Script) Prepare(cfg *config.Config) error {
 		return fmt.Errorf("error calculating duration: %w", err)
 	}
 
-	// Create the metric generators
+	// Create the metric generators.  Every action is checked, but later
+	// actions for the same ID reconfigure the one created by the first
+	// as they are reached.
+	created := map[string]generator.MetricGenerator{}
 	for _, action := range s.actions {
 		switch action.Type {
 		case "metricGenerator":
@@ -121,11 +220,20 @@ func (s *Script) Prepare(cfg *config.Config) error {
 			if err != nil {
 				return errors.New("Error creating metric generator: " + err.Error())
 			}
-			s.metricGenerators[action.ID] = g
+			if _, ok := created[action.ID]; !ok {
+				created[action.ID] = g
+			}
 		default:
 			// Ignore other types of actions for now
 		}
 	}
+	if err := generator.LinkGenerators(created); err != nil {
+		return fmt.Errorf("error linking metric generators: %w", err)
+	}
+	for id, g := range created {
+		s.metricGenerators[id] = &recordingGenerator{MetricGenerator: g, id: id}
+	}
+	s.assignShards()
 
 	return nil
 }
@@ -155,23 +263,83 @@ func run(ctx context.Context, cfg *config.Config, rscript *Script) error {
 	}
 
 	rs := state.NewRunState(rscript.duration, seed)
+	rs.AlignTimestamps = cfg.TimestampAlignment == "scrape"
+	rs.RunID = cfg.RunID
+	if rs.RunID == "auto" {
+		rs.RunID = uuid.NewString()
+	}
 	if cfg.WallclockStart.IsZero() {
 		cfg.WallclockStart = time.Now()
 	}
+	// Backfills and dry runs are not slept through, only paced.
+	realtime := !cfg.Dryrun && !cfg.Backfill
+	budget := newFootprint(cfg.Budget, !realtime)
+	var pace *pacer
+	if !realtime {
+		pace = newPacer(cfg.MaxExportRate, rs.Duration)
+	}
 	seconds := int64(rs.Duration.Seconds())
-	slog.Info("Running simulation", "duration", rs.Duration, "seed", seed, "wallclockStart", cfg.WallclockStart)
+	slog.Info("Running simulation", "duration", rs.Duration, "seed", seed, "wallclockStart", cfg.WallclockStart, "warmup", cfg.Warmup, "runID", rs.RunID)
+	for _, f := range rscript.onStart {
+		f()
+	}
+	defer func() {
+		for _, f := range rscript.onStop {
+			f()
+		}
+	}()
+ticks:
 	for now := range seconds + 1 {
+		if ctx.Err() != nil {
+			slog.Info("Simulation stopped", "tick", rs.Tick)
+			break
+		}
 		rs.Tick = time.Duration(now) * time.Second
-		rs.Wallclock = cfg.WallclockStart.Add(rs.Tick)
+		rs.Wallclock = cfg.WallclockStart.Add(rs.Tick - cfg.Warmup)
+		rs.MinFrequency = tierFrequency(cfg.ResolutionTiers, rs.Duration-rs.Tick)
+		rscript.mu.Lock()
+		rscript.tick = rs.Tick
+		exported := rscript.exported
 		err := tick(ctx, rscript, rs)
+		exported = rscript.exported - exported
+		rscript.mu.Unlock()
 		if err != nil {
 			return fmt.Errorf("error running script: %w", err)
 		}
-		if !cfg.Dryrun && rs.Tick < rscript.duration {
-			time.Sleep(1 * time.Second)
+		if budget != nil && rs.Tick%budgetCheckInterval == 0 {
+			budget.check(rs)
+		}
+		if pace != nil {
+			pace.wait(ctx, rs.Tick, exported)
+		}
+		if realtime && rs.Tick >= cfg.Warmup && rs.Tick < rscript.duration {
+			select {
+			case <-ctx.Done():
+				slog.Info("Simulation stopped", "tick", rs.Tick)
+				break ticks
+			case <-time.After(1 * time.Second):
+			}
+		}
+		for _, f := range rscript.onTick {
+			if err := f(ctx, rs.Tick); err != nil {
+				if ctx.Err() != nil {
+					slog.Info("Simulation stopped", "tick", rs.Tick)
+					break ticks
+				}
+				return fmt.Errorf("error running script: %w", err)
+			}
+		}
+	}
+	// Flush even when stopped, so what was generated is not lost.
+	flushCtx := context.WithoutCancel(ctx)
+	for _, e := range rscript.emitters {
+		if f, ok := e.(emitter.Flusher); ok {
+			if err := f.Flush(flushCtx, rs); err != nil {
+				return fmt.Errorf("error flushing emitter: %w", err)
+			}
 		}
 	}
-	return nil
+	return rscript.closeAttached()
 }
 
 func tick(ctx context.Context, rscript *Script, rs *state.RunState) error {
@@ -179,92 +347,207 @@ func tick(ctx context.Context, rscript *Script, rs *state.RunState) error {
 		if rscript.actions[rs.CurrentAction].At <= rs.Tick {
 			action := rscript.actions[rs.CurrentAction]
 			rs.CurrentAction++
+			var err error
 			switch action.Type {
-			case "metricGenerator":
-				g, ok := rscript.metricGenerators[action.ID]
-				if !ok {
-					return fmt.Errorf("metric generator not found: %s", action.ID)
-				}
-				err := g.Reconfigure(action.At, action.Spec)
-				if err != nil {
-					return fmt.Errorf("error reconfiguring metric generator: %s", action.ID)
-				}
-			case "metric":
-				if producer, ok := rscript.metricProducers[action.ID]; ok {
-					if err := producer.Reconfigure(rscript.metricGenerators, action.Spec); err != nil {
-						return fmt.Errorf("error reconfiguring metric exporter: %s", action.ID)
-					}
-				}
-				producer, err := metricproducer.CreateMetricExporter(rscript.metricGenerators, action.ID, action)
-				if err != nil {
-					return fmt.Errorf("error creating metric exporter: %v", err)
-				}
-				rscript.metricProducers[action.ID] = producer
-			case "disableMetric":
-				if producer, ok := rscript.metricProducers[action.ID]; ok {
-					producer.Disable()
-				} else {
-					return fmt.Errorf("disableMetric producer not found: %s", action.ID)
-				}
-			case "enableMetric":
-				if producer, ok := rscript.metricProducers[action.ID]; ok {
-					producer.Enable()
-				} else {
-					return fmt.Errorf("enableMetric producer not found: %s", action.ID)
-				}
-			case "traceRate":
-				slog.Info("trace rate", "at", action.At, "to", action.To, "rate", action.Spec["rate"])
-				producer, ok := rscript.traceProducers[action.ID]
-				if !ok {
-					return fmt.Errorf("trace producer not found: %s", action.ID)
-				}
-				rate, ok := action.Spec["rate"].(float64)
-				if !ok {
-					return fmt.Errorf("trace rate not found in action spec: %s", action.ID)
-				}
-				producer.SetRate(action.At, action.To, rs.Tick, rate)
-				if start, ok := action.Spec["start"].(float64); ok {
-					producer.SetStart(start)
-				}
+			case "addEmitter", "removeEmitter":
+				err = applyEmitterAction(ctx, rscript, rs, action)
 			default:
-				return fmt.Errorf("unknown action type: %s", action.Type)
+				err = applyAction(rscript, rs, action)
 			}
+			if err != nil {
+				return err
+			}
+			rscript.recordSpec(action)
 		}
 	}
 
-	if err := emitMetrics(ctx, rscript, rs); err != nil {
+	mb := signalbuilder.NewMetricsBuilder()
+	tb := signalbuilder.NewTracesBuilder()
+	if err := emitSessions(rscript, rs, tb, mb); err != nil {
+		return err
+	}
+
+	if err := emitMetrics(ctx, rscript, rs, mb); err != nil {
 		return fmt.Errorf("error emitting metrics: %w", err)
 	}
 
-	if err := emitTraces(ctx, rscript, rs); err != nil {
+	if err := emitTraces(ctx, rscript, rs, tb); err != nil {
 		return fmt.Errorf("error emitting traces: %w", err)
 	}
 
+	if rscript.entityEvents.Enabled {
+		if err := emitEntityEvents(ctx, rscript, rs); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func emitMetrics(ctx context.Context, rscript *Script, rs *state.RunState) error {
-	metricNames := make([]string, 0, len(rscript.metricProducers))
-	for name := range rscript.metricProducers {
-		metricNames = append(metricNames, name)
+// applyAction creates or changes the component an action names.
+func applyAction(rscript *Script, rs *state.RunState, action scriptaction.ScriptAction) error {
+	switch action.Type {
+	case "metricGenerator":
+		g, ok := rscript.metricGenerators[action.ID]
+		if !ok {
+			return fmt.Errorf("metric generator not found: %s", action.ID)
+		}
+		err := g.Reconfigure(action.At, action.Spec)
+		if err != nil {
+			return fmt.Errorf("error reconfiguring metric generator: %s", action.ID)
+		}
+	case "metric":
+		if producer, ok := rscript.metricProducers[action.ID]; ok {
+			if err := producer.Reconfigure(rscript.metricGenerators, action.Spec); err != nil {
+				return fmt.Errorf("error reconfiguring metric exporter: %s", action.ID)
+			}
+		}
+		producer, err := metricproducer.CreateMetricExporter(rscript.metricGenerators, action.ID, action)
+		if err != nil {
+			return fmt.Errorf("error creating metric exporter: %v", err)
+		}
+		rscript.metricProducers[action.ID] = producer
+	case "disableMetric":
+		if producer, ok := rscript.metricProducers[action.ID]; ok {
+			producer.Disable()
+		} else {
+			return fmt.Errorf("disableMetric producer not found: %s", action.ID)
+		}
+	case "enableMetric":
+		if producer, ok := rscript.metricProducers[action.ID]; ok {
+			producer.Enable()
+		} else {
+			return fmt.Errorf("enableMetric producer not found: %s", action.ID)
+		}
+	case "disableTrace":
+		if producer, ok := rscript.traceProducers[action.ID]; ok {
+			producer.Disable()
+		} else {
+			return fmt.Errorf("disableTrace producer not found: %s", action.ID)
+		}
+	case "enableTrace":
+		if producer, ok := rscript.traceProducers[action.ID]; ok {
+			producer.Enable()
+		} else {
+			return fmt.Errorf("enableTrace producer not found: %s", action.ID)
+		}
+	case "trace":
+		if _, ok := rscript.traceProducers[action.ID]; ok {
+			return fmt.Errorf("trace producer already exists: %s, use traceProducer to reconfigure it", action.ID)
+		}
+		producer, err := traceproducer.CreateTraceProducer(action)
+		if err != nil {
+			return fmt.Errorf("error creating trace producer: %s: %w", action.ID, err)
+		}
+		rscript.traceProducers[action.ID] = producer
+	case "rum":
+		if producer, ok := rscript.rumProducers[action.ID]; ok {
+			if err := producer.Reconfigure(action.Spec); err != nil {
+				return fmt.Errorf("error reconfiguring rum producer: %s: %w", action.ID, err)
+			}
+			break
+		}
+		producer, err := rumproducer.CreateRUMProducer(action)
+		if err != nil {
+			return fmt.Errorf("error creating rum producer: %s: %w", action.ID, err)
+		}
+		rscript.rumProducers[action.ID] = producer
+	case "traceProducer":
+		producer, ok := rscript.traceProducers[action.ID]
+		if !ok {
+			return fmt.Errorf("trace producer not found: %s", action.ID)
+		}
+		if err := producer.Reconfigure(rs.Tick, action.Spec); err != nil {
+			return fmt.Errorf("error reconfiguring trace producer: %s: %w", action.ID, err)
+		}
+	case "traceRate":
+		slog.Info("trace rate", "at", action.At, "to", action.To, "rate", action.Spec["rate"])
+		producer, ok := rscript.traceProducers[action.ID]
+		if !ok {
+			return fmt.Errorf("trace producer not found: %s", action.ID)
+		}
+		rate, ok := action.Spec["rate"].(float64)
+		if !ok {
+			return fmt.Errorf("trace rate not found in action spec: %s", action.ID)
+		}
+		producer.SetRate(action.At, action.To, rs.Tick, rate)
+		if start, ok := action.Spec["start"].(float64); ok {
+			producer.SetStart(start)
+		}
+	case "outage":
+		o, err := newOutage(action)
+		if err != nil {
+			return fmt.Errorf("error creating outage: %s: %w", action.ID, err)
+		}
+		rscript.outages = append(rscript.outages, o)
+	case "addEmitter", "removeEmitter":
+		// Attached and detached by tick, which can flush them; Prepare
+		// has checked their specs.
+	default:
+		return fmt.Errorf("unknown action type: %s", action.Type)
 	}
-	mb := signalbuilder.NewMetricsBuilder()
+	return nil
+}
+
+// emitSessions runs the RUM producers.  Each producer, here and in
+// emitMetrics and emitTraces, draws from its own random stream for the
+// tick, so the order they run in does not matter.
+func emitSessions(rscript *Script, rs *state.RunState, tb *signalbuilder.TracesBuilder, mb *signalbuilder.MetricsBuilder) error {
+	for _, name := range slices.Sorted(maps.Keys(rscript.rumProducers)) {
+		if !rscript.owns("rum/" + name) {
+			continue
+		}
+		rs.Reseed("rum/" + name)
+		if err := rscript.rumProducers[name].Emit(rs, tb, mb); err != nil {
+			return fmt.Errorf("error emitting rum session: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func emitMetrics(ctx context.Context, rscript *Script, rs *state.RunState, mb *signalbuilder.MetricsBuilder) error {
+	// Metrics that read other generators, such as through an
+	// expression, run after those that feed them, so they see this
+	// tick's values.
+	metricNames := slices.Sorted(maps.Keys(rscript.metricProducers))
+	slices.SortStableFunc(metricNames, func(a, b string) int {
+		return cmp.Compare(rscript.readsGenerators(a), rscript.readsGenerators(b))
+	})
 	for _, name := range metricNames {
 		producer, ok := rscript.metricProducers[name]
 		if !ok {
 			return fmt.Errorf("metric producer not found: %s", name)
 		}
+		if !rscript.owns("metric/" + name) {
+			continue
+		}
+		rs.Reseed("metric/" + name)
 		err := producer.Emit(rscript.metricGenerators, rs, mb)
 		if err != nil {
 			return fmt.Errorf("error emitting metric: %s", name)
 		}
 	}
+	// Recorded once every producer has run, so a follow generator sees
+	// the same values whatever order producers ran in.
+	for _, name := range metricNames {
+		if at, v, ok := rscript.metricProducers[name].LastEmit(); ok && at == rs.Tick {
+			rs.RecordMetric(name, at, v)
+		}
+	}
 	md := mb.Build()
+	rscript.applyDomainsToMetrics(rs.Tick, md)
+	for _, rm := range md.ResourceMetrics().All() {
+		rscript.identities.Apply(rm.Resource().Attributes())
+		if rs.RunID != "" {
+			rm.Resource().Attributes().PutStr(RunIDAttribute, rs.RunID)
+		}
+	}
 	// if md.DataPointCount() > 0 {
 	// 	slog.Info("Emitting metrics", "count", md.DataPointCount())
 	// }
 
 	if rs.Tick >= rscript.from {
+		rscript.exported += md.DataPointCount()
 		for _, emitter := range rscript.emitters {
 			if err := emitter.EmitMetrics(ctx, rs, md); err != nil {
 				return fmt.Errorf("error emitting metric: %w", err)
@@ -275,15 +558,44 @@ func emitMetrics(ctx context.Context, rscript *Script, rs *state.RunState) error
 	return nil
 }
 
-func emitTraces(ctx context.Context, rscript *Script, rs *state.RunState) error {
-	tb := signalbuilder.NewTracesBuilder()
+// readsGenerators returns 1 if the named metric runs a generator that
+// reads others, and 0 if not.
+func (s *Script) readsGenerators(name string) int {
+	for _, id := range s.metricProducers[name].GeneratorIDs() {
+		g := s.metricGenerators[id]
+		if r, ok := g.(*recordingGenerator); ok {
+			g = r.MetricGenerator
+		}
+		if _, ok := g.(generator.Linker); ok {
+			return 1
+		}
+	}
+	return 0
+}
+
+func emitTraces(ctx context.Context, rscript *Script, rs *state.RunState, tb *signalbuilder.TracesBuilder) error {
+	ld := plog.NewLogs()
 	for name, producer := range rscript.traceProducers {
+		if !rscript.owns("trace/" + name) {
+			continue
+		}
+		rs.Reseed("trace/" + name)
 		err := producer.Emit(rs, tb)
 		if err != nil {
 			return fmt.Errorf("error emitting trace: %s", name)
 		}
+		if lp, ok := producer.(traceproducer.LogProducer); ok {
+			lp.TakeLogs().ResourceLogs().MoveAndAppendTo(ld.ResourceLogs())
+		}
 	}
 	td := tb.Build()
+	rscript.applyDomainsToTraces(rs.Tick, td)
+	for _, rspans := range td.ResourceSpans().All() {
+		rscript.identities.Apply(rspans.Resource().Attributes())
+		if rs.RunID != "" {
+			rspans.Resource().Attributes().PutStr(RunIDAttribute, rs.RunID)
+		}
+	}
 	// if td.SpanCount() > 0 {
 	// 	rootCount := 0
 	// 	for _, rspan := range td.ResourceSpans().All() {
@@ -299,6 +611,7 @@ func emitTraces(ctx context.Context, rscript *Script, rs *state.RunState) error
 	// }
 
 	if rs.Tick >= rscript.from {
+		rscript.exported += td.SpanCount()
 		for _, emitter := range rscript.emitters {
 			if err := emitter.EmitTraces(ctx, rs, td); err != nil {
 				return fmt.Errorf("error emitting trace: %w", err)
@@ -306,5 +619,27 @@ func emitTraces(ctx context.Context, rscript *Script, rs *state.RunState) error
 		}
 	}
 
+	return emitTraceLogs(ctx, rscript, rs, ld)
+}
+
+// emitTraceLogs sends the log records the trace producers made this
+// tick, such as SLA breaches, to the emitters that send logs.
+func emitTraceLogs(ctx context.Context, rscript *Script, rs *state.RunState, ld plog.Logs) error {
+	for _, rl := range ld.ResourceLogs().All() {
+		placeInDomain(rscript.failureDomains, rl.Resource().Attributes())
+		rscript.identities.Apply(rl.Resource().Attributes())
+		if rs.RunID != "" {
+			rl.Resource().Attributes().PutStr(RunIDAttribute, rs.RunID)
+		}
+	}
+	if rs.Tick < rscript.from || ld.LogRecordCount() == 0 {
+		return nil
+	}
+	rscript.exported += ld.LogRecordCount()
+	for _, e := range rscript.emitters {
+		if err := emitter.EmitLogs(ctx, e, rs, ld); err != nil {
+			return fmt.Errorf("error emitting trace logs: %w", err)
+		}
+	}
 	return nil
 }
@@ -15,11 +15,29 @@
 package script
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/emitter"
 	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
 )
 
 func TestCalculateDuration(t *testing.T) {
@@ -105,3 +123,1128 @@ func TestCalculateDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestTraceAction(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "checkout",
+		Type: "trace",
+		To:   10 * time.Second,
+		Spec: map[string]any{
+			"rate": 20.0,
+			"exemplar": map[string]any{
+				"name":     "POST /checkout",
+				"kind":     "server",
+				"duration": "120ms",
+				"children": []any{
+					map[string]any{"name": "SELECT orders", "kind": "client", "duration": "30ms"},
+				},
+			},
+		},
+	})
+	counter := emitter.NewCountingEmitter(io.Discard)
+	rscript.AddEmitter(counter)
+
+	cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := counter.Traces().Items
+	if spans == 0 || spans%2 != 0 {
+		t.Errorf("expected a non-zero, even number of spans, got %d", spans)
+	}
+}
+
+func TestTraceAction_MissingExemplar(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "checkout",
+		Type: "trace",
+		To:   time.Second,
+		Spec: map[string]any{"rate": 1.0},
+	})
+	cfg := &config.Config{Dryrun: true, Seed: 1}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err == nil {
+		t.Error("expected an error for a trace without an exemplar")
+	}
+}
+
+func TestTraceAction_Duplicate(t *testing.T) {
+	rscript := NewScript()
+	for _, at := range []time.Duration{0, 2 * time.Second} {
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "checkout",
+			Type: "trace",
+			At:   at,
+			To:   5 * time.Second,
+			Spec: map[string]any{
+				"rate":     1.0,
+				"exemplar": map[string]any{"name": "POST /checkout", "duration": "10ms"},
+			},
+		})
+	}
+	cfg := &config.Config{Dryrun: true, Seed: 1}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err == nil {
+		t.Error("expected an error for a repeated trace name")
+	}
+}
+
+func TestHooksAndCancel(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "checkout",
+		Type: "trace",
+		To:   time.Hour,
+		Spec: map[string]any{
+			"rate":     5.0,
+			"exemplar": map[string]any{"name": "POST /checkout", "duration": "10ms"},
+		},
+	})
+	counter := emitter.NewCountingEmitter(io.Discard)
+	rscript.AddEmitter(counter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var events []string
+	rscript.OnStart(func() { events = append(events, "start") })
+	rscript.OnStop(func() { events = append(events, "stop") })
+
+	// Not a dry run, so the run sleeps between ticks until cancelled.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	cfg := &config.Config{Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+	if err := Simulate(ctx, cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0] != "start" || events[1] != "stop" {
+		t.Errorf("expected start then stop hooks, got %v", events)
+	}
+	if counter.Traces().Items == 0 {
+		t.Error("expected the first tick's spans before the run was cancelled")
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_base",
+		Type: "metricGenerator",
+		Spec: map[string]any{"type": "constant", "value": 40.0},
+	})
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu",
+		Type: "metric",
+		To:   20 * time.Second,
+		Spec: map[string]any{"type": "gauge", "generators": []any{"cpu_base"}},
+	})
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_base",
+		Type: "metricGenerator",
+		At:   10 * time.Second,
+		Spec: map[string]any{"type": "constant", "value": 60.0},
+	})
+	cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	rscript.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugPath+"?format=json", nil))
+	var snap DebugSnapshot
+	if err := json.NewDecoder(rec.Body).Decode(&snap); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snap.Generators) != 1 || len(snap.Metrics) != 1 {
+		t.Fatalf("expected one generator and one metric, got %+v", snap)
+	}
+	g := snap.Generators[0]
+	if g.Type != "constant" || g.Spec["value"] != 60.0 || g.Value == nil || *g.Value != 60 {
+		t.Errorf("unexpected generator status: %+v", g)
+	}
+	m := snap.Metrics[0]
+	if m.Value == nil || *m.Value != 60 || m.LastEmit == nil || *m.LastEmit != 20*time.Second {
+		t.Errorf("unexpected metric status: %+v", m)
+	}
+
+	rec = httptest.NewRecorder()
+	rscript.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugPath, nil))
+	if body := rec.Body.String(); !strings.Contains(body, "cpu_base") || !strings.Contains(body, "Tick 20s") {
+		t.Errorf("debug page is missing the generator or tick:\n%s", body)
+	}
+}
+
+func TestGeneratorStats(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_ramp",
+		Type: "metricGenerator",
+		Spec: map[string]any{"type": "ramp", "start": 0.0, "target": 10.0, "duration": "10s"},
+	})
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu",
+		Type: "metric",
+		To:   10 * time.Second,
+		Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_ramp"}},
+	})
+	cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := rscript.GeneratorStats()
+	if len(stats) != 1 {
+		t.Fatalf("expected stats for one generator, got %+v", stats)
+	}
+	st := stats[0]
+	// The gauge is created on the second tick, one action per tick, so
+	// the ramp emits 1, 2, ... 10.
+	if st.ID != "cpu_ramp" || st.Type != "ramp" || st.Count != 10 || st.Min != 1 || st.Max != 10 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+	if math.Abs(st.Mean-5.5) > 1e-9 || math.Abs(st.StdDev-math.Sqrt(8.25)) > 1e-9 {
+		t.Errorf("expected mean 5.5 and stddev sqrt(8.25), got %+v", st)
+	}
+
+	var b strings.Builder
+	if err := rscript.WriteGeneratorStats(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(b.String(), "cpu_ramp") {
+		t.Errorf("stats table is missing the generator:\n%s", b.String())
+	}
+}
+
+func TestDumpFormat(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_base",
+		Type: "metricGenerator",
+		Spec: map[string]any{"type": "constant", "value": 40.0},
+	})
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu",
+		Type: "metric",
+		To:   2 * time.Minute,
+		Spec: map[string]any{"type": "gauge"},
+	})
+	rscript.AddAction(scriptaction.ScriptAction{ID: "cpu", Type: "disableMetric", At: time.Minute})
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_base",
+		Type: "metricGenerator",
+		At:   90 * time.Second,
+		Spec: map[string]any{"type": "constant", "value": 60.0},
+	})
+
+	var b strings.Builder
+	if err := rscript.DumpFormat(&b, "mermaid"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"gantt\n",
+		"  section cpu\n    metric gauge :0, 120\n    disableMetric :60, 120\n",
+		"  section cpu_base\n    metricGenerator constant :0, 90\n    metricGenerator constant :90, 120\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("mermaid output is missing %q:\n%s", want, b.String())
+		}
+	}
+
+	b.Reset()
+	if err := rscript.DumpFormat(&b, "yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(b.String(), "- id: cpu_base\n  at: 0s\n") || !strings.Contains(b.String(), "to: 2m0s") {
+		t.Errorf("unexpected yaml output:\n%s", b.String())
+	}
+
+	if err := rscript.DumpFormat(io.Discard, "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestDumpResolved(t *testing.T) {
+	// DumpResolved uses the script up, so each dump gets a new one.
+	newScript := func() *Script {
+		rscript := NewScript()
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu_base",
+			Type: "metricGenerator",
+			Spec: map[string]any{"type": "smoothNoise", "amplitude": 4.0},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu",
+			Type: "metric",
+			At:   time.Second,
+			Spec: map[string]any{"type": "gauge", "generators": []any{"cpu_base"}},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu",
+			Type: "metric",
+			At:   30 * time.Second,
+			Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_base"}},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu_base",
+			Type: "metricGenerator",
+			At:   time.Minute,
+			Spec: map[string]any{"type": "smoothNoise", "period": "5m"},
+		})
+		return rscript
+	}
+
+	var b strings.Builder
+	cfg := &config.Config{Seed: 1}
+	if err := newScript().DumpResolved(&b, cfg, "json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected four actions, got:\n%s", b.String())
+	}
+	for i, want := range []string{
+		// The default period is filled in.
+		`"spec":{"type":"smoothNoise","target":0,"amplitude":4,"period":60000000000}`,
+		// The gauge's default frequency is filled in.
+		`"frequency":10000000000,`,
+		// Each action shows the spec as it was then, not at the end.
+		`"frequency":1000000000,`,
+		// The redefinition keeps the earlier amplitude.
+		`"spec":{"type":"smoothNoise","target":0,"amplitude":4,"period":300000000000}`,
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("action %d is missing %s:\n%s", i, want, lines[i])
+		}
+	}
+
+	b.Reset()
+	if err := newScript().DumpResolved(&b, cfg, "yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if i := strings.Index(b.String(), "frequency: 10s"); i < 0 || !strings.Contains(b.String()[i:], "frequency: 1s") {
+		t.Errorf("expected the 10s then the 1s frequency, got:\n%s", b.String())
+	}
+
+	if err := NewScript().DumpResolved(io.Discard, cfg, "mermaid"); err == nil {
+		t.Error("expected an error for mermaid")
+	}
+}
+
+// runIDEmitter collects the run IDs found on emitted resources.
+type runIDEmitter struct {
+	ids map[string]int
+}
+
+func (e *runIDEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	for _, rm := range md.ResourceMetrics().All() {
+		if v, ok := rm.Resource().Attributes().Get(RunIDAttribute); ok {
+			e.ids[v.Str()]++
+		}
+	}
+	return nil
+}
+
+func (e *runIDEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	for _, rs := range td.ResourceSpans().All() {
+		if v, ok := rs.Resource().Attributes().Get(RunIDAttribute); ok {
+			e.ids[v.Str()]++
+		}
+	}
+	return nil
+}
+
+func TestRunID(t *testing.T) {
+	for _, runID := range []string{"test-run", "auto"} {
+		rscript := NewScript()
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu_base",
+			Type: "metricGenerator",
+			Spec: map[string]any{"type": "constant", "value": 1.0},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu",
+			Type: "metric",
+			Spec: map[string]any{"type": "gauge", "generators": []any{"cpu_base"}},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "checkout",
+			Type: "trace",
+			To:   5 * time.Second,
+			Spec: map[string]any{"rate": 5.0, "exemplar": map[string]any{"name": "GET /", "duration": "10ms"}},
+		})
+		e := &runIDEmitter{ids: map[string]int{}}
+		rscript.AddEmitter(e)
+
+		cfg := &config.Config{Dryrun: true, Seed: 1, Duration: 5 * time.Second, RunID: runID}
+		if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(e.ids) != 1 {
+			t.Fatalf("expected one run ID, got %v", e.ids)
+		}
+		for id := range e.ids {
+			if runID == "auto" {
+				if _, err := uuid.Parse(id); err != nil {
+					t.Errorf("expected a UUID, got %q", id)
+				}
+			} else if id != runID {
+				t.Errorf("expected run ID %q, got %q", runID, id)
+			}
+		}
+	}
+}
+
+// gaugeEmitter collects the values emitted for one gauge.
+type gaugeEmitter struct {
+	name   string
+	values []float64
+}
+
+func (e *gaugeEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	for _, rm := range md.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				if m.Name() != e.name {
+					continue
+				}
+				for _, dp := range m.Gauge().DataPoints().All() {
+					e.values = append(e.values, dp.DoubleValue())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (e *gaugeEmitter) EmitTraces(context.Context, *state.RunState, ptrace.Traces) error {
+	return nil
+}
+
+func TestNoiseReproducibleAcrossProducers(t *testing.T) {
+	cpuValues := func(memFrequency string) []float64 {
+		rscript := NewScript()
+		for _, id := range []string{"cpu_noise", "mem_noise"} {
+			rscript.AddAction(scriptaction.ScriptAction{
+				ID:   id,
+				Type: "metricGenerator",
+				Spec: map[string]any{"type": "normalNoise", "target": 50.0, "variation": 20.0},
+			})
+		}
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu",
+			Type: "metric",
+			Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_noise"}},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "mem",
+			Type: "metric",
+			To:   30 * time.Second,
+			Spec: map[string]any{"type": "gauge", "frequency": memFrequency, "generators": []any{"mem_noise"}},
+		})
+		e := &gaugeEmitter{name: "cpu"}
+		rscript.AddEmitter(e)
+		cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+		if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return e.values
+	}
+
+	a, b := cpuValues("1s"), cpuValues("7s")
+	if len(a) == 0 || !slices.Equal(a, b) {
+		t.Errorf("changing mem's frequency changed cpu:\n%v\n%v", a, b)
+	}
+}
+
+func TestNoiseReproducibleAcrossGenerators(t *testing.T) {
+	noiseStats := func(chain []any) GeneratorStats {
+		rscript := NewScript()
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu_jitter",
+			Type: "metricGenerator",
+			Spec: map[string]any{"type": "uniformNoise", "min": 0.0, "max": 1.0},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu_noise",
+			Type: "metricGenerator",
+			Spec: map[string]any{"type": "normalNoise", "target": 50.0, "variation": 20.0},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu",
+			Type: "metric",
+			To:   30 * time.Second,
+			Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": chain},
+		})
+		cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+		if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, st := range rscript.GeneratorStats() {
+			if st.ID == "cpu_noise" {
+				return st
+			}
+		}
+		t.Fatal("no stats for cpu_noise")
+		return GeneratorStats{}
+	}
+
+	// Adding a generator that draws before cpu_noise in the chain leaves
+	// what cpu_noise contributes unchanged.
+	a := noiseStats([]any{"cpu_noise"})
+	b := noiseStats([]any{"cpu_jitter", "cpu_noise"})
+	if a.Count == 0 || a != b {
+		t.Errorf("adding cpu_jitter changed cpu_noise:\n%+v\n%+v", a, b)
+	}
+}
+
+func TestExpressionReadsSourceValue(t *testing.T) {
+	run := func(source map[string]any, withErrors bool) (traffic, errs []float64) {
+		rscript := NewScript()
+		rscript.AddAction(scriptaction.ScriptAction{ID: "traffic_gen", Type: "metricGenerator", Spec: source})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "traffic",
+			Type: "metric",
+			To:   30 * time.Second,
+			Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"traffic_gen"}},
+		})
+		if withErrors {
+			rscript.AddAction(scriptaction.ScriptAction{
+				ID:   "errors_gen",
+				Type: "metricGenerator",
+				Spec: map[string]any{"type": "expression", "expression": "gen(traffic_gen) * 0.5"},
+			})
+			rscript.AddAction(scriptaction.ScriptAction{
+				ID:   "errors",
+				Type: "metric",
+				To:   30 * time.Second,
+				Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"errors_gen"}},
+			})
+		}
+		te, ee := &gaugeEmitter{name: "traffic"}, &gaugeEmitter{name: "errors"}
+		rscript.AddEmitter(te)
+		rscript.AddEmitter(ee)
+		cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+		if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return te.values, ee.values
+	}
+
+	noise := map[string]any{"type": "normalNoise", "target": 100.0, "variation": 20.0}
+	traffic, errs := run(noise, true)
+	if len(errs) == 0 || len(errs) > len(traffic) {
+		t.Fatalf("expected errors alongside traffic, got %d and %d points", len(errs), len(traffic))
+	}
+	// errors starts later, so compare its points with the last ones of
+	// traffic.
+	offset := len(traffic) - len(errs)
+	for i, v := range errs {
+		if want := 0.5 * traffic[offset+i]; math.Abs(v-want) > 1e-9 {
+			t.Fatalf("point %d: errors %v is not half of traffic %v", i, v, traffic[offset+i])
+		}
+	}
+
+	// Reading a stateful source does not advance it a second time.
+	walk := map[string]any{"type": "randomWalk", "target": 100.0, "stepSize": 5.0, "elasticity": 0.1}
+	alone, _ := run(walk, false)
+	read, _ := run(walk, true)
+	if !slices.Equal(alone, read) {
+		t.Errorf("reading traffic_gen changed traffic:\n%v\n%v", alone, read)
+	}
+}
+
+func TestFollowMetric(t *testing.T) {
+	rscript := NewScript()
+	for _, action := range []scriptaction.ScriptAction{
+		{ID: "rps_level", Type: "metricGenerator", Spec: map[string]any{"type": "constant", "value": 100.0}},
+		{ID: "rps_level", Type: "metricGenerator", At: 5 * time.Second, Spec: map[string]any{"type": "constant", "value": 200.0}},
+		{ID: "cpu_follow", Type: "metricGenerator", Spec: map[string]any{"type": "follow", "metric": "rps", "factor": 0.5, "lag": "2s"}},
+		{ID: "rps", Type: "metric", To: 10 * time.Second, Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"rps_level"}}},
+		{ID: "cpu", Type: "metric", To: 10 * time.Second, Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_follow"}}},
+	} {
+		rscript.AddAction(action)
+	}
+	e := &gaugeEmitter{name: "cpu"}
+	rscript.AddEmitter(e)
+	rps := &gaugeEmitter{name: "rps"}
+	rscript.AddEmitter(rps)
+	cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// cpu lags rps by 2s: nothing for the first two ticks, then half of
+	// whatever rps emitted two ticks before.
+	if want := []float64{100, 100, 100, 100, 200, 200, 200, 200, 200, 200}; !slices.Equal(rps.values, want) {
+		t.Fatalf("unexpected rps values: %v", rps.values)
+	}
+	want := []float64{0, 0, 50, 50, 50, 50, 100, 100, 100, 100}
+	if !slices.Equal(e.values, want) {
+		t.Errorf("expected cpu to follow rps:\nwant %v\ngot  %v", want, e.values)
+	}
+}
+
+func TestResolutionTiers(t *testing.T) {
+	tiers := []config.ResolutionTier{
+		{OlderThan: 30 * time.Minute, Frequency: 5 * time.Minute},
+		{OlderThan: 50 * time.Minute, Frequency: 10 * time.Minute},
+	}
+	for _, tt := range []struct {
+		age  time.Duration
+		want time.Duration
+	}{
+		{0, 0},
+		{30 * time.Minute, 5 * time.Minute},
+		{49 * time.Minute, 5 * time.Minute},
+		{time.Hour, 10 * time.Minute},
+	} {
+		if got := tierFrequency(tiers, tt.age); got != tt.want {
+			t.Errorf("tierFrequency(%s) = %s, want %s", tt.age, got, tt.want)
+		}
+	}
+
+	run := func(tiers []config.ResolutionTier) (int, error) {
+		rscript := NewScript()
+		rscript.AddAction(scriptaction.ScriptAction{ID: "level", Type: "metricGenerator", Spec: map[string]any{"type": "constant", "value": 1.0}})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "rps",
+			Type: "metric",
+			To:   time.Hour,
+			Spec: map[string]any{"type": "gauge", "frequency": "10s", "generators": []any{"level"}},
+		})
+		e := &gaugeEmitter{name: "rps"}
+		rscript.AddEmitter(e)
+		cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0), ResolutionTiers: tiers}
+		err := Simulate(context.Background(), cfg, rscript, 0)
+		return len(e.values), err
+	}
+	n, err := run(tiers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The first datapoint at 10m, then every 5m to 30m, then every 10s.
+	if want := 1 + 4 + 180; n != want {
+		t.Errorf("expected %d datapoints, got %d", want, n)
+	}
+	if _, err := run([]config.ResolutionTier{{OlderThan: time.Hour}}); err == nil {
+		t.Error("expected an error for a tier without a frequency")
+	}
+}
+
+func TestFootprintBudget(t *testing.T) {
+	if newFootprint(config.Budget{}, false) != nil {
+		t.Error("expected no footprint check without limits")
+	}
+	if newFootprint(config.Budget{MaxCPU: 1}, true) != nil {
+		t.Error("expected the CPU limit to be dropped in dry-run mode")
+	}
+	if mem, _ := readRuntimeFootprint(); mem == 0 {
+		t.Error("expected the runtime to report the memory it holds")
+	}
+
+	var mem uint64
+	var cpu float64
+	wall := time.Unix(0, 0)
+	f := newFootprint(config.Budget{MaxMemoryMB: 100, MaxCPU: 0.5}, false)
+	f.read = func() (uint64, float64) { return mem, cpu }
+	f.now = func() time.Time { return wall }
+	f.lastCPU, f.lastWall = 0, wall
+
+	rs := state.NewRunState(time.Minute, 1)
+	step := func(memMB uint64, cores float64) {
+		wall = wall.Add(10 * time.Second)
+		mem = memMB << 20
+		cpu += 10 * cores
+		f.check(rs)
+	}
+
+	step(50, 0.1)
+	if rs.Degrade != 0 {
+		t.Fatalf("expected no degrading within budget, got level %d", rs.Degrade)
+	}
+	step(150, 0.1)
+	if rs.Degrade != 1 || rs.DegradeFactor() != 2 {
+		t.Fatalf("expected level 1 over the memory budget, got %d", rs.Degrade)
+	}
+	step(50, 0.9)
+	if rs.Degrade != 2 {
+		t.Fatalf("expected level 2 over the CPU budget, got %d", rs.Degrade)
+	}
+	for range 10 {
+		step(500, 4)
+	}
+	if rs.Degrade != maxDegrade {
+		t.Errorf("expected degrading to stop at %d, got %d", maxDegrade, rs.Degrade)
+	}
+}
+
+func TestWarmupTooLong(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_base",
+		Type: "metricGenerator",
+		Spec: map[string]any{"type": "constant", "value": 1.0},
+	})
+	cfg := &config.Config{Dryrun: true, Seed: 1, Duration: 10 * time.Second, Warmup: 10 * time.Second}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err == nil {
+		t.Error("expected an error for a warm-up as long as the run")
+	} else if !strings.Contains(err.Error(), "warmup") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPacer(t *testing.T) {
+	wall := time.Unix(0, 0)
+	var slept time.Duration
+	p := newPacer(100, time.Hour)
+	p.now = func() time.Time { return wall }
+	p.sleep = func(_ context.Context, d time.Duration) {
+		slept += d
+		wall = wall.Add(d)
+	}
+	p.start, p.next, p.lastReport = wall, wall, wall
+
+	// 250 items at 100/s hold the run for 2.5s.
+	p.wait(context.Background(), time.Second, 250)
+	if slept != 2500*time.Millisecond {
+		t.Fatalf("expected 2.5s of sleep, got %s", slept)
+	}
+	// Time spent idle is not saved up for a later burst.
+	wall = wall.Add(time.Minute)
+	slept = 0
+	p.wait(context.Background(), 2*time.Second, 100)
+	if slept != time.Second {
+		t.Errorf("expected 1s of sleep after an idle stretch, got %s", slept)
+	}
+
+	unlimited := newPacer(0, time.Hour)
+	unlimited.sleep = func(context.Context, time.Duration) { t.Error("expected no sleep without a rate") }
+	unlimited.wait(context.Background(), time.Second, 1e6)
+}
+
+func TestETA(t *testing.T) {
+	if got := eta(10*time.Second, 0, time.Hour); got != 0 {
+		t.Errorf("expected no ETA before the first tick, got %s", got)
+	}
+	if got := eta(time.Minute, 15*time.Minute, time.Hour); got != 3*time.Minute {
+		t.Errorf("expected 3m left a quarter of the way in, got %s", got)
+	}
+	if got := progressPercent(15*time.Minute, time.Hour); got != 25 {
+		t.Errorf("expected 25%%, got %d", got)
+	}
+}
+
+func TestMaxExportRateNegative(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_base",
+		Type: "metricGenerator",
+		Spec: map[string]any{"type": "constant", "value": 1.0},
+	})
+	cfg := &config.Config{Dryrun: true, Seed: 1, Duration: 10 * time.Second, MaxExportRate: -1}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err == nil {
+		t.Error("expected an error for a negative maxExportRate")
+	}
+}
+
+// podUIDEmitter collects the pod UIDs found on emitted resources, by
+// signal.
+type podUIDEmitter struct {
+	uids map[string]string
+}
+
+func (e *podUIDEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	for _, rm := range md.ResourceMetrics().All() {
+		if v, ok := rm.Resource().Attributes().Get("k8s.pod.uid"); ok {
+			e.uids["metrics"] = v.Str()
+		}
+	}
+	return nil
+}
+
+func (e *podUIDEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	for _, rs := range td.ResourceSpans().All() {
+		if v, ok := rs.Resource().Attributes().Get("k8s.pod.uid"); ok {
+			e.uids["traces"] = v.Str()
+		}
+	}
+	return nil
+}
+
+func TestResourceIdentity(t *testing.T) {
+	pod := map[string]any{"k8s.namespace.name": "shop", "k8s.pod.name": "web-1"}
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_base",
+		Type: "metricGenerator",
+		Spec: map[string]any{"type": "constant", "value": 1.0},
+	})
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu",
+		Type: "metric",
+		Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_base"}, "attributes": map[string]any{"resource": pod}},
+	})
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "checkout",
+		Type: "trace",
+		To:   5 * time.Second,
+		Spec: map[string]any{"rate": 5.0, "exemplar": map[string]any{"name": "GET /", "duration": "10ms", "resourceAttributes": pod}},
+	})
+	e := &podUIDEmitter{uids: map[string]string{}}
+	rscript.AddEmitter(e)
+
+	cfg := &config.Config{Dryrun: true, Seed: 1, Duration: 5 * time.Second}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.uids["metrics"] == "" || e.uids["metrics"] != e.uids["traces"] {
+		t.Errorf("expected metrics and traces to share one pod UID, got %v", e.uids)
+	}
+}
+
+// logEmitter collects the event names and timestamps of emitted logs.
+type logEmitter struct {
+	events []string
+}
+
+func (e *logEmitter) EmitMetrics(context.Context, *state.RunState, pmetric.Metrics) error {
+	return nil
+}
+
+func (e *logEmitter) EmitTraces(context.Context, *state.RunState, ptrace.Traces) error {
+	return nil
+}
+
+func (e *logEmitter) EmitLogs(_ context.Context, _ *state.RunState, ld plog.Logs) error {
+	for _, rl := range ld.ResourceLogs().All() {
+		for _, sl := range rl.ScopeLogs().All() {
+			for _, lr := range sl.LogRecords().All() {
+				e.events = append(e.events, fmt.Sprintf("%s@%d", lr.EventName(), lr.Timestamp().AsTime().Unix()))
+			}
+		}
+	}
+	return nil
+}
+
+func TestEntityEvents(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu_base",
+		Type: "metricGenerator",
+		Spec: map[string]any{"type": "constant", "value": 1.0},
+	})
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "cpu",
+		Type: "metric",
+		To:   5 * time.Second,
+		Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_base"}, "attributes": map[string]any{"resource": map[string]any{"k8s.pod.name": "web-1"}}},
+	})
+	e := &logEmitter{}
+	rscript.AddEmitter(e)
+
+	cfg := &config.Config{
+		Dryrun:         true,
+		Seed:           1,
+		Duration:       20 * time.Second,
+		WallclockStart: time.Unix(1000, 0),
+		EntityEvents:   config.EntityEvents{Enabled: true, DeleteAfter: 3 * time.Second},
+	}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The pod's series runs from 1s to 5s.
+	want := []string{"k8s.pod.created@1001", "k8s.pod.deleted@1005"}
+	if !slices.Equal(e.events, want) {
+		t.Errorf("unexpected entity events:\nwant %v\ngot  %v", want, e.events)
+	}
+}
+
+func TestSLABreachLogs(t *testing.T) {
+	rscript := NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{
+		ID:   "checkout",
+		Type: "trace",
+		To:   3 * time.Second,
+		Spec: map[string]any{
+			"rate": 2.0,
+			"sla":  map[string]any{"threshold": "10ms", "log": true},
+			"exemplar": map[string]any{
+				"name": "GET /checkout", "kind": "server", "duration": "500ms",
+				"resourceAttributes": map[string]any{"service.name": "frontend"},
+			},
+		},
+	})
+	e := &logEmitter{}
+	rscript.AddEmitter(e)
+
+	cfg := &config.Config{
+		Dryrun:         true,
+		Seed:           1,
+		Duration:       5 * time.Second,
+		WallclockStart: time.Unix(1000, 0),
+	}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(e.events) == 0 {
+		t.Fatal("expected SLA breach logs")
+	}
+	for _, event := range e.events {
+		if !strings.HasPrefix(event, "sla.breach@") {
+			t.Errorf("unexpected log %s", event)
+		}
+	}
+}
+
+// outageEmitter records, each tick, which metrics were emitted and
+// whether the root spans failed.
+type outageEmitter struct {
+	metrics map[string][]time.Duration
+	errored map[time.Duration]string
+}
+
+func (e *outageEmitter) EmitMetrics(_ context.Context, rs *state.RunState, md pmetric.Metrics) error {
+	for _, rm := range md.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				e.metrics[m.Name()] = append(e.metrics[m.Name()], rs.Tick)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *outageEmitter) EmitTraces(_ context.Context, rs *state.RunState, td ptrace.Traces) error {
+	for _, rspans := range td.ResourceSpans().All() {
+		for _, ss := range rspans.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				if span.ParentSpanID().IsEmpty() && span.Status().Code() == ptrace.StatusCodeError {
+					e.errored[rs.Tick] = span.Status().Message()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestOutage(t *testing.T) {
+	rscript := NewScript()
+	for _, action := range []scriptaction.ScriptAction{
+		{ID: "cpu_base", Type: "metricGenerator", Spec: map[string]any{"type": "constant", "value": 1.0}},
+		{ID: "cpu_a", Type: "metric", Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_base"},
+			"attributes": map[string]any{"resource": map[string]any{"cloud.availability_zone": "zone-a"}}}},
+		// Placed in zone-b by its node.
+		{ID: "cpu_b", Type: "metric", Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_base"},
+			"attributes": map[string]any{"resource": map[string]any{"k8s.node.name": "node-3"}}}},
+		{ID: "checkout", Type: "trace", Spec: map[string]any{"rate": 2.0, "exemplar": map[string]any{
+			"name": "GET /checkout", "kind": "server", "duration": "20ms",
+			"resourceAttributes": map[string]any{"service.name": "frontend", "cloud.availability_zone": "zone-a"},
+			"children": []any{map[string]any{
+				"name": "charge", "kind": "internal", "duration": "5ms",
+				"resourceAttributes": map[string]any{"service.name": "payments", "k8s.node.name": "node-3"},
+			}},
+		}}},
+		{ID: "zone_b_down", Type: "outage", At: 5 * time.Second, To: 8 * time.Second, Spec: map[string]any{"zone": "zone-b"}},
+	} {
+		rscript.AddAction(action)
+	}
+	e := &outageEmitter{metrics: map[string][]time.Duration{}, errored: map[time.Duration]string{}}
+	rscript.AddEmitter(e)
+
+	cfg := &config.Config{
+		Dryrun:   true,
+		Seed:     1,
+		Duration: 9 * time.Second,
+		FailureDomains: []config.FailureDomain{
+			{Zone: "zone-b", Region: "us-east-1", Resources: map[string][]string{"k8s.node.name": {"node-3", "node-4"}}},
+		},
+	}
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := time.Second
+	if want := []time.Duration{s, 2 * s, 3 * s, 4 * s, 5 * s, 6 * s, 7 * s, 8 * s, 9 * s}; !slices.Equal(e.metrics["cpu_a"], want) {
+		t.Errorf("expected zone-a metrics throughout, got %v", e.metrics["cpu_a"])
+	}
+	if want := []time.Duration{s, 2 * s, 3 * s, 4 * s, 8 * s, 9 * s}; !slices.Equal(e.metrics["cpu_b"], want) {
+		t.Errorf("expected zone-b metrics to stop during the outage, got %v", e.metrics["cpu_b"])
+	}
+	for i := range 10 {
+		tick := time.Duration(i) * s
+		msg, failed := e.errored[tick]
+		if down := tick >= 5*s && tick < 8*s; failed != down {
+			t.Errorf("tick %s: expected root spans failed=%v, got %v", tick, down, failed)
+		} else if failed && msg != "zone-b outage" {
+			t.Errorf("unexpected status message %q", msg)
+		}
+	}
+
+	_, err := newOutage(scriptaction.ScriptAction{Spec: map[string]any{}})
+	if err == nil {
+		t.Error("expected an error for an outage without a zone or region")
+	}
+}
+
+func TestAddEmitter(t *testing.T) {
+	dir := t.TempDir()
+	newScript := func(spec map[string]any) *Script {
+		rscript := NewScript()
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu_base",
+			Type: "metricGenerator",
+			Spec: map[string]any{"type": "constant", "value": 1.0},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "cpu",
+			Type: "metric",
+			To:   20 * time.Second,
+			Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{"cpu_base"}},
+		})
+		rscript.AddAction(scriptaction.ScriptAction{ID: "archive", Type: "addEmitter", At: 5 * time.Second, Spec: spec})
+		rscript.AddAction(scriptaction.ScriptAction{ID: "archive", Type: "removeEmitter", At: 10 * time.Second})
+		return rscript
+	}
+	cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+
+	path := dir + "/archive.jsonl"
+	rscript := newScript(map[string]any{"type": "json", "path": path})
+	all := &gaugeEmitter{name: "cpu"}
+	rscript.AddEmitter(all)
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Attached from the 5s tick until the 10s one.
+	if lines := strings.Count(string(b), "\n"); lines != 5 || len(all.values) <= lines {
+		t.Errorf("expected 5 payloads written while attached, of %d, got %d", len(all.values), lines)
+	}
+
+	// An emitter that cannot be created is skipped, and removing it
+	// does nothing.
+	rscript = newScript(map[string]any{"type": "json", "path": dir + "/missing/archive.jsonl"})
+	if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+		t.Errorf("expected the run to continue without the emitter, got %v", err)
+	}
+
+	rscript = newScript(map[string]any{"type": "kafka"})
+	if err := Simulate(context.Background(), cfg, rscript, 0); err == nil || !strings.Contains(err.Error(), "invalid addEmitter archive") {
+		t.Errorf("expected an invalid spec to be rejected before the run, got %v", err)
+	}
+
+	rscript = NewScript()
+	rscript.AddAction(scriptaction.ScriptAction{ID: "archive", Type: "removeEmitter", At: time.Second})
+	if err := Simulate(context.Background(), cfg, rscript, 0); err == nil || !strings.Contains(err.Error(), "no earlier addEmitter") {
+		t.Errorf("expected removeEmitter without addEmitter to be rejected, got %v", err)
+	}
+}
+
+// seriesEmitter collects the gauge values and span counts emitted, by
+// name.
+type seriesEmitter struct {
+	gauges map[string][]float64
+	spans  map[string]int
+}
+
+func (e *seriesEmitter) EmitMetrics(_ context.Context, _ *state.RunState, md pmetric.Metrics) error {
+	for _, rm := range md.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				for _, dp := range m.Gauge().DataPoints().All() {
+					e.gauges[m.Name()] = append(e.gauges[m.Name()], dp.DoubleValue())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (e *seriesEmitter) EmitTraces(_ context.Context, _ *state.RunState, td ptrace.Traces) error {
+	for _, rs := range td.ResourceSpans().All() {
+		for _, ss := range rs.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				e.spans[span.Name()]++
+			}
+		}
+	}
+	return nil
+}
+
+func TestShards(t *testing.T) {
+	simulate := func(shard, shards int) (*seriesEmitter, []time.Duration) {
+		rscript := NewScript()
+		for _, id := range []string{"cpu", "mem", "disk"} {
+			rscript.AddAction(scriptaction.ScriptAction{
+				ID:   id + "_noise",
+				Type: "metricGenerator",
+				Spec: map[string]any{"type": "normalNoise", "target": 50.0, "variation": 20.0},
+			})
+			rscript.AddAction(scriptaction.ScriptAction{
+				ID:   id,
+				Type: "metric",
+				Spec: map[string]any{"type": "gauge", "frequency": "1s", "generators": []any{id + "_noise"}},
+			})
+		}
+		rscript.AddAction(scriptaction.ScriptAction{
+			ID:   "checkout",
+			Type: "trace",
+			To:   10 * time.Second,
+			Spec: map[string]any{"rate": 5.0, "exemplar": map[string]any{"name": "POST /checkout", "duration": "10ms"}},
+		})
+		if err := rscript.SetShard(shard, shards); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var ticks []time.Duration
+		rscript.OnTick(func(_ context.Context, tick time.Duration) error {
+			ticks = append(ticks, tick)
+			return nil
+		})
+		e := &seriesEmitter{gauges: map[string][]float64{}, spans: map[string]int{}}
+		rscript.AddEmitter(e)
+		cfg := &config.Config{Dryrun: true, Seed: 1, WallclockStart: time.Unix(1700000000, 0)}
+		if err := Simulate(context.Background(), cfg, rscript, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return e, ticks
+	}
+
+	whole, ticks := simulate(0, 1)
+	if len(ticks) != 11 || ticks[10] != 10*time.Second {
+		t.Errorf("expected a hook call for each of ticks 0s to 10s, got %v", ticks)
+	}
+	merged := &seriesEmitter{gauges: map[string][]float64{}, spans: map[string]int{}}
+	for shard := range 2 {
+		part, _ := simulate(shard, 2)
+		if len(part.gauges)+len(part.spans) < 2 {
+			t.Errorf("shard %d emitted too little: %v %v", shard, part.gauges, part.spans)
+		}
+		for name, values := range part.gauges {
+			if _, ok := merged.gauges[name]; ok {
+				t.Errorf("%s emitted by more than one shard", name)
+			}
+			merged.gauges[name] = values
+		}
+		for name, n := range part.spans {
+			if _, ok := merged.spans[name]; ok {
+				t.Errorf("%s emitted by more than one shard", name)
+			}
+			merged.spans[name] = n
+		}
+	}
+	for name, values := range whole.gauges {
+		if !slices.Equal(values, merged.gauges[name]) {
+			t.Errorf("sharded %s differs:\n%v\n%v", name, values, merged.gauges[name])
+		}
+	}
+	if len(merged.gauges) != len(whole.gauges) || merged.spans["POST /checkout"] != whole.spans["POST /checkout"] {
+		t.Errorf("shards emitted %v %v, expected %v %v", merged.gauges, merged.spans, whole.gauges, whole.spans)
+	}
+
+	if err := NewScript().SetShard(2, 2); err == nil {
+		t.Error("expected an error for a shard out of range")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/traceproducer"
+)
+
+// SetShard has the script emit only its share of the metric, trace,
+// and RUM producers when a run is split across shards workers.  Every
+// shard still applies every action, so each sees the run the same way,
+// and a producer draws the same values whichever shard emits it.  The
+// shard also keeps the trace and span IDs apart from other shards'.
+func (s *Script) SetShard(shard, shards int) error {
+	if shards < 1 || shard < 0 || shard >= shards {
+		return fmt.Errorf("shard %d must be in [0, %d)", shard, shards)
+	}
+	s.shard, s.shards = shard, shards
+	traceproducer.SetIDShard(shard)
+	return nil
+}
+
+// OnTick adds a hook that runs after each tick, once it has been
+// emitted and paced.  An error stops the run.
+func (s *Script) OnTick(f func(ctx context.Context, tick time.Duration) error) {
+	s.onTick = append(s.onTick, f)
+}
+
+// assignShards deals the producers the actions create, sorted by
+// stream name, round-robin across the shards.
+func (s *Script) assignShards() {
+	if s.shards <= 1 {
+		s.owned = nil
+		return
+	}
+	names := map[string]bool{}
+	for id := range s.traceProducers {
+		names["trace/"+id] = true
+	}
+	for _, action := range s.actions {
+		switch action.Type {
+		case "metric", "trace", "rum":
+			names[action.Type+"/"+action.ID] = true
+		}
+	}
+	s.owned = map[string]bool{}
+	for i, name := range slices.Sorted(maps.Keys(names)) {
+		if i%s.shards == s.shard {
+			s.owned[name] = true
+		}
+	}
+}
+
+// owns reports whether this shard emits the producer with the stream
+// name.
+func (s *Script) owns(name string) bool {
+	return s.owned == nil || s.owned[name]
+}
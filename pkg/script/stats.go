@@ -0,0 +1,93 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"text/tabwriter"
+)
+
+// runningStats accumulates mean and variance with Welford's method, so
+// long runs neither store samples nor lose precision.
+type runningStats struct {
+	count    int
+	mean, m2 float64
+	min, max float64
+}
+
+func (s *runningStats) add(v float64) {
+	if s.count == 0 {
+		s.min, s.max = v, v
+	}
+	s.count++
+	delta := v - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (v - s.mean)
+	s.min = min(s.min, v)
+	s.max = max(s.max, v)
+}
+
+// GeneratorStats summarizes what one generator contributed over a run.
+// A generator shared by several metrics counts each of their emits.
+type GeneratorStats struct {
+	ID     string  `json:"id"`
+	Type   string  `json:"type"`
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// GeneratorStats returns per-generator statistics, by ID.  Generators
+// that never emitted are left out.
+func (s *Script) GeneratorStats() []GeneratorStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []GeneratorStats
+	for _, id := range slices.Sorted(maps.Keys(s.metricGenerators)) {
+		r, ok := s.metricGenerators[id].(*recordingGenerator)
+		if !ok || r.stats.count == 0 {
+			continue
+		}
+		typ, _ := s.generatorSpecs[id]["type"].(string)
+		out = append(out, GeneratorStats{
+			ID:     id,
+			Type:   typ,
+			Count:  r.stats.count,
+			Mean:   r.stats.mean,
+			StdDev: math.Sqrt(r.stats.m2 / float64(r.stats.count)),
+			Min:    r.stats.min,
+			Max:    r.stats.max,
+		})
+	}
+	return out
+}
+
+// WriteGeneratorStats prints GeneratorStats as an aligned table.
+func (s *Script) WriteGeneratorStats(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GENERATOR\tTYPE\tCOUNT\tMEAN\tSTDDEV\tMIN\tMAX")
+	for _, st := range s.GeneratorStats() {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.6g\t%.6g\t%.6g\t%.6g\n",
+			st.ID, st.Type, st.Count, st.Mean, st.StdDev, st.Min, st.Max)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package script
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+)
+
+func validateTiers(tiers []config.ResolutionTier) error {
+	for _, t := range tiers {
+		if t.OlderThan <= 0 || t.Frequency <= 0 {
+			return fmt.Errorf("resolution tier olderThan %s and frequency %s must be positive", t.OlderThan, t.Frequency)
+		}
+	}
+	return nil
+}
+
+// tierFrequency is the minimum metric frequency for a tick age before
+// the end of the run: that of the oldest tier the age falls in, or 0
+// outside every tier.
+func tierFrequency(tiers []config.ResolutionTier, age time.Duration) time.Duration {
+	var oldest time.Duration
+	var freq time.Duration
+	for _, t := range tiers {
+		if age >= t.OlderThan && t.OlderThan > oldest {
+			oldest, freq = t.OlderThan, t.Frequency
+		}
+	}
+	return freq
+}
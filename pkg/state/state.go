@@ -15,6 +15,7 @@
 package state
 
 import (
+	"hash/fnv"
 	"math/rand/v2"
 	"time"
 )
@@ -24,14 +25,139 @@ type RunState struct {
 	Wallclock     time.Time
 	Duration      time.Duration
 	RND           *rand.Rand
+	Seed          uint64
 	CurrentAction int
+	// AlignTimestamps makes metric producers truncate datapoint
+	// timestamps to a multiple of their frequency.
+	AlignTimestamps bool
+	// RunID is stamped on every emitted resource, unless empty.
+	RunID string
+	// MinFrequency is the least time between a metric's datapoints at
+	// this tick, raised for older data by resolution tiers.
+	MinFrequency time.Duration
+	// DropDatapoint is set by a generator, such as dropout, to skip the
+	// datapoint its metric producer is calculating.  Producers clear
+	// it before running their generators.
+	DropDatapoint bool
+	// Degrade is raised when flutter exceeds its footprint budget.
+	// Each level halves output; see DegradeFactor.
+	Degrade int
+
+	pcg *rand.PCG
+	// stream is the id RND was last reseeded with.
+	stream  string
+	metrics map[string][]metricSample
+	// generators is the value each generator last produced for a
+	// metric, for generators that read others.
+	generators map[string]float64
+}
+
+// MaxMetricLag is how far back MetricValue can look.
+const MaxMetricLag = time.Hour
+
+type metricSample struct {
+	at    time.Duration
+	value float64
 }
 
 func NewRunState(duration time.Duration, seed uint64) *RunState {
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
 	return &RunState{
 		Duration: duration,
 		RND:      MakeRNG(seed),
+		Seed:     seed,
+	}
+}
+
+// DegradeFactor is what metric frequencies are multiplied by, and
+// trace rates divided by, at the current Degrade level.
+func (rs *RunState) DegradeFactor() int {
+	return 1 << rs.Degrade
+}
+
+// Reseed points RND at a stream derived from Seed, id, and Tick, so
+// what one component draws on a tick does not depend on how many draws
+// other components made before it.  Changing one producer's frequency
+// or adding another then leaves every other series unchanged.
+func (rs *RunState) Reseed(id string) {
+	rs.stream = id
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	if rs.pcg == nil {
+		rs.pcg = rand.NewPCG(0, 0)
+		rs.RND = rand.New(rs.pcg)
+	}
+	hi := splitmix64(rs.Seed ^ h.Sum64())
+	rs.pcg.Seed(hi, splitmix64(hi^uint64(rs.Tick)))
+}
+
+// Fork runs f with RND on a stream of its own, derived from the
+// current stream and id, then puts the current stream back as it was.
+// A generator forked this way draws the same values however many
+// draws other generators in its chain or its producer make, and
+// whether or not they exist.
+func (rs *RunState) Fork(id string, f func()) {
+	rnd, pcg, stream := rs.RND, rs.pcg, rs.stream
+	rs.pcg = nil
+	rs.Reseed(stream + "/" + id)
+	defer func() {
+		rs.RND, rs.pcg, rs.stream = rnd, pcg, stream
+	}()
+	f()
+}
+
+// RecordMetric notes the value a metric producer emitted at a tick, for
+// generators that follow it.  Samples older than MaxMetricLag are
+// dropped, except the one a lookup that far back would still find.
+func (rs *RunState) RecordMetric(name string, at time.Duration, value float64) {
+	if rs.metrics == nil {
+		rs.metrics = map[string][]metricSample{}
+	}
+	h := append(rs.metrics[name], metricSample{at: at, value: value})
+	i := 0
+	for i+1 < len(h) && h[i+1].at <= at-MaxMetricLag {
+		i++
+	}
+	rs.metrics[name] = h[i:]
+}
+
+// RecordGenerator notes the value a generator produced for a metric.
+func (rs *RunState) RecordGenerator(id string, value float64) {
+	if rs.generators == nil {
+		rs.generators = map[string]float64{}
 	}
+	rs.generators[id] = value
+}
+
+// GeneratorValue returns the value the generator last produced for a
+// metric.  ok is false if no metric has used it yet.
+func (rs *RunState) GeneratorValue(id string) (value float64, ok bool) {
+	value, ok = rs.generators[id]
+	return value, ok
+}
+
+// MetricValue returns the value the named metric producer most
+// recently emitted at or before at.  ok is false if it had not
+// emitted by then.
+func (rs *RunState) MetricValue(name string, at time.Duration) (value float64, ok bool) {
+	h := rs.metrics[name]
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].at <= at {
+			return h[i].value, true
+		}
+	}
+	return 0, false
+}
+
+// splitmix64 scrambles x, so nearby seeds and ticks give unrelated
+// streams.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
 }
 
 func MakeRNG(seed uint64) *rand.Rand {
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cespare/xxhash"
@@ -35,9 +36,15 @@ func mergeMetric(rs *script.Script, metric Metric) error {
 			return fmt.Errorf("no timeline for metric %s", metric.Name)
 		}
 
+		if len(variant.SigmaTimeline) > 0 && metric.Type != "histogram" {
+			return fmt.Errorf("sigmaTimeline is only supported on histogram metrics, not %s", metric.Name)
+		}
+
 		id := makeMetricID(metric, variant)
 		frequency := getMetricFrequency(metric.Frequency)
-		generators := generateGeneratorIDs(id, variant.Timeline)
+		if variant.Frequency.Get() != 0 {
+			frequency = variant.Frequency.Get()
+		}
 		firstAt := variant.Timeline[0].StartTs.Get()
 		lastAt := time.Duration(0)
 		for _, tl := range variant.Timeline {
@@ -49,22 +56,28 @@ func mergeMetric(rs *script.Script, metric Metric) error {
 			return fmt.Errorf("lastAt is 0 for metric %s", id)
 		}
 
-		if err := addMetricToConfig(rs, id, metric, variant, frequency, generators, firstAt, lastAt); err != nil {
+		ramps, err := addMetricTimelineToScript(rs, id, variant.Timeline, variant.BetweenSegments, findPriorRamps(rs, id))
+		if err != nil {
+			return err
+		}
+		generators := append([]string{id + "_noise"}, ramps...)
+		sigmaGenerators, err := addMetricTimelineToScript(rs, id+"_sigma", variant.SigmaTimeline, variant.BetweenSegments, findPriorRamps(rs, id+"_sigma"))
+		if err != nil {
 			return err
 		}
 
-		if err := addMetricNoiseGenerator(rs, id, variant.Noise); err != nil {
+		if err := addMetricToConfig(rs, id, metric, variant, frequency, generators, sigmaGenerators, firstAt, lastAt); err != nil {
 			return err
 		}
 
-		if err := addMetricTimelineToScript(rs, id, variant.Timeline); err != nil {
+		if err := addMetricNoiseGenerator(rs, id, variant.Noise); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func addMetricToConfig(rs *script.Script, id string, metric Metric, variant Variant, frequency time.Duration, generators []string, startAt, endAt time.Duration) error {
+func addMetricToConfig(rs *script.Script, id string, metric Metric, variant Variant, frequency time.Duration, generators, sigmaGenerators []string, startAt, endAt time.Duration) error {
 	action := scriptaction.ScriptAction{
 		At:   startAt,
 		To:   endAt,
@@ -81,9 +94,14 @@ func addMetricToConfig(rs *script.Script, id string, metric Metric, variant Vari
 					Datapoint: variant.Attributes,
 				},
 				Generators: generators,
+				Min:        metric.Min,
+				Max:        metric.Max,
 			},
 		}),
 	}
+	if len(sigmaGenerators) > 0 {
+		action.Spec["sigmaGenerators"] = sigmaGenerators
+	}
 	rs.AddAction(action)
 	return nil
 }
@@ -117,28 +135,77 @@ func addMetricNoiseGenerator(rs *script.Script, id string, noise *NoiseConfig) e
 	return nil
 }
 
-func addMetricTimelineToScript(rs *script.Script, id string, timeline []Segment) error {
+// priorRamps describes the ramps an earlier merge already added for a
+// metric variant, such as when the same variant appears in several
+// timeline files.
+type priorRamps struct {
+	count  int
+	target float64
+}
+
+// findPriorRamps looks for ramp generators already in the script for id,
+// returning how many there are and the target of the latest one.
+func findPriorRamps(rs *script.Script, id string) priorRamps {
+	prior := priorRamps{}
+	var lastAt time.Duration
+	for _, action := range rs.Actions() {
+		if action.Type != "metricGenerator" || !strings.HasPrefix(action.ID, id+"_ramp_") {
+			continue
+		}
+		if target, ok := action.Spec["target"].(float64); ok && (prior.count == 0 || action.At >= lastAt) {
+			prior.target = target
+			lastAt = action.At
+		}
+		prior.count++
+	}
+	return prior
+}
+
+// addMetricTimelineToScript adds the ramps for a variant's timeline and
+// returns their generator IDs.  When an earlier merge added ramps for the
+// same variant, numbering continues after them and, unless the first
+// segment sets an explicit start, the first ramp starts from the previous
+// file's final target.  Gaps between segments are filled according to
+// betweenSegments.
+func addMetricTimelineToScript(rs *script.Script, id string, timeline []Segment, betweenSegments string, prior priorRamps) ([]string, error) {
+	switch betweenSegments {
+	case "", "zero", "hold", "interpolate":
+	default:
+		return nil, fmt.Errorf("unknown betweenSegments %q for metric %s", betweenSegments, id)
+	}
 	if len(timeline) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	startAt := timeline[0].StartTs.Get()
 	startValue := 0.0
+	if prior.count > 0 {
+		startValue = prior.target
+	}
 	if timeline[0].Start != nil {
 		startValue = *(timeline[0].Start)
 	}
 	disabled := false
 
-	rampCounter := 0
-
-	// count the number of ramps so we can do something special with the last one
-	nRamps := 0
-	for _, dp := range timeline {
-		if dp.Type == "segment" {
-			nRamps++
-		}
+	type ramp struct {
+		at   time.Duration
+		spec generator.MetricRampSpec
+	}
+	var ramps []ramp
+	addRamp := func(at, duration time.Duration, start, target float64) {
+		ramps = append(ramps, ramp{at: at, spec: generator.MetricRampSpec{
+			MetricGeneratorSpec: generator.MetricGeneratorSpec{
+				Type: "ramp",
+			},
+			Start:    start,
+			Target:   target,
+			Duration: duration,
+		}})
 	}
 
+	// prevEnd is where the last ramp ended, or -1 if there is no ramp
+	// to continue from.
+	prevEnd := time.Duration(-1)
 	for _, dp := range timeline {
 		if dp.StartTs.Get() != 0 {
 			startAt = dp.StartTs.Get()
@@ -153,11 +220,21 @@ func addMetricTimelineToScript(rs *script.Script, id string, timeline []Segment)
 				Type: "disableMetric",
 			}
 			disabled = true
+			prevEnd = -1
 			rs.AddAction(action)
 			continue
 		}
 		if dp.Type != "segment" {
-			return fmt.Errorf("unknown segment type %s for metric %s", dp.Type, id)
+			return nil, fmt.Errorf("unknown segment type %s for metric %s", dp.Type, id)
+		}
+		if prevEnd >= 0 && startAt > prevEnd {
+			prevTarget := ramps[len(ramps)-1].spec.Target
+			switch betweenSegments {
+			case "hold":
+				addRamp(prevEnd, startAt-prevEnd, prevTarget, prevTarget)
+			case "interpolate":
+				addRamp(prevEnd, startAt-prevEnd, prevTarget, startValue)
+			}
 		}
 		duration := dp.EndTs.Get() - startAt
 		if duration <= 0 {
@@ -172,26 +249,27 @@ func addMetricTimelineToScript(rs *script.Script, id string, timeline []Segment)
 			rs.AddAction(action)
 			disabled = false
 		}
-		action := scriptaction.ScriptAction{
-			ID:   id + "_ramp_" + strconv.Itoa(rampCounter),
-			Type: "metricGenerator",
-			At:   startAt,
-			Spec: specToMap(generator.MetricRampSpec{
-				MetricGeneratorSpec: generator.MetricGeneratorSpec{
-					Type: "ramp",
-				},
-				Start:       startValue,
-				Target:      dp.Target,
-				Duration:    duration,
-				PostEndZero: rampCounter < nRamps-1,
-			}),
-		}
-		rampCounter++
+		addRamp(startAt, duration, startValue, dp.Target)
 		startValue = dp.Target
 		startAt = dp.EndTs.Get()
-		rs.AddAction(action)
+		prevEnd = startAt
 	}
-	return nil
+
+	// Every ramp but the last drops to zero once it ends, so only the
+	// current one contributes to the sum.
+	ids := make([]string, 0, len(ramps))
+	for i, r := range ramps {
+		r.spec.PostEndZero = i < len(ramps)-1
+		rampID := id + "_ramp_" + strconv.Itoa(prior.count+i)
+		rs.AddAction(scriptaction.ScriptAction{
+			ID:   rampID,
+			Type: "metricGenerator",
+			At:   r.at,
+			Spec: specToMap(r.spec),
+		})
+		ids = append(ids, rampID)
+	}
+	return ids, nil
 }
 
 func getMetricFrequency(frequency config.Duration) time.Duration {
@@ -206,6 +284,11 @@ func makeMetricID(metric Metric, variant Variant) string {
 	id += metric.Type + "|"
 	id += makeMapID(metric.ResourceAttributes) + "|"
 	id += makeMapID(variant.Attributes) + "|"
+	// Only fold in a variant frequency when set, so IDs of existing
+	// timelines are unchanged.
+	if variant.Frequency.Get() != 0 {
+		id += variant.Frequency.Get().String() + "|"
+	}
 
 	x := xxhash.Sum64([]byte(id))
 	return strconv.FormatUint(x, 32)
@@ -0,0 +1,87 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeline
+
+import (
+	"bytes"
+	"maps"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/traceproducer"
+)
+
+// Overrides rewrite selected resource attributes in every loaded
+// timeline, so a shared scenario library can be retargeted at another
+// cluster, namespace or tenant without editing its files.
+type Overrides struct {
+	// ResourceAttributes replaces the value of each named attribute on
+	// every resource that already has it.  Resources without the
+	// attribute are left alone.
+	ResourceAttributes map[string]any `json:"resourceAttributes"`
+}
+
+func ParseOverrides(b []byte) (*Overrides, error) {
+	var overrides Overrides
+	if err := config.JSONDecode(bytes.NewReader(b), &overrides); err != nil {
+		return nil, err
+	}
+	return &overrides, nil
+}
+
+// Merge adds other's rewrites, with other winning where both name the
+// same attribute.
+func (o *Overrides) Merge(other *Overrides) {
+	if o.ResourceAttributes == nil {
+		o.ResourceAttributes = map[string]any{}
+	}
+	maps.Copy(o.ResourceAttributes, other.ResourceAttributes)
+}
+
+// ApplyOverrides rewrites the resource attributes of the timeline's
+// metrics, trace exemplars and subtrees.
+func (t *Timeline) ApplyOverrides(o *Overrides) {
+	if len(o.ResourceAttributes) == 0 {
+		return
+	}
+	for _, metric := range t.Metrics {
+		overrideAttributes(metric.ResourceAttributes, o.ResourceAttributes)
+	}
+	for i := range t.Traces {
+		trace := &t.Traces[i]
+		overrideSpan(&trace.Exemplar, o.ResourceAttributes)
+		for j := range trace.Exemplars {
+			overrideSpan(&trace.Exemplars[j].Exemplar, o.ResourceAttributes)
+		}
+	}
+	for name, span := range t.Subtrees {
+		overrideSpan(&span, o.ResourceAttributes)
+		t.Subtrees[name] = span
+	}
+}
+
+func overrideSpan(span *traceproducer.Span, rewrites map[string]any) {
+	overrideAttributes(span.ResourceAttributes, rewrites)
+	for i := range span.Children {
+		overrideSpan(&span.Children[i], rewrites)
+	}
+}
+
+func overrideAttributes(attrs map[string]any, rewrites map[string]any) {
+	for k, v := range rewrites {
+		if _, ok := attrs[k]; ok {
+			attrs[k] = v
+		}
+	}
+}
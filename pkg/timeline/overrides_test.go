@@ -0,0 +1,61 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	tl, err := ParseTimeline([]byte(`{
+		"metrics": [{"name": "rps", "type": "gauge",
+			"resourceAttributes": {"service.name": "checkout", "k8s.cluster.name": "test-cluster"},
+			"variants": [{"timeline": [{"start_ts": "0s", "end_ts": "10m", "target": 5}]}]
+		}],
+		"traces": [{"name": "checkout",
+			"exemplar": {"name": "GET /", "resourceAttributes": {"k8s.cluster.name": "test-cluster", "tenant": "demo"},
+				"children": [{"name": "db", "resourceAttributes": {"service.name": "postgres", "tenant": "demo"}}]},
+			"variants": [{"timeline": [{"start_ts": "0s", "end_ts": "10m", "target": 5}]}]
+		}],
+		"subtrees": {"auth": {"name": "auth", "resourceAttributes": {"tenant": "demo"}}}
+	}`))
+	require.NoError(t, err)
+
+	overrides := &Overrides{}
+	for _, input := range []string{
+		`{"resourceAttributes": {"k8s.cluster.name": "prod-east", "tenant": "acme"}}`,
+		`{"resourceAttributes": {"tenant": "globex"}}`,
+	} {
+		o, err := ParseOverrides([]byte(input))
+		require.NoError(t, err)
+		overrides.Merge(o)
+	}
+	tl.ApplyOverrides(overrides)
+
+	assert.Equal(t, map[string]any{"service.name": "checkout", "k8s.cluster.name": "prod-east"}, tl.Metrics[0].ResourceAttributes)
+	exemplar := tl.Traces[0].Exemplar
+	assert.Equal(t, map[string]any{"k8s.cluster.name": "prod-east", "tenant": "globex"}, exemplar.ResourceAttributes)
+	assert.Equal(t, map[string]any{"service.name": "postgres", "tenant": "globex"}, exemplar.Children[0].ResourceAttributes,
+		"attributes a resource lacks are not added")
+	assert.Equal(t, map[string]any{"tenant": "globex"}, tl.Subtrees["auth"].ResourceAttributes)
+}
+
+func TestParseOverrides_UnknownField(t *testing.T) {
+	_, err := ParseOverrides([]byte(`{"resourceAttribute": {"tenant": "acme"}}`))
+	assert.Error(t, err)
+}
@@ -16,19 +16,41 @@ package timeline
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"maps"
 	"slices"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cardinalhq/flutter/pkg/config"
 	"github.com/cardinalhq/flutter/pkg/script"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
 	"github.com/cardinalhq/flutter/pkg/traceproducer"
 )
 
 type Timeline struct {
 	Metrics []Metric `json:"metrics"`
 	Traces  []Trace  `json:"traces,omitempty"`
+	// Subtrees are shared span trees that trace exemplars can
+	// include by name with "use".
+	Subtrees map[string]traceproducer.Span `json:"subtrees,omitempty"`
+	// Variables are named scenario curves that metric and trace
+	// variants can follow through an "expr".
+	Variables map[string][]Segment `json:"variables,omitempty"`
+	// Emitters are attached partway through the run, and optionally
+	// detached again, for dual-write and migration scenarios.
+	Emitters []Emitter `json:"emitters,omitempty"`
+}
+
+// Emitter attaches the emitter Spec describes, as with an addEmitter
+// action, at At.
+type Emitter struct {
+	Name string          `json:"name"`
+	At   config.Duration `json:"at"`
+	// To detaches it; zero keeps it until the run ends.
+	To   config.Duration `json:"to,omitempty"`
+	Spec map[string]any  `json:"spec"`
 }
 
 type Metric struct {
@@ -38,6 +60,8 @@ type Metric struct {
 	ResourceAttributes map[string]any  `json:"resourceAttributes"`
 	Variants           []Variant       `json:"variants"`
 	Description        string          `json:"description"`
+	Min                *float64        `json:"min,omitempty"` // optional, clamps emitted values
+	Max                *float64        `json:"max,omitempty"` // optional, clamps emitted values
 }
 
 type NoiseConfig struct {
@@ -47,9 +71,19 @@ type NoiseConfig struct {
 }
 
 type Variant struct {
-	Attributes map[string]any `json:"attributes"`
-	Timeline   []Segment      `json:"timeline"`
-	Noise      *NoiseConfig   `json:"noise,omitempty"`
+	Attributes map[string]any  `json:"attributes"`
+	Frequency  config.Duration `json:"frequency,omitempty"` // optional, overrides the metric's frequency
+	Timeline   []Segment       `json:"timeline"`
+	Expr       string          `json:"expr,omitempty"` // optional, derives the timeline from variables
+	// SigmaTimeline drives the spread of a histogram metric, while
+	// Timeline drives its median.
+	SigmaTimeline []Segment    `json:"sigmaTimeline,omitempty"`
+	Noise         *NoiseConfig `json:"noise,omitempty"`
+	// BetweenSegments is what the variant emits in a gap between one
+	// segment's end_ts and the next segment's start_ts: "zero" (the
+	// default), "hold" the previous target, or "interpolate" linearly
+	// to the next segment's start.
+	BetweenSegments string `json:"betweenSegments,omitempty"`
 }
 
 type Segment struct {
@@ -61,24 +95,36 @@ type Segment struct {
 }
 
 type Trace struct {
-	Ref         string             `json:"ref"`
-	Name        string             `json:"name"`
-	Exemplar    traceproducer.Span `json:"exemplar"`
-	Variants    []TraceVariant     `json:"variants"`
-	Description string             `json:"description"`
+	Ref         string                             `json:"ref"`
+	Name        string                             `json:"name"`
+	Exemplar    traceproducer.Span                 `json:"exemplar"`
+	Exemplars   []traceproducer.WeightedExemplar   `json:"exemplars,omitempty"`
+	Variants    []TraceVariant                     `json:"variants"`
+	Description string                             `json:"description"`
+	Pools       map[string]traceproducer.ValuePool `json:"pools,omitempty"`
+	StartSpread traceproducer.StartSpread          `json:"startSpread,omitempty"`
+	Shape       *traceproducer.ShapeVariation      `json:"shape,omitempty"`
+	Sampling    *traceproducer.Sampling            `json:"sampling,omitempty"`
+	Bursts      []traceproducer.Burst              `json:"bursts,omitempty"`
+	SLA         *traceproducer.SLA                 `json:"sla,omitempty"`
 }
 
 type TraceVariant struct {
 	Ref       string                  `json:"ref"`
 	Name      string                  `json:"name"`
 	Timeline  []Segment               `json:"timeline"`
+	Expr      string                  `json:"expr,omitempty"` // optional, derives the timeline from variables
 	Overrides map[string]SpanOverride `json:"overrides,omitempty"`
 }
 
 type SpanOverride struct {
-	Duration   *config.Duration `json:"duration,omitempty"`
-	Error      *bool            `json:"error,omitempty"`
-	Attributes map[string]any   `json:"attributes,omitempty"`
+	Duration      *config.Duration         `json:"duration,omitempty"`
+	Error         *bool                    `json:"error,omitempty"`
+	StatusMessage *string                  `json:"statusMessage,omitempty"`
+	Exception     *traceproducer.Exception `json:"exception,omitempty"`
+	TraceState    *string                  `json:"traceState,omitempty"`
+	TraceFlags    *uint32                  `json:"traceFlags,omitempty"`
+	Attributes    map[string]any           `json:"attributes,omitempty"`
 }
 
 func ParseTimeline(b []byte) (*Timeline, error) {
@@ -94,37 +140,148 @@ func ParseTimeline(b []byte) (*Timeline, error) {
 					variant.Timeline[i].Type = "segment"
 				}
 			}
+			for i := range variant.SigmaTimeline {
+				if variant.SigmaTimeline[i].Type == "" {
+					variant.SigmaTimeline[i].Type = "segment"
+				}
+			}
 		}
 	}
 
 	return &timeline, nil
 }
 
+// SplitOffset splits a --timeline argument of the form "file.json@+30m"
+// into the file path and the offset to shift its timeline by.  An
+// argument without an "@" has no offset.
+func SplitOffset(arg string) (string, time.Duration, error) {
+	i := strings.LastIndex(arg, "@")
+	if i < 0 {
+		return arg, 0, nil
+	}
+	path, offset := arg[:i], strings.TrimPrefix(arg[i+1:], "+")
+	d, err := time.ParseDuration(offset)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid timeline offset %q: %w", arg[i+1:], err)
+	}
+	if d < 0 {
+		return "", 0, fmt.Errorf("timeline offset %q must not be negative", arg[i+1:])
+	}
+	return path, d, nil
+}
+
+// Offset shifts every time in the timeline later by d, so a scenario
+// written to start at 0s can be staggered within a larger run.  A zero
+// start_ts on a later segment still means "where the previous segment
+// ended", and is left alone.
+func (t *Timeline) Offset(d time.Duration) {
+	if d == 0 {
+		return
+	}
+	for _, metric := range t.Metrics {
+		for _, variant := range metric.Variants {
+			offsetSegments(variant.Timeline, d)
+			offsetSegments(variant.SigmaTimeline, d)
+		}
+	}
+	for _, trace := range t.Traces {
+		for _, variant := range trace.Variants {
+			offsetSegments(variant.Timeline, d)
+		}
+		for i := range trace.Bursts {
+			trace.Bursts[i].At.Duration += d
+		}
+	}
+	for _, segments := range t.Variables {
+		offsetSegments(segments, d)
+	}
+	for i := range t.Emitters {
+		t.Emitters[i].At.Duration += d
+		if t.Emitters[i].To.Duration != 0 {
+			t.Emitters[i].To.Duration += d
+		}
+	}
+}
+
+func offsetSegments(segments []Segment, d time.Duration) {
+	for i := range segments {
+		if i == 0 || segments[i].StartTs.Get() != 0 || segments[i].Type == "disable" {
+			segments[i].StartTs.Duration += d
+		}
+		segments[i].EndTs.Duration += d
+	}
+}
+
 func (t *Timeline) MergeIntoScript(rs *script.Script) error {
+	curves := map[string]variableCurve{}
+	for _, name := range slices.Sorted(maps.Keys(t.Variables)) {
+		curve, err := newVariableCurve(name, t.Variables[name])
+		if err != nil {
+			return err
+		}
+		curves[name] = curve
+	}
+
 	for _, metric := range t.Metrics {
+		metric.Variants = slices.Clone(metric.Variants)
+		for i, variant := range metric.Variants {
+			timeline, err := variantTimeline(variant.Timeline, variant.Expr, curves)
+			if err != nil {
+				return fmt.Errorf("metric %s: %w", metric.Name, err)
+			}
+			metric.Variants[i].Timeline = timeline
+		}
 		if err := mergeMetric(rs, metric); err != nil {
 			return err
 		}
 	}
 	for _, trace := range t.Traces {
-		if err := mergeTrace(rs, trace); err != nil {
+		trace.Variants = slices.Clone(trace.Variants)
+		for i, variant := range trace.Variants {
+			timeline, err := variantTimeline(variant.Timeline, variant.Expr, curves)
+			if err != nil {
+				return fmt.Errorf("trace %s: %w", trace.Name, err)
+			}
+			trace.Variants[i].Timeline = timeline
+		}
+		if err := mergeTrace(rs, trace, t.Subtrees); err != nil {
 			return err
 		}
 	}
+	for _, e := range t.Emitters {
+		if e.Name == "" {
+			return errors.New("an emitter needs a name")
+		}
+		if e.To.Duration != 0 && e.To.Duration <= e.At.Duration {
+			return fmt.Errorf("emitter %s: to must be after at", e.Name)
+		}
+		rs.AddAction(scriptaction.ScriptAction{
+			ID:   e.Name,
+			At:   e.At.Duration,
+			Type: "addEmitter",
+			Spec: e.Spec,
+		})
+		if e.To.Duration != 0 {
+			rs.AddAction(scriptaction.ScriptAction{
+				ID:   e.Name,
+				At:   e.To.Duration,
+				Type: "removeEmitter",
+			})
+		}
+	}
 	return nil
 }
 
-func generateGeneratorIDs(id string, timeline []Segment) []string {
-	generators := []string{id + "_noise"}
-	counter := 0
-	for _, tl := range timeline {
-		if tl.Type != "segment" {
-			continue
-		}
-		generators = append(generators, id+"_ramp_"+strconv.Itoa(counter))
-		counter++
+// variantTimeline returns the variant's own timeline, or the one derived
+// from its expression over the scenario variables.
+func variantTimeline(timeline []Segment, src string, curves map[string]variableCurve) ([]Segment, error) {
+	if src == "" {
+		return timeline, nil
+	}
+	if len(timeline) > 0 {
+		return nil, errors.New("a variant cannot have both a timeline and an expr")
 	}
-	return generators
+	return expandVariableExpr(src, curves)
 }
 
 func makeMapID(m map[string]any) string {
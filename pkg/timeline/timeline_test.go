@@ -15,8 +15,11 @@
 package timeline
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,7 +27,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/generator"
 	"github.com/cardinalhq/flutter/pkg/script"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
+	"github.com/cardinalhq/flutter/pkg/state"
 )
 
 func TestParseTimeline(t *testing.T) {
@@ -459,6 +465,266 @@ func TestTimelineConversion(t *testing.T) {
 	})
 }
 
+func TestMergeMetric_VariantFrequency(t *testing.T) {
+	input := `{
+		"metrics": [{
+			"name": "checkout",
+			"type": "gauge",
+			"frequency": "1m",
+			"variants": [
+				{
+					"attributes": {"kind": "kpi"},
+					"timeline": [{"start_ts": "0s", "end_ts": "10m", "target": 5}]
+				},
+				{
+					"attributes": {"kind": "latency"},
+					"frequency": "1s",
+					"timeline": [{"start_ts": "0s", "end_ts": "10m", "target": 5}]
+				}
+			]
+		}]
+	}`
+	tl, err := ParseTimeline([]byte(input))
+	require.NoError(t, err)
+	rscript := script.NewScript()
+	require.NoError(t, tl.MergeIntoScript(rscript))
+
+	var buf bytes.Buffer
+	require.NoError(t, rscript.Dump(&buf))
+	frequencies := map[string]time.Duration{}
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var action scriptaction.ScriptAction
+		require.NoError(t, dec.Decode(&action))
+		if action.Type != "metric" {
+			continue
+		}
+		attrs := action.Spec["attributes"].(map[string]any)["datapoint"].(map[string]any)
+		frequencies[attrs["kind"].(string)] = time.Duration(action.Spec["frequency"].(float64))
+	}
+	assert.Equal(t, map[string]time.Duration{"kpi": time.Minute, "latency": time.Second}, frequencies)
+}
+
+func TestMergeMetric_ContinuesAcrossFiles(t *testing.T) {
+	first := `{"metrics": [{"name": "rps", "type": "gauge", "variants": [{
+		"attributes": {"svc": "a"},
+		"timeline": [
+			{"start_ts": "0s", "end_ts": "10m", "target": 50},
+			{"start_ts": "10m", "end_ts": "20m", "target": 80}
+		]
+	}]}]}`
+	second := `{"metrics": [{"name": "rps", "type": "gauge", "variants": [{
+		"attributes": {"svc": "a"},
+		"timeline": [{"start_ts": "20m", "end_ts": "30m", "target": 20}]
+	}]}]}`
+
+	rscript := script.NewScript()
+	for _, input := range []string{first, second} {
+		tl, err := ParseTimeline([]byte(input))
+		require.NoError(t, err)
+		require.NoError(t, tl.MergeIntoScript(rscript))
+	}
+
+	ramps := map[string]scriptaction.ScriptAction{}
+	var generators []any
+	for _, action := range rscript.Actions() {
+		switch action.Type {
+		case "metricGenerator":
+			if action.Spec["type"] == "ramp" {
+				ramps[action.ID] = action
+			}
+		case "metric":
+			generators = action.Spec["generators"].([]any)
+		}
+	}
+	require.Len(t, ramps, 3)
+	require.Len(t, generators, 2)
+	last, ok := ramps[generators[1].(string)]
+	require.True(t, ok)
+	assert.Equal(t, 80.0, last.Spec["start"])
+	assert.Equal(t, 20.0, last.Spec["target"])
+	assert.Equal(t, 20*time.Minute, last.At)
+}
+
+func TestMergeMetric_BetweenSegments(t *testing.T) {
+	// rampsAt sums the variant's ramps at the given tick, as the metric would.
+	rampsAt := func(t *testing.T, mode string, tick time.Duration) float64 {
+		input := `{"metrics": [{"name": "rps", "type": "gauge", "variants": [{
+			"attributes": {"svc": "a"},
+			"betweenSegments": "` + mode + `",
+			"timeline": [
+				{"start_ts": "0s", "end_ts": "10m", "start": 40, "target": 40},
+				{"start_ts": "20m", "end_ts": "30m", "start": 60, "target": 60}
+			]
+		}]}]}`
+		tl, err := ParseTimeline([]byte(input))
+		require.NoError(t, err)
+		rscript := script.NewScript()
+		require.NoError(t, tl.MergeIntoScript(rscript))
+
+		sum := 0.0
+		for _, action := range rscript.Actions() {
+			if action.Type != "metricGenerator" || action.Spec["type"] != "ramp" {
+				continue
+			}
+			g, err := generator.CreateMetricGenerator(action)
+			require.NoError(t, err)
+			sum += g.Emit(&state.RunState{Tick: tick}, 0)
+		}
+		return sum
+	}
+
+	tests := []struct {
+		mode     string
+		tick     time.Duration
+		expected float64
+	}{
+		{"zero", 10 * time.Minute, 40},
+		{"zero", 15 * time.Minute, 0},
+		{"", 15 * time.Minute, 0},
+		{"hold", 15 * time.Minute, 40},
+		{"hold", 20 * time.Minute, 40},
+		{"interpolate", 15 * time.Minute, 50},
+		{"interpolate", 25 * time.Minute, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+"@"+tt.tick.String(), func(t *testing.T) {
+			assert.InDelta(t, tt.expected, rampsAt(t, tt.mode, tt.tick), 1e-9)
+		})
+	}
+
+	tl, err := ParseTimeline([]byte(`{"metrics": [{"name": "rps", "variants": [{
+		"betweenSegments": "bogus",
+		"timeline": [{"start_ts": "0s", "end_ts": "10m", "target": 40}]
+	}]}]}`))
+	require.NoError(t, err)
+	assert.Error(t, tl.MergeIntoScript(script.NewScript()))
+}
+
+func TestMergeMetric_HistogramSigmaTimeline(t *testing.T) {
+	input := `{"metrics": [{"name": "http.duration", "type": "histogram", "variants": [{
+		"timeline": [{"start_ts": "0s", "end_ts": "1h", "start": 80, "target": 300}],
+		"sigmaTimeline": [{"start_ts": "0s", "end_ts": "1h", "start": 0.2, "target": 0.9}]
+	}]}]}`
+	tl, err := ParseTimeline([]byte(input))
+	require.NoError(t, err)
+	rscript := script.NewScript()
+	require.NoError(t, tl.MergeIntoScript(rscript))
+
+	var metric scriptaction.ScriptAction
+	sigmaRamps := 0
+	for _, action := range rscript.Actions() {
+		switch action.Type {
+		case "metric":
+			metric = action
+		case "metricGenerator":
+			if strings.Contains(action.ID, "_sigma_ramp_") {
+				sigmaRamps++
+			}
+		}
+	}
+	assert.Equal(t, 1, sigmaRamps)
+	assert.Len(t, metric.Spec["sigmaGenerators"], 1)
+
+	tl, err = ParseTimeline([]byte(strings.Replace(input, `"histogram"`, `"gauge"`, 1)))
+	require.NoError(t, err)
+	assert.Error(t, tl.MergeIntoScript(script.NewScript()))
+}
+
+func TestSplitOffset(t *testing.T) {
+	tests := []struct {
+		arg     string
+		path    string
+		offset  time.Duration
+		wantErr bool
+	}{
+		{"checkout.json", "checkout.json", 0, false},
+		{"checkout.json@+30m", "checkout.json", 30 * time.Minute, false},
+		{"checkout.json@1h30m", "checkout.json", 90 * time.Minute, false},
+		{"lib/a@b.json@+5m", "lib/a@b.json", 5 * time.Minute, false},
+		{"checkout.json@soon", "", 0, true},
+		{"checkout.json@-5m", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			path, offset, err := SplitOffset(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.path, path)
+			assert.Equal(t, tt.offset, offset)
+		})
+	}
+}
+
+func TestTimelineOffset(t *testing.T) {
+	input := `{
+		"metrics": [{"name": "rps", "type": "gauge", "variants": [{
+			"attributes": {"svc": "a"},
+			"timeline": [
+				{"start_ts": "0s", "end_ts": "10m", "target": 50},
+				{"end_ts": "20m", "target": 80}
+			]
+		}]}],
+		"traces": [{"name": "checkout", "exemplar": {"name": "GET /"},
+			"bursts": [{"at": "5m", "duration": "1m", "multiplier": 3}],
+			"variants": [{"timeline": [{"type": "segment", "start_ts": "0s", "end_ts": "10m", "target": 5}]}]
+		}]
+	}`
+	tl, err := ParseTimeline([]byte(input))
+	require.NoError(t, err)
+	tl.Offset(30 * time.Minute)
+
+	segments := tl.Metrics[0].Variants[0].Timeline
+	assert.Equal(t, 30*time.Minute, segments[0].StartTs.Get())
+	assert.Equal(t, 40*time.Minute, segments[0].EndTs.Get())
+	assert.Equal(t, time.Duration(0), segments[1].StartTs.Get(), "an omitted start_ts still follows the previous segment")
+	assert.Equal(t, 50*time.Minute, segments[1].EndTs.Get())
+	assert.Equal(t, 35*time.Minute, tl.Traces[0].Bursts[0].At.Get())
+
+	rscript := script.NewScript()
+	require.NoError(t, tl.MergeIntoScript(rscript))
+	producers := 0
+	for _, action := range rscript.Actions() {
+		switch action.Type {
+		case "metric", "traceRate":
+			assert.Equal(t, 30*time.Minute, action.At, action.ID)
+			producers++
+		}
+	}
+	assert.Equal(t, 2, producers)
+}
+
+func TestTimelineEmitters(t *testing.T) {
+	input := `{
+		"metrics": [],
+		"emitters": [
+			{"name": "archive", "at": "30m", "spec": {"type": "json", "path": "archive.jsonl"}},
+			{"name": "canary", "at": "10m", "to": "20m", "spec": {"type": "otlp", "endpoint": "http://canary:4318"}}
+		]
+	}`
+	tl, err := ParseTimeline([]byte(input))
+	require.NoError(t, err)
+	tl.Offset(time.Minute)
+
+	rscript := script.NewScript()
+	require.NoError(t, tl.MergeIntoScript(rscript))
+	var got []string
+	for _, action := range rscript.Actions() {
+		got = append(got, fmt.Sprintf("%s %s %s", action.Type, action.ID, action.At))
+	}
+	assert.Equal(t, []string{
+		"addEmitter archive 31m0s",
+		"addEmitter canary 11m0s",
+		"removeEmitter canary 21m0s",
+	}, got)
+
+	tl.Emitters = []Emitter{{Name: "bad", At: config.Duration{Duration: time.Minute}, To: config.Duration{Duration: time.Minute}}}
+	assert.ErrorContains(t, tl.MergeIntoScript(script.NewScript()), "to must be after at")
+}
+
 func TestApplyMap(t *testing.T) {
 	t.Run("merges non-overlapping keys", func(t *testing.T) {
 		a := map[string]any{"foo": 1}
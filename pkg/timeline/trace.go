@@ -25,10 +25,22 @@ import (
 	"github.com/cardinalhq/flutter/pkg/traceproducer"
 )
 
-func mergeTrace(rs *script.Script, trace Trace) error {
+func mergeTrace(rs *script.Script, trace Trace, subtrees map[string]traceproducer.Span) error {
 	if len(trace.Variants) == 0 {
 		return fmt.Errorf("no variants for trace %s", trace.Name)
 	}
+	exemplar, err := traceproducer.ResolveSubtrees(trace.Exemplar, subtrees)
+	if err != nil {
+		return fmt.Errorf("trace %s: %w", trace.Name, err)
+	}
+	exemplars := make([]traceproducer.WeightedExemplar, len(trace.Exemplars))
+	for i, we := range trace.Exemplars {
+		exemplars[i].Weight = we.Weight
+		exemplars[i].Exemplar, err = traceproducer.ResolveSubtrees(we.Exemplar, subtrees)
+		if err != nil {
+			return fmt.Errorf("trace %s: %w", trace.Name, err)
+		}
+	}
 	for _, variant := range trace.Variants {
 		if len(variant.Timeline) == 0 {
 			return fmt.Errorf("no segments for trace %s", trace.Name)
@@ -38,8 +50,12 @@ func mergeTrace(rs *script.Script, trace Trace) error {
 		firstAt := variant.Timeline[0].StartTs.Get()
 		lastAt := variant.Timeline[len(variant.Timeline)-1].EndTs.Get()
 
-		span := duplicateSpans(trace.Exemplar, variant)
-		if err := addTraceToConfig(rs, id, span, firstAt, lastAt); err != nil {
+		span := duplicateSpans(exemplar, variant)
+		weighted := make([]traceproducer.WeightedExemplar, len(exemplars))
+		for i, we := range exemplars {
+			weighted[i] = traceproducer.WeightedExemplar{Weight: we.Weight, Exemplar: duplicateSpans(we.Exemplar, variant)}
+		}
+		if err := addTraceToConfig(rs, id, trace, span, weighted, firstAt, lastAt); err != nil {
 			return err
 		}
 
@@ -79,16 +95,35 @@ func applySpanOverride(span *traceproducer.Span, override SpanOverride) {
 	if override.Error != nil {
 		span.Error = *override.Error
 	}
+	if override.StatusMessage != nil {
+		span.StatusMessage = *override.StatusMessage
+	}
+	if override.Exception != nil {
+		span.Exception = override.Exception
+	}
+	if override.TraceState != nil {
+		span.TraceState = *override.TraceState
+	}
+	if override.TraceFlags != nil {
+		span.TraceFlags = override.TraceFlags
+	}
 	if override.Attributes != nil {
 		span.Attributes = ApplyMap(span.Attributes, override.Attributes)
 	}
 }
 
-func addTraceToConfig(rs *script.Script, id string, span traceproducer.Span, firstAt, endAt time.Duration) error {
+func addTraceToConfig(rs *script.Script, id string, trace Trace, span traceproducer.Span, exemplars []traceproducer.WeightedExemplar, firstAt, endAt time.Duration) error {
 	spec := traceproducer.TraceProducerSpec{
-		At:       firstAt,
-		To:       endAt,
-		Exemplar: span,
+		At:          firstAt,
+		To:          endAt,
+		Exemplar:    span,
+		Exemplars:   exemplars,
+		Pools:       trace.Pools,
+		StartSpread: trace.StartSpread,
+		Shape:       trace.Shape,
+		Sampling:    trace.Sampling,
+		Bursts:      trace.Bursts,
+		SLA:         trace.SLA,
 	}
 
 	tp, err := traceproducer.NewTraceProducer(spec)
@@ -106,11 +141,32 @@ func addTraceTimelineToScript(rs *script.Script, id string, timeline []Segment)
 	}
 
 	startAt := timeline[0].StartTs.Get()
+	disabled := false
 
 	for _, dp := range timeline {
+		if dp.Type == "disable" {
+			rs.AddAction(scriptaction.ScriptAction{
+				ID:   id,
+				Type: "disableTrace",
+				At:   dp.StartTs.Get(),
+			})
+			disabled = true
+			continue
+		}
 		if dp.Type != "segment" {
 			continue
 		}
+		if disabled {
+			if dp.StartTs.Get() != 0 {
+				startAt = dp.StartTs.Get()
+			}
+			rs.AddAction(scriptaction.ScriptAction{
+				ID:   id,
+				Type: "enableTrace",
+				At:   startAt,
+			})
+			disabled = false
+		}
 
 		spec := map[string]any{
 			"rate": dp.Target,
@@ -15,11 +15,15 @@
 package timeline
 
 import (
+	"bytes"
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/script"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
 	"github.com/cardinalhq/flutter/pkg/traceproducer"
 )
 
@@ -162,3 +166,98 @@ func TestApplySpanOverride(t *testing.T) {
 		}
 	})
 }
+
+func TestAddTraceTimelineToScript_Disable(t *testing.T) {
+	d := config.DurationFromDuration
+	timeline := []Segment{
+		{Type: "segment", StartTs: d(0), EndTs: d(10 * time.Minute), Target: 5},
+		{Type: "disable", StartTs: d(10 * time.Minute)},
+		{Type: "segment", StartTs: d(15 * time.Minute), EndTs: d(20 * time.Minute), Target: 5},
+	}
+
+	rs := script.NewScript()
+	if err := addTraceTimelineToScript(rs, "trace-v1", timeline); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := rs.Dump(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var action scriptaction.ScriptAction
+		if err := dec.Decode(&action); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, action.Type+"@"+action.At.String())
+	}
+
+	want := []string{"traceRate@0s", "disableTrace@10m0s", "enableTrace@15m0s", "traceRate@15m0s"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected actions %v, got %v", want, got)
+	}
+}
+
+func TestMergeTrace_Subtrees(t *testing.T) {
+	input := `{
+		"subtrees": {
+			"auth": {"ref": "auth", "name": "auth.verify", "duration": "5ms"}
+		},
+		"traces": [{
+			"name": "cart",
+			"exemplar": {"name": "GET /cart", "duration": "50ms", "children": [{"use": "auth"}]},
+			"variants": [{
+				"name": "slow-auth",
+				"timeline": [{"type": "segment", "start_ts": "0s", "end_ts": "1m", "target": 5}],
+				"overrides": {"auth": {"duration": "500ms"}}
+			}]
+		}]
+	}`
+	tl, err := ParseTimeline([]byte(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tl.MergeIntoScript(script.NewScript()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exemplar, err := traceproducer.ResolveSubtrees(tl.Traces[0].Exemplar, tl.Subtrees)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dup := duplicateSpans(exemplar, tl.Traces[0].Variants[0])
+	if got := dup.Children[0].Duration.Get(); got != 500*time.Millisecond {
+		t.Errorf("expected the override to reach the shared subtree, got duration %v", got)
+	}
+
+	tl.Traces[0].Exemplar.Children[0].Use = "missing"
+	if err := tl.MergeIntoScript(script.NewScript()); err == nil {
+		t.Error("expected an error for an unknown subtree")
+	}
+}
+
+func TestMergeTrace_WeightedExemplars(t *testing.T) {
+	input := `{
+		"traces": [{
+			"name": "checkout",
+			"exemplars": [
+				{"weight": 8, "exemplar": {"ref": "root", "name": "POST /checkout", "duration": "40ms"}},
+				{"weight": 2, "exemplar": {"ref": "root", "name": "POST /checkout", "duration": "400ms"}}
+			],
+			"variants": [{
+				"name": "errors",
+				"timeline": [{"type": "segment", "start_ts": "0s", "end_ts": "1m", "target": 5}],
+				"overrides": {"root": {"error": true}}
+			}]
+		}]
+	}`
+	tl, err := ParseTimeline([]byte(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tl.MergeIntoScript(script.NewScript()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
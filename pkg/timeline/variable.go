@@ -0,0 +1,144 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeline
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/expr"
+)
+
+// piece is one linear stretch of a scenario variable.
+type piece struct {
+	from, to      time.Duration
+	start, target float64
+}
+
+// variableCurve is a scenario variable's timeline as contiguous linear
+// pieces.  Gaps between segments hold the previous target.
+type variableCurve []piece
+
+func newVariableCurve(name string, timeline []Segment) (variableCurve, error) {
+	if len(timeline) == 0 {
+		return nil, fmt.Errorf("no timeline for variable %s", name)
+	}
+	var curve variableCurve
+	startAt := timeline[0].StartTs.Get()
+	startValue := 0.0
+	for _, dp := range timeline {
+		if dp.Type != "" && dp.Type != "segment" {
+			return nil, fmt.Errorf("variable %s: unsupported segment type %s", name, dp.Type)
+		}
+		if dp.StartTs.Get() != 0 {
+			startAt = dp.StartTs.Get()
+		}
+		if dp.Start != nil {
+			startValue = *dp.Start
+		}
+		if len(curve) > 0 {
+			if prev := curve[len(curve)-1]; startAt > prev.to {
+				curve = append(curve, piece{from: prev.to, to: startAt, start: prev.target, target: prev.target})
+			}
+		}
+		if dp.EndTs.Get() <= startAt || (len(curve) > 0 && startAt < curve[len(curve)-1].to) {
+			return nil, fmt.Errorf("variable %s: segments must be in order and not overlap", name)
+		}
+		curve = append(curve, piece{from: startAt, to: dp.EndTs.Get(), start: startValue, target: dp.Target})
+		startValue = dp.Target
+		startAt = dp.EndTs.Get()
+	}
+	return curve, nil
+}
+
+// at returns the value at t.  When the curve jumps at t, fromLeft picks
+// the value just before the jump rather than just after it.  Before the
+// first piece the curve holds its first start, and after the last it
+// holds the final target.
+func (c variableCurve) at(t time.Duration, fromLeft bool) float64 {
+	if t < c[0].from || (fromLeft && t == c[0].from) {
+		return c[0].start
+	}
+	for _, p := range c {
+		if t < p.from || t > p.to || (fromLeft && t == p.from) || (!fromLeft && t == p.to) {
+			continue
+		}
+		frac := float64(t-p.from) / float64(p.to-p.from)
+		return p.start + (p.target-p.start)*frac
+	}
+	return c[len(c)-1].target
+}
+
+// expandVariableExpr builds a segment timeline for an expression over
+// scenario variables.  Each segment spans two consecutive breakpoints of
+// the variables involved, so an expression that is linear in them is
+// reproduced exactly; others are linear between breakpoints.
+func expandVariableExpr(src string, curves map[string]variableCurve) ([]Segment, error) {
+	e, err := expr.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	names := e.Names()
+	if len(names) == 0 {
+		return nil, fmt.Errorf("expression %q refers to no variables", src)
+	}
+	var breakpoints []time.Duration
+	for _, name := range names {
+		curve, ok := curves[name]
+		if !ok {
+			return nil, fmt.Errorf("expression %q: unknown variable %q", src, name)
+		}
+		for _, p := range curve {
+			breakpoints = append(breakpoints, p.from, p.to)
+		}
+	}
+	slices.Sort(breakpoints)
+	breakpoints = slices.Compact(breakpoints)
+
+	eval := func(t time.Duration, fromLeft bool) (float64, error) {
+		vars := make(map[string]float64, len(names))
+		for _, name := range names {
+			vars[name] = curves[name].at(t, fromLeft)
+		}
+		v, err := e.Eval(vars)
+		if err != nil {
+			return 0, fmt.Errorf("expression %q at %s: %w", src, t, err)
+		}
+		return v, nil
+	}
+
+	segments := make([]Segment, 0, len(breakpoints)-1)
+	for i := range len(breakpoints) - 1 {
+		from, to := breakpoints[i], breakpoints[i+1]
+		start, err := eval(from, false)
+		if err != nil {
+			return nil, err
+		}
+		target, err := eval(to, true)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, Segment{
+			Type:    "segment",
+			StartTs: config.DurationFromDuration(from),
+			EndTs:   config.DurationFromDuration(to),
+			Start:   &start,
+			Target:  target,
+		})
+	}
+	return segments, nil
+}
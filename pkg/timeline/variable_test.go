@@ -0,0 +1,114 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/script"
+)
+
+func TestScenarioVariables(t *testing.T) {
+	input := `{
+		"variables": {
+			"traffic_level": [
+				{"start_ts": "0s", "end_ts": "10m", "start": 1, "target": 2},
+				{"end_ts": "20m", "target": 1}
+			]
+		},
+		"metrics": [{
+			"name": "http.requests",
+			"type": "gauge",
+			"variants": [{"attributes": {"svc": "a"}, "expr": "traffic_level * 40"}]
+		}],
+		"traces": [{
+			"name": "checkout",
+			"exemplar": {"name": "POST /checkout", "duration": "40ms"},
+			"variants": [{"name": "ok", "expr": "traffic_level * 5"}]
+		}]
+	}`
+	tl, err := ParseTimeline([]byte(input))
+	require.NoError(t, err)
+	rscript := script.NewScript()
+	require.NoError(t, tl.MergeIntoScript(rscript))
+
+	var ramps [][2]float64
+	var rates []float64
+	for _, action := range rscript.Actions() {
+		switch {
+		case action.Type == "metricGenerator" && action.Spec["type"] == "ramp":
+			ramps = append(ramps, [2]float64{action.Spec["start"].(float64), action.Spec["target"].(float64)})
+		case action.Type == "traceRate":
+			rates = append(rates, action.Spec["rate"].(float64))
+		}
+	}
+	assert.Equal(t, [][2]float64{{40, 80}, {80, 40}}, ramps)
+	assert.Equal(t, []float64{10, 5}, rates)
+	require.NoError(t, rscript.Prepare(&config.Config{}))
+	assert.Equal(t, 20*time.Minute, rscript.Duration())
+}
+
+func TestExpandVariableExpr(t *testing.T) {
+	d := config.DurationFromDuration
+	one, ten := 1.0, 10.0
+	traffic, err := newVariableCurve("traffic", []Segment{
+		{StartTs: d(0), EndTs: d(20 * time.Minute), Start: &one, Target: 3},
+	})
+	require.NoError(t, err)
+	// errors jumps to 10 at 10m, after holding 0 in the gap.
+	errs, err := newVariableCurve("errors", []Segment{
+		{StartTs: d(0), EndTs: d(5 * time.Minute), Target: 0},
+		{StartTs: d(10 * time.Minute), EndTs: d(20 * time.Minute), Start: &ten, Target: 10},
+	})
+	require.NoError(t, err)
+
+	segments, err := expandVariableExpr("traffic + errors", map[string]variableCurve{"traffic": traffic, "errors": errs})
+	require.NoError(t, err)
+
+	var got [][4]float64
+	for _, s := range segments {
+		got = append(got, [4]float64{s.StartTs.Get().Minutes(), s.EndTs.Get().Minutes(), *s.Start, s.Target})
+	}
+	assert.Equal(t, [][4]float64{
+		{0, 5, 1, 1.5},
+		{5, 10, 1.5, 2},
+		{10, 20, 12, 13},
+	}, got)
+
+	_, err = expandVariableExpr("traffic * 2", map[string]variableCurve{})
+	assert.Error(t, err)
+	_, err = expandVariableExpr("42", map[string]variableCurve{"traffic": traffic})
+	assert.Error(t, err)
+	_, err = newVariableCurve("empty", nil)
+	assert.Error(t, err)
+}
+
+func TestVariantExprWithTimeline(t *testing.T) {
+	input := `{
+		"variables": {"v": [{"start_ts": "0s", "end_ts": "10m", "target": 1}]},
+		"metrics": [{"name": "m", "variants": [{
+			"expr": "v",
+			"timeline": [{"start_ts": "0s", "end_ts": "10m", "target": 1}]
+		}]}]
+	}`
+	tl, err := ParseTimeline([]byte(input))
+	require.NoError(t, err)
+	assert.Error(t, tl.MergeIntoScript(script.NewScript()))
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+)
+
+// Burst multiplies the producer's baseline rate by Multiplier from At
+// for Duration.  At is a script time, like the producer's own at.
+type Burst struct {
+	At         config.Duration `mapstructure:"at" yaml:"at" json:"at"`
+	Duration   config.Duration `mapstructure:"duration" yaml:"duration" json:"duration"`
+	Multiplier float64         `mapstructure:"multiplier" yaml:"multiplier" json:"multiplier"`
+}
+
+func validateBursts(bursts []Burst) error {
+	for _, b := range bursts {
+		if b.Duration.Get() <= 0 {
+			return errors.New("burst duration must be positive")
+		}
+		if b.Multiplier < 0 {
+			return errors.New("burst multiplier must not be negative")
+		}
+	}
+	return nil
+}
+
+// burstMultiplier is the combined multiplier of the bursts active at
+// now.  Overlapping bursts compound.
+func burstMultiplier(bursts []Burst, now time.Duration) float64 {
+	m := 1.0
+	for _, b := range bursts {
+		if now >= b.At.Get() && now < b.At.Get()+b.Duration.Get() {
+			m *= b.Multiplier
+		}
+	}
+	return m
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Exception describes the exception event recorded on a span that
+// errors, following the OpenTelemetry exception semantic conventions.
+// Message and Stacktrace may use {name} for the span name and {type}
+// for the exception type.
+type Exception struct {
+	Type       string `mapstructure:"type" yaml:"type" json:"type"`
+	Message    string `mapstructure:"message,omitempty" yaml:"message,omitempty" json:"message,omitempty"`
+	Stacktrace string `mapstructure:"stacktrace,omitempty" yaml:"stacktrace,omitempty" json:"stacktrace,omitempty"`
+}
+
+func (e *Exception) expand(template, spanName string) string {
+	return strings.NewReplacer("{name}", spanName, "{type}", e.Type).Replace(template)
+}
+
+// statusMessage is the status message for an erroring span.
+func statusMessage(s Span) string {
+	if s.StatusMessage != "" {
+		return s.StatusMessage
+	}
+	if s.Exception != nil && s.Exception.Message != "" {
+		return s.Exception.expand(s.Exception.Message, s.Name)
+	}
+	return "error"
+}
+
+func addExceptionEvent(ospan ptrace.Span, s Span, ts pcommon.Timestamp) {
+	event := ospan.Events().AppendEmpty()
+	event.SetName("exception")
+	event.SetTimestamp(ts)
+	attrs := event.Attributes()
+	attrs.PutStr("exception.type", s.Exception.Type)
+	if s.Exception.Message != "" {
+		attrs.PutStr("exception.message", s.Exception.expand(s.Exception.Message, s.Name))
+	}
+	if s.Exception.Stacktrace != "" {
+		attrs.PutStr("exception.stacktrace", s.Exception.expand(s.Exception.Stacktrace, s.Name))
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"encoding/binary"
+	"math/rand/v2"
+	"sync/atomic"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// Trace and span IDs are built from process-wide counters passed
+// through mix64.  When a run is split across workers, each worker's
+// shard index sits in the counter's top bits, so the workers count in
+// disjoint ranges.  mix64 is a bijection, so no two IDs from the counters
+// can collide within a run, on one worker or across them, while the
+// IDs still look random to a backend that buckets or samples by them.
+var (
+	traceSeq atomic.Uint64
+	spanSeq  atomic.Uint64
+	idShard  atomic.Uint64
+)
+
+// idShardShift leaves each shard 2^48 trace and span IDs.
+const idShardShift = 48
+
+// SetIDShard puts shard in the top bits of the ID counters, so workers
+// in one split run never produce the same IDs.
+func SetIDShard(shard int) {
+	idShard.Store(uint64(shard) << idShardShift)
+}
+
+// mix64 is the splitmix64 finalizer.  Each step is invertible, so
+// distinct inputs always give distinct outputs.
+func mix64(z uint64) uint64 {
+	z ^= z >> 30
+	z *= 0xbf58476d1ce4e5b9
+	z ^= z >> 27
+	z *= 0x94d049bb133111eb
+	z ^= z >> 31
+	return z
+}
+
+// NewTraceID returns a trace ID that is unique within the run.
+// The high 8 bytes come from the trace counter, and the low 8 bytes
+// from r, so seeded runs still vary the ID bits that samplers read.
+func NewTraceID(r *rand.Rand) pcommon.TraceID {
+	var id pcommon.TraceID
+	binary.BigEndian.PutUint64(id[:8], mix64(idShard.Load()|traceSeq.Add(1)))
+	binary.BigEndian.PutUint64(id[8:], r.Uint64())
+	if id.IsEmpty() {
+		id[15] = 1
+	}
+	return id
+}
+
+// NewSpanID returns a span ID that is unique within the run.
+func NewSpanID() pcommon.SpanID {
+	v := mix64(idShard.Load() | spanSeq.Add(1))
+	if v == 0 {
+		// The one counter value that mixes to the invalid all-zero ID.
+		v = mix64(idShard.Load() | spanSeq.Add(1))
+	}
+	var id pcommon.SpanID
+	binary.BigEndian.PutUint64(id[:], v)
+	return id
+}
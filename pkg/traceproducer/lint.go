@@ -0,0 +1,73 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// lintExemplar finds mistakes in an exemplar span tree that the
+// producer can still emit but that backends render as broken traces:
+// children outside their parent's time span, spans with no
+// service.name, and a server or client span directly under another of
+// the same kind.
+// lintFinding is one problem with one span.  Span is the path to it
+// from the root, by ref, or by name for spans without a ref.
+type lintFinding struct {
+	Span    string
+	Problem string
+}
+
+func lintExemplar(root Span) []lintFinding {
+	var findings []lintFinding
+	var walk func(s Span, path string, parent *Span)
+	walk = func(s Span, path string, parent *Span) {
+		report := func(format string, args ...any) {
+			findings = append(findings, lintFinding{Span: path, Problem: fmt.Sprintf(format, args...)})
+		}
+		if _, ok := s.ResourceAttributes["service.name"]; !ok {
+			report("no service.name resource attribute")
+		}
+		if parent != nil {
+			start, end := s.StartTs.Get(), s.StartTs.Get()+s.Duration.Get()
+			pstart, pend := parent.StartTs.Get(), parent.StartTs.Get()+parent.Duration.Get()
+			switch {
+			case start < pstart:
+				report("starts at %s, before its parent at %s", start, pstart)
+			case s.Duration.Get() > parent.Duration.Get():
+				report("lasts %s, longer than its parent's %s", s.Duration.Get(), parent.Duration.Get())
+			case end > pend:
+				report("ends at %s, after its parent at %s", end, pend)
+			}
+			kind := strings.ToLower(s.Kind)
+			if (kind == "server" || kind == "client") && kind == strings.ToLower(parent.Kind) {
+				report("%s span directly under a %s span", kind, kind)
+			}
+		}
+		for _, child := range s.Children {
+			walk(child, path+"/"+spanLabel(child), &s)
+		}
+	}
+	walk(root, spanLabel(root), nil)
+	return findings
+}
+
+func spanLabel(s Span) string {
+	if s.Ref != "" {
+		return s.Ref
+	}
+	return s.Name
+}
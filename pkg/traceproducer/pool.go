@@ -0,0 +1,139 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+)
+
+// ValuePool is a set of attribute values that spans draw from once per
+// emitted trace.  Either Values lists them, or Size synthesizes
+// Prefix0 through Prefix<Size-1>.
+type ValuePool struct {
+	Values       []string `mapstructure:"values,omitempty" yaml:"values,omitempty" json:"values,omitempty"`
+	Prefix       string   `mapstructure:"prefix,omitempty" yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	Size         int      `mapstructure:"size,omitempty" yaml:"size,omitempty" json:"size,omitempty"`
+	Distribution string   `mapstructure:"distribution,omitempty" yaml:"distribution,omitempty" json:"distribution,omitempty"`
+	Skew         float64  `mapstructure:"skew,omitempty" yaml:"skew,omitempty" json:"skew,omitempty"`
+}
+
+const defaultZipfSkew = 1.0
+
+// valuePool is a ValuePool ready to sample from.  cdf is only set for
+// zipf pools; uniform pools pick an index directly.
+type valuePool struct {
+	spec ValuePool
+	size int
+	cdf  []float64
+}
+
+func newValuePool(name string, spec ValuePool) (*valuePool, error) {
+	size := spec.Size
+	if len(spec.Values) > 0 {
+		size = len(spec.Values)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("pool %s: values or a positive size is required", name)
+	}
+	p := &valuePool{spec: spec, size: size}
+	switch spec.Distribution {
+	case "", "uniform":
+	case "zipf":
+		skew := spec.Skew
+		if skew == 0 {
+			skew = defaultZipfSkew
+		}
+		if skew < 0 {
+			return nil, fmt.Errorf("pool %s: skew must not be negative", name)
+		}
+		p.cdf = make([]float64, size)
+		total := 0.0
+		for i := range size {
+			total += 1 / math.Pow(float64(i+1), skew)
+			p.cdf[i] = total
+		}
+		for i := range p.cdf {
+			p.cdf[i] /= total
+		}
+	default:
+		return nil, fmt.Errorf("pool %s: unknown distribution %q", name, spec.Distribution)
+	}
+	return p, nil
+}
+
+func (p *valuePool) sample(r *rand.Rand) string {
+	idx := 0
+	if p.cdf != nil {
+		idx, _ = slices.BinarySearch(p.cdf, r.Float64())
+		idx = min(idx, p.size-1)
+	} else {
+		idx = r.IntN(p.size)
+	}
+	if len(p.spec.Values) > 0 {
+		return p.spec.Values[idx]
+	}
+	return p.spec.Prefix + strconv.Itoa(idx)
+}
+
+// samplePools draws one value from every pool, in name order so that
+// runs with the same seed draw the same values.
+func samplePools(pools map[string]*valuePool, r *rand.Rand) map[string]string {
+	if len(pools) == 0 {
+		return nil
+	}
+	names := slices.Sorted(maps.Keys(pools))
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		values[name] = pools[name].sample(r)
+	}
+	return values
+}
+
+func compilePools(pools map[string]ValuePool, roots []Span) (map[string]*valuePool, error) {
+	compiled := make(map[string]*valuePool, len(pools))
+	for name, spec := range pools {
+		p, err := newValuePool(name, spec)
+		if err != nil {
+			return nil, err
+		}
+		compiled[name] = p
+	}
+	for _, root := range roots {
+		if err := checkPoolRefs(compiled, root); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
+}
+
+func checkPoolRefs(pools map[string]*valuePool, s Span) error {
+	for attr, name := range s.AttributePools {
+		if _, ok := pools[name]; !ok {
+			return errors.New("span " + s.Name + ": attribute " + attr + " references unknown pool " + name)
+		}
+	}
+	for _, child := range s.Children {
+		if err := checkPoolRefs(pools, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"errors"
+	"math/rand/v2"
+)
+
+// sampledFlag is the W3C trace-flags bit marking a trace as sampled.
+const sampledFlag = 0x01
+
+// Sampling emulates an upstream head sampler.  Each trace is kept with
+// probability Rate, or the rate in Services for the root span's
+// service.name.  Kept traces have the sampled trace flag set.  Traces
+// that are not kept are dropped, or when Unsampled is "root", reduced
+// to an unsampled root span, as a service that records but does not
+// propagate would send.
+type Sampling struct {
+	Rate      float64            `mapstructure:"rate" yaml:"rate" json:"rate"`
+	Services  map[string]float64 `mapstructure:"services,omitempty" yaml:"services,omitempty" json:"services,omitempty"`
+	Unsampled string             `mapstructure:"unsampled,omitempty" yaml:"unsampled,omitempty" json:"unsampled,omitempty"`
+}
+
+func (s *Sampling) validate() error {
+	if s == nil {
+		return nil
+	}
+	if s.Rate < 0 || s.Rate > 1 {
+		return errors.New("sampling rate must be between 0 and 1")
+	}
+	for service, rate := range s.Services {
+		if rate < 0 || rate > 1 {
+			return errors.New("sampling rate for " + service + " must be between 0 and 1")
+		}
+	}
+	switch s.Unsampled {
+	case "", "drop", "root":
+		return nil
+	default:
+		return errors.New("unknown sampling unsampled mode: " + s.Unsampled)
+	}
+}
+
+// decide reports whether the trace is sampled, and whether anything
+// should be emitted for it.
+func (s *Sampling) decide(r *rand.Rand, root Span) (sampled, emit bool) {
+	rate := s.Rate
+	if service, ok := root.ResourceAttributes["service.name"].(string); ok {
+		if serviceRate, ok := s.Services[service]; ok {
+			rate = serviceRate
+		}
+	}
+	if r.Float64() < rate {
+		return true, true
+	}
+	return false, s.Unsampled == "root"
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"errors"
+	"math/rand/v2"
+)
+
+// ShapeVariation randomly reshapes the exemplar for each emitted
+// trace.  Every child subtree may be pruned with probability Prune,
+// or repeated with probability Duplicate.  The root span is at depth
+// 1.  Subtrees at or above MinDepth are never pruned, and spans below
+// MaxDepth are always cut.  Each span keeps between MinWidth and
+// MaxWidth children, where it has any to begin with.  Zero bounds are
+// unlimited.
+type ShapeVariation struct {
+	Prune     float64 `mapstructure:"prune,omitempty" yaml:"prune,omitempty" json:"prune,omitempty"`
+	Duplicate float64 `mapstructure:"duplicate,omitempty" yaml:"duplicate,omitempty" json:"duplicate,omitempty"`
+	MinDepth  int     `mapstructure:"minDepth,omitempty" yaml:"minDepth,omitempty" json:"minDepth,omitempty"`
+	MaxDepth  int     `mapstructure:"maxDepth,omitempty" yaml:"maxDepth,omitempty" json:"maxDepth,omitempty"`
+	MinWidth  int     `mapstructure:"minWidth,omitempty" yaml:"minWidth,omitempty" json:"minWidth,omitempty"`
+	MaxWidth  int     `mapstructure:"maxWidth,omitempty" yaml:"maxWidth,omitempty" json:"maxWidth,omitempty"`
+}
+
+func (v *ShapeVariation) validate() error {
+	if v == nil {
+		return nil
+	}
+	if v.Prune < 0 || v.Prune > 1 || v.Duplicate < 0 || v.Duplicate > 1 {
+		return errors.New("shape prune and duplicate must be between 0 and 1")
+	}
+	if v.MinDepth < 0 || v.MaxDepth < 0 || v.MinWidth < 0 || v.MaxWidth < 0 {
+		return errors.New("shape bounds must not be negative")
+	}
+	if v.MaxDepth > 0 && v.MinDepth > v.MaxDepth {
+		return errors.New("shape minDepth must not exceed maxDepth")
+	}
+	if v.MaxWidth > 0 && v.MinWidth > v.MaxWidth {
+		return errors.New("shape minWidth must not exceed maxWidth")
+	}
+	return nil
+}
+
+// apply returns a reshaped copy of root.  Only the Children slices are
+// copied; the spans share everything else with the exemplar.
+func (v *ShapeVariation) apply(r *rand.Rand, root Span) Span {
+	if v == nil {
+		return root
+	}
+	root.Children = v.children(r, root.Children, 1)
+	return root
+}
+
+func (v *ShapeVariation) children(r *rand.Rand, children []Span, depth int) []Span {
+	if len(children) == 0 {
+		return nil
+	}
+	childDepth := depth + 1
+	if v.MaxDepth > 0 && childDepth > v.MaxDepth {
+		return nil
+	}
+	out := make([]Span, 0, len(children))
+	for _, child := range children {
+		if childDepth <= v.MinDepth || r.Float64() >= v.Prune {
+			out = append(out, child)
+		}
+		if r.Float64() < v.Duplicate {
+			out = append(out, child)
+		}
+	}
+	for len(out) < v.MinWidth {
+		out = append(out, children[r.IntN(len(children))])
+	}
+	if v.MaxWidth > 0 && len(out) > v.MaxWidth {
+		out = out[:v.MaxWidth]
+	}
+	for i := range out {
+		out[i].Children = v.children(r, out[i].Children, childDepth)
+	}
+	return out
+}
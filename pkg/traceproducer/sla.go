@@ -0,0 +1,92 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+)
+
+const DefaultSLAEventName = "sla.breach"
+
+// SLA records every root span that runs longer than Threshold, so an
+// alert on slow requests always has matching traces to link to.  The
+// record is a span event, named EventName, or with Log set a log
+// record carrying the span's trace and span IDs.  Either way it holds
+// Attributes along with the threshold and the actual duration in
+// milliseconds.
+type SLA struct {
+	Threshold  config.Duration `mapstructure:"threshold" yaml:"threshold" json:"threshold"`
+	EventName  string          `mapstructure:"eventName,omitempty" yaml:"eventName,omitempty" json:"eventName,omitempty"`
+	Attributes map[string]any  `mapstructure:"attributes,omitempty" yaml:"attributes,omitempty" json:"attributes,omitempty"`
+	Log        bool            `mapstructure:"log,omitempty" yaml:"log,omitempty" json:"log,omitempty"`
+}
+
+func (s *SLA) validate() error {
+	if s == nil {
+		return nil
+	}
+	if s.Threshold.Get() <= 0 {
+		return errors.New("sla threshold must be positive")
+	}
+	return nil
+}
+
+// record adds a breach for ospan, which belongs to the resource rattr,
+// as a span event or, with Log set, as a log record in logs.
+func (s *SLA) record(ospan ptrace.Span, rattr pcommon.Map, logs plog.Logs) error {
+	start, end := ospan.StartTimestamp().AsTime(), ospan.EndTimestamp().AsTime()
+	duration := end.Sub(start)
+	if duration <= s.Threshold.Get() {
+		return nil
+	}
+	name := s.EventName
+	if name == "" {
+		name = DefaultSLAEventName
+	}
+	var attrs pcommon.Map
+	if s.Log {
+		rl := logs.ResourceLogs().AppendEmpty()
+		rattr.CopyTo(rl.Resource().Attributes())
+		lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.SetTimestamp(ospan.EndTimestamp())
+		lr.SetObservedTimestamp(ospan.EndTimestamp())
+		lr.SetEventName(name)
+		lr.SetSeverityNumber(plog.SeverityNumberWarn)
+		lr.SetSeverityText("WARN")
+		lr.Body().SetStr(ospan.Name() + " exceeded its SLA")
+		lr.SetTraceID(ospan.TraceID())
+		lr.SetSpanID(ospan.SpanID())
+		attrs = lr.Attributes()
+	} else {
+		event := ospan.Events().AppendEmpty()
+		event.SetName(name)
+		event.SetTimestamp(ospan.EndTimestamp())
+		attrs = event.Attributes()
+	}
+	if err := attrs.FromRaw(s.Attributes); err != nil {
+		return err
+	}
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	attrs.PutDouble("sla.threshold_ms", ms(s.Threshold.Get()))
+	attrs.PutDouble("duration_ms", ms(duration))
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+)
+
+// StartSpread controls where root spans start within the second that
+// a tick covers.  Spans start within Window of the beginning of that
+// second, placed by Distribution:
+//
+//   - uniform spreads them evenly across the window (the default).
+//   - normal centers them in the window.
+//   - exponential front-loads them, as a burst at the start of the window.
+type StartSpread struct {
+	Window       config.Duration `mapstructure:"window,omitempty" yaml:"window,omitempty" json:"window,omitempty"`
+	Distribution string          `mapstructure:"distribution,omitempty" yaml:"distribution,omitempty" json:"distribution,omitempty"`
+}
+
+func (s StartSpread) validate() error {
+	if s.Window.Duration < 0 || s.Window.Duration > time.Second {
+		return errors.New("startSpread window must be between 0 and 1s")
+	}
+	switch s.Distribution {
+	case "", "uniform", "normal", "exponential":
+		return nil
+	default:
+		return errors.New("unknown startSpread distribution: " + s.Distribution)
+	}
+}
+
+// offset returns how far into the tick's second a root span starts.
+func (s StartSpread) offset(r *rand.Rand) time.Duration {
+	window := s.Window.Duration
+	if window == 0 {
+		window = time.Second
+	}
+	var frac float64
+	switch s.Distribution {
+	case "normal":
+		frac = 0.5 + r.NormFloat64()/6
+	case "exponential":
+		frac = r.ExpFloat64() / 5
+	default:
+		return time.Duration(r.Int64N(int64(window)))
+	}
+	frac = min(max(frac, 0), 1)
+	return min(time.Duration(frac*float64(window)), window-1)
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ResolveSubtrees returns a copy of s where every span that sets Use
+// is replaced by the shared subtree of that name.  A start_ts on the
+// referencing span overrides the subtree's own, so one subtree can be
+// placed at different points in different exemplars.  Subtrees may
+// use other subtrees, but not themselves.
+func ResolveSubtrees(s Span, subtrees map[string]Span) (Span, error) {
+	return resolveSubtrees(s, subtrees, nil)
+}
+
+func resolveSubtrees(s Span, subtrees map[string]Span, using []string) (Span, error) {
+	if s.Use != "" {
+		if slices.Contains(using, s.Use) {
+			return Span{}, fmt.Errorf("subtree %s uses itself", s.Use)
+		}
+		shared, ok := subtrees[s.Use]
+		if !ok {
+			return Span{}, fmt.Errorf("span references unknown subtree %s", s.Use)
+		}
+		using = append(using, s.Use)
+		if s.StartTs.Get() != 0 {
+			shared.StartTs = s.StartTs
+		}
+		s = shared
+	}
+	if len(s.Children) == 0 {
+		return s, nil
+	}
+	children := make([]Span, len(s.Children))
+	for i, child := range s.Children {
+		resolved, err := resolveSubtrees(child, subtrees, using)
+		if err != nil {
+			return Span{}, err
+		}
+		children[i] = resolved
+	}
+	s.Children = children
+	return s, nil
+}
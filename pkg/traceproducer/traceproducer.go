@@ -15,15 +15,19 @@
 package traceproducer
 
 import (
+	"errors"
+	"log/slog"
 	"math/rand/v2"
 	"strings"
 	"time"
 
 	"github.com/cardinalhq/oteltools/signalbuilder"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
 	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/scriptaction"
 	"github.com/cardinalhq/flutter/pkg/state"
 )
 
@@ -34,63 +38,205 @@ type Attributes struct {
 }
 
 type Span struct {
-	Ref                string          `json:"ref"`
-	Name               string          `json:"name"`
-	Kind               string          `json:"kind"`
-	StartTs            config.Duration `json:"start_ts"`
-	Duration           config.Duration `json:"duration"`
-	Error              bool            `json:"error"`
-	ResourceAttributes map[string]any  `json:"resourceAttributes"`
-	Attributes         map[string]any  `json:"attributes"`
-	Children           []Span          `json:"children"`
+	Ref                string                     `mapstructure:"ref" json:"ref"`
+	Use                string                     `mapstructure:"use" json:"use,omitempty"`
+	Name               string                     `mapstructure:"name" json:"name"`
+	Kind               string                     `mapstructure:"kind" json:"kind"`
+	StartTs            config.Duration            `mapstructure:"start_ts" json:"start_ts"`
+	Duration           config.Duration            `mapstructure:"duration" json:"duration"`
+	Error              bool                       `mapstructure:"error" json:"error"`
+	StatusMessage      string                     `mapstructure:"statusMessage" json:"statusMessage,omitempty"`
+	Exception          *Exception                 `mapstructure:"exception" json:"exception,omitempty"`
+	TraceState         string                     `mapstructure:"traceState" json:"traceState,omitempty"`
+	TraceFlags         *uint32                    `mapstructure:"traceFlags" json:"traceFlags,omitempty"`
+	ResourceAttributes map[string]any             `mapstructure:"resourceAttributes" json:"resourceAttributes"`
+	Attributes         map[string]any             `mapstructure:"attributes" json:"attributes"`
+	AttributePools     map[string]string          `mapstructure:"attributePools" json:"attributePools,omitempty"`
+	WeightedAttributes map[string][]WeightedValue `mapstructure:"weightedAttributes" json:"weightedAttributes,omitempty"`
+	Children           []Span                     `mapstructure:"children" json:"children"`
 }
 
 type TraceProducer interface {
 	Emit(state *state.RunState, tb *signalbuilder.TracesBuilder) error
 	SetRate(at time.Duration, to time.Duration, now time.Duration, rate float64)
 	SetStart(start float64)
+	Reconfigure(now time.Duration, spec map[string]any) error
+	Enable()
+	Disable()
+	IsDisabled() bool
+}
+
+// LogProducer is implemented by trace producers that also produce log
+// records, such as SLA breaches sent as logs.  TakeLogs returns the
+// records produced since the last call.
+type LogProducer interface {
+	TakeLogs() plog.Logs
+}
+
+// WeightedExemplar is one of several exemplars a producer chooses
+// between for each trace, in proportion to Weight.
+type WeightedExemplar struct {
+	Weight   float64 `mapstructure:"weight" yaml:"weight" json:"weight"`
+	Exemplar Span    `mapstructure:"exemplar" yaml:"exemplar" json:"exemplar"`
 }
 
 type TraceProducerSpec struct {
-	At       time.Duration `mapstructure:"at,omitempty" yaml:"at,omitempty" json:"at,omitempty"`
-	To       time.Duration `mapstructure:"to,omitempty" yaml:"to,omitempty" json:"to,omitempty"`
-	Exemplar Span          `mapstructure:"exemplar" yaml:"exemplar" json:"exemplar"`
-	Disabled bool          `mapstructure:"disabled,omitempty" yaml:"disabled,omitempty" json:"disabled,omitempty"`
-	Rate     float64       `mapstructure:"rate,omitempty" yaml:"rate,omitempty" json:"rate,omitempty"`
+	At          time.Duration        `mapstructure:"at,omitempty" yaml:"at,omitempty" json:"at,omitempty"`
+	To          time.Duration        `mapstructure:"to,omitempty" yaml:"to,omitempty" json:"to,omitempty"`
+	Exemplar    Span                 `mapstructure:"exemplar" yaml:"exemplar" json:"exemplar"`
+	Exemplars   []WeightedExemplar   `mapstructure:"exemplars,omitempty" yaml:"exemplars,omitempty" json:"exemplars,omitempty"`
+	Disabled    bool                 `mapstructure:"disabled,omitempty" yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	Rate        float64              `mapstructure:"rate,omitempty" yaml:"rate,omitempty" json:"rate,omitempty"`
+	Pools       map[string]ValuePool `mapstructure:"pools,omitempty" yaml:"pools,omitempty" json:"pools,omitempty"`
+	StartSpread StartSpread          `mapstructure:"startSpread,omitempty" yaml:"startSpread,omitempty" json:"startSpread,omitempty"`
+	Shape       *ShapeVariation      `mapstructure:"shape,omitempty" yaml:"shape,omitempty" json:"shape,omitempty"`
+	Subtrees    map[string]Span      `mapstructure:"subtrees,omitempty" yaml:"subtrees,omitempty" json:"subtrees,omitempty"`
+	Sampling    *Sampling            `mapstructure:"sampling,omitempty" yaml:"sampling,omitempty" json:"sampling,omitempty"`
+	Bursts      []Burst              `mapstructure:"bursts,omitempty" yaml:"bursts,omitempty" json:"bursts,omitempty"`
+	SLA         *SLA                 `mapstructure:"sla,omitempty" yaml:"sla,omitempty" json:"sla,omitempty"`
 }
 
-var idRNG = state.MakeRNG(0)
+// CreateTraceProducer builds a trace producer from a script action's
+// spec.  The action's at and to are used unless the spec sets them.
+func CreateTraceProducer(action scriptaction.ScriptAction) (TraceProducer, error) {
+	if action.Spec == nil {
+		return nil, errors.New("missing spec in trace producer")
+	}
+	spec := TraceProducerSpec{
+		At: action.At,
+		To: action.To,
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(action.Spec); err != nil {
+		return nil, err
+	}
+	if spec.Exemplar.Name == "" && len(spec.Exemplars) == 0 {
+		return nil, errors.New("missing exemplar name in trace producer spec")
+	}
+	return NewTraceProducer(spec)
+}
 
 func NewTraceProducer(spec TraceProducerSpec) (TraceProducer, error) {
+	pools, err := spec.prepare()
+	if err != nil {
+		return nil, err
+	}
 	return &exemplar{
 		TraceProducerSpec: spec,
 		start:             spec.Rate,
+		pools:             pools,
+		logs:              plog.NewLogs(),
 	}, nil
 }
 
+// prepare resolves the spec's subtrees, checks it, and compiles its
+// value pools.
+func (spec *TraceProducerSpec) prepare() (map[string]*valuePool, error) {
+	var err error
+	spec.Exemplar, err = ResolveSubtrees(spec.Exemplar, spec.Subtrees)
+	if err != nil {
+		return nil, err
+	}
+	roots := []Span{spec.Exemplar}
+	for i := range spec.Exemplars {
+		if spec.Exemplars[i].Weight <= 0 {
+			return nil, errors.New("exemplar weights must be positive")
+		}
+		spec.Exemplars[i].Exemplar, err = ResolveSubtrees(spec.Exemplars[i].Exemplar, spec.Subtrees)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, spec.Exemplars[i].Exemplar)
+	}
+	for _, root := range roots {
+		for _, f := range lintExemplar(root) {
+			slog.Warn("Trace exemplar looks wrong", "span", f.Span, "problem", f.Problem)
+		}
+	}
+	pools, err := compilePools(spec.Pools, roots)
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range roots {
+		if err := checkWeighted(root); err != nil {
+			return nil, err
+		}
+	}
+	if err := spec.StartSpread.validate(); err != nil {
+		return nil, err
+	}
+	if err := spec.Shape.validate(); err != nil {
+		return nil, err
+	}
+	if err := spec.Sampling.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateBursts(spec.Bursts); err != nil {
+		return nil, err
+	}
+	if err := spec.SLA.validate(); err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
+
+// pick returns the exemplar for one emitted trace: one of Exemplars,
+// chosen by weight, or Exemplar when there are none.
+func (spec *TraceProducerSpec) pick(r *rand.Rand) Span {
+	if len(spec.Exemplars) == 0 {
+		return spec.Exemplar
+	}
+	total := 0.0
+	for _, e := range spec.Exemplars {
+		total += e.Weight
+	}
+	x := r.Float64() * total
+	for _, e := range spec.Exemplars {
+		x -= e.Weight
+		if x < 0 {
+			return e.Exemplar
+		}
+	}
+	return spec.Exemplars[len(spec.Exemplars)-1].Exemplar
+}
+
 type exemplar struct {
 	TraceProducerSpec
 
 	start float64
+	pools map[string]*valuePool
+	logs  plog.Logs
 }
 
-func randomTraceID(r *rand.Rand) pcommon.TraceID {
-	traceidBytes := make([]byte, 16)
-	for i := range 16 {
-		traceidBytes[i] = byte(r.IntN(256))
-	}
-
-	return pcommon.TraceID(traceidBytes)
+var _ LogProducer = (*exemplar)(nil)
+
+// emission is the state shared by every span of one emitted trace.
+type emission struct {
+	now        time.Time
+	jitter0    time.Duration
+	jitter1    time.Duration
+	tb         *signalbuilder.TracesBuilder
+	rnd        *rand.Rand
+	traceID    pcommon.TraceID
+	poolValues map[string]string
+	rootOnly   bool
+	sla        *SLA
+	logs       plog.Logs
+	sampling   samplingDecision
+	traceState string
+	flags      uint32
 }
 
-func randomSpanID(r *rand.Rand) pcommon.SpanID {
-	spanidBytes := make([]byte, 8)
-	for i := range 8 {
-		spanidBytes[i] = byte(r.IntN(256))
-	}
+type samplingDecision int
 
-	return pcommon.SpanID(spanidBytes)
-}
+const (
+	samplingNone samplingDecision = iota
+	samplingSampled
+	samplingUnsampled
+)
 
 // intrerpolate linearly interpolates from start → target over the given duration,
 // beginning at offset startAt, and evaluated at offset at.
@@ -110,11 +256,11 @@ func intrerpolate(start, target float64, startAt, now, duration time.Duration) f
 }
 
 func (t *exemplar) Emit(rs *state.RunState, tb *signalbuilder.TracesBuilder) error {
-	if t.Disabled || rs.Tick < t.At || rs.Tick > t.To {
+	if t.Disabled || rs.Tick < t.At || (t.To != 0 && rs.Tick > t.To) {
 		return nil
 	}
 
-	rate := intrerpolate(t.start, t.Rate, t.At, rs.Tick, t.To-t.At)
+	rate := t.rateAt(rs.Tick) * burstMultiplier(t.Bursts, rs.Tick) / float64(rs.DegradeFactor())
 	rateJitter := scaledKindaNormal(rs.RND) * (rate * 0.1)
 	if rate < 10 {
 		if rateJitter < 0 {
@@ -129,10 +275,32 @@ func (t *exemplar) Emit(rs *state.RunState, tb *signalbuilder.TracesBuilder) err
 	}
 	for range int(rate) {
 		offset := rs.Wallclock.Add(-time.Second)
-		offset = offset.Add(time.Duration(rs.RND.Int64N(int64(time.Second))))
-		jitter0 := time.Duration(scaledKindaNormal(rs.RND)*2) * time.Millisecond
-		jitter1 := time.Duration(scaledKindaNormal(rs.RND)*2) * time.Millisecond
-		if err := emitSpan(offset, jitter0, jitter1, tb, t.Exemplar, randomTraceID(rs.RND), pcommon.NewSpanIDEmpty()); err != nil {
+		offset = offset.Add(t.StartSpread.offset(rs.RND))
+		em := &emission{
+			now:     offset,
+			jitter0: time.Duration(scaledKindaNormal(rs.RND)*2) * time.Millisecond,
+			jitter1: time.Duration(scaledKindaNormal(rs.RND)*2) * time.Millisecond,
+			tb:      tb,
+			rnd:     rs.RND,
+			sla:     t.SLA,
+			logs:    t.logs,
+		}
+		em.traceID = NewTraceID(rs.RND)
+		em.poolValues = samplePools(t.pools, rs.RND)
+		root := t.pick(rs.RND)
+		if t.Sampling != nil {
+			sampled, emit := t.Sampling.decide(rs.RND, root)
+			if !emit {
+				continue
+			}
+			if sampled {
+				em.sampling = samplingSampled
+			} else {
+				em.sampling = samplingUnsampled
+				em.rootOnly = true
+			}
+		}
+		if err := em.emitSpan(t.Shape.apply(rs.RND, root), pcommon.NewSpanIDEmpty()); err != nil {
 			return err
 		}
 	}
@@ -150,7 +318,7 @@ func scaledKindaNormal(r *rand.Rand) float64 {
 	}
 }
 
-func emitSpan(now time.Time, jitter0, jitter1 time.Duration, tb *signalbuilder.TracesBuilder, s Span, traceID pcommon.TraceID, parentSpanID pcommon.SpanID) error {
+func (em *emission) emitSpan(s Span, parentSpanID pcommon.SpanID) error {
 	rattr := pcommon.NewMap()
 	if err := rattr.FromRaw(s.ResourceAttributes); err != nil {
 		return err
@@ -158,32 +326,66 @@ func emitSpan(now time.Time, jitter0, jitter1 time.Duration, tb *signalbuilder.T
 
 	sattr := pcommon.NewMap()
 
-	ospan := tb.Resource(rattr).Scope(sattr).AddSpan()
+	ospan := em.tb.Resource(rattr).Scope(sattr).AddSpan()
 
 	if err := ospan.Attributes().FromRaw(s.Attributes); err != nil {
 		return err
 	}
+	for attr, pool := range s.AttributePools {
+		ospan.Attributes().PutStr(attr, em.poolValues[pool])
+	}
+	for attr, value := range sampleWeighted(em.rnd, s) {
+		if err := ospan.Attributes().PutEmpty(attr).FromRaw(value); err != nil {
+			return err
+		}
+	}
 
-	spanID := randomSpanID(idRNG)
+	spanID := NewSpanID()
 
-	ospan.SetTraceID(traceID)
+	ospan.SetTraceID(em.traceID)
 	ospan.SetSpanID(spanID)
 	ospan.SetParentSpanID(parentSpanID)
 	ospan.SetName(s.Name)
+	// Trace state and flags carry down to children that don't set their own.
+	parentState, parentFlags := em.traceState, em.flags
+	defer func() { em.traceState, em.flags = parentState, parentFlags }()
+	if s.TraceState != "" {
+		em.traceState = s.TraceState
+	}
+	if s.TraceFlags != nil {
+		em.flags = *s.TraceFlags
+	}
+	flags := em.flags
+	switch em.sampling {
+	case samplingSampled:
+		flags |= sampledFlag
+	case samplingUnsampled:
+		flags &^= sampledFlag
+	}
+	ospan.SetFlags(flags)
+	ospan.TraceState().FromRaw(em.traceState)
 
-	stime := now.Add(s.StartTs.Get())
+	stime := em.now.Add(s.StartTs.Get())
 	scale := len(s.Children) + 1
-	j0ms := jitter0 * time.Duration(scale)
+	j0ms := em.jitter0 * time.Duration(scale)
 	sts := stime.Add(-j0ms)
 	ospan.SetStartTimestamp(pcommon.NewTimestampFromTime(sts))
 
-	j1ms := jitter1 * time.Duration(scale)
+	j1ms := em.jitter1 * time.Duration(scale)
 	ets := stime.Add(s.Duration.Get() + j1ms*time.Duration(scale))
 	ospan.SetEndTimestamp(pcommon.NewTimestampFromTime(ets))
+	if em.sla != nil && parentSpanID.IsEmpty() {
+		if err := em.sla.record(ospan, rattr, em.logs); err != nil {
+			return err
+		}
+	}
 
 	if s.Error {
 		ospan.Status().SetCode(ptrace.StatusCodeError)
-		ospan.Status().SetMessage("error")
+		ospan.Status().SetMessage(statusMessage(s))
+		if s.Exception != nil {
+			addExceptionEvent(ospan, s, ospan.EndTimestamp())
+		}
 	} else {
 		ospan.Status().SetCode(ptrace.StatusCodeOk)
 		ospan.Status().SetMessage("")
@@ -204,8 +406,11 @@ func emitSpan(now time.Time, jitter0, jitter1 time.Duration, tb *signalbuilder.T
 		ospan.SetKind(ptrace.SpanKindUnspecified)
 	}
 
+	if em.rootOnly {
+		return nil
+	}
 	for _, child := range s.Children {
-		if err := emitSpan(now, jitter0, jitter1, tb, child, traceID, spanID); err != nil {
+		if err := em.emitSpan(child, spanID); err != nil {
 			return err
 		}
 	}
@@ -213,14 +418,73 @@ func emitSpan(now time.Time, jitter0, jitter1 time.Duration, tb *signalbuilder.T
 	return nil
 }
 
+// rateAt returns the base rate at now, before bursts, degradation,
+// and jitter.
+func (t *exemplar) rateAt(now time.Duration) float64 {
+	return intrerpolate(t.start, t.Rate, t.At, now, t.To-t.At)
+}
+
 func (t *exemplar) SetRate(at time.Duration, to time.Duration, now time.Duration, rate float64) {
-	current := intrerpolate(t.start, t.Rate, t.At, now, t.To-t.At)
-	t.start = current
+	t.start = t.rateAt(now)
 	t.At = at
 	t.To = to
 	t.Rate = rate
 }
 
+func (t *exemplar) TakeLogs() plog.Logs {
+	ld := t.logs
+	t.logs = plog.NewLogs()
+	return ld
+}
+
 func (t *exemplar) SetStart(start float64) {
 	t.start = start
 }
+
+// Reconfigure applies a partial TraceProducerSpec.  An exemplar or
+// exemplars in the spec replace the current ones rather than being
+// merged into them, and a new rate is approached from the rate in
+// effect at now.  Unless the spec sets at, the approach starts at now
+// and runs until the producer's to, or takes effect at once when
+// there is none.
+func (t *exemplar) Reconfigure(now time.Duration, is map[string]any) error {
+	spec := t.TraceProducerSpec
+	if _, ok := is["exemplar"]; ok {
+		spec.Exemplar = Span{}
+	}
+	if _, ok := is["exemplars"]; ok {
+		spec.Exemplars = nil
+	}
+	decoder, err := config.NewMapstructureDecoder(&spec)
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(is); err != nil {
+		return err
+	}
+	pools, err := spec.prepare()
+	if err != nil {
+		return err
+	}
+	if _, ok := is["rate"]; ok {
+		t.start = t.rateAt(now)
+		if _, ok := is["at"]; !ok {
+			spec.At = now
+		}
+	}
+	t.TraceProducerSpec = spec
+	t.pools = pools
+	return nil
+}
+
+func (t *exemplar) Enable() {
+	t.Disabled = false
+}
+
+func (t *exemplar) Disable() {
+	t.Disabled = true
+}
+
+func (t *exemplar) IsDisabled() bool {
+	return t.Disabled
+}
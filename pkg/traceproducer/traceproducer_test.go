@@ -15,8 +15,19 @@
 package traceproducer
 
 import (
+	"math"
 	"math/rand/v2"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/cardinalhq/oteltools/signalbuilder"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/cardinalhq/flutter/pkg/config"
+	"github.com/cardinalhq/flutter/pkg/state"
 )
 
 func TestScaledKindaNormal_Range(t *testing.T) {
@@ -28,3 +39,811 @@ func TestScaledKindaNormal_Range(t *testing.T) {
 		}
 	}
 }
+
+func TestReconfigure(t *testing.T) {
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		To:       10 * time.Minute,
+		Rate:     20,
+		Exemplar: Span{Name: "GET /old", Kind: "server"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ex := tp.(*exemplar)
+	if r := ex.rateAt(time.Minute); r != 20 {
+		t.Errorf("expected rate 20 before the action, got %v", r)
+	}
+
+	err = tp.Reconfigure(time.Minute, map[string]any{
+		"rate": 40.0,
+		"exemplar": map[string]any{
+			"name":     "GET /new",
+			"kind":     "server",
+			"duration": "250ms",
+			"error":    true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The new rate ramps from 20 at the action to 40 at the producer's to.
+	for _, tt := range []struct {
+		at   time.Duration
+		want float64
+	}{
+		{time.Minute, 20},
+		{11 * time.Minute / 2, 30},
+		{10 * time.Minute, 40},
+	} {
+		if r := ex.rateAt(tt.at); math.Abs(r-tt.want) > 1e-9 {
+			t.Errorf("expected rate %v at %v, got %v", tt.want, tt.at, r)
+		}
+	}
+
+	rs := state.NewRunState(10*time.Minute, 1)
+	rs.Tick = time.Minute
+	rs.Wallclock = time.Unix(1700000000, 0)
+	tb := signalbuilder.NewTracesBuilder()
+	if err := tp.Emit(rs, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	td := tb.Build()
+	if td.SpanCount() == 0 {
+		t.Fatal("expected spans after reconfigure")
+	}
+	span := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	if span.Name() != "GET /new" {
+		t.Errorf("expected span name %q, got %q", "GET /new", span.Name())
+	}
+	if span.Status().Code() != ptrace.StatusCodeError {
+		t.Errorf("expected error status, got %v", span.Status().Code())
+	}
+	if d := span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()); d < 200*time.Millisecond {
+		t.Errorf("expected a duration near 250ms, got %v", d)
+	}
+}
+
+func TestReconfigure_UnknownField(t *testing.T) {
+	tp, err := NewTraceProducer(TraceProducerSpec{To: time.Minute, Rate: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tp.Reconfigure(0, map[string]any{"latency": "1s"}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestValuePool_Zipf(t *testing.T) {
+	p, err := newValuePool("users", ValuePool{Prefix: "user-", Size: 10000, Distribution: "zipf"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	counts := map[string]int{}
+	for range 10000 {
+		counts[p.sample(r)]++
+	}
+	if counts["user-0"] <= counts["user-1"] || counts["user-1"] <= counts["user-9"] {
+		t.Errorf("expected zipf ordering, got user-0=%d user-1=%d user-9=%d", counts["user-0"], counts["user-1"], counts["user-9"])
+	}
+	if len(counts) < 1000 {
+		t.Errorf("expected a long tail of values, got %d distinct", len(counts))
+	}
+}
+
+func TestValuePool_Errors(t *testing.T) {
+	if _, err := newValuePool("empty", ValuePool{}); err == nil {
+		t.Error("expected an error for an empty pool")
+	}
+	if _, err := newValuePool("bad", ValuePool{Size: 3, Distribution: "pareto"}); err == nil {
+		t.Error("expected an error for an unknown distribution")
+	}
+	_, err := NewTraceProducer(TraceProducerSpec{
+		Exemplar: Span{Name: "root", AttributePools: map[string]string{"user.id": "users"}},
+	})
+	if err == nil {
+		t.Error("expected an error for an unknown pool reference")
+	}
+}
+
+func TestAttributePools_SampledPerTrace(t *testing.T) {
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		To:   time.Minute,
+		Rate: 50,
+		Pools: map[string]ValuePool{
+			"users": {Prefix: "user-", Size: 1000},
+		},
+		Exemplar: Span{
+			Name:           "root",
+			AttributePools: map[string]string{"user.id": "users"},
+			Children: []Span{
+				{Name: "child", AttributePools: map[string]string{"enduser.id": "users"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Tick = time.Second
+	rs.Wallclock = time.Unix(1700000000, 0)
+	tb := signalbuilder.NewTracesBuilder()
+	if err := tp.Emit(rs, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byTrace := map[pcommon.TraceID][]string{}
+	spans := tb.Build().ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := range spans.Len() {
+		span := spans.At(i)
+		key := "user.id"
+		if span.Name() == "child" {
+			key = "enduser.id"
+		}
+		v, ok := span.Attributes().Get(key)
+		if !ok {
+			t.Fatalf("span %s is missing %s", span.Name(), key)
+		}
+		byTrace[span.TraceID()] = append(byTrace[span.TraceID()], v.Str())
+	}
+	distinct := map[string]bool{}
+	for id, values := range byTrace {
+		if len(values) != 2 || values[0] != values[1] {
+			t.Errorf("trace %s: expected one pool value for all spans, got %v", id, values)
+		}
+		distinct[values[0]] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("expected values to vary across traces, got %v", distinct)
+	}
+}
+
+func TestWeightedAttributes(t *testing.T) {
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		To:   time.Minute,
+		Rate: 400,
+		Exemplar: Span{
+			Name: "GET",
+			WeightedAttributes: map[string][]WeightedValue{
+				"url.template": {
+					{Value: "/cart", Weight: 3},
+					{Value: "/checkout", Weight: 1},
+				},
+				"http.response.status_code": {
+					{Value: 200, Weight: 1},
+					{Value: 500, Weight: 0},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Tick = time.Second
+	rs.Wallclock = time.Unix(1700000000, 0)
+	tb := signalbuilder.NewTracesBuilder()
+	if err := tp.Emit(rs, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	routes := map[string]int{}
+	spans := tb.Build().ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := range spans.Len() {
+		attrs := spans.At(i).Attributes()
+		route, _ := attrs.Get("url.template")
+		routes[route.Str()]++
+		status, _ := attrs.Get("http.response.status_code")
+		if status.Int() != 200 {
+			t.Errorf("expected only status 200, got %v", status.AsRaw())
+		}
+	}
+	if routes["/cart"] <= routes["/checkout"] || routes["/checkout"] == 0 {
+		t.Errorf("expected a 3:1 route mix, got %v", routes)
+	}
+}
+
+func TestWeightedAttributes_Errors(t *testing.T) {
+	_, err := NewTraceProducer(TraceProducerSpec{
+		Exemplar: Span{
+			Name:               "GET",
+			WeightedAttributes: map[string][]WeightedValue{"url.template": {{Value: "/", Weight: 0}}},
+		},
+	})
+	if err == nil {
+		t.Error("expected an error when no weight is positive")
+	}
+}
+
+func TestErrorException(t *testing.T) {
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		To:   time.Minute,
+		Rate: 20,
+		Exemplar: Span{
+			Name:  "charge",
+			Error: true,
+			Exception: &Exception{
+				Type:       "PaymentDeclined",
+				Message:    "card declined in {name}",
+				Stacktrace: "{type}: card declined\n\tat billing.Charge(billing.go:42)",
+			},
+			Children: []Span{{Name: "plain", Error: true}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Tick = time.Second
+	rs.Wallclock = time.Unix(1700000000, 0)
+	tb := signalbuilder.NewTracesBuilder()
+	if err := tp.Emit(rs, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := tb.Build().ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := range spans.Len() {
+		span := spans.At(i)
+		switch span.Name() {
+		case "charge":
+			if span.Status().Message() != "card declined in charge" {
+				t.Errorf("unexpected status message %q", span.Status().Message())
+			}
+			if span.Events().Len() != 1 {
+				t.Fatalf("expected one exception event, got %d", span.Events().Len())
+			}
+			event := span.Events().At(0)
+			if event.Name() != "exception" {
+				t.Errorf("unexpected event name %q", event.Name())
+			}
+			st, _ := event.Attributes().Get("exception.stacktrace")
+			if !strings.HasPrefix(st.Str(), "PaymentDeclined: card declined") {
+				t.Errorf("unexpected stacktrace %q", st.Str())
+			}
+		case "plain":
+			if span.Status().Message() != "error" || span.Events().Len() != 0 {
+				t.Errorf("expected the default error status without events, got %q and %d events", span.Status().Message(), span.Events().Len())
+			}
+		}
+	}
+}
+
+func TestStartSpread(t *testing.T) {
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		To:   time.Minute,
+		Rate: 200,
+		StartSpread: StartSpread{
+			Window:       config.DurationFromDuration(100 * time.Millisecond),
+			Distribution: "exponential",
+		},
+		Exemplar: Span{Name: "root"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Tick = time.Second
+	rs.Wallclock = time.Unix(1700000000, 0)
+	tb := signalbuilder.NewTracesBuilder()
+	if err := tp.Emit(rs, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Allow for the few milliseconds of start jitter.
+	windowStart := rs.Wallclock.Add(-time.Second - 10*time.Millisecond)
+	windowEnd := rs.Wallclock.Add(-time.Second + 100*time.Millisecond)
+	early := 0
+	spans := tb.Build().ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := range spans.Len() {
+		start := spans.At(i).StartTimestamp().AsTime()
+		if start.Before(windowStart) || !start.Before(windowEnd) {
+			t.Fatalf("span started at %v, outside the spread window", start)
+		}
+		if start.Before(windowStart.Add(40 * time.Millisecond)) {
+			early++
+		}
+	}
+	if early*2 < spans.Len() {
+		t.Errorf("expected exponential spread to front-load spans, got %d of %d early", early, spans.Len())
+	}
+}
+
+func TestStartSpread_Invalid(t *testing.T) {
+	_, err := NewTraceProducer(TraceProducerSpec{
+		StartSpread: StartSpread{Window: config.DurationFromDuration(2 * time.Second)},
+		Exemplar:    Span{Name: "root"},
+	})
+	if err == nil {
+		t.Error("expected an error for a window over 1s")
+	}
+}
+
+func spanCount(s Span) int {
+	n := 1
+	for _, child := range s.Children {
+		n += spanCount(child)
+	}
+	return n
+}
+
+func TestShapeVariation(t *testing.T) {
+	leaf := Span{Name: "leaf"}
+	root := Span{
+		Name: "root",
+		Children: []Span{
+			{Name: "a", Children: []Span{leaf, leaf}},
+			{Name: "b", Children: []Span{leaf}},
+		},
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+
+	t.Run("maxDepth cuts deeper spans", func(t *testing.T) {
+		v := &ShapeVariation{MaxDepth: 2}
+		if got := spanCount(v.apply(r, root)); got != 3 {
+			t.Errorf("expected 3 spans, got %d", got)
+		}
+	})
+
+	t.Run("minDepth protects shallow subtrees", func(t *testing.T) {
+		v := &ShapeVariation{Prune: 1, MinDepth: 2}
+		if got := spanCount(v.apply(r, root)); got != 3 {
+			t.Errorf("expected 3 spans, got %d", got)
+		}
+	})
+
+	t.Run("duplicates are bounded by maxWidth", func(t *testing.T) {
+		v := &ShapeVariation{Duplicate: 1, MaxWidth: 3}
+		shaped := v.apply(r, root)
+		if len(shaped.Children) != 3 {
+			t.Errorf("expected 3 children, got %d", len(shaped.Children))
+		}
+		if len(root.Children) != 2 || len(root.Children[0].Children) != 2 {
+			t.Error("apply modified the exemplar")
+		}
+	})
+
+	t.Run("minWidth refills pruned children", func(t *testing.T) {
+		v := &ShapeVariation{Prune: 1, MinWidth: 1}
+		shaped := v.apply(r, root)
+		if len(shaped.Children) != 1 || len(shaped.Children[0].Children) != 1 {
+			t.Errorf("expected one child at each level, got %+v", shaped)
+		}
+	})
+
+	t.Run("invalid bounds", func(t *testing.T) {
+		v := &ShapeVariation{MinDepth: 3, MaxDepth: 2}
+		if v.validate() == nil {
+			t.Error("expected an error for minDepth over maxDepth")
+		}
+	})
+}
+
+func TestResolveSubtrees(t *testing.T) {
+	subtrees := map[string]Span{
+		"auth": {
+			Name:     "auth.verify",
+			StartTs:  config.DurationFromDuration(time.Millisecond),
+			Children: []Span{{Use: "cache"}},
+		},
+		"cache": {Name: "redis GET"},
+		"loop":  {Name: "loop", Children: []Span{{Use: "loop"}}},
+	}
+
+	root := Span{
+		Name: "GET /cart",
+		Children: []Span{
+			{Use: "auth"},
+			{Use: "auth", StartTs: config.DurationFromDuration(50 * time.Millisecond)},
+		},
+	}
+	resolved, err := ResolveSubtrees(root, subtrees)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := spanCount(resolved); got != 5 {
+		t.Errorf("expected 5 spans, got %d", got)
+	}
+	first, second := resolved.Children[0], resolved.Children[1]
+	if first.Name != "auth.verify" || first.Children[0].Name != "redis GET" {
+		t.Errorf("unexpected resolved subtree %+v", first)
+	}
+	if first.StartTs.Get() != time.Millisecond || second.StartTs.Get() != 50*time.Millisecond {
+		t.Errorf("unexpected start offsets %v and %v", first.StartTs.Get(), second.StartTs.Get())
+	}
+	if root.Children[0].Use != "auth" {
+		t.Error("ResolveSubtrees modified its input")
+	}
+
+	if _, err := ResolveSubtrees(Span{Use: "loop"}, subtrees); err == nil {
+		t.Error("expected an error for a subtree that uses itself")
+	}
+	if _, err := ResolveSubtrees(Span{Use: "missing"}, subtrees); err == nil {
+		t.Error("expected an error for an unknown subtree")
+	}
+}
+
+func TestLintExemplar(t *testing.T) {
+	ms := func(n int) config.Duration { return config.DurationFromDuration(time.Duration(n) * time.Millisecond) }
+	svc := func(name string) map[string]any { return map[string]any{"service.name": name} }
+
+	root := Span{
+		Ref: "frontend", Name: "GET /cart", Kind: "server", Duration: ms(100), ResourceAttributes: svc("frontend"),
+		Children: []Span{
+			{Ref: "early", Name: "auth", Kind: "client", StartTs: ms(0), Duration: ms(10), ResourceAttributes: svc("frontend")},
+			{Name: "cart", Kind: "client", StartTs: ms(10), Duration: ms(80), ResourceAttributes: svc("frontend"),
+				Children: []Span{
+					{Ref: "db", Kind: "client", StartTs: ms(20), Duration: ms(90), ResourceAttributes: svc("cart")},
+					{Ref: "cache", Kind: "server", StartTs: ms(80), Duration: ms(20)},
+				}},
+		},
+	}
+	got := lintExemplar(root)
+	want := []lintFinding{
+		{Span: "frontend/cart/db", Problem: "lasts 90ms, longer than its parent's 80ms"},
+		{Span: "frontend/cart/db", Problem: "client span directly under a client span"},
+		{Span: "frontend/cart/cache", Problem: "no service.name resource attribute"},
+		{Span: "frontend/cart/cache", Problem: "ends at 100ms, after its parent at 90ms"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("unexpected findings:\nwant %v\ngot  %v", want, got)
+	}
+
+	root.Children[0].StartTs = config.DurationFromDuration(-time.Millisecond)
+	root.Children[0].Kind = "server"
+	got = lintExemplar(root)
+	if !slices.Contains(got, lintFinding{Span: "frontend/early", Problem: "starts at -1ms, before its parent at 0s"}) ||
+		!slices.Contains(got, lintFinding{Span: "frontend/early", Problem: "server span directly under a server span"}) {
+		t.Errorf("expected findings for the early server span, got %v", got)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	emit := func(sampling *Sampling) ptrace.SpanSlice {
+		tp, err := NewTraceProducer(TraceProducerSpec{
+			To:       time.Minute,
+			Rate:     400,
+			Sampling: sampling,
+			Exemplar: Span{
+				Name:               "root",
+				ResourceAttributes: map[string]any{"service.name": "frontend"},
+				Children:           []Span{{Name: "child"}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rs := state.NewRunState(time.Minute, 1)
+		rs.Tick = time.Second
+		rs.Wallclock = time.Unix(1700000000, 0)
+		tb := signalbuilder.NewTracesBuilder()
+		if err := tp.Emit(rs, tb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		all := ptrace.NewSpanSlice()
+		for _, rs := range tb.Build().ResourceSpans().All() {
+			for _, ss := range rs.ScopeSpans().All() {
+				ss.Spans().MoveAndAppendTo(all)
+			}
+		}
+		return all
+	}
+
+	t.Run("unsampled traces keep only an unsampled root", func(t *testing.T) {
+		spans := emit(&Sampling{Rate: 0.25, Unsampled: "root"})
+		roots, children := 0, 0
+		for i := range spans.Len() {
+			span := spans.At(i)
+			sampled := span.Flags()&sampledFlag != 0
+			if span.Name() == "root" {
+				roots++
+				continue
+			}
+			children++
+			if !sampled {
+				t.Fatal("expected children only in sampled traces")
+			}
+		}
+		if children == 0 || children*2 > roots {
+			t.Errorf("expected about a quarter of traces sampled, got %d of %d", children, roots)
+		}
+	})
+
+	t.Run("per-service rate drops everything", func(t *testing.T) {
+		spans := emit(&Sampling{Rate: 1, Services: map[string]float64{"frontend": 0}})
+		if spans.Len() != 0 {
+			t.Errorf("expected no spans, got %d", spans.Len())
+		}
+	})
+}
+
+func TestTraceStateAndFlags(t *testing.T) {
+	childFlags := uint32(0x02)
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		To:   time.Minute,
+		Rate: 20,
+		Exemplar: Span{
+			Name:       "root",
+			TraceState: "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7",
+			Children: []Span{
+				{Name: "inherits"},
+				{Name: "overrides", TraceState: "rojo=1", TraceFlags: &childFlags},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Tick = time.Second
+	rs.Wallclock = time.Unix(1700000000, 0)
+	tb := signalbuilder.NewTracesBuilder()
+	if err := tp.Emit(rs, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := tb.Build().ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := range spans.Len() {
+		span := spans.At(i)
+		switch span.Name() {
+		case "root", "inherits":
+			if span.TraceState().AsRaw() != "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7" || span.Flags() != 0 {
+				t.Errorf("%s: unexpected trace state %q and flags %d", span.Name(), span.TraceState().AsRaw(), span.Flags())
+			}
+		case "overrides":
+			if span.TraceState().AsRaw() != "rojo=1" || span.Flags() != childFlags {
+				t.Errorf("%s: unexpected trace state %q and flags %d", span.Name(), span.TraceState().AsRaw(), span.Flags())
+			}
+		}
+	}
+}
+
+func TestBursts(t *testing.T) {
+	d := config.DurationFromDuration
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		Rate: 20,
+		Bursts: []Burst{
+			{At: d(10 * time.Second), Duration: d(5 * time.Second), Multiplier: 5},
+			{At: d(12 * time.Second), Duration: d(time.Second), Multiplier: 2},
+		},
+		Exemplar: Span{Name: "root"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rs := state.NewRunState(time.Minute, 1)
+	count := func(tick time.Duration) int {
+		rs.Tick = tick
+		rs.Wallclock = time.Unix(1700000000, 0).Add(tick)
+		tb := signalbuilder.NewTracesBuilder()
+		if err := tp.Emit(rs, tb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return tb.Build().SpanCount()
+	}
+
+	for _, tc := range []struct {
+		tick     time.Duration
+		min, max int
+	}{
+		{9 * time.Second, 20, 23},
+		{10 * time.Second, 100, 111},
+		{12 * time.Second, 200, 221},
+		{15 * time.Second, 20, 23},
+	} {
+		if got := count(tc.tick); got < tc.min || got > tc.max {
+			t.Errorf("at %v: expected %d to %d traces, got %d", tc.tick, tc.min, tc.max, got)
+		}
+	}
+
+	if _, err := NewTraceProducer(TraceProducerSpec{Bursts: []Burst{{Multiplier: 2}}}); err == nil {
+		t.Error("expected an error for a burst without a duration")
+	}
+}
+
+func TestSLABreach(t *testing.T) {
+	d := config.DurationFromDuration
+	emit := func(threshold time.Duration) (roots, breaches int) {
+		tp, err := NewTraceProducer(TraceProducerSpec{
+			To:   time.Minute,
+			Rate: 20,
+			SLA: &SLA{
+				Threshold:  d(threshold),
+				Attributes: map[string]any{"alert.name": "checkout-latency"},
+			},
+			Exemplar: Span{
+				Name:     "root",
+				Duration: d(300 * time.Millisecond),
+				Children: []Span{{Name: "child", Duration: d(290 * time.Millisecond)}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rs := state.NewRunState(time.Minute, 1)
+		rs.Tick = time.Second
+		rs.Wallclock = time.Unix(1700000000, 0)
+		tb := signalbuilder.NewTracesBuilder()
+		if err := tp.Emit(rs, tb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		spans := tb.Build().ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := range spans.Len() {
+			span := spans.At(i)
+			if span.Name() == "root" {
+				roots++
+			}
+			for j := range span.Events().Len() {
+				event := span.Events().At(j)
+				if event.Name() != DefaultSLAEventName {
+					continue
+				}
+				if span.Name() != "root" {
+					t.Errorf("unexpected breach event on %s", span.Name())
+				}
+				if v, _ := event.Attributes().Get("alert.name"); v.Str() != "checkout-latency" {
+					t.Errorf("unexpected alert.name %q", v.Str())
+				}
+				breaches++
+			}
+		}
+		return roots, breaches
+	}
+
+	if roots, breaches := emit(250 * time.Millisecond); roots == 0 || breaches != roots {
+		t.Errorf("expected a breach on every root, got %d of %d", breaches, roots)
+	}
+	if _, breaches := emit(time.Second); breaches != 0 {
+		t.Errorf("expected no breaches, got %d", breaches)
+	}
+}
+
+func TestSLABreachLog(t *testing.T) {
+	d := config.DurationFromDuration
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		To:   time.Minute,
+		Rate: 20,
+		SLA: &SLA{
+			Threshold:  d(250 * time.Millisecond),
+			Attributes: map[string]any{"alert.name": "checkout-latency"},
+			Log:        true,
+		},
+		Exemplar: Span{
+			Name:               "root",
+			Duration:           d(300 * time.Millisecond),
+			ResourceAttributes: map[string]any{"service.name": "checkout"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Tick = time.Second
+	rs.Wallclock = time.Unix(1700000000, 0)
+	tb := signalbuilder.NewTracesBuilder()
+	if err := tp.Emit(rs, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	roots := map[pcommon.SpanID]ptrace.Span{}
+	spans := tb.Build().ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := range spans.Len() {
+		span := spans.At(i)
+		if span.Events().Len() != 0 {
+			t.Errorf("unexpected span event %s with the breach sent as a log", span.Events().At(0).Name())
+		}
+		roots[span.SpanID()] = span
+	}
+
+	ld := tp.(LogProducer).TakeLogs()
+	if len(roots) == 0 || ld.LogRecordCount() != len(roots) {
+		t.Fatalf("expected a breach log for each of %d roots, got %d", len(roots), ld.LogRecordCount())
+	}
+	for _, rl := range ld.ResourceLogs().All() {
+		if v, _ := rl.Resource().Attributes().Get("service.name"); v.Str() != "checkout" {
+			t.Errorf("unexpected service.name %q", v.Str())
+		}
+		lr := rl.ScopeLogs().At(0).LogRecords().At(0)
+		span, ok := roots[lr.SpanID()]
+		if !ok || lr.TraceID() != span.TraceID() {
+			t.Errorf("breach log does not link to its span")
+		}
+		if lr.EventName() != DefaultSLAEventName || lr.Timestamp() != span.EndTimestamp() {
+			t.Errorf("unexpected breach log %s at %v", lr.EventName(), lr.Timestamp())
+		}
+		if v, _ := lr.Attributes().Get("alert.name"); v.Str() != "checkout-latency" {
+			t.Errorf("unexpected alert.name %q", v.Str())
+		}
+	}
+	if n := tp.(LogProducer).TakeLogs().LogRecordCount(); n != 0 {
+		t.Errorf("expected TakeLogs to drain the records, %d left", n)
+	}
+}
+
+func TestIDsAreUnique(t *testing.T) {
+	const n = 1 << 18
+	spans := make(map[pcommon.SpanID]struct{}, n)
+	traces := make(map[pcommon.TraceID]struct{}, n)
+	for range n {
+		spanID := NewSpanID()
+		if spanID.IsEmpty() {
+			t.Fatal("got an empty span ID")
+		}
+		spans[spanID] = struct{}{}
+		// A fresh, identically seeded source each time gives the same
+		// random bits, so only the counter keeps trace IDs apart.
+		traces[NewTraceID(rand.New(rand.NewPCG(1, 1)))] = struct{}{}
+	}
+	if len(spans) != n {
+		t.Errorf("expected %d distinct span IDs, got %d", n, len(spans))
+	}
+	if len(traces) != n {
+		t.Errorf("expected %d distinct trace IDs, got %d", n, len(traces))
+	}
+}
+
+func TestIDsAreUniqueAcrossShards(t *testing.T) {
+	t.Cleanup(func() { SetIDShard(0) })
+	const n = 1 << 12
+	spans := map[pcommon.SpanID]struct{}{}
+	traces := map[pcommon.TraceID]struct{}{}
+	for shard := range 4 {
+		// Each worker is its own process, with counters from zero.
+		traceSeq.Store(0)
+		spanSeq.Store(0)
+		SetIDShard(shard)
+		for range n {
+			spans[NewSpanID()] = struct{}{}
+			traces[NewTraceID(rand.New(rand.NewPCG(1, 1)))] = struct{}{}
+		}
+	}
+	if len(spans) != 4*n {
+		t.Errorf("expected %d distinct span IDs, got %d", 4*n, len(spans))
+	}
+	if len(traces) != 4*n {
+		t.Errorf("expected %d distinct trace IDs, got %d", 4*n, len(traces))
+	}
+}
+
+func TestWeightedExemplars(t *testing.T) {
+	tp, err := NewTraceProducer(TraceProducerSpec{
+		To:   time.Minute,
+		Rate: 400,
+		Exemplars: []WeightedExemplar{
+			{Weight: 3, Exemplar: Span{Name: "checkout fast-path"}},
+			{Weight: 1, Exemplar: Span{Name: "checkout cache-miss", Children: []Span{{Name: "db"}}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rs := state.NewRunState(time.Minute, 1)
+	rs.Tick = time.Second
+	rs.Wallclock = time.Unix(1700000000, 0)
+	tb := signalbuilder.NewTracesBuilder()
+	if err := tp.Emit(rs, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := map[string]int{}
+	spans := tb.Build().ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := range spans.Len() {
+		names[spans.At(i).Name()]++
+	}
+	fast, slow := names["checkout fast-path"], names["checkout cache-miss"]
+	if slow == 0 || fast < 2*slow || names["db"] != slow {
+		t.Errorf("expected about 3 fast traces per cache-miss trace, got %v", names)
+	}
+
+	_, err = NewTraceProducer(TraceProducerSpec{
+		Exemplars: []WeightedExemplar{{Weight: 0, Exemplar: Span{Name: "x"}}},
+	})
+	if err == nil {
+		t.Error("expected an error for a zero weight")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 CardinalHQ, Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceproducer
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+)
+
+// WeightedValue is one choice for a weighted attribute.  Choices are
+// picked in proportion to their Weight.
+type WeightedValue struct {
+	Value  any     `mapstructure:"value" yaml:"value" json:"value"`
+	Weight float64 `mapstructure:"weight" yaml:"weight" json:"weight"`
+}
+
+func pickWeighted(r *rand.Rand, choices []WeightedValue) any {
+	total := 0.0
+	for _, c := range choices {
+		total += c.Weight
+	}
+	x := r.Float64() * total
+	for _, c := range choices {
+		x -= c.Weight
+		if x < 0 {
+			return c.Value
+		}
+	}
+	return choices[len(choices)-1].Value
+}
+
+// sampleWeighted picks a value for each weighted attribute of s, in key
+// order so that runs with the same seed pick the same values.
+func sampleWeighted(r *rand.Rand, s Span) map[string]any {
+	if len(s.WeightedAttributes) == 0 {
+		return nil
+	}
+	values := make(map[string]any, len(s.WeightedAttributes))
+	for _, key := range slices.Sorted(maps.Keys(s.WeightedAttributes)) {
+		values[key] = pickWeighted(r, s.WeightedAttributes[key])
+	}
+	return values
+}
+
+func checkWeighted(s Span) error {
+	for key, choices := range s.WeightedAttributes {
+		if len(choices) == 0 {
+			return fmt.Errorf("span %s: weighted attribute %s has no values", s.Name, key)
+		}
+		total := 0.0
+		for _, c := range choices {
+			if c.Weight < 0 {
+				return fmt.Errorf("span %s: weighted attribute %s has a negative weight", s.Name, key)
+			}
+			total += c.Weight
+		}
+		if total == 0 {
+			return fmt.Errorf("span %s: weighted attribute %s has no positive weights", s.Name, key)
+		}
+	}
+	for _, child := range s.Children {
+		if err := checkWeighted(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}